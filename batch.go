@@ -0,0 +1,253 @@
+package goa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultBatchMaxRequests is the default maximum number of sub-requests of a batch request.
+	DefaultBatchMaxRequests = 50
+	// DefaultBatchConcurrency is the default maximum number of sub-requests of a batch request
+	// dispatched concurrently.
+	DefaultBatchConcurrency = 8
+)
+
+type (
+	// BatchOptions configures the batch endpoint mounted by MountBatch.
+	BatchOptions struct {
+		// MaxRequests is the maximum number of sub-requests of a batch request,
+		// DefaultBatchMaxRequests if zero.
+		MaxRequests int
+		// Concurrency is the maximum number of sub-requests dispatched concurrently,
+		// DefaultBatchConcurrency if zero.
+		Concurrency int
+		// RequestID returns the ID of the batch request given its context, for example
+		// middleware.ContextRequestID. The ID is recorded in the context of the sub-requests,
+		// see ContextParentRequestID. The value of the X-Request-Id header of the batch
+		// request is used if RequestID is nil.
+		RequestID func(context.Context) string
+	}
+
+	// BatchRequest is a sub-request of a batch request.
+	BatchRequest struct {
+		// Method is the HTTP method of the sub-request.
+		Method string `json:"method"`
+		// Path is the path of the sub-request including the query string if any.
+		Path string `json:"path"`
+		// Headers lists the headers of the sub-request. The sub-request also inherits the
+		// headers of the batch request that it does not override.
+		Headers map[string]string `json:"headers,omitempty"`
+		// Body is the JSON body of the sub-request if any.
+		Body json.RawMessage `json:"body,omitempty"`
+		// DependsOn lists the indices of the sub-requests that must complete before the
+		// sub-request is dispatched. A sub-request may only depend on the sub-requests that
+		// precede it.
+		DependsOn []int `json:"depends_on,omitempty"`
+	}
+
+	// BatchResponse is the response to a sub-request of a batch request.
+	BatchResponse struct {
+		// Status is the HTTP status code of the sub-response.
+		Status int `json:"status"`
+		// Headers lists the headers of the sub-response, multiple values are separated by
+		// commas.
+		Headers map[string]string `json:"headers,omitempty"`
+		// Body is the body of the sub-response, a JSON string if the body is not valid JSON.
+		Body json.RawMessage `json:"body,omitempty"`
+	}
+
+	// batchResponseWriter records the response to a sub-request.
+	batchResponseWriter struct {
+		header http.Header
+		status int
+		body   bytes.Buffer
+	}
+)
+
+// MountBatch mounts the batch endpoint on the service mux at the given path. The endpoint accepts
+// POST requests whose body is a JSON array of BatchRequest values and responds with a JSON array of
+// the corresponding BatchResponse values in the same order.
+//
+// Each sub-request is dispatched in-process through the service mux and thus runs the full
+// middleware chain of the targeted action including the security middleware. Sub-requests run
+// concurrently unless they depend on other sub-requests, opts may be nil to use the defaults.
+func (service *Service) MountBatch(path string, opts *BatchOptions) {
+	if opts == nil {
+		opts = &BatchOptions{}
+	}
+	maxRequests := opts.MaxRequests
+	if maxRequests <= 0 {
+		maxRequests = DefaultBatchMaxRequests
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+	ctrl := service.NewController("Batch")
+	handler := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		if req.Context().Value(batchKey) != nil {
+			return ErrBadRequest("batch requests cannot be nested")
+		}
+		var reqs []*BatchRequest
+		if err := json.NewDecoder(req.Body).Decode(&reqs); err != nil {
+			return ErrBadRequest(err)
+		}
+		if len(reqs) > maxRequests {
+			return ErrBadRequest(fmt.Sprintf("batch request contains %d sub-requests, the maximum is %d", len(reqs), maxRequests))
+		}
+		for i, r := range reqs {
+			if r == nil {
+				return ErrBadRequest(fmt.Sprintf("sub-request %d is null", i))
+			}
+			for _, d := range r.DependsOn {
+				if d < 0 || d >= i {
+					return ErrBadRequest(fmt.Sprintf("sub-request %d depends on invalid sub-request %d", i, d))
+				}
+			}
+		}
+		parentID := req.Header.Get("X-Request-Id")
+		if opts.RequestID != nil {
+			parentID = opts.RequestID(ctx)
+		}
+		// Derive the sub-request contexts from the HTTP request context rather than from the
+		// batch action context so that they do not inherit its action data. The sub-request
+		// contexts are marked so that sub-requests cannot be batch requests themselves whatever
+		// the path used to reach a batch endpoint.
+		subctx := WithParentRequestID(req.Context(), parentID)
+		subctx = context.WithValue(subctx, batchKey, true)
+
+		var (
+			resps = make([]*BatchResponse, len(reqs))
+			done  = make([]chan struct{}, len(reqs))
+			sem   = make(chan struct{}, concurrency)
+			wg    sync.WaitGroup
+		)
+		for i := range reqs {
+			done[i] = make(chan struct{})
+		}
+		for i, r := range reqs {
+			wg.Add(1)
+			go func(i int, r *BatchRequest) {
+				defer wg.Done()
+				defer close(done[i])
+				for _, d := range r.DependsOn {
+					<-done[d]
+				}
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				resps[i] = service.dispatchBatchRequest(subctx, path, req, r)
+			}(i, r)
+		}
+		wg.Wait()
+
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusOK)
+		return json.NewEncoder(rw).Encode(resps)
+	}
+	service.Mux.Handle("POST", path, ctrl.MuxHandler("batch", handler, nil))
+	service.LogInfo("mount", "ctrl", "Batch", "action", "batch", "route", "POST "+path)
+}
+
+// dispatchBatchRequest dispatches the sub-request r of the batch request req through the service
+// mux and returns the response.
+func (service *Service) dispatchBatchRequest(ctx context.Context, batchPath string, req *http.Request, r *BatchRequest) *BatchResponse {
+	u, err := url.Parse(r.Path)
+	if err != nil || !strings.HasPrefix(u.Path, "/") {
+		return batchError(ErrBadRequest(fmt.Sprintf("invalid sub-request path %#v", r.Path)))
+	}
+	if path.Clean(u.Path) == batchPath {
+		return batchError(ErrBadRequest("batch requests cannot be nested"))
+	}
+	method := r.Method
+	if method == "" {
+		method = "GET"
+	}
+	sub, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(r.Body))
+	if err != nil {
+		return batchError(ErrBadRequest(err))
+	}
+	for k, vs := range req.Header {
+		switch http.CanonicalHeaderKey(k) {
+		case "Content-Length", "Content-Type", "X-Request-Id":
+			continue
+		}
+		sub.Header[k] = vs
+	}
+	if len(r.Body) > 0 {
+		sub.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range r.Headers {
+		sub.Header.Set(k, v)
+	}
+	sub.Host = req.Host
+	sub.RemoteAddr = req.RemoteAddr
+	sub.TLS = req.TLS
+
+	w := &batchResponseWriter{header: make(http.Header)}
+	service.Mux.ServeHTTP(w, sub)
+	return w.response()
+}
+
+// batchError returns the sub-response that describes err.
+func batchError(err error) *BatchResponse {
+	status := http.StatusInternalServerError
+	if serr, ok := err.(ServiceError); ok {
+		status = serr.ResponseStatus()
+	}
+	body, _ := json.Marshal(err)
+	return &BatchResponse{
+		Status:  status,
+		Headers: map[string]string{"Content-Type": ErrorMediaIdentifier},
+		Body:    body,
+	}
+}
+
+// Header returns the sub-response headers.
+func (w *batchResponseWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader records the sub-response status code.
+func (w *batchResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+// Write records the sub-response body.
+func (w *batchResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+// response returns the recorded sub-response.
+func (w *batchResponseWriter) response() *BatchResponse {
+	resp := &BatchResponse{Status: w.status}
+	if resp.Status == 0 {
+		resp.Status = http.StatusOK
+	}
+	if len(w.header) > 0 {
+		resp.Headers = make(map[string]string, len(w.header))
+		for k, vs := range w.header {
+			resp.Headers[k] = strings.Join(vs, ", ")
+		}
+	}
+	if b := bytes.TrimSpace(w.body.Bytes()); len(b) > 0 {
+		if json.Valid(b) {
+			resp.Body = b
+		} else {
+			resp.Body, _ = json.Marshal(string(b))
+		}
+	}
+	return resp
+}
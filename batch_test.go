@@ -0,0 +1,222 @@
+package goa_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1"
+	"github.com/shogo82148/goa-v1/middleware"
+)
+
+var _ = Describe("MountBatch", func() {
+	var service *goa.Service
+	var opts *goa.BatchOptions
+	var body string
+	var rw *httptest.ResponseRecorder
+	var resps []*goa.BatchResponse
+
+	var mu sync.Mutex
+	var order []string
+	var parentIDs []string
+	var running, maxRunning int32
+
+	BeforeEach(func() {
+		service = goa.New("test")
+		service.Encoder.Register(goa.NewJSONEncoder, "*/*")
+		service.Decoder.Register(goa.NewJSONDecoder, "*/*")
+		service.Use(middleware.RequestID())
+		service.Use(middleware.ErrorHandler(service, false))
+		opts = &goa.BatchOptions{RequestID: middleware.ContextRequestID}
+		order = nil
+		parentIDs = nil
+		running, maxRunning = 0, 0
+
+		record := func(ctx context.Context, name string) {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			parentIDs = append(parentIDs, goa.ContextParentRequestID(ctx))
+		}
+
+		ctrl := service.NewController("items")
+		show := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			id := goa.ContextRequest(ctx).Params.Get("id")
+			record(ctx, "show "+id)
+			return service.Send(ctx, 200, map[string]string{"id": id})
+		}
+		create := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			b, _ := io.ReadAll(req.Body)
+			record(ctx, "create")
+			rw.Header().Set("Location", "/items/1")
+			rw.WriteHeader(201)
+			_, err := rw.Write(b)
+			return err
+		}
+		service.Mux.Handle("GET", "/items/:id", ctrl.MuxHandler("show", show, nil))
+		service.Mux.Handle("POST", "/items", ctrl.MuxHandler("create", create, nil))
+
+		secure := service.NewController("secrets")
+		secure.Use(func(h goa.Handler) goa.Handler {
+			return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				if req.Header.Get("Authorization") != "Bearer secret" {
+					rw.WriteHeader(401)
+					return nil
+				}
+				return h(ctx, rw, req)
+			}
+		})
+		secret := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			_, err := io.WriteString(rw, "plain text")
+			return err
+		}
+		service.Mux.Handle("GET", "/secret", secure.MuxHandler("show", secret, nil))
+	})
+
+	JustBeforeEach(func() {
+		service.MountBatch("/batch", opts)
+		req, _ := http.NewRequest("POST", "/batch", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		rw = httptest.NewRecorder()
+		service.Mux.ServeHTTP(rw, req)
+		resps = nil
+		if rw.Code == 200 {
+			Ω(json.Unmarshal(rw.Body.Bytes(), &resps)).Should(Succeed())
+		}
+	})
+
+	Context("with independent sub-requests", func() {
+		BeforeEach(func() {
+			body = `[
+				{"method": "GET", "path": "/items/1"},
+				{"method": "GET", "path": "/items/2"},
+				{"method": "GET", "path": "/items/3"},
+				{"method": "GET", "path": "/unknown"}
+			]`
+			opts.Concurrency = 2
+		})
+
+		It("dispatches them concurrently within the limit", func() {
+			Ω(rw.Code).Should(Equal(200))
+			Ω(resps).Should(HaveLen(4))
+			for i, id := range []string{"1", "2", "3"} {
+				Ω(resps[i].Status).Should(Equal(200))
+				Ω(string(resps[i].Body)).Should(MatchJSON(`{"id":"` + id + `"}`))
+			}
+			Ω(resps[3].Status).Should(Equal(404))
+			Ω(maxRunning).Should(BeEquivalentTo(2))
+		})
+
+		It("records the batch request ID in the sub-request contexts", func() {
+			Ω(parentIDs).Should(HaveLen(3))
+			Ω(parentIDs[0]).ShouldNot(BeEmpty())
+			Ω(parentIDs).Should(HaveEach(parentIDs[0]))
+		})
+	})
+
+	Context("with dependent sub-requests", func() {
+		BeforeEach(func() {
+			body = `[
+				{"method": "POST", "path": "/items", "body": {"name": "foo"}},
+				{"path": "/items/1", "depends_on": [0]}
+			]`
+		})
+
+		It("dispatches them in order", func() {
+			Ω(order).Should(Equal([]string{"create", "show 1"}))
+			Ω(resps[0].Status).Should(Equal(201))
+			Ω(resps[0].Headers).Should(HaveKeyWithValue("Location", "/items/1"))
+			Ω(string(resps[0].Body)).Should(MatchJSON(`{"name":"foo"}`))
+		})
+	})
+
+	Context("with secured sub-requests", func() {
+		BeforeEach(func() {
+			body = `[
+				{"path": "/secret"},
+				{"path": "/secret", "headers": {"Authorization": "Bearer wrong"}}
+			]`
+		})
+
+		It("runs the middleware of each sub-request", func() {
+			Ω(resps[0].Status).Should(Equal(200))
+			Ω(string(resps[0].Body)).Should(Equal(`"plain text"`))
+			Ω(resps[1].Status).Should(Equal(401))
+		})
+	})
+
+	Context("with a nested batch request", func() {
+		BeforeEach(func() {
+			body = `[{"method": "POST", "path": "/batch", "body": []}]`
+		})
+
+		It("rejects the sub-request", func() {
+			Ω(resps[0].Status).Should(Equal(400))
+		})
+	})
+
+	Context("with nested batch requests using non canonical paths", func() {
+		BeforeEach(func() {
+			body = `[{"method": "POST", "path": "/batch/", "body": []}, {"method": "POST", "path": "//batch", "body": []}, {"method": "POST", "path": "/./batch", "body": []}]`
+		})
+
+		It("rejects the sub-requests", func() {
+			Ω(resps).Should(HaveLen(3))
+			for _, r := range resps {
+				Ω(r.Status).Should(Equal(400))
+			}
+		})
+	})
+
+	Context("with a batch request nested through another batch endpoint", func() {
+		BeforeEach(func() {
+			service.MountBatch("/other", nil)
+			body = `[{"method": "POST", "path": "/other", "body": [{"path": "/items/1"}]}]`
+		})
+
+		It("rejects the sub-request", func() {
+			Ω(resps[0].Status).Should(Equal(400))
+			Ω(order).Should(BeEmpty())
+		})
+	})
+
+	Context("with a sub-request depending on a later sub-request", func() {
+		BeforeEach(func() {
+			body = `[{"path": "/items/1", "depends_on": [1]}, {"path": "/items/2"}]`
+		})
+
+		It("rejects the batch request", func() {
+			Ω(rw.Code).Should(Equal(400))
+			Ω(order).Should(BeEmpty())
+		})
+	})
+
+	Context("with too many sub-requests", func() {
+		BeforeEach(func() {
+			opts.MaxRequests = 1
+			body = `[{"path": "/items/1"}, {"path": "/items/2"}]`
+		})
+
+		It("rejects the batch request", func() {
+			Ω(rw.Code).Should(Equal(400))
+			Ω(order).Should(BeEmpty())
+		})
+	})
+})
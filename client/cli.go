@@ -73,6 +73,36 @@ func HandleResponse(c *Client, resp *http.Response, pretty bool) {
 	os.Exit(exitStatus)
 }
 
+// HandleEvents prints the data of the Server-Sent Events streamed in the response to STDOUT, one
+// event per line. It delegates to HandleResponse if the response status code is not 2xx.
+func HandleEvents(c *Client, resp *http.Response, pretty bool) error {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		HandleResponse(c, resp, pretty)
+		return nil
+	}
+	r := NewEventReader(resp.Body)
+	defer r.Close()
+	for {
+		event, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		out := string(event.Data)
+		if pretty {
+			var jdata interface{}
+			if err := json.Unmarshal(event.Data, &jdata); err == nil {
+				if b, err := json.MarshalIndent(jdata, "", "    "); err == nil {
+					out = string(b)
+				}
+			}
+		}
+		fmt.Println(out)
+	}
+}
+
 // WSWrite sends STDIN lines to a websocket server.
 func WSWrite(ws *websocket.Conn) {
 	scanner := bufio.NewScanner(os.Stdin)
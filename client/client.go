@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httputil"
+	"strings"
 	"time"
 
 	"github.com/shogo82148/goa-v1"
@@ -50,6 +51,34 @@ func HTTPClientDoer(hc *http.Client) Doer {
 	})
 }
 
+// HeaderVersionDoer returns a Doer that sets the request header with the given name to version
+// before calling d. The generated clients of API versions selected by header use it.
+func HeaderVersionDoer(d Doer, header, version string) Doer {
+	return doFunc(func(ctx context.Context, req *http.Request) (*http.Response, error) {
+		req.Header.Set(header, version)
+		return d.Do(ctx, req)
+	})
+}
+
+// MediaTypeVersionDoer returns a Doer that adds the media type parameter with the given name and
+// value to the media ranges of the request Accept header before calling d. The Accept header is
+// set to "*/*" with the parameter if the request does not have one. The generated clients of API
+// versions selected by media type parameter use it.
+func MediaTypeVersionDoer(d Doer, param, version string) Doer {
+	return doFunc(func(ctx context.Context, req *http.Request) (*http.Response, error) {
+		accept := req.Header.Get("Accept")
+		if accept == "" {
+			accept = "*/*"
+		}
+		ranges := strings.Split(accept, ",")
+		for i, r := range ranges {
+			ranges[i] = strings.TrimSpace(r) + "; " + param + "=" + version
+		}
+		req.Header.Set("Accept", strings.Join(ranges, ", "))
+		return d.Do(ctx, req)
+	})
+}
+
 // doFunc is the type definition of the Doer.Do method. It implements Doer.
 type doFunc func(context.Context, *http.Request) (*http.Response, error)
 
@@ -81,6 +110,13 @@ func (c *Client) Do(ctx context.Context, req *http.Request) (*http.Response, err
 		return nil, err
 	}
 	goa.LogInfo(ctx, "completed", "id", id, "status", resp.StatusCode, "time", time.Since(startedAt).String())
+	if deprecation := resp.Header.Get("Deprecation"); deprecation != "" {
+		keyvals := []interface{}{"id", id, "deprecation", deprecation}
+		if sunset := resp.Header.Get("Sunset"); sunset != "" {
+			keyvals = append(keyvals, "sunset", sunset)
+		}
+		goa.LogInfo(ctx, "deprecated endpoint", keyvals...)
+	}
 	if c.Dump {
 		c.dumpResponse(ctx, resp)
 	}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// Event is a Server-Sent Event.
+	Event struct {
+		// ID is the event ID, it is sent back in the Last-Event-ID header when resuming the
+		// stream.
+		ID string
+		// Event is the event type, empty for the default "message" type.
+		Event string
+		// Data is the event data.
+		Data []byte
+		// Retry is the reconnection delay requested by the server if any.
+		Retry time.Duration
+	}
+
+	// EventReader reads the Server-Sent Events of a "text/event-stream" response body.
+	EventReader struct {
+		body        io.ReadCloser
+		scanner     *bufio.Scanner
+		lastEventID string
+	}
+)
+
+// NewEventReader returns a reader of the events streamed in body.
+func NewEventReader(body io.ReadCloser) *EventReader {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 4096), 1<<20)
+	return &EventReader{body: body, scanner: scanner}
+}
+
+// Next returns the next event of the stream. It returns io.EOF when the server closes the stream.
+// Comments such as the keepalives sent by goa services are skipped.
+func (r *EventReader) Next() (*Event, error) {
+	var (
+		event   Event
+		data    bytes.Buffer
+		hasData bool
+	)
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+		if line == "" {
+			if !hasData {
+				event = Event{}
+				continue
+			}
+			event.Data = bytes.TrimSuffix(data.Bytes(), []byte("\n"))
+			if event.ID == "" {
+				event.ID = r.lastEventID
+			}
+			r.lastEventID = event.ID
+			return &event, nil
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			event.ID = value
+		case "event":
+			event.Event = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil {
+				event.Retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// LastEventID returns the ID of the last event read, it can be used to resume the stream.
+func (r *EventReader) LastEventID() string {
+	return r.lastEventID
+}
+
+// Close closes the underlying response body.
+func (r *EventReader) Close() error {
+	return r.body.Close()
+}
@@ -0,0 +1,45 @@
+package client_test
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shogo82148/goa-v1/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EventReader", func() {
+	var stream string
+	var reader *client.EventReader
+
+	BeforeEach(func() {
+		stream = ": keepalive\n\n" +
+			"id: 1\nevent: update\ndata: {\"a\":\ndata: 1}\n\n" +
+			"retry: 500\ndata: second\n\n"
+	})
+
+	JustBeforeEach(func() {
+		reader = client.NewEventReader(io.NopCloser(strings.NewReader(stream)))
+	})
+
+	It("reads the events and skips the comments", func() {
+		event, err := reader.Next()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(event.ID).To(Equal("1"))
+		Expect(event.Event).To(Equal("update"))
+		Expect(string(event.Data)).To(Equal("{\"a\":\n1}"))
+
+		event, err = reader.Next()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(event.ID).To(Equal("1"))
+		Expect(event.Retry).To(Equal(500 * time.Millisecond))
+		Expect(string(event.Data)).To(Equal("second"))
+		Expect(reader.LastEventID()).To(Equal("1"))
+
+		_, err = reader.Next()
+		Expect(err).To(Equal(io.EOF))
+	})
+})
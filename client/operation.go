@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var (
+	// OperationPollInterval is the delay before the first request polling the status of a
+	// long-running operation when the server does not specify one with the Retry-After header.
+	// The delay doubles after each request.
+	OperationPollInterval = time.Second
+	// MaxOperationPollInterval is the maximum delay between two requests polling the status of a
+	// long-running operation.
+	MaxOperationPollInterval = 30 * time.Second
+)
+
+// OperationRequestFunc creates the request that retrieves the status of the operation at the given
+// location. Generated clients use the request builder of the operation status action so that the
+// requests are signed.
+type OperationRequestFunc func(ctx context.Context, location *url.URL) (*http.Request, error)
+
+// PollOperation polls the status of the long-running operation started by the request that
+// returned resp. resp must be a response whose Location header points to the operation status.
+// PollOperation calls done with resp then sends the requests created by newRequest, plain GET
+// requests to the status location if newRequest is nil, and calls done with each response until
+// done returns true or an error or ctx is canceled. The requests carry the Accept header of the
+// request that returned resp unless newRequest sets one. The requests are spaced by the delay set
+// in the Retry-After header of the last response if any, by an exponential backoff otherwise. The
+// response bodies are closed once done returns.
+func (c *Client) PollOperation(ctx context.Context, resp *http.Response, newRequest OperationRequestFunc, done func(*http.Response) (bool, error)) error {
+	location := resp.Header.Get("Location")
+	if location == "" {
+		resp.Body.Close()
+		return errors.New("missing operation status location")
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		resp.Body.Close()
+		return err
+	}
+	var accept string
+	if resp.Request != nil {
+		u = resp.Request.URL.ResolveReference(u)
+		accept = resp.Request.Header.Get("Accept")
+	}
+	if newRequest == nil {
+		newRequest = func(ctx context.Context, u *url.URL) (*http.Request, error) {
+			return http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+		}
+	}
+	backoff := OperationPollInterval
+	for {
+		ok, err := done(resp)
+		resp.Body.Close()
+		if ok || err != nil {
+			return err
+		}
+		delay := backoff
+		if d, ok := retryAfter(resp.Header); ok {
+			delay = d
+		} else if backoff *= 2; backoff > MaxOperationPollInterval {
+			backoff = MaxOperationPollInterval
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		req, err := newRequest(ctx, u)
+		if err != nil {
+			return err
+		}
+		if accept != "" && req.Header.Get("Accept") == "" {
+			req.Header.Set("Accept", accept)
+		}
+		if resp, err = c.Do(ctx, req); err != nil {
+			return err
+		}
+	}
+}
+
+// retryAfter returns the delay set in the Retry-After header of h in seconds, capped to
+// MaxOperationPollInterval.
+func retryAfter(h http.Header) (time.Duration, bool) {
+	secs, err := strconv.Atoi(h.Get("Retry-After"))
+	if err != nil || secs < 0 {
+		return 0, false
+	}
+	d := time.Duration(secs) * time.Second
+	if d > MaxOperationPollInterval {
+		d = MaxOperationPollInterval
+	}
+	return d, true
+}
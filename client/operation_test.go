@@ -0,0 +1,113 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"github.com/shogo82148/goa-v1/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PollOperation", func() {
+	var pending int
+	var polls int
+	var server *httptest.Server
+	var c *client.Client
+	var statuses []string
+	var newRequest client.OperationRequestFunc
+	var pollHeader http.Header
+	var err error
+
+	BeforeEach(func() {
+		pending = 2
+		polls = 0
+		statuses = nil
+		newRequest = nil
+		pollHeader = nil
+		client.OperationPollInterval = time.Millisecond
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/jobs":
+				w.Header().Set("Location", "/operations/1")
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusAccepted)
+				fmt.Fprint(w, "running")
+			case "/operations/1":
+				polls++
+				pollHeader = r.Header
+				if polls < pending {
+					fmt.Fprint(w, "running")
+					return
+				}
+				fmt.Fprint(w, "succeeded")
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		c = client.New(client.HTTPClientDoer(http.DefaultClient))
+	})
+
+	JustBeforeEach(func() {
+		req, _ := http.NewRequest("POST", server.URL+"/jobs", nil)
+		req.Header.Set("Accept", "application/json")
+		resp, rerr := c.Do(context.Background(), req)
+		Ω(rerr).ShouldNot(HaveOccurred())
+		err = c.PollOperation(context.Background(), resp, newRequest, func(resp *http.Response) (bool, error) {
+			b, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return false, err
+			}
+			statuses = append(statuses, string(b))
+			return string(b) != "running", nil
+		})
+	})
+
+	AfterEach(func() {
+		server.Close()
+		client.OperationPollInterval = time.Second
+	})
+
+	It("polls the operation status until it is done", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(statuses).Should(Equal([]string{"running", "running", "succeeded"}))
+		Ω(pollHeader.Get("Accept")).Should(Equal("application/json"))
+	})
+
+	Context("with a request factory", func() {
+		BeforeEach(func() {
+			newRequest = func(ctx context.Context, u *url.URL) (*http.Request, error) {
+				req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+				if err != nil {
+					return nil, err
+				}
+				req.Header.Set("Authorization", "Bearer token")
+				return req, nil
+			}
+		})
+
+		It("sends the requests it creates", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(statuses).Should(Equal([]string{"running", "running", "succeeded"}))
+			Ω(pollHeader.Get("Authorization")).Should(Equal("Bearer token"))
+			Ω(pollHeader.Get("Accept")).Should(Equal("application/json"))
+		})
+	})
+
+	Context("with an operation that completed before the first poll", func() {
+		BeforeEach(func() {
+			pending = 0
+		})
+
+		It("polls once", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(statuses).Should(Equal([]string{"running", "succeeded"}))
+		})
+	})
+})
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// WalkPages sends req and calls fn with the response of each page of the paginated collection it
+// targets. The next pages are retrieved by following the "next" link of the RFC 8288 Link response
+// header, the requests reuse the method and headers of req. WalkPages stops and returns the error
+// if fn returns an error or ctx is canceled. The response bodies are closed once fn returns.
+func (c *Client) WalkPages(ctx context.Context, req *http.Request, fn func(*http.Response) error) error {
+	for req != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		resp, err := c.Do(ctx, req)
+		if err != nil {
+			return err
+		}
+		err = fn(resp)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		if req, err = nextPageRequest(ctx, req, resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nextPageRequest returns the request that retrieves the page following the one returned in resp,
+// nil if resp is the last page.
+func nextPageRequest(ctx context.Context, req *http.Request, resp *http.Response) (*http.Request, error) {
+	next := LinkTarget(resp.Header, "next")
+	if next == "" {
+		return nil, nil
+	}
+	u, err := req.URL.Parse(next)
+	if err != nil {
+		return nil, err
+	}
+	if u.String() == req.URL.String() {
+		return nil, nil
+	}
+	nreq, err := http.NewRequestWithContext(ctx, req.Method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	nreq.Header = req.Header.Clone()
+	nreq.Host = req.Host
+	return nreq, nil
+}
+
+// LinkTarget returns the target of the first link with the given relation type listed in the RFC
+// 8288 Link headers of h, the empty string if there is none.
+func LinkTarget(h http.Header, rel string) string {
+	for _, value := range h.Values("Link") {
+		for value != "" {
+			start := strings.IndexByte(value, '<')
+			end := strings.IndexByte(value, '>')
+			if start < 0 || end < start {
+				break
+			}
+			target := value[start+1 : end]
+			value = value[end+1:]
+			params := value
+			if i := strings.Index(value, ",<"); i >= 0 {
+				params = value[:i]
+			} else if i := strings.Index(value, ", <"); i >= 0 {
+				params = value[:i]
+			}
+			value = value[len(params):]
+			for _, param := range strings.Split(params, ";") {
+				k, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || !strings.EqualFold(k, "rel") {
+					continue
+				}
+				for _, r := range strings.Fields(strings.Trim(v, `"`)) {
+					if strings.EqualFold(r, rel) {
+						return target
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/shogo82148/goa-v1/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LinkTarget", func() {
+	It("returns the target of the link with the given relation", func() {
+		h := http.Header{}
+		h.Add("Link", `</bottles?offset=0>; rel="first", </bottles?offset=20>; rel="next"`)
+		Expect(client.LinkTarget(h, "next")).To(Equal("/bottles?offset=20"))
+		Expect(client.LinkTarget(h, "first")).To(Equal("/bottles?offset=0"))
+		Expect(client.LinkTarget(h, "prev")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("WalkPages", func() {
+	var server *httptest.Server
+	var pages []string
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var fn func(*http.Response) error
+	var err error
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			Expect(req.Header.Get("Authorization")).To(Equal("Bearer token"))
+			page := req.URL.Query().Get("cursor")
+			if page != "3" {
+				next := "1"
+				if page != "" {
+					next = fmt.Sprint(page[0] - '0' + 1)
+				}
+				rw.Header().Set("Link", fmt.Sprintf(`</bottles?cursor=%s>; rel="next"`, next))
+			}
+			rw.Write([]byte(page))
+		}))
+		pages = nil
+		ctx, cancel = context.WithCancel(context.Background())
+		fn = func(resp *http.Response) error {
+			b, err := io.ReadAll(resp.Body)
+			pages = append(pages, string(b))
+			return err
+		}
+	})
+
+	AfterEach(func() {
+		cancel()
+		server.Close()
+	})
+
+	JustBeforeEach(func() {
+		req, e := http.NewRequest("GET", server.URL+"/bottles", nil)
+		Expect(e).NotTo(HaveOccurred())
+		req.Header.Set("Authorization", "Bearer token")
+		c := client.New(client.HTTPClientDoer(http.DefaultClient))
+		err = c.WalkPages(ctx, req, fn)
+	})
+
+	It("follows the next links", func() {
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pages).To(Equal([]string{"", "1", "2", "3"}))
+	})
+
+	Context("when the context is canceled", func() {
+		BeforeEach(func() {
+			walk := fn
+			fn = func(resp *http.Response) error {
+				cancel()
+				return walk(resp)
+			}
+		})
+
+		It("stops walking", func() {
+			Expect(err).To(Equal(context.Canceled))
+			Expect(pages).To(HaveLen(1))
+		})
+	})
+})
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// CollectionReader reads the elements of a streamed collection response body one at a time. The
+// body may contain newline delimited JSON ("application/x-ndjson") or a JSON array.
+type CollectionReader struct {
+	body    io.ReadCloser
+	dec     *json.Decoder
+	array   bool
+	started bool
+	done    bool
+}
+
+// NewCollectionReader returns a reader of the elements of the collection streamed in the body of
+// resp. The body encoding is determined by the response Content-Type header.
+func NewCollectionReader(resp *http.Response) *CollectionReader {
+	mt, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return &CollectionReader{
+		body:  resp.Body,
+		dec:   json.NewDecoder(resp.Body),
+		array: mt != "application/x-ndjson",
+	}
+}
+
+// Next decodes the next element of the collection into v. It returns io.EOF once all the elements
+// have been read and io.ErrUnexpectedEOF if the body is truncated.
+func (r *CollectionReader) Next(v interface{}) error {
+	if r.done {
+		return io.EOF
+	}
+	if !r.array {
+		err := r.dec.Decode(v)
+		if err == io.EOF {
+			r.done = true
+			return err
+		}
+		return truncated(err)
+	}
+	if !r.started {
+		if err := r.expectDelim('['); err != nil {
+			return err
+		}
+		r.started = true
+	}
+	if !r.dec.More() {
+		if err := r.expectDelim(']'); err != nil {
+			return err
+		}
+		r.done = true
+		return io.EOF
+	}
+	if err := r.dec.Decode(v); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return truncated(err)
+	}
+	return nil
+}
+
+// Close closes the response body.
+func (r *CollectionReader) Close() error {
+	return r.body.Close()
+}
+
+// expectDelim reads the next JSON token and checks that it is the delimiter d.
+func (r *CollectionReader) expectDelim(d json.Delim) error {
+	t, err := r.dec.Token()
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return truncated(err)
+	}
+	if t != d {
+		return fmt.Errorf("invalid collection, expected %q got %v", d, t)
+	}
+	return nil
+}
+
+// truncated returns io.ErrUnexpectedEOF if err is the syntax error reported by the JSON decoder
+// when the input ends in the middle of a value, err otherwise.
+func truncated(err error) error {
+	var serr *json.SyntaxError
+	if errors.As(err, &serr) && serr.Error() == "unexpected end of JSON input" {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,89 @@
+package client_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/shogo82148/goa-v1/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CollectionReader", func() {
+	var contentType, body string
+	var reader *client.CollectionReader
+
+	readAll := func() ([]int, error) {
+		var ids []int
+		for {
+			var elem struct{ ID int }
+			if err := reader.Next(&elem); err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+				return ids, err
+			}
+			ids = append(ids, elem.ID)
+		}
+	}
+
+	BeforeEach(func() {
+		contentType = "application/vnd.item+json; type=collection"
+		body = "[{\"id\":1}\n,{\"id\":2}\n]"
+	})
+
+	JustBeforeEach(func() {
+		resp := &http.Response{
+			Header: http.Header{"Content-Type": []string{contentType}},
+			Body:   io.NopCloser(strings.NewReader(body)),
+		}
+		reader = client.NewCollectionReader(resp)
+	})
+
+	It("reads the elements of a JSON array", func() {
+		ids, err := readAll()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ids).To(Equal([]int{1, 2}))
+		Expect(reader.Next(&struct{}{})).To(Equal(io.EOF))
+	})
+
+	Context("with an empty array", func() {
+		BeforeEach(func() {
+			body = "[]"
+		})
+
+		It("returns io.EOF", func() {
+			ids, err := readAll()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids).To(BeEmpty())
+		})
+	})
+
+	Context("with a truncated array", func() {
+		BeforeEach(func() {
+			body = "[{\"id\":1}\n,"
+		})
+
+		It("fails", func() {
+			ids, err := readAll()
+			Expect(errors.Is(err, io.ErrUnexpectedEOF)).To(BeTrue(), "got %v", err)
+			Expect(ids).To(Equal([]int{1}))
+		})
+	})
+
+	Context("with newline delimited JSON", func() {
+		BeforeEach(func() {
+			contentType = "application/x-ndjson"
+			body = "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n"
+		})
+
+		It("reads the elements", func() {
+			ids, err := readAll()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids).To(Equal([]int{1, 2, 3}))
+		})
+	})
+})
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/shogo82148/goa-v1"
+)
+
+// WebhookDispatcher sends signed webhook requests and retries the deliveries that fail. The
+// generated webhook functions use it to send webhooks.
+type WebhookDispatcher struct {
+	// Doer is the underlying http client.
+	Doer
+	// Secret is the key used to sign the requests, see goa.SignWebhook.
+	Secret []byte
+	// MaxAttempts is the maximum number of delivery attempts, values lower than 1 mean 1.
+	MaxAttempts int
+	// Backoff is the delay before the first retry, it doubles after each attempt.
+	Backoff time.Duration
+}
+
+// NewWebhookDispatcher returns a dispatcher that sends webhooks with d and signs them with secret.
+// Deliveries are attempted 3 times, waiting 1s then 2s between attempts.
+func NewWebhookDispatcher(d Doer, secret []byte) *WebhookDispatcher {
+	if d == nil {
+		d = HTTPClientDoer(http.DefaultClient)
+	}
+	return &WebhookDispatcher{Doer: d, Secret: secret, MaxAttempts: 3, Backoff: time.Second}
+}
+
+// Dispatch encodes payload in JSON and posts it to url with the given headers and a
+// goa.WebhookSignatureHeader header. Deliveries that fail with a transport error or with a 408,
+// 429 or 5xx response are retried until MaxAttempts is reached or ctx is done, Dispatch returns the
+// error of the last attempt.
+func (d *WebhookDispatcher) Dispatch(ctx context.Context, url string, payload interface{}, header http.Header) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	backoff := d.Backoff
+	for attempt := 1; ; attempt++ {
+		err = d.deliver(ctx, url, body, header)
+		if err == nil || attempt >= d.MaxAttempts {
+			return err
+		}
+		if rerr, ok := err.(*webhookStatusError); ok && !rerr.retryable() {
+			return err
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// deliver makes one delivery attempt.
+func (d *WebhookDispatcher) deliver(ctx context.Context, url string, body []byte, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(goa.WebhookSignatureHeader, goa.SignWebhook(d.Secret, time.Now(), body))
+	resp, err := d.Doer.Do(ctx, req)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &webhookStatusError{url: url, status: resp.Status, code: resp.StatusCode}
+	}
+	return nil
+}
+
+// webhookStatusError is the error returned when a webhook receiver responds with a non 2xx
+// status.
+type webhookStatusError struct {
+	url    string
+	status string
+	code   int
+}
+
+// Error returns the error message.
+func (e *webhookStatusError) Error() string {
+	return fmt.Sprintf("webhook delivery to %s failed with status %s", e.url, e.status)
+}
+
+// retryable returns true if the delivery may succeed if retried.
+func (e *webhookStatusError) retryable() bool {
+	return e.code == http.StatusRequestTimeout || e.code == http.StatusTooManyRequests || e.code >= 500
+}
@@ -0,0 +1,93 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/shogo82148/goa-v1"
+	"github.com/shogo82148/goa-v1/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WebhookDispatcher", func() {
+	var secret = []byte("secret")
+
+	var statuses []int
+	var requests []*http.Request
+	var bodies []string
+	var server *httptest.Server
+	var err error
+
+	BeforeEach(func() {
+		statuses = nil
+		requests = nil
+		bodies = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := goa.VerifyWebhook(r, secret, goa.DefaultWebhookTolerance)
+			requests = append(requests, r)
+			bodies = append(bodies, string(b))
+			status := http.StatusNoContent
+			if len(statuses) > 0 {
+				status, statuses = statuses[0], statuses[1:]
+			}
+			w.WriteHeader(status)
+		}))
+	})
+
+	JustBeforeEach(func() {
+		d := client.NewWebhookDispatcher(nil, secret)
+		d.Backoff = time.Millisecond
+		header := http.Header{"X-Tenant": []string{"acme"}}
+		err = d.Dispatch(context.Background(), server.URL, map[string]int{"id": 1}, header)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("sends a signed JSON request", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(requests).Should(HaveLen(1))
+		Ω(requests[0].Header.Get("Content-Type")).Should(Equal("application/json"))
+		Ω(requests[0].Header.Get("X-Tenant")).Should(Equal("acme"))
+		Ω(bodies[0]).Should(Equal(`{"id":1}`))
+	})
+
+	Context("with a receiver failing temporarily", func() {
+		BeforeEach(func() {
+			statuses = []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}
+		})
+
+		It("retries the delivery", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(bodies).Should(Equal([]string{`{"id":1}`, `{"id":1}`, `{"id":1}`}))
+		})
+	})
+
+	Context("with a receiver always failing", func() {
+		BeforeEach(func() {
+			statuses = []int{500, 500, 500, 500}
+		})
+
+		It("gives up after the maximum number of attempts", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring("500"))
+			Ω(requests).Should(HaveLen(3))
+		})
+	})
+
+	Context("with a receiver rejecting the request", func() {
+		BeforeEach(func() {
+			statuses = []int{http.StatusBadRequest}
+		})
+
+		It("does not retry", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(requests).Should(HaveLen(1))
+		})
+	})
+})
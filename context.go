@@ -17,6 +17,9 @@ var (
 	logKey            = &contextKey{"logger"}
 	errKey            = &contextKey{"error"}
 	securityScopesKey = &contextKey{"security-scope"}
+	apiVersionKey     = &contextKey{"api-version"}
+	parentReqIDKey    = &contextKey{"parent-request-id"}
+	batchKey          = &contextKey{"batch"}
 )
 
 type (
@@ -135,6 +138,16 @@ func WithAction(ctx context.Context, action string) context.Context {
 	return context.WithValue(ctx, actionKey, action)
 }
 
+// WithAPIVersion creates a context with the given API version name.
+func WithAPIVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, apiVersionKey, version)
+}
+
+// WithParentRequestID creates a context with the ID of the batch request that contains the request.
+func WithParentRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, parentReqIDKey, id)
+}
+
 // WithLogger sets the request context logger and returns the resulting new context.
 func WithLogger(ctx context.Context, logger LogAdapter) context.Context {
 	return context.WithValue(ctx, logKey, logger)
@@ -204,6 +217,25 @@ func ContextError(ctx context.Context) error {
 	return nil
 }
 
+// ContextAPIVersion extracts the name of the API version targeted by the request from the given
+// context. It returns the empty string if the API does not declare versions.
+func ContextAPIVersion(ctx context.Context) string {
+	if v := ctx.Value(apiVersionKey); v != nil {
+		return v.(string)
+	}
+	return ""
+}
+
+// ContextParentRequestID extracts the ID of the batch request that contains the request from the
+// given context. It returns the empty string if the request is not part of a batch request or if
+// the batch request ID is unknown.
+func ContextParentRequestID(ctx context.Context) string {
+	if v := ctx.Value(parentReqIDKey); v != nil {
+		return v.(string)
+	}
+	return ""
+}
+
 // SwitchWriter overrides the underlying response writer. It returns the response
 // writer that was previously set.
 func (r *ResponseData) SwitchWriter(rw http.ResponseWriter) http.ResponseWriter {
@@ -0,0 +1,52 @@
+package goa
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Deprecation describes the deprecation of an endpoint.
+type Deprecation struct {
+	// Since is the time at which the endpoint was deprecated, zero if unknown.
+	Since time.Time
+	// Sunset is the time at which the endpoint will stop responding, zero if unknown.
+	Sunset time.Time
+	// Successor is the URL of the endpoint that replaces the deprecated endpoint if any.
+	Successor string
+}
+
+// Deprecated returns a middleware that flags the responses of a deprecated endpoint. The
+// middleware sets the Deprecation (RFC 9745), Sunset (RFC 8594) and Link response headers
+// described by d and counts the requests made to the endpoint using the "goa.deprecated"
+// counter keyed by controller and action names so that it is possible to know when the endpoint
+// stops being used. The generated code uses this middleware to mount deprecated actions.
+func Deprecated(d Deprecation) Middleware {
+	deprecation := "true"
+	if !d.Since.IsZero() {
+		deprecation = "@" + strconv.FormatInt(d.Since.Unix(), 10)
+	}
+	var sunset, link string
+	if !d.Sunset.IsZero() {
+		sunset = d.Sunset.UTC().Format(http.TimeFormat)
+	}
+	if d.Successor != "" {
+		link = fmt.Sprintf("<%s>; rel=\"successor-version\"", d.Successor)
+	}
+	return func(h Handler) Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			IncrCounter([]string{"goa", "deprecated", ContextController(ctx), ContextAction(ctx)}, 1.0)
+			header := rw.Header()
+			header.Set("Deprecation", deprecation)
+			if sunset != "" {
+				header.Set("Sunset", sunset)
+			}
+			if link != "" {
+				header.Add("Link", link)
+			}
+			return h(ctx, rw, req)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package goa_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1"
+)
+
+type countingCollector struct {
+	goa.Collector
+	counters map[string]float32
+}
+
+func (c *countingCollector) IncrCounter(key []string, val float32) {
+	c.counters[strings.Join(key, ".")] += val
+}
+
+var _ = Describe("Deprecated", func() {
+	var deprecation goa.Deprecation
+	var collector *countingCollector
+	var rw *httptest.ResponseRecorder
+	var called bool
+
+	BeforeEach(func() {
+		deprecation = goa.Deprecation{}
+		collector = &countingCollector{Collector: goa.NewNoOpCollector(), counters: make(map[string]float32)}
+		rw = httptest.NewRecorder()
+		called = false
+	})
+
+	JustBeforeEach(func() {
+		prev := goa.GetMetrics()
+		goa.SetMetrics(collector)
+		defer goa.SetMetrics(prev)
+		service := goa.New("test")
+		ctrl := service.NewController("bottle")
+		req, err := http.NewRequest("GET", "/bottles/1", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		ctx := goa.WithAction(ctrl.Context, "show")
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			called = true
+			return nil
+		}
+		Ω(goa.Deprecated(deprecation)(h)(ctx, rw, req)).ShouldNot(HaveOccurred())
+	})
+
+	It("calls the handler and counts the request", func() {
+		Ω(called).Should(BeTrue())
+		Ω(collector.counters).Should(HaveKeyWithValue("goa.deprecated.bottle.show", float32(1)))
+	})
+
+	It("sets the Deprecation header", func() {
+		Ω(rw.Header().Get("Deprecation")).Should(Equal("true"))
+		Ω(rw.Header().Get("Sunset")).Should(BeEmpty())
+		Ω(rw.Header().Get("Link")).Should(BeEmpty())
+	})
+
+	Context("with dates and a successor", func() {
+		BeforeEach(func() {
+			deprecation = goa.Deprecation{
+				Since:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				Sunset:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				Successor: "/v2/bottles",
+			}
+		})
+
+		It("sets the Deprecation, Sunset and Link headers", func() {
+			Ω(rw.Header().Get("Deprecation")).Should(Equal("@1704067200"))
+			Ω(rw.Header().Get("Sunset")).Should(Equal("Wed, 01 Jan 2025 00:00:00 GMT"))
+			Ω(rw.Header().Get("Link")).Should(Equal(`</v2/bottles>; rel="successor-version"`))
+		})
+	})
+})
@@ -98,6 +98,8 @@ var (
 		"application/x-cbor":    "github.com/shogo82148/goa-v1/encoding/cbor",
 		"application/msgpack":   "github.com/shogo82148/goa-v1/encoding/msgpack",
 		"application/x-msgpack": "github.com/shogo82148/goa-v1/encoding/msgpack",
+		HALContentType:          "github.com/shogo82148/goa-v1/encoding/hal",
+		JSONAPIContentType:      "github.com/shogo82148/goa-v1/encoding/jsonapi",
 	}
 
 	// KnownEncoderFunctions contains the list of encoding encoder and decoder functions known
@@ -113,6 +115,8 @@ var (
 		"application/x-cbor":    {"NewEncoder", "NewDecoder"},
 		"application/msgpack":   {"NewEncoder", "NewDecoder"},
 		"application/x-msgpack": {"NewEncoder", "NewDecoder"},
+		HALContentType:          {"NewEncoder", "NewDecoder"},
+		JSONAPIContentType:      {"NewEncoder", "NewDecoder"},
 	}
 
 	// JSONContentTypes list the Content-Type header values that cause goa to encode or decode
@@ -148,6 +152,7 @@ var (
 		},
 		Identifier: ErrorMediaIdentifier,
 		Views:      map[string]*ViewDefinition{"default": errorMediaView},
+		Hypermedia: NoHypermedia,
 	}
 
 	errorMediaType = Object{
@@ -187,6 +192,22 @@ var (
 	}
 )
 
+// GeneratedMetadata is the metadata key set on the definitions that the DSL creates on behalf of
+// the design: the operation actions added by LongRunning, the query string parameters added by
+// Paginated, Selectable, Filterable and Sortable and the media types created by CollectionOf.
+const GeneratedMetadata = "goa:generated"
+
+// IsGenerated returns true if the given metadata marks a definition created by the DSL.
+func IsGenerated(m dslengine.MetadataDefinition) bool {
+	_, ok := m[GeneratedMetadata]
+	return ok
+}
+
+// GeneratedMetadataDefinition returns the metadata of a definition created by the DSL.
+func GeneratedMetadataDefinition() dslengine.MetadataDefinition {
+	return dslengine.MetadataDefinition{GeneratedMetadata: {"true"}}
+}
+
 func init() {
 	goa := "github.com/shogo82148/goa-v1"
 	DefaultEncoders = []*EncodingDefinition{
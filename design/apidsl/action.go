@@ -258,6 +258,15 @@ func Headers(params ...interface{}) {
 				def.Headers = def.Headers.Merge(headers)
 			}
 
+		case *design.WebhookDefinition:
+			headers := &design.AttributeDefinition{}
+			if a := def.Action(); a != nil {
+				headers = newAttribute(a.Parent.MediaType)
+			}
+			if dslengine.Execute(dsl, headers) {
+				def.Headers = def.Headers.Merge(headers)
+			}
+
 		case *design.ResponseDefinition:
 			var h *design.AttributeDefinition
 			switch actual := def.Parent.(type) {
@@ -394,57 +403,81 @@ func payload(isOptional bool, p interface{}, dsls ...func()) {
 		dslengine.ReportError("too many arguments given to Payload")
 		return
 	}
-	if a, ok := actionDefinition(); ok {
-		var att *design.AttributeDefinition
-		var dsl func()
-		switch actual := p.(type) {
-		case func():
-			dsl = actual
-			att = newAttribute(a.Parent.MediaType)
-			att.Type = design.Object{}
-		case *design.AttributeDefinition:
-			att = design.DupAtt(actual)
-		case *design.UserTypeDefinition:
-			if len(dsls) == 0 {
-				a.Payload = actual
-				a.PayloadOptional = isOptional
-				return
-			}
-			att = design.DupAtt(actual.Definition())
-		case *design.MediaTypeDefinition:
-			att = design.DupAtt(actual.AttributeDefinition)
-		case string:
-			ut, ok := design.Design.Types[actual]
-			if !ok {
-				dslengine.ReportError("unknown payload type %s", actual)
-			}
-			att = design.DupAtt(ut.AttributeDefinition)
-		case *design.Array:
-			att = &design.AttributeDefinition{Type: actual}
-		case *design.Hash:
-			att = &design.AttributeDefinition{Type: actual}
-		case design.Primitive:
-			att = &design.AttributeDefinition{Type: actual}
-		default:
-			dslengine.ReportError("invalid Payload argument, must be a type, a media type or a DSL building a type")
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.ActionDefinition:
+		name := fmt.Sprintf("%s%sPayload", camelize(def.Name), camelize(def.Parent.Name))
+		if ut := payloadType(name, def.Parent.MediaType, p, dsls...); ut != nil {
+			def.Payload = ut
+			def.PayloadOptional = isOptional
+		}
+	case *design.WebhookDefinition:
+		if isOptional {
+			dslengine.ReportError("webhook payloads cannot be optional")
 			return
 		}
-		if len(dsls) == 1 {
-			if dsl != nil {
-				dslengine.ReportError("invalid arguments in Payload call, must be (type), (dsl) or (type, dsl)")
-			}
-			dsl = dsls[0]
+		name := camelize(def.Name) + "WebhookPayload"
+		var baseMT string
+		if a := def.Action(); a != nil {
+			name = camelize(a.Name) + camelize(a.Parent.Name) + name
+			baseMT = a.Parent.MediaType
 		}
-		if dsl != nil {
-			dslengine.Execute(dsl, att)
+		if ut := payloadType(name, baseMT, p, dsls...); ut != nil {
+			def.Payload = ut
 		}
-		rn := camelize(a.Parent.Name)
-		an := camelize(a.Name)
-		a.Payload = &design.UserTypeDefinition{
-			AttributeDefinition: att,
-			TypeName:            fmt.Sprintf("%s%sPayload", an, rn),
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
+
+// payloadType returns the payload type described by the arguments of Payload. name is the name of
+// the type built when the payload is described inline, baseMT the identifier of the media type
+// used as base type.
+func payloadType(name, baseMT string, p interface{}, dsls ...func()) *design.UserTypeDefinition {
+	var att *design.AttributeDefinition
+	var dsl func()
+	switch actual := p.(type) {
+	case func():
+		dsl = actual
+		att = newAttribute(baseMT)
+		att.Type = design.Object{}
+	case *design.AttributeDefinition:
+		att = design.DupAtt(actual)
+	case *design.UserTypeDefinition:
+		if len(dsls) == 0 {
+			return actual
+		}
+		att = design.DupAtt(actual.Definition())
+	case *design.MediaTypeDefinition:
+		att = design.DupAtt(actual.AttributeDefinition)
+	case string:
+		ut, ok := design.Design.Types[actual]
+		if !ok {
+			dslengine.ReportError("unknown payload type %s", actual)
+			return nil
 		}
-		a.PayloadOptional = isOptional
+		att = design.DupAtt(ut.AttributeDefinition)
+	case *design.Array:
+		att = &design.AttributeDefinition{Type: actual}
+	case *design.Hash:
+		att = &design.AttributeDefinition{Type: actual}
+	case design.Primitive:
+		att = &design.AttributeDefinition{Type: actual}
+	default:
+		dslengine.ReportError("invalid Payload argument, must be a type, a media type or a DSL building a type")
+		return nil
+	}
+	if len(dsls) == 1 {
+		if dsl != nil {
+			dslengine.ReportError("invalid arguments in Payload call, must be (type), (dsl) or (type, dsl)")
+		}
+		dsl = dsls[0]
+	}
+	if dsl != nil {
+		dslengine.Execute(dsl, att)
+	}
+	return &design.UserTypeDefinition{
+		AttributeDefinition: att,
+		TypeName:            name,
 	}
 }
 
@@ -92,6 +92,541 @@ var _ = Describe("Action", func() {
 				))
 			})
 		})
+
+		Context("with a deprecation", func() {
+			var since, sunset string
+
+			BeforeEach(func() {
+				since = "2024-01-01"
+				sunset = "2025-01-01"
+			})
+
+			JustBeforeEach(func() {
+				dslengine.Reset()
+				apidsl.Resource("res", func() {
+					apidsl.Action(name, func() {
+						apidsl.Deprecated(since, sunset, "/v2/foo")
+						apidsl.Routing(route)
+						apidsl.Params(func() {
+							apidsl.Param("id", design.Integer)
+							apidsl.Param("bar", design.String, func() {
+								apidsl.Deprecated(since, "", "baz")
+							})
+						})
+					})
+				})
+				dslengine.Run()
+				action = design.Design.Resources["res"].Actions[name]
+			})
+
+			It("sets the action and parameter deprecations", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(action.Deprecation).Should(Equal(&design.DeprecationDefinition{Since: since, Sunset: sunset, Replacement: "/v2/foo"}))
+				Ω(action.Deprecation.SuccessorURL()).Should(Equal("/v2/foo"))
+				bar := action.Params.Type.ToObject()["bar"]
+				Ω(bar.Deprecation).ShouldNot(BeNil())
+				Ω(bar.Deprecation.SuccessorURL()).Should(BeEmpty())
+				Ω(bar.Deprecation.Message()).Should(Equal("deprecated since 2024-01-01, use baz instead"))
+			})
+
+			Context("with a sunset date before the deprecation date", func() {
+				BeforeEach(func() {
+					sunset = "2023-01-01"
+				})
+
+				It("produces an invalid action", func() {
+					Ω(action.Validate()).Should(HaveOccurred())
+				})
+			})
+
+			Context("with an invalid date", func() {
+				BeforeEach(func() {
+					since = "yesterday"
+				})
+
+				It("produces an invalid action", func() {
+					Ω(action.Validate()).Should(HaveOccurred())
+				})
+			})
+		})
+
+		Context("with server-sent events", func() {
+			var eventType string
+
+			BeforeEach(func() {
+				eventType = "application/vnd.event"
+			})
+
+			JustBeforeEach(func() {
+				dslengine.Reset()
+				apidsl.MediaType("application/vnd.event", func() {
+					apidsl.Attributes(func() {
+						apidsl.Attribute("count", design.Integer)
+					})
+					apidsl.View("default", func() {
+						apidsl.Attribute("count")
+					})
+				})
+				apidsl.Resource("res", func() {
+					apidsl.Action(name, func() {
+						apidsl.Routing(route)
+						apidsl.ServerSentEvents(eventType, func() {
+							apidsl.KeepAlive(30)
+						})
+					})
+				})
+				dslengine.Run()
+				action = design.Design.Resources["res"].Actions[name]
+			})
+
+			It("sets the event stream", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(action.Events).ShouldNot(BeNil())
+				Ω(action.Events.KeepAlive).Should(Equal(30))
+				Ω(action.Events.EventType()).Should(Equal(design.Design.MediaTypes["application/vnd.event"]))
+			})
+
+			Context("with an unknown media type", func() {
+				BeforeEach(func() {
+					eventType = "application/vnd.unknown"
+				})
+
+				It("produces an invalid action", func() {
+					Ω(dslengine.Errors).Should(HaveOccurred())
+					Ω(action.Validate()).Should(HaveOccurred())
+				})
+			})
+		})
+
+		Context("with messages", func() {
+			var scheme string
+
+			BeforeEach(func() {
+				scheme = "ws"
+			})
+
+			JustBeforeEach(func() {
+				dslengine.Reset()
+				msg := apidsl.Type("message", func() {
+					apidsl.Attribute("text", design.String)
+				})
+				apidsl.Resource("res", func() {
+					apidsl.Action(name, func() {
+						apidsl.Scheme(scheme)
+						apidsl.Routing(route)
+						apidsl.Messages(msg, msg, func() {
+							apidsl.PingInterval(10)
+						})
+					})
+				})
+				dslengine.Run()
+				action = design.Design.Resources["res"].Actions[name]
+			})
+
+			It("sets the message types", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(action.Messages).ShouldNot(BeNil())
+				Ω(action.Messages.PingInterval).Should(Equal(10))
+				Ω(action.Messages.InboundType()).Should(Equal(design.Design.Types["message"]))
+				Ω(action.Messages.OutboundType()).Should(Equal(design.Design.Types["message"]))
+			})
+
+			Context("on an action that is not a websocket action", func() {
+				BeforeEach(func() {
+					scheme = "http"
+				})
+
+				It("produces an invalid action", func() {
+					Ω(dslengine.Errors).Should(HaveOccurred())
+					Ω(action.Validate()).Should(HaveOccurred())
+				})
+			})
+		})
+
+		Context("with webhooks", func() {
+			var webhookName string
+			var payload interface{}
+
+			BeforeEach(func() {
+				webhookName = "res.failed"
+				payload = func() {
+					apidsl.Attribute("error", design.String)
+				}
+			})
+
+			JustBeforeEach(func() {
+				dslengine.Reset()
+				apidsl.Resource("res", func() {
+					apidsl.Action(name, func() {
+						apidsl.Routing(route)
+						apidsl.Webhook("res.created", func() {
+							apidsl.Payload(func() {
+								apidsl.Attribute("id", design.Integer)
+							})
+						})
+						apidsl.Webhook(webhookName, func() {
+							apidsl.Payload(payload)
+						})
+					})
+				})
+				dslengine.Run()
+				action = design.Design.Resources["res"].Actions[name]
+			})
+
+			It("sets the action webhooks", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(action.Webhooks).Should(HaveLen(2))
+				w := action.Webhooks["res.created"]
+				Ω(w.Action()).Should(BeIdenticalTo(action))
+				Ω(w.Payload.TypeName).Should(Equal("FooResResCreatedWebhookPayload"))
+			})
+
+			Context("with a payload that is not an object", func() {
+				BeforeEach(func() {
+					payload = design.String
+				})
+
+				It("produces an invalid action", func() {
+					Ω(dslengine.Errors).Should(HaveOccurred())
+					Ω(action.Validate()).Should(HaveOccurred())
+				})
+			})
+
+			Context("with the same name", func() {
+				BeforeEach(func() {
+					webhookName = "res.created"
+				})
+
+				It("produces an error", func() {
+					Ω(dslengine.Errors).Should(HaveOccurred())
+				})
+			})
+		})
+
+		Context("with a long-running operation", func() {
+			var retryAfter int
+
+			BeforeEach(func() {
+				retryAfter = 5
+			})
+
+			JustBeforeEach(func() {
+				dslengine.Reset()
+				apidsl.Resource("res", func() {
+					apidsl.Action(name, func() {
+						apidsl.Routing(route)
+						apidsl.LongRunning(retryAfter)
+					})
+					apidsl.Action("other", func() {
+						apidsl.Routing(apidsl.POST("/other"))
+						apidsl.LongRunning(1)
+					})
+				})
+				dslengine.Run()
+				action = design.Design.Resources["res"].Actions[name]
+			})
+
+			It("defines the accepted response and the operation actions", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(action.LongRunning).ShouldNot(BeNil())
+				Ω(action.LongRunning.RetryAfter).Should(Equal(5))
+				Ω(action.Responses).Should(HaveKey(design.Accepted))
+				accepted := action.Responses[design.Accepted]
+				Ω(accepted.MediaType).Should(Equal(design.OperationMediaIdentifier))
+				Ω(accepted.Headers.Type.ToObject()).Should(HaveKey("Location"))
+				Ω(accepted.Headers.Type.ToObject()).Should(HaveKey("Retry-After"))
+				Ω(design.Design.MediaTypeWithIdentifier(design.OperationMediaIdentifier)).Should(Equal(design.OperationMedia))
+
+				show := action.LongRunning.StatusAction()
+				Ω(show).ShouldNot(BeNil())
+				Ω(show.Routes[0].Verb).Should(Equal("GET"))
+				Ω(show.Routes[0].FullPath()).Should(Equal("/operations/:operationID"))
+				Ω(show.Responses).Should(HaveKey(design.OK))
+				cancel := action.LongRunning.CancelAction()
+				Ω(cancel).ShouldNot(BeNil())
+				Ω(cancel.Routes[0].Verb).Should(Equal("DELETE"))
+				Ω(design.Design.Resources["res"].Actions).Should(HaveLen(4))
+			})
+
+			Context("with a negative retry delay", func() {
+				BeforeEach(func() {
+					retryAfter = -1
+				})
+
+				It("produces an invalid action", func() {
+					Ω(dslengine.Errors).Should(HaveOccurred())
+					Ω(action.Validate()).Should(HaveOccurred())
+				})
+			})
+		})
+
+		Context("with pagination", func() {
+			var style design.PaginationStyle
+			var maxLimit int
+
+			BeforeEach(func() {
+				style = design.OffsetPagination
+				maxLimit = 100
+			})
+
+			JustBeforeEach(func() {
+				dslengine.Reset()
+				apidsl.Resource("res", func() {
+					apidsl.Action(name, func() {
+						apidsl.Routing(route)
+						apidsl.Paginated(style, 20, maxLimit)
+						apidsl.Params(func() {
+							apidsl.Param("id", design.Integer)
+						})
+					})
+				})
+				dslengine.Run()
+				action = design.Design.Resources["res"].Actions[name]
+			})
+
+			It("adds the pagination params", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(action.Pagination).Should(Equal(&design.PaginationDefinition{Style: style, DefaultLimit: 20, MaxLimit: 100}))
+				params := action.Params.Type.ToObject()
+				Ω(params).Should(HaveKey("id"))
+				Ω(params).Should(HaveKey(design.OffsetParam))
+				Ω(params).ShouldNot(HaveKey(design.CursorParam))
+				limit := params[design.LimitParam]
+				Ω(limit).ShouldNot(BeNil())
+				Ω(limit.DefaultValue).Should(Equal(20))
+				Ω(*limit.Validation.Maximum).Should(Equal(float64(100)))
+			})
+
+			Context("using cursors", func() {
+				BeforeEach(func() {
+					style = design.CursorPagination
+				})
+
+				It("adds the cursor param", func() {
+					Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+					params := action.Params.Type.ToObject()
+					Ω(params).Should(HaveKey(design.CursorParam))
+					Ω(params).ShouldNot(HaveKey(design.OffsetParam))
+				})
+			})
+
+			Context("with a maximum limit lower than the default", func() {
+				BeforeEach(func() {
+					maxLimit = 10
+				})
+
+				It("produces an invalid action", func() {
+					Ω(action.Validate()).Should(HaveOccurred())
+				})
+			})
+
+			Context("with an unknown style", func() {
+				BeforeEach(func() {
+					style = "page"
+				})
+
+				It("produces an invalid action", func() {
+					Ω(action.Validate()).Should(HaveOccurred())
+				})
+			})
+		})
+
+		Context("with filters and sorts", func() {
+			var filters, sorts []string
+
+			BeforeEach(func() {
+				filters = []string{"name", "rating", "color"}
+				sorts = []string{"name"}
+			})
+
+			JustBeforeEach(func() {
+				dslengine.Reset()
+				mt := apidsl.MediaType("application/vnd.bottle", func() {
+					apidsl.Attributes(func() {
+						apidsl.Attribute("name", design.String)
+						apidsl.Attribute("rating", design.Integer)
+						apidsl.Attribute("color", design.String, func() {
+							apidsl.Enum("red", "white")
+						})
+						apidsl.Attribute("tags", apidsl.ArrayOf(design.String))
+						apidsl.Attribute("price", design.Number)
+						apidsl.Attribute("bottled", design.Date)
+						apidsl.Attribute("updated_at", design.DateTime)
+					})
+					apidsl.View("default", func() {
+						apidsl.Attribute("name")
+						apidsl.Attribute("rating")
+						apidsl.Attribute("color")
+						apidsl.Attribute("tags")
+						apidsl.Attribute("price")
+						apidsl.Attribute("bottled")
+						apidsl.Attribute("updated_at")
+					})
+				})
+				apidsl.Resource("res", func() {
+					apidsl.Action(name, func() {
+						apidsl.Routing(route)
+						apidsl.Filterable(filters...)
+						apidsl.Sortable(sorts...)
+						apidsl.Response(design.OK, apidsl.CollectionOf(mt))
+					})
+				})
+				dslengine.Run()
+				action = design.Design.Resources["res"].Actions[name]
+			})
+
+			It("adds the filter and sort params", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				params := action.Params.Type.ToObject()
+				for _, n := range []string{"name", "name_in", "name_prefix", "rating", "rating_in", "rating_min", "rating_max", "color", "color_in", design.SortParam, design.OrderParam} {
+					Ω(params).Should(HaveKey(n))
+				}
+				Ω(params).ShouldNot(HaveKey("color_prefix"))
+				Ω(params).ShouldNot(HaveKey("name_min"))
+				Ω(params["rating_in"].Type.IsArray()).Should(BeTrue())
+				Ω(params["color_in"].Type.ToArray().ElemType.Validation.Values).Should(Equal([]interface{}{"red", "white"}))
+				Ω(params[design.SortParam].Validation.Values).Should(Equal([]interface{}{"name"}))
+				Ω(params[design.OrderParam].DefaultValue).Should(Equal("asc"))
+				Ω(action.QueryParams.Type.ToObject()).Should(HaveKey("rating_min"))
+			})
+
+			Context("with ordered attributes", func() {
+				BeforeEach(func() {
+					filters = []string{"rating", "price", "bottled", "updated_at"}
+				})
+
+				It("adds the range filter params", func() {
+					Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+					params := action.Params.Type.ToObject()
+					for n, t := range map[string]design.DataType{"rating": design.Integer, "price": design.Number, "bottled": design.Date, "updated_at": design.DateTime} {
+						Ω(params).Should(HaveKey(n))
+						Ω(params).Should(HaveKey(n + "_min"))
+						Ω(params).Should(HaveKey(n + "_max"))
+						Ω(params[n+"_min"].Type).Should(Equal(t))
+						Ω(params[n+"_max"].Type).Should(Equal(t))
+						Ω(params[n+"_min"].Description).Should(ContainSubstring("greater than or equal to"))
+						Ω(params[n+"_max"].Description).Should(ContainSubstring("less than or equal to"))
+					}
+				})
+			})
+
+			Context("with an unknown attribute", func() {
+				BeforeEach(func() {
+					filters = []string{"vintage"}
+				})
+
+				It("produces an invalid action", func() {
+					Ω(dslengine.Errors).Should(HaveOccurred())
+				})
+			})
+
+			Context("with a non primitive attribute", func() {
+				BeforeEach(func() {
+					sorts = []string{"tags"}
+				})
+
+				It("produces an invalid action", func() {
+					Ω(dslengine.Errors).Should(HaveOccurred())
+				})
+			})
+		})
+
+		Context("with a selectable response", func() {
+			var selectors []design.ResponseSelector
+			var fullView func()
+
+			BeforeEach(func() {
+				selectors = nil
+				fullView = func() {
+					apidsl.Attribute("id")
+					apidsl.Attribute("owner")
+				}
+			})
+
+			JustBeforeEach(func() {
+				dslengine.Reset()
+				design.ProjectedMediaTypes = make(design.MediaTypeRoot)
+				owner := apidsl.MediaType("application/vnd.owner", func() {
+					apidsl.Attributes(func() {
+						apidsl.Attribute("id", design.Integer)
+						apidsl.Attribute("name", design.String)
+					})
+					apidsl.View("default", func() {
+						apidsl.Attribute("id")
+						apidsl.Attribute("name")
+					})
+					apidsl.View("tiny", func() {
+						apidsl.Attribute("id")
+					})
+				})
+				mt := apidsl.MediaType("application/vnd.shelf", func() {
+					apidsl.Attributes(func() {
+						apidsl.Attribute("id", design.Integer)
+						apidsl.Attribute("name", design.String)
+						apidsl.Attribute("owner", owner)
+					})
+					apidsl.View("default", fullView)
+					apidsl.View("tiny", func() {
+						apidsl.Attribute("id")
+						apidsl.Attribute("owner", func() {
+							apidsl.View("tiny")
+						})
+					})
+				})
+				apidsl.Resource("res", func() {
+					apidsl.Action(name, func() {
+						apidsl.Routing(route)
+						apidsl.Selectable(selectors...)
+						apidsl.Response(design.OK, mt)
+					})
+				})
+				dslengine.Run()
+				action = design.Design.Resources["res"].Actions[name]
+			})
+
+			It("adds the view and fields params", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(action.Selection).ShouldNot(BeNil())
+				params := action.Params.Type.ToObject()
+				Ω(params).Should(HaveKey(design.FieldsParam))
+				Ω(params).Should(HaveKey(design.ViewParam))
+				view := params[design.ViewParam]
+				Ω(view.DefaultValue).Should(Equal(design.DefaultView))
+				Ω(view.Validation.Values).Should(Equal([]interface{}{"default", "tiny"}))
+			})
+
+			It("computes the attributes rendered by each view", func() {
+				Ω(action.Selection.ViewFields("default")).Should(Equal([]string{"id", "owner", "owner.id", "owner.name"}))
+				Ω(action.Selection.ViewFields("tiny")).Should(Equal([]string{"id", "owner", "owner.id"}))
+			})
+
+			Context("with only the fields selector", func() {
+				BeforeEach(func() {
+					selectors = []design.ResponseSelector{design.FieldsSelector}
+				})
+
+				It("only adds the fields param", func() {
+					Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+					params := action.Params.Type.ToObject()
+					Ω(params).Should(HaveKey(design.FieldsParam))
+					Ω(params).ShouldNot(HaveKey(design.ViewParam))
+				})
+			})
+
+			Context("with a view rendering attributes missing from the default view", func() {
+				BeforeEach(func() {
+					fullView = func() {
+						apidsl.Attribute("id")
+						apidsl.Attribute("name")
+					}
+				})
+
+				It("produces an invalid action", func() {
+					Ω(dslengine.Errors).Should(HaveOccurred())
+					Ω(action.Validate()).Should(HaveOccurred())
+				})
+			})
+		})
 	})
 
 	Context("with a string payload", func() {
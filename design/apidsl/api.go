@@ -96,7 +96,7 @@ func Version(ver string) {
 }
 
 // Description sets the definition description.
-// Description can be called inside API, Resource, Action, MediaType, Attribute, Response or ResponseTemplate
+// Description can be called inside API, Resource, Action, MediaType, Attribute, Response, ResponseTemplate or Webhook
 func Description(lines ...string) {
 	d := strings.Join(lines, "\n")
 	switch def := dslengine.CurrentDefinition().(type) {
@@ -118,6 +118,10 @@ func Description(lines ...string) {
 		def.Description = d
 	case *design.SecuritySchemeDefinition:
 		def.Description = d
+	case *design.APIVersionDefinition:
+		def.Description = d
+	case *design.WebhookDefinition:
+		def.Description = d
 	default:
 		dslengine.IncompatibleDSL()
 	}
@@ -125,7 +129,8 @@ func Description(lines ...string) {
 
 // BasePath defines the API base path, i.e. the common path prefix to all the API actions.
 // The path may define wildcards (see Routing for a description of the wildcard syntax).
-// The corresponding parameters must be described using Params.
+// The corresponding parameters must be described using Params. When used in APIVersion BasePath
+// defines the path prefix that selects the version, it is inserted after the API base path.
 func BasePath(val string) {
 	switch def := dslengine.CurrentDefinition().(type) {
 	case *design.APIDefinition:
@@ -143,6 +148,8 @@ func BasePath(val string) {
 				}
 			}
 		}
+	case *design.APIVersionDefinition:
+		def.BasePath = val
 	default:
 		dslengine.IncompatibleDSL()
 	}
@@ -407,19 +414,76 @@ func buildEncodingDefinition(encoding bool, args ...interface{}) *design.Encodin
 }
 
 // Package sets the Go package path to the encoder or decoder. It must be used inside a
-// Consumes or Produces DSL.
+// Consumes, Produces or GoType DSL. When used in a GoType DSL Package sets the import path of
+// the package that defines the Go type.
 func Package(path string) {
-	if e, ok := encodingDefinition(); ok {
-		e.PackagePath = path
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.EncodingDefinition:
+		def.PackagePath = path
+	case *design.GoTypeMappingDefinition:
+		def.PackagePath = path
+	default:
+		dslengine.IncompatibleDSL()
 	}
 }
 
 // Function sets the Go function name used to instantiate the encoder or decoder. Defaults to
-// NewEncoder / NewDecoder.
+// NewEncoder / NewDecoder. When used in a GoType DSL Function sets the name of the function used
+// to parse string values (path, query string and header values) into the Go type. The function
+// must have the signature func(string) (T, error) where T is the Go type. Defaults to
+// goa.ParseText which requires the Go type to implement encoding.TextUnmarshaler.
 func Function(fn string) {
-	if e, ok := encodingDefinition(); ok {
-		e.Function = fn
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.EncodingDefinition:
+		def.Function = fn
+	case *design.GoTypeMappingDefinition:
+		def.Function = fn
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
+
+// GoType maps a primitive or user type to an existing Go type. The generated code uses the Go type
+// wherever it would otherwise use the Go type generated for the design type (the Go native type
+// for primitives or the generated struct for user types). Validations are not generated for
+// attributes of mapped types, the Go type is responsible for enforcing them when decoding. The
+// Go type must be given qualified with the package name, the import path of the package is set
+// with Package:
+//
+//	GoType(UUID, "guuid.UUID", func() {
+//		Package("github.com/google/uuid")
+//		Function("guuid.Parse")
+//	})
+//
+// GoType must appear in an API definition.
+func GoType(t design.DataType, goType string, dsl ...func()) {
+	a, ok := apiDefinition()
+	if !ok {
+		return
+	}
+	switch t.(type) {
+	case design.Primitive, *design.UserTypeDefinition:
+	default:
+		dslengine.ReportError("GoType only supports primitive and user types")
+		return
+	}
+	if goType == "" {
+		dslengine.ReportError("missing Go type name in call to GoType")
+		return
+	}
+	if len(dsl) > 1 {
+		dslengine.ReportError("too many arguments given to GoType")
+		return
+	}
+	if m := a.GoTypeMapping(t); m != nil {
+		dslengine.ReportError("multiple Go type mappings for %s", m.Context())
+		return
+	}
+	m := &design.GoTypeMappingDefinition{Type: t, GoType: goType}
+	if len(dsl) == 1 {
+		dslengine.Execute(dsl[0], m)
 	}
+	a.GoTypeMappings = append(a.GoTypeMappings, m)
 }
 
 // ResponseTemplate defines a response template that action definitions can use to describe their
@@ -56,6 +56,62 @@ var _ = Describe("API", func() {
 		})
 	})
 
+	Context("with a type mapped to multiple Go types", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				apidsl.GoType(design.DateTime, "civil.DateTime")
+				apidsl.GoType(design.DateTime, "time.Time")
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with versions selected by different headers", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				apidsl.APIVersion("v1", func() { apidsl.VersionHeader("X-Api-Version") })
+				apidsl.APIVersion("v2", func() { apidsl.VersionHeader("Api-Version") })
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a version without selector", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				apidsl.APIVersion("v1")
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a webhook without payload", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				apidsl.Webhook("order.created", func() {
+					apidsl.Description("Sent when an order is created")
+				})
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(design.Design.Validate()).Should(HaveOccurred())
+		})
+	})
+
 	Context("with valid DSL", func() {
 		JustBeforeEach(func() {
 			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
@@ -200,6 +256,80 @@ var _ = Describe("API", func() {
 			})
 		})
 
+		Context("with GoType", func() {
+			const goType = "civil.DateTime"
+			const pkgPath = "cloud.google.com/go/civil"
+			const fn = "civil.ParseDateTime"
+
+			BeforeEach(func() {
+				dsl = func() {
+					apidsl.GoType(design.DateTime, goType, func() {
+						apidsl.Package(pkgPath)
+						apidsl.Function(fn)
+					})
+				}
+			})
+
+			It("sets the API Go type mappings", func() {
+				Ω(design.Design.GoTypeMappings).Should(HaveLen(1))
+				m := design.Design.GoTypeMappings[0]
+				Ω(m.Type).Should(Equal(design.DateTime))
+				Ω(m.GoType).Should(Equal(goType))
+				Ω(m.PackagePath).Should(Equal(pkgPath))
+				Ω(m.Function).Should(Equal(fn))
+				Ω(m.PackageName()).Should(Equal("civil"))
+				Ω(design.Design.GoTypeMapping(design.DateTime)).Should(Equal(m))
+				Ω(design.Design.GoTypeMapping(design.UUID)).Should(BeNil())
+			})
+		})
+
+		Context("with APIVersion", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					apidsl.APIVersion("v1", func() {
+						apidsl.Description("first")
+						apidsl.BasePath("/v1")
+					})
+					apidsl.APIVersion("v2", func() {
+						apidsl.VersionParam("version")
+					})
+				}
+			})
+
+			It("sets the API versions", func() {
+				Ω(design.Design.APIVersions).Should(HaveLen(2))
+				v1 := design.Design.VersionDefinition("v1")
+				Ω(v1).Should(Equal(&design.APIVersionDefinition{Name: "v1", Description: "first", BasePath: "/v1"}))
+				v2 := design.Design.VersionDefinition("v2")
+				Ω(v2).Should(Equal(&design.APIVersionDefinition{Name: "v2", MediaTypeParam: "version"}))
+			})
+
+			Context("and resources restricted to versions", func() {
+				BeforeEach(func() {
+					apidsl.Resource("bottle", func() {
+						apidsl.Versions("v2")
+						apidsl.Action("show", func() {
+							apidsl.Routing(apidsl.GET("/:id"))
+						})
+						apidsl.Action("list", func() {
+							apidsl.Versions("v1", "v2")
+							apidsl.Routing(apidsl.GET(""))
+						})
+					})
+				})
+
+				It("sets the versions", func() {
+					Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+					Ω(design.Design.Validate()).ShouldNot(HaveOccurred())
+					res := design.Design.Resources["bottle"]
+					Ω(res.Versions).Should(Equal([]string{"v2"}))
+					Ω(res.Actions["show"].SupportsVersion("v1")).Should(BeFalse())
+					Ω(res.Actions["show"].SupportsVersion("v2")).Should(BeTrue())
+					Ω(res.Actions["list"].SupportsVersion("v1")).Should(BeTrue())
+				})
+			})
+		})
+
 		Context("with a BasePath", func() {
 			const basePath = "basePath"
 
@@ -340,6 +470,35 @@ var _ = Describe("API", func() {
 			})
 		})
 
+		Context("with a Webhook", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					apidsl.Webhook("order.created", func() {
+						apidsl.Description("Sent when an order is created")
+						apidsl.Payload(func() {
+							apidsl.Attribute("id", design.Integer)
+							apidsl.Required("id")
+						})
+						apidsl.Headers(func() {
+							apidsl.Header("X-Tenant")
+						})
+					})
+				}
+			})
+
+			It("sets the API webhooks", func() {
+				Ω(design.Design.Webhooks).Should(HaveKey("order.created"))
+				w := design.Design.Webhooks["order.created"]
+				Ω(w.Description).Should(Equal("Sent when an order is created"))
+				Ω(w.Action()).Should(BeNil())
+				Ω(w.Payload).ShouldNot(BeNil())
+				Ω(w.Payload.TypeName).Should(Equal("OrderCreatedWebhookPayload"))
+				Ω(w.Payload.IsRequired("id")).Should(BeTrue())
+				Ω(w.Headers).ShouldNot(BeNil())
+				Ω(w.Headers.Type.ToObject()).Should(HaveKey("X-Tenant"))
+			})
+		})
+
 		Context("with Traits", func() {
 			const traitName = "Authenticated"
 
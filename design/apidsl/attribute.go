@@ -19,7 +19,8 @@ import (
 // attributes may include other attributes. At the basic level an attribute has a name,
 // a type and optionally a default value and validation rules. The type of an attribute can be one of:
 //
-// * The primitive types Boolean, Integer, Number, DateTime, UUID or String.
+// * The primitive types Boolean, Integer, Number, DateTime, UUID, Date, TimeOfDay, Duration,
+// Decimal or String.
 //
 // * A type defined via the Type function.
 //
@@ -346,7 +347,8 @@ func NoExample() {
 
 // Enum can be used in: Attribute, Header, Param, HashOf, ArrayOf
 //
-// Enum adds a "enum" validation to the attribute.
+// Enum adds a "enum" validation to the attribute. Enum cannot be used on attributes of type Date,
+// TimeOfDay, Duration or Decimal.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor76.
 func Enum(val ...interface{}) {
 	if a, ok := attributeDefinition(); ok {
@@ -610,6 +612,14 @@ func qualifiedTypeName(t design.DataType) string {
 	switch t.Kind() {
 	case design.DateTimeKind:
 		return "datetime"
+	case design.DateKind:
+		return "date"
+	case design.TimeOfDayKind:
+		return "timeofday"
+	case design.DurationKind:
+		return "duration"
+	case design.DecimalKind:
+		return "decimal"
 	case design.ArrayKind:
 		return fmt.Sprintf("%s<%s>", t.Name(), qualifiedTypeName(t.ToArray().ElemType.Type))
 	case design.HashKind:
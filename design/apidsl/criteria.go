@@ -0,0 +1,60 @@
+package apidsl
+
+import (
+	"github.com/shogo82148/goa-v1/design"
+)
+
+// Filterable declares that requests may filter the collection returned by the action on the given
+// attributes of its items. The action "OK" response must be a collection media type.
+//
+// Filterable adds query string parameters derived from the type of each attribute: the attribute
+// name filters the items whose attribute is equal to the value, the "_in" suffix filters the items
+// whose attribute is equal to one of the values for strings, UUIDs, numbers and enums, the "_min"
+// and "_max" suffixes filter the items whose attribute is within a range for numbers, dates and
+// date times and the "_prefix" suffix filters the items whose attribute starts with the value for
+// strings. The generated action context exposes a Criteria method that returns the filters set by
+// the request and the generated client exposes a builder for these parameters.
+//
+// Filterable must appear in Action. Example:
+//
+//	Action("list", func() {
+//		Routing(GET(""))
+//		Filterable("name", "rating", "vintage")
+//		Response(OK, CollectionOf(BottleMedia))
+//	})
+func Filterable(attributes ...string) {
+	if c, ok := criteriaDefinition(); ok {
+		c.Filters = append(c.Filters, attributes...)
+	}
+}
+
+// Sortable declares that requests may sort the collection returned by the action by one of the
+// given attributes of its items. The action "OK" response must be a collection media type.
+//
+// Sortable adds the "sort" query string parameter whose value is the name of one of the
+// attributes and the "order" query string parameter whose value is "asc" (the default) or "desc".
+//
+// Sortable must appear in Action. Example:
+//
+//	Action("list", func() {
+//		Routing(GET(""))
+//		Sortable("name", "rating")
+//		Response(OK, CollectionOf(BottleMedia))
+//	})
+func Sortable(attributes ...string) {
+	if c, ok := criteriaDefinition(); ok {
+		c.Sorts = append(c.Sorts, attributes...)
+	}
+}
+
+// criteriaDefinition returns the criteria of the action being defined, creating it if needed.
+func criteriaDefinition() (*design.CriteriaDefinition, bool) {
+	a, ok := actionDefinition()
+	if !ok {
+		return nil, false
+	}
+	if a.Criteria == nil {
+		a.Criteria = &design.CriteriaDefinition{Parent: a}
+	}
+	return a.Criteria, true
+}
@@ -22,16 +22,6 @@ func apiDefinition() (*design.APIDefinition, bool) {
 	return a, ok
 }
 
-// encodingDefinition returns true and current context if it is an EncodingDefinition,
-// nil and false otherwise.
-func encodingDefinition() (*design.EncodingDefinition, bool) {
-	e, ok := dslengine.CurrentDefinition().(*design.EncodingDefinition)
-	if !ok {
-		dslengine.IncompatibleDSL()
-	}
-	return e, ok
-}
-
 // contactDefinition returns true and current context if it is an ContactDefinition,
 // nil and false otherwise.
 func contactDefinition() (*design.ContactDefinition, bool) {
@@ -62,6 +52,16 @@ func attributeDefinition() (*design.AttributeDefinition, bool) {
 	return a, ok
 }
 
+// apiVersionDefinition returns true and current context if it is an APIVersionDefinition,
+// nil and false otherwise.
+func apiVersionDefinition() (*design.APIVersionDefinition, bool) {
+	v, ok := dslengine.CurrentDefinition().(*design.APIVersionDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+	}
+	return v, ok
+}
+
 // resourceDefinition returns true and current context if it is a ResourceDefinition,
 // nil and false otherwise.
 func resourceDefinition() (*design.ResourceDefinition, bool) {
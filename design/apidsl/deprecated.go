@@ -0,0 +1,42 @@
+package apidsl
+
+import (
+	"github.com/shogo82148/goa-v1/design"
+	"github.com/shogo82148/goa-v1/dslengine"
+)
+
+// Deprecated marks an action, a parameter, an attribute or a media type as deprecated.
+// since is the date at which the definition was deprecated, sunset the date at which it will be
+// removed. Both dates are formatted as RFC3339 full-date or date-time values and may be empty if
+// unknown. replacement describes what replaces the deprecated definition, it may be empty as well.
+// The replacement of an action should be the URL of the successor endpoint.
+//
+// Deprecated definitions are flagged as such in the generated Swagger and JSON schema
+// specifications and the generated client and CLI code print warnings when they are used. The
+// generated controller code sets the Deprecation, Sunset and Link response headers of deprecated
+// actions and counts the requests made to them (see goa.Deprecated).
+//
+//	Action("show", func() {
+//		Deprecated("2024-01-01", "2025-01-01", "/v2/bottles/:id")
+//		Routing(GET("/:id"))
+//		Params(func() {
+//			Param("id", Integer)
+//			Param("vintage", Integer, func() {
+//				Deprecated("2024-01-01", "", "year")
+//			})
+//		})
+//		Response(OK)
+//	})
+func Deprecated(since, sunset, replacement string) {
+	d := &design.DeprecationDefinition{Since: since, Sunset: sunset, Replacement: replacement}
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.ActionDefinition:
+		def.Deprecation = d
+	case *design.MediaTypeDefinition:
+		def.Deprecation = d
+	case *design.AttributeDefinition:
+		def.Deprecation = d
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
@@ -0,0 +1,60 @@
+package apidsl
+
+import (
+	"github.com/shogo82148/goa-v1/design"
+	"github.com/shogo82148/goa-v1/dslengine"
+)
+
+// ServerSentEvents declares that the action streams Server-Sent Events to the client. The first
+// argument is the media type of the event data given by value or by identifier. The optional DSL
+// may set the keepalive interval.
+//
+// The generated action context exposes a Send method that encodes the event data with the service
+// encoder, writes the event and flushes the response. The context also exposes the ID of the last
+// event received by a reconnecting client via LastEventID. The generated client exposes a method
+// that returns a typed reader of the events and the generated CLI streams the events to stdout.
+//
+// ServerSentEvents must appear in Action. Example:
+//
+//	Action("watch", func() {
+//		Routing(GET("/:id/events"))
+//		ServerSentEvents(BottleEventMedia, func() {
+//			KeepAlive(30)
+//		})
+//	})
+func ServerSentEvents(val interface{}, dsl ...func()) {
+	a, ok := actionDefinition()
+	if !ok {
+		return
+	}
+	if a.Events != nil {
+		dslengine.ReportError("events are defined twice")
+		return
+	}
+	e := &design.EventStreamDefinition{Parent: a, KeepAlive: design.DefaultKeepAlive}
+	switch v := val.(type) {
+	case string:
+		e.MediaType = v
+	case *design.MediaTypeDefinition:
+		e.MediaType = v.Identifier
+	default:
+		dslengine.ReportError("events media type must be a media type or a media type identifier, got %#v", val)
+		return
+	}
+	if len(dsl) > 0 && !dslengine.Execute(dsl[0], e) {
+		return
+	}
+	a.Events = e
+}
+
+// KeepAlive sets the interval in seconds between the comments sent on idle event streams to keep
+// the connection open through proxies, 0 disables them. The default is 15 seconds.
+//
+// KeepAlive must appear in ServerSentEvents.
+func KeepAlive(seconds int) {
+	if e, ok := dslengine.CurrentDefinition().(*design.EventStreamDefinition); ok {
+		e.KeepAlive = seconds
+		return
+	}
+	dslengine.IncompatibleDSL()
+}
@@ -0,0 +1,56 @@
+package apidsl
+
+import (
+	"github.com/shogo82148/goa-v1/design"
+	"github.com/shogo82148/goa-v1/dslengine"
+)
+
+// Hypermedia can be used in: API, MediaType
+//
+// Hypermedia sets the format used to render media types and their links: design.NoHypermedia,
+// design.HALHypermedia or design.JSONAPIHypermedia. When used in API it sets the format of all the
+// media types that do not set one. Collections are rendered with the format of their elements.
+//
+// HAL documents render the "href" attribute and the links of a media type in the "_links" object
+// and the attributes holding related media types in the "_embedded" object. JSON:API documents
+// render the media types as resources in the "data" member, the related media types as
+// relationships whose resources are listed in the "included" member. The JSON:API type of a
+// resource defaults to the last segment of the media type identifier and may be set with the
+// "jsonapi:type" metadata. Media types rendered as JSON:API resources must define an "id"
+// attribute.
+//
+// The generated response helpers render the hypermedia documents and the generated service and
+// client register the matching encoders and decoders. Example:
+//
+//	var BottleMedia = MediaType("application/vnd.goa.example.bottle", func() {
+//		Hypermedia(JSONAPIHypermedia)
+//		Metadata("jsonapi:type", "bottles")
+//		Attributes(func() {
+//			Attribute("id", Integer)
+//			Attribute("href", String)
+//			Attribute("account", AccountMedia)
+//		})
+//		Links(func() {
+//			Link("account")
+//		})
+//		View("default", func() {
+//			Attribute("id")
+//			Attribute("href")
+//			Attribute("links")
+//		})
+//	})
+func Hypermedia(format design.HypermediaFormat) {
+	if !format.IsValid() {
+		dslengine.ReportError("invalid hypermedia format %#v, must be %#v, %#v or %#v", string(format),
+			design.NoHypermedia, design.HALHypermedia, design.JSONAPIHypermedia)
+		return
+	}
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		def.Hypermedia = format
+	case *design.MediaTypeDefinition:
+		def.Hypermedia = format
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
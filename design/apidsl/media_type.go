@@ -429,7 +429,10 @@ func CollectionOf(v interface{}, paramAndDSL ...interface{}) *design.MediaTypeDe
 			// Cannot compute collection type name before element media type DSL has executed
 			// since the DSL may modify element type name via the TypeName function.
 			mt.TypeName = m.TypeName + "Collection"
-			mt.AttributeDefinition = &design.AttributeDefinition{Type: ArrayOf(m)}
+			mt.AttributeDefinition = &design.AttributeDefinition{
+				Type:     ArrayOf(m),
+				Metadata: design.GeneratedMetadataDefinition(),
+			}
 			if apidsl != nil {
 				dslengine.Execute(apidsl, mt)
 			}
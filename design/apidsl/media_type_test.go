@@ -216,6 +216,72 @@ var _ = Describe("MediaType", func() {
 			Ω(o[viewAtt].Type).Should(Equal(design.String))
 		})
 	})
+
+	Context("with a hypermedia format", func() {
+		var format design.HypermediaFormat
+		var attName string
+		var coll *design.MediaTypeDefinition
+
+		BeforeEach(func() {
+			name = "application/vnd.goa.example.bottle"
+			format = design.JSONAPIHypermedia
+			attName = "id"
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			mt = apidsl.MediaType(name, func() {
+				apidsl.Hypermedia(format)
+				apidsl.Attributes(func() {
+					apidsl.Attribute(attName)
+				})
+				apidsl.View("default", func() { apidsl.Attribute(attName) })
+			})
+			coll = apidsl.CollectionOf(mt)
+			design.ProjectedMediaTypes = make(design.MediaTypeRoot)
+			dslengine.Run()
+		})
+
+		It("sets the format", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(mt.HypermediaFormat()).Should(Equal(design.JSONAPIHypermedia))
+			Ω(mt.JSONAPIType()).Should(Equal("bottle"))
+		})
+
+		It("renders the projections and the collections with the format", func() {
+			p, _, err := mt.Project("default")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(p.HypermediaFormat()).Should(Equal(design.JSONAPIHypermedia))
+			Ω(p.JSONAPIType()).Should(Equal("bottle"))
+			Ω(coll.HypermediaFormat()).Should(Equal(design.JSONAPIHypermedia))
+		})
+
+		It("registers the encoder and decoder", func() {
+			Ω(design.Design.Produces[len(design.Design.Produces)-1].MIMETypes).Should(Equal([]string{design.JSONAPIContentType}))
+			Ω(design.Design.Consumes[len(design.Design.Consumes)-1].MIMETypes).Should(Equal([]string{design.JSONAPIContentType}))
+		})
+
+		Context("with an invalid format", func() {
+			BeforeEach(func() {
+				format = "xml"
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+
+		Context("with a JSON:API media type without id", func() {
+			BeforeEach(func() {
+				attName = "name"
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`"id"`))
+			})
+		})
+	})
 })
 
 var _ = Describe("Duplicate media types", func() {
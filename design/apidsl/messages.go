@@ -0,0 +1,59 @@
+package apidsl
+
+import (
+	"github.com/shogo82148/goa-v1/design"
+	"github.com/shogo82148/goa-v1/dslengine"
+)
+
+// Messages declares the types of the messages exchanged over the connections of a websocket
+// action. inbound is the type of the messages sent by the client and outbound the type of the
+// messages sent by the server. Both are user types or media types given by value, one of them may
+// be nil for one-way connections. The optional DSL may set the ping interval.
+//
+// The messages are encoded in JSON text frames. The generated action context exposes a Handler
+// method whose connections have typed Send and Recv methods that validate the messages, the
+// generated client exposes a similar connection type and the generated test helpers return a
+// typed connection to the controller under test.
+//
+// Messages must appear in Action. Example:
+//
+//	Action("chat", func() {
+//		Scheme("ws")
+//		Routing(GET("/chat"))
+//		Messages(ChatMessage, ChatEvent, func() {
+//			PingInterval(10)
+//		})
+//		Response(SwitchingProtocols)
+//	})
+func Messages(inbound, outbound design.DataType, dsl ...func()) {
+	a, ok := actionDefinition()
+	if !ok {
+		return
+	}
+	if a.Messages != nil {
+		dslengine.ReportError("messages are defined twice")
+		return
+	}
+	m := &design.MessagesDefinition{
+		Parent:       a,
+		Inbound:      inbound,
+		Outbound:     outbound,
+		PingInterval: design.DefaultPingInterval,
+	}
+	if len(dsl) > 0 && !dslengine.Execute(dsl[0], m) {
+		return
+	}
+	a.Messages = m
+}
+
+// PingInterval sets the interval in seconds between the pings sent on websocket connections to
+// keep them alive, 0 disables them. The default is 30 seconds.
+//
+// PingInterval must appear in Messages.
+func PingInterval(seconds int) {
+	if m, ok := dslengine.CurrentDefinition().(*design.MessagesDefinition); ok {
+		m.PingInterval = seconds
+		return
+	}
+	dslengine.IncompatibleDSL()
+}
@@ -34,6 +34,15 @@ import (
 //        Metadata("struct:tag:json", "myName,omitempty")
 //        Metadata("struct:tag:xml", "myName,attr")
 //
+// `struct:enum`: generates a named Go type for an attribute that defines an Enum validation. The
+// generated type comes with one exported constant per enum value, a Valid method and JSON and text
+// marshalling methods. The type is generated in the app package and aliased in the client package.
+// The value specifies the name of the Go type. Attributes sharing the same type name must define
+// the same enum values.
+// Applicable to attributes of user types, media types and payloads.
+//
+//        Metadata("struct:enum", "Color")
+//
 // `swagger:generate`: specifies whether Swagger specification should be generated. Defaults to
 // true.
 // Applicable to resources, actions and file servers.
@@ -66,6 +75,10 @@ import (
 //
 //        Metadata("swagger:extension:x-api", `{"foo":"bar"}`)
 //
+// `goa:generated`: set by goa on the actions, parameters and media types created by the DSL such
+// as the operation actions added by LongRunning. The linter does not check these definitions.
+// It should not be set by designs.
+//
 // The special key names listed above may be used as follows:
 //
 //        var Account = Type("Account", func() {
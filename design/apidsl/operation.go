@@ -0,0 +1,74 @@
+package apidsl
+
+import (
+	"github.com/shogo82148/goa-v1/design"
+	"github.com/shogo82148/goa-v1/dslengine"
+)
+
+// LongRunning declares that the action starts a long-running operation and responds before the
+// operation completes. retryAfter is the number of seconds clients should wait before requesting
+// the status of the operation.
+//
+// LongRunning defines the action "Accepted" response whose body is the status of the operation
+// described by the built-in OperationMedia media type and whose Location and Retry-After headers
+// point to the status of the operation. It also adds the "show_operation" and "cancel_operation"
+// actions to the resource, these actions respectively retrieve the status of an operation and
+// cancel it using the "GET" and "DELETE" methods on the "/operations/:operationID" path relative to
+// the resource base path. The operation actions are shared by all the long-running actions of the
+// resource. The generated action context exposes an AcceptedOperation method that sends the
+// response and the generated client exposes a method that polls the status of the operation until
+// it completes.
+//
+// LongRunning must appear in Action. Example:
+//
+//	Action("import", func() {
+//		Routing(POST("/import"))
+//		Payload(ImportPayload)
+//		LongRunning(5)
+//	})
+func LongRunning(retryAfter int) {
+	a, ok := actionDefinition()
+	if !ok {
+		return
+	}
+	a.LongRunning = &design.LongRunningDefinition{RetryAfter: retryAfter, Parent: a}
+	if design.Design.MediaTypes == nil {
+		design.Design.MediaTypes = make(map[string]*design.MediaTypeDefinition)
+	}
+	design.Design.MediaTypes[design.CanonicalIdentifier(design.OperationMediaIdentifier)] = design.OperationMedia
+	Response(design.Accepted, design.OperationMedia, func() {
+		Headers(func() {
+			Header("Location", design.String, "URL of the operation status")
+			Header("Retry-After", design.Integer, "Number of seconds to wait before requesting the operation status")
+			Required("Location")
+		})
+	})
+	r := a.Parent
+	operationAction(r, design.ShowOperationAction, "Retrieve the status of a long-running operation", GET(design.OperationPath))
+	operationAction(r, design.CancelOperationAction, "Cancel a long-running operation", DELETE(design.OperationPath))
+}
+
+// operationAction adds the action with the given name and route that returns the status of an
+// operation to r unless r already defines it.
+func operationAction(r *design.ResourceDefinition, name, description string, route *design.RouteDefinition) {
+	if _, ok := r.Actions[name]; ok {
+		return
+	}
+	action := &design.ActionDefinition{
+		Parent:   r,
+		Name:     name,
+		Metadata: design.GeneratedMetadataDefinition(),
+	}
+	ok := dslengine.Execute(func() {
+		Description(description)
+		Routing(route)
+		Params(func() {
+			Param(design.OperationIDParam, design.String, "ID of the operation")
+		})
+		Response(design.OK, design.OperationMedia)
+		Response(design.NotFound)
+	}, action)
+	if ok {
+		r.Actions[name] = action
+	}
+}
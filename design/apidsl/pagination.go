@@ -0,0 +1,35 @@
+package apidsl
+
+import (
+	"github.com/shogo82148/goa-v1/design"
+)
+
+// Paginated declares that the action returns a paginated collection. style is either
+// CursorPagination or OffsetPagination. defaultLimit is the number of items returned when the
+// request does not specify a limit and maxLimit the maximum number of items returned by a single
+// request.
+//
+// Paginated adds the "limit" query string parameter and the "offset" or "cursor" parameter
+// depending on the style to the action. The generated action context exposes a SetPageLinks method
+// that sets the RFC 8288 Link response header pointing to the other pages of the collection and
+// the generated client exposes a method that iterates over all the pages by following these links.
+//
+// Paginated must appear in Action. Example:
+//
+//	Action("list", func() {
+//		Routing(GET(""))
+//		Paginated(OffsetPagination, 20, 100)
+//		Response(OK, CollectionOf(BottleMedia))
+//	})
+func Paginated(style design.PaginationStyle, defaultLimit, maxLimit int) {
+	a, ok := actionDefinition()
+	if !ok {
+		return
+	}
+	a.Pagination = &design.PaginationDefinition{
+		Style:        style,
+		DefaultLimit: defaultLimit,
+		MaxLimit:     maxLimit,
+	}
+	a.Params = a.Params.Merge(a.Pagination.Params())
+}
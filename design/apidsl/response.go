@@ -118,6 +118,21 @@ func Status(status int) {
 	}
 }
 
+// Streaming makes it possible to stream the elements of the response collection. The generated
+// action context exposes response helpers that accept an iterator or a channel in addition to the
+// helpers that accept the whole collection. The elements are encoded and written one at a time,
+// either as a JSON array or as newline delimited JSON when the request accepts
+// "application/x-ndjson". Streaming must appear in a Response whose media type is a collection:
+//
+//	Response(OK, CollectionOf(BottleMedia), func() {
+//		Streaming()
+//	})
+func Streaming() {
+	if r, ok := responseDefinition(); ok {
+		r.Stream = true
+	}
+}
+
 func executeResponseDSL(name string, paramsAndDSL ...interface{}) *design.ResponseDefinition {
 	var params []string
 	var dsl func()
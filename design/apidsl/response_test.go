@@ -177,6 +177,41 @@ var _ = Describe("Response", func() {
 		})
 	})
 
+	Context("with streaming", func() {
+		BeforeEach(func() {
+			name = "OK"
+			mt := apidsl.MediaType("application/vnd.item", func() {
+				apidsl.Attributes(func() {
+					apidsl.Attribute("id", design.Integer)
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("id")
+				})
+			})
+			dt = apidsl.CollectionOf(mt)
+			dsl = func() {
+				apidsl.Streaming()
+			}
+		})
+
+		It("sets the Stream flag", func() {
+			Ω(res).ShouldNot(BeNil())
+			Ω(res.Validate()).ShouldNot(HaveOccurred())
+			Ω(res.Stream).Should(BeTrue())
+		})
+
+		Context("of a media type that is not a collection", func() {
+			BeforeEach(func() {
+				dt = design.Design.MediaTypeWithIdentifier("application/vnd.item")
+			})
+
+			It("produces an invalid response definition", func() {
+				Ω(res).ShouldNot(BeNil())
+				Ω(res.Validate()).Should(HaveOccurred())
+			})
+		})
+	})
+
 	Context("not from the goa default definitions", func() {
 		BeforeEach(func() {
 			name = "foo"
@@ -0,0 +1,49 @@
+package apidsl
+
+import (
+	"github.com/shogo82148/goa-v1/design"
+	"github.com/shogo82148/goa-v1/dslengine"
+)
+
+// Selectable declares that requests may select the parts of the action "OK" response they get.
+// selectors lists ViewSelector, FieldsSelector or both, all selectors are enabled if none is given.
+//
+// ViewSelector adds the "view" query string parameter whose value is the name of the view of the
+// response media type used to render the response, "default" by default. FieldsSelector adds the
+// "fields" query string parameter whose value is a comma separated list of the attributes rendered
+// in the response, nested attributes are separated by dots, for example "id,owner.name". The
+// generated action context rejects the requests that list attributes unknown to the selected view
+// and the generated "OK" response helper renders only the selected view and attributes. The views
+// of the media type must only render attributes also rendered by the default view as the response
+// helper is given the default view of the media type.
+//
+// Selectable must appear in a GET Action. Example:
+//
+//	Action("show", func() {
+//		Routing(GET("/:id"))
+//		Selectable(ViewSelector, FieldsSelector)
+//		Response(OK, BottleMedia)
+//	})
+func Selectable(selectors ...design.ResponseSelector) {
+	a, ok := actionDefinition()
+	if !ok {
+		return
+	}
+	if len(selectors) == 0 {
+		selectors = []design.ResponseSelector{design.ViewSelector, design.FieldsSelector}
+	}
+	s := &design.SelectionDefinition{Parent: a}
+	for _, sel := range selectors {
+		switch sel {
+		case design.ViewSelector:
+			s.View = true
+		case design.FieldsSelector:
+			s.Fields = true
+		default:
+			dslengine.ReportError("invalid response selector %#v, must be %#v or %#v", string(sel), string(design.ViewSelector), string(design.FieldsSelector))
+			return
+		}
+	}
+	a.Selection = s
+	a.Params = a.Params.Merge(s.Params())
+}
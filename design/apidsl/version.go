@@ -0,0 +1,103 @@
+package apidsl
+
+import (
+	"github.com/shogo82148/goa-v1/design"
+	"github.com/shogo82148/goa-v1/dslengine"
+)
+
+// APIVersion declares a version of the API. An API may declare multiple versions that share the
+// same types, resources and actions may then be restricted to a subset of the versions with
+// Versions. Requests select the version either by path using BasePath, by header using
+// VersionHeader or by media type parameter using VersionParam. All the versions that are not
+// selected by path must use the same header or media type parameter.
+//
+// The generated controllers mount each action once for each version that exposes it (see
+// goa.SelectVersion) and the Swagger specification, client and JavaScript generators produce one
+// output per version.
+//
+// APIVersion must appear in API. Example:
+//
+//	API("cellar", func() {
+//		APIVersion("v1", func() {
+//			Description("Initial version")
+//			BasePath("/v1")
+//		})
+//		APIVersion("v2", func() {
+//			BasePath("/v2")
+//		})
+//	})
+func APIVersion(name string, dsl ...func()) {
+	if len(dsl) > 1 {
+		dslengine.ReportError("too many arguments given to APIVersion")
+		return
+	}
+	api, ok := apiDefinition()
+	if !ok {
+		return
+	}
+	if api.VersionDefinition(name) != nil {
+		dslengine.ReportError("API version %#v is already defined", name)
+		return
+	}
+	version := &design.APIVersionDefinition{Name: name}
+	if len(dsl) == 1 {
+		if !dslengine.Execute(dsl[0], version) {
+			return
+		}
+	}
+	api.APIVersions = append(api.APIVersions, version)
+}
+
+// VersionHeader sets the name of the request header whose value selects the version. Requests
+// that don't set the header are handled by the first version that exposes the action.
+//
+// VersionHeader must appear in APIVersion. Example:
+//
+//	APIVersion("2024-01-01", func() {
+//		VersionHeader("X-Api-Version")
+//	})
+func VersionHeader(name string) {
+	if v, ok := apiVersionDefinition(); ok {
+		v.Header = name
+	}
+}
+
+// VersionParam sets the name of the media type parameter that selects the version. The parameter
+// is read from the request Accept header and from the Content-Type header if the Accept header
+// does not specify it, e.g. "Accept: application/json; version=v2". Requests that don't set the
+// parameter are handled by the first version that exposes the action.
+//
+// VersionParam must appear in APIVersion. Example:
+//
+//	APIVersion("v2", func() {
+//		VersionParam("version")
+//	})
+func VersionParam(name string) {
+	if v, ok := apiVersionDefinition(); ok {
+		v.MediaTypeParam = name
+	}
+}
+
+// Versions restricts a resource or an action to the given API versions. Resources are exposed by
+// all the versions by default, actions are exposed by the same versions as their resource by
+// default.
+//
+// Versions must appear in Resource or Action. Example:
+//
+//	Resource("bottle", func() {
+//		Versions("v1", "v2")
+//		Action("rate", func() {
+//			Versions("v2")
+//			Routing(PUT("/:id/rate"))
+//		})
+//	})
+func Versions(names ...string) {
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.ResourceDefinition:
+		def.Versions = append(def.Versions, names...)
+	case *design.ActionDefinition:
+		def.Versions = append(def.Versions, names...)
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
@@ -0,0 +1,51 @@
+package apidsl
+
+import (
+	"github.com/shogo82148/goa-v1/design"
+	"github.com/shogo82148/goa-v1/dslengine"
+)
+
+// Webhook defines a HTTP request sent by the API to a URL provided by a third party, for example to
+// notify it that a resource changed. Webhook may appear in API to describe webhooks sent
+// independently of any action or in Action to describe the callbacks triggered by the action.
+//
+// The webhook DSL uses Payload to describe the request body and Headers to describe the request
+// headers, Payload is required. The generated app package exposes a function that validates,
+// encodes, signs and sends the webhook with retries and a function that verifies the signature of
+// a received webhook and decodes its payload. Example:
+//
+//	Webhook("order.created", func() {
+//		Description("Sent when an order is created")
+//		Payload(func() {
+//			Member("id", Integer)
+//			Member("total", Number)
+//			Required("id")
+//		})
+//		Headers(func() {
+//			Header("X-Tenant")
+//		})
+//	})
+func Webhook(name string, dsl func()) {
+	var webhooks *map[string]*design.WebhookDefinition
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		webhooks = &def.Webhooks
+	case *design.ActionDefinition:
+		webhooks = &def.Webhooks
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if _, ok := (*webhooks)[name]; ok {
+		dslengine.ReportError("webhook %s is defined twice", name)
+		return
+	}
+	w := &design.WebhookDefinition{Name: name, Parent: dslengine.CurrentDefinition()}
+	if !dslengine.Execute(dsl, w) {
+		return
+	}
+	if *webhooks == nil {
+		*webhooks = make(map[string]*design.WebhookDefinition)
+	}
+	(*webhooks)[name] = w
+}
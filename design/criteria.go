@@ -0,0 +1,266 @@
+package design
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shogo82148/goa-v1/dslengine"
+)
+
+// FilterOperator is an operator used to filter the items of a collection on the value of one of
+// their attributes.
+type FilterOperator string
+
+const (
+	// EqualFilter selects the items whose attribute is equal to the parameter value. The
+	// parameter is named after the attribute, e.g. "color".
+	EqualFilter FilterOperator = "eq"
+	// InFilter selects the items whose attribute is equal to one of the parameter values. The
+	// parameter name is the attribute name suffixed with "_in", e.g. "color_in".
+	InFilter FilterOperator = "in"
+	// RangeFilter selects the items whose attribute is between the values of two parameters
+	// inclusive. The parameter names are the attribute name suffixed with "_min" and "_max",
+	// e.g. "rating_min" and "rating_max".
+	RangeFilter FilterOperator = "range"
+	// PrefixFilter selects the items whose attribute starts with the parameter value. The
+	// parameter name is the attribute name suffixed with "_prefix", e.g. "name_prefix".
+	PrefixFilter FilterOperator = "prefix"
+)
+
+const (
+	// SortParam is the name of the query string parameter that sets the attribute used to sort
+	// the collection returned by a sortable action.
+	SortParam = "sort"
+	// OrderParam is the name of the query string parameter that sets the sort order of the
+	// collection returned by a sortable action, "asc" or "desc".
+	OrderParam = "order"
+)
+
+type (
+	// CriteriaDefinition describes how requests filter and sort the collection returned by an
+	// action.
+	CriteriaDefinition struct {
+		// Filters lists the names of the attributes of the collection items that requests
+		// may filter on.
+		Filters []string
+		// Sorts lists the names of the attributes of the collection items that requests
+		// may sort by.
+		Sorts []string
+		// Parent is the filterable or sortable action.
+		Parent *ActionDefinition
+
+		// finalized is true once the parameters have been added to the action.
+		finalized bool
+	}
+
+	// FilterParamDefinition describes a query string parameter that filters a collection.
+	FilterParamDefinition struct {
+		// Name is the name of the query string parameter.
+		Name string
+		// Attribute is the name of the filtered attribute of the collection items.
+		Attribute string
+		// Operator is the filter operator.
+		Operator FilterOperator
+		// Max is true for the parameter holding the upper bound of a range filter.
+		Max bool
+	}
+)
+
+// Context returns the generic definition name used in error messages.
+func (c *CriteriaDefinition) Context() string {
+	return fmt.Sprintf("criteria of %s", c.Parent.Context())
+}
+
+// ItemType returns the attribute describing the items of the collection returned in the "OK"
+// response of the action, nil if there is none.
+func (c *CriteriaDefinition) ItemType() *AttributeDefinition {
+	resp, ok := c.Parent.Responses["OK"]
+	if !ok {
+		return nil
+	}
+	mt, ok := resp.Type.(*MediaTypeDefinition)
+	if !ok {
+		mt = Design.MediaTypeWithIdentifier(resp.MediaType)
+	}
+	if mt == nil || !mt.IsArray() {
+		return nil
+	}
+	return mt.Type.ToArray().ElemType
+}
+
+// Operators returns the operators that may be used to filter on the given attribute: all the
+// attributes support EqualFilter, strings, UUIDs and numbers also support InFilter, strings also support
+// PrefixFilter and numbers, dates and date times also support RangeFilter. Attributes with an enum
+// validation only support EqualFilter and InFilter.
+func Operators(att *AttributeDefinition) []FilterOperator {
+	if !att.Type.IsPrimitive() {
+		return nil
+	}
+	if att.Validation != nil && len(att.Validation.Values) > 0 {
+		return []FilterOperator{EqualFilter, InFilter}
+	}
+	switch att.Type.Kind() {
+	case StringKind:
+		return []FilterOperator{EqualFilter, InFilter, PrefixFilter}
+	case UUIDKind:
+		return []FilterOperator{EqualFilter, InFilter}
+	case IntegerKind, NumberKind:
+		return []FilterOperator{EqualFilter, InFilter, RangeFilter}
+	case DateTimeKind, DateKind:
+		return []FilterOperator{EqualFilter, RangeFilter}
+	case FileKind, AnyKind:
+		return nil
+	default:
+		return []FilterOperator{EqualFilter}
+	}
+}
+
+// FilterParams returns the filter query string parameters sorted by name.
+func (c *CriteriaDefinition) FilterParams() []*FilterParamDefinition {
+	item := c.ItemType()
+	if item == nil {
+		return nil
+	}
+	var params []*FilterParamDefinition
+	for _, n := range c.Filters {
+		att, ok := item.Type.ToObject()[n]
+		if !ok {
+			continue
+		}
+		for _, op := range Operators(att) {
+			switch op {
+			case EqualFilter:
+				params = append(params, &FilterParamDefinition{Name: n, Attribute: n, Operator: op})
+			case RangeFilter:
+				params = append(params,
+					&FilterParamDefinition{Name: n + "_min", Attribute: n, Operator: op},
+					&FilterParamDefinition{Name: n + "_max", Attribute: n, Operator: op, Max: true})
+			default:
+				params = append(params, &FilterParamDefinition{Name: n + "_" + string(op), Attribute: n, Operator: op})
+			}
+		}
+	}
+	sort.Slice(params, func(i, j int) bool { return params[i].Name < params[j].Name })
+	return params
+}
+
+// Params returns the query string parameters used to filter and sort the collection.
+func (c *CriteriaDefinition) Params() *AttributeDefinition {
+	item := c.ItemType()
+	if item == nil {
+		return nil
+	}
+	params := Object{}
+	for _, p := range c.FilterParams() {
+		att := item.Type.ToObject()[p.Attribute]
+		param := &AttributeDefinition{Type: att.Type}
+		if att.Validation != nil {
+			param.Validation = att.Validation.Dup()
+			param.Validation.Required = nil
+		}
+		switch p.Operator {
+		case EqualFilter:
+			param.Description = fmt.Sprintf("Only return the items whose %s is equal to the value", p.Attribute)
+		case InFilter:
+			param = &AttributeDefinition{
+				Type:        &Array{ElemType: param},
+				Description: fmt.Sprintf("Only return the items whose %s is equal to one of the values", p.Attribute),
+			}
+		case PrefixFilter:
+			param = &AttributeDefinition{
+				Type:        String,
+				Description: fmt.Sprintf("Only return the items whose %s starts with the value", p.Attribute),
+			}
+		case RangeFilter:
+			param.Validation = nil
+			if att.Validation != nil && att.Validation.Format != "" {
+				param.Validation = &dslengine.ValidationDefinition{Format: att.Validation.Format}
+			}
+			param.Description = fmt.Sprintf("Only return the items whose %s is greater than or equal to the value", p.Attribute)
+			if p.Max {
+				param.Description = fmt.Sprintf("Only return the items whose %s is less than or equal to the value", p.Attribute)
+			}
+		}
+		param.Metadata = GeneratedMetadataDefinition()
+		params[p.Name] = param
+	}
+	if len(c.Sorts) > 0 {
+		values := make([]interface{}, len(c.Sorts))
+		for i, s := range c.Sorts {
+			values[i] = s
+		}
+		params[SortParam] = &AttributeDefinition{
+			Type:        String,
+			Description: "Name of the attribute used to sort the items",
+			Validation:  &dslengine.ValidationDefinition{Values: values},
+			Metadata:    GeneratedMetadataDefinition(),
+		}
+		params[OrderParam] = &AttributeDefinition{
+			Type:         String,
+			Description:  "Sort order of the items",
+			DefaultValue: "asc",
+			Validation:   &dslengine.ValidationDefinition{Values: []interface{}{"asc", "desc"}},
+			Metadata:     GeneratedMetadataDefinition(),
+		}
+	}
+	return &AttributeDefinition{Type: params}
+}
+
+// Finalize adds the filter and sort query string parameters to the action parameters.
+func (c *CriteriaDefinition) Finalize() {
+	if c.finalized {
+		return
+	}
+	if params := c.Params(); params != nil {
+		c.Parent.Params = c.Parent.Params.Merge(params)
+	}
+	c.finalized = true
+}
+
+// Validate checks that the action returns a collection whose items define the filtered and
+// sorted attributes and that the generated parameters do not clash with the action parameters.
+func (c *CriteriaDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	item := c.ItemType()
+	if item == nil {
+		verr.Add(c, "OK response must be a collection media type")
+		return verr.AsError()
+	}
+	o := item.Type.ToObject()
+	if o == nil {
+		verr.Add(c, "collection items must be objects")
+		return verr.AsError()
+	}
+	for _, n := range c.Filters {
+		att, ok := o[n]
+		if !ok {
+			verr.Add(c, "unknown filtered attribute %#v", n)
+		} else if len(Operators(att)) == 0 {
+			verr.Add(c, "filtered attribute %#v must be a primitive", n)
+		}
+	}
+	for _, n := range c.Sorts {
+		att, ok := o[n]
+		if !ok {
+			verr.Add(c, "unknown sorted attribute %#v", n)
+		} else if len(Operators(att)) == 0 || att.Type.Kind() == BooleanKind {
+			verr.Add(c, "sorted attribute %#v must be a string, a number or a date", n)
+		}
+	}
+	if c.Parent.Params != nil && !c.finalized {
+		existing := c.Parent.Params.Type.ToObject()
+		var names []string
+		for _, p := range c.FilterParams() {
+			names = append(names, p.Name)
+		}
+		if len(c.Sorts) > 0 {
+			names = append(names, SortParam, OrderParam)
+		}
+		for _, n := range names {
+			if _, ok := existing[n]; ok {
+				verr.Add(c, "parameter %#v clashes with the generated filter or sort parameter", n)
+			}
+		}
+	}
+	return verr.AsError()
+}
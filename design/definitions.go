@@ -7,6 +7,7 @@ import (
 	"path"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/dimfeld/httppath"
 	"github.com/shogo82148/goa-v1/dslengine"
@@ -74,6 +75,17 @@ type (
 		Security *SecurityDefinition
 		// NoExamples indicates whether to bypass automatic example generation.
 		NoExamples bool
+		// GoTypeMappings lists the primitive and user types mapped to existing Go types.
+		GoTypeMappings []*GoTypeMappingDefinition
+		// APIVersions lists the versions of the API described by the design if any.
+		APIVersions []*APIVersionDefinition
+		// Webhooks lists the webhooks sent by the API indexed by name.
+		Webhooks map[string]*WebhookDefinition
+		// Hypermedia is the format used to render the API media types, NoHypermedia if empty.
+		Hypermedia HypermediaFormat
+		// SelectedVersion is the version described by the API definitions returned by
+		// ForVersion, nil otherwise.
+		SelectedVersion *APIVersionDefinition
 
 		// rand is the random generator used to generate examples.
 		rand *RandomGenerator
@@ -105,6 +117,24 @@ type (
 		URL string `json:"url,omitempty"`
 	}
 
+	// APIVersionDefinition describes a version of the API. All the versions share the API
+	// types, resources and actions may be restricted to a subset of the versions. Requests
+	// select the version with a path prefix, a header or a media type parameter.
+	APIVersionDefinition struct {
+		// Name of the version, e.g. "v1"
+		Name string
+		// Description of the version
+		Description string
+		// BasePath is the path prefix to all the version endpoints if the version is
+		// selected by path.
+		BasePath string
+		// Header is the name of the request header whose value selects the version if any.
+		Header string
+		// MediaTypeParam is the name of the parameter of the request Accept or Content-Type
+		// media types whose value selects the version if any.
+		MediaTypeParam string
+	}
+
 	// ResourceDefinition describes a REST resource.
 	// It defines both a media type and a set of actions that can be executed through HTTP
 	// requests.
@@ -144,6 +174,9 @@ type (
 		// Security defines security requirements for the Resource,
 		// for actions that don't define one themselves.
 		Security *SecurityDefinition
+		// Versions lists the names of the API versions exposing the resource, empty if
+		// the resource is exposed by all versions.
+		Versions []string
 	}
 
 	// CORSDefinition contains the definition for a specific origin CORS policy.
@@ -182,6 +215,22 @@ type (
 		Encoder bool
 	}
 
+	// GoTypeMappingDefinition maps a primitive or user type to an existing Go type. The code
+	// generators use the Go type in place of the type they would generate otherwise.
+	GoTypeMappingDefinition struct {
+		// Type is the mapped primitive or user type.
+		Type DataType
+		// GoType is the qualified name of the Go type, e.g. "civil.DateTime".
+		GoType string
+		// PackagePath is the import path of the package defining the Go type if any.
+		PackagePath string
+		// Function is the name of the Go function used to parse string values (path, query
+		// string and header values) into the Go type, e.g. "civil.ParseDateTime". The function
+		// must have the signature func(string) (T, error). Defaults to goa.ParseText which
+		// requires the Go type to implement encoding.TextUnmarshaler.
+		Function string
+	}
+
 	// ResponseDefinition defines a HTTP response status and optional validation rules.
 	ResponseDefinition struct {
 		// Response name
@@ -204,6 +253,8 @@ type (
 		Metadata dslengine.MetadataDefinition
 		// Standard is true if the response definition comes from the goa default responses
 		Standard bool
+		// Stream is true if the elements of the response collection may be streamed
+		Stream bool
 	}
 
 	// ResponseTemplateDefinition defines a response template.
@@ -253,6 +304,28 @@ type (
 		Metadata dslengine.MetadataDefinition
 		// Security defines security requirements for the action
 		Security *SecurityDefinition
+		// Deprecation describes the deprecation of the action if any.
+		Deprecation *DeprecationDefinition
+		// Versions lists the names of the API versions exposing the action, empty if the
+		// action is exposed by the same versions as its parent resource.
+		Versions []string
+		// Pagination describes how the action paginates the collection it returns if any.
+		Pagination *PaginationDefinition
+		// Events describes the Server-Sent Events streamed by the action if any.
+		Events *EventStreamDefinition
+		// Messages describes the messages exchanged over the connections of websocket
+		// actions if any.
+		Messages *MessagesDefinition
+		// Webhooks lists the webhooks triggered by the action indexed by name.
+		Webhooks map[string]*WebhookDefinition
+		// LongRunning describes the operations started by the action if it is long-running.
+		LongRunning *LongRunningDefinition
+		// Selection describes how requests select the parts of the OK response they get if
+		// the action is selectable.
+		Selection *SelectionDefinition
+		// Criteria describes how requests filter and sort the collection returned by the
+		// action if it is filterable or sortable.
+		Criteria *CriteriaDefinition
 	}
 
 	// FileServerDefinition defines an endpoint that servers static assets.
@@ -335,6 +408,25 @@ type (
 		NonZeroAttributes map[string]bool
 		// DSLFunc contains the initialization DSL. This is used for user types.
 		DSLFunc func()
+		// Deprecation describes the deprecation of the attribute if any.
+		Deprecation *DeprecationDefinition
+		// explicitExample is true if the example was set with SetExample rather than
+		// generated.
+		explicitExample bool
+	}
+
+	// DeprecationDefinition describes the deprecation of an action, a parameter, an attribute or
+	// a media type.
+	DeprecationDefinition struct {
+		// Since is the date at which the definition was deprecated formatted as a RFC3339
+		// full-date or date-time, e.g. "2024-01-02".
+		Since string
+		// Sunset is the date at which the definition will be removed formatted as a RFC3339
+		// full-date or date-time.
+		Sunset string
+		// Replacement describes what replaces the deprecated definition. Replacements of
+		// actions may be given as the URL of the successor endpoint.
+		Replacement string
 	}
 
 	// ContainerDefinition defines a generic container definition that contains attributes.
@@ -552,6 +644,79 @@ func (a *APIDefinition) IterateMediaTypes(it MediaTypeIterator) error {
 	return nil
 }
 
+// GoTypeMapping returns the mapping of the given primitive or user type to an existing Go type,
+// nil if there is none.
+func (a *APIDefinition) GoTypeMapping(t DataType) *GoTypeMappingDefinition {
+	if a == nil || t == nil {
+		return nil
+	}
+	for _, m := range a.GoTypeMappings {
+		switch actual := t.(type) {
+		case Primitive:
+			if m.Type == actual {
+				return m
+			}
+		case *UserTypeDefinition:
+			if ut, ok := m.Type.(*UserTypeDefinition); ok && ut.TypeName == actual.TypeName {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+// VersionDefinition returns the definition of the API version with the given name, nil if there
+// isn't one.
+func (a *APIDefinition) VersionDefinition(name string) *APIVersionDefinition {
+	for _, v := range a.APIVersions {
+		if v.Name == name {
+			return v
+		}
+	}
+	return nil
+}
+
+// ForVersion returns a copy of the API definition that only contains the resources and actions
+// exposed by the version with the given name. The base path of the returned API includes the
+// version base path if any. The code generators use the returned definition in place of Design to
+// produce the version specific outputs. ForVersion returns nil if there is no such version.
+func (a *APIDefinition) ForVersion(name string) *APIDefinition {
+	v := a.VersionDefinition(name)
+	if v == nil {
+		return nil
+	}
+	api := *a
+	api.Version = v.Name
+	api.SelectedVersion = v
+	if v.BasePath != "" {
+		api.BasePath = path.Join("/", a.BasePath, v.BasePath)
+	}
+	api.Resources = make(map[string]*ResourceDefinition)
+	for n, r := range a.Resources {
+		if !r.SupportsVersion(v.Name) {
+			continue
+		}
+		res := *r
+		res.Actions = make(map[string]*ActionDefinition)
+		for an, act := range r.Actions {
+			if !act.SupportsVersion(v.Name) {
+				continue
+			}
+			action := *act
+			action.Parent = &res
+			action.Routes = make([]*RouteDefinition, len(act.Routes))
+			for i, route := range act.Routes {
+				rt := *route
+				rt.Parent = &action
+				action.Routes[i] = &rt
+			}
+			res.Actions[an] = &action
+		}
+		api.Resources[n] = &res
+	}
+	return &api
+}
+
 // IterateUserTypes calls the given iterator passing in each user type sorted in alphabetical order.
 // Iteration stops if an iterator returns an error and in this case IterateUserTypes returns that
 // error.
@@ -665,6 +830,7 @@ func (a *APIDefinition) Finalize() {
 	if len(a.Produces) == 0 {
 		a.Produces = DefaultEncoders
 	}
+	a.addHypermediaEncodings()
 	a.IterateResources(func(r *ResourceDefinition) error {
 		returnsError := func(resp *ResponseDefinition) bool {
 			if resp.MediaType == ErrorMediaIdentifier {
@@ -811,6 +977,11 @@ func (r *ResourceDefinition) FullPath() string {
 	return httppath.Clean(path.Join(basePath, r.BasePath))
 }
 
+// SupportsVersion returns true if the resource is exposed by the API version with the given name.
+func (r *ResourceDefinition) SupportsVersion(name string) bool {
+	return supportsVersion(r.Versions, name)
+}
+
 // Parent returns the parent resource if any, nil otherwise.
 func (r *ResourceDefinition) Parent() *ResourceDefinition {
 	if r.ParentName != "" {
@@ -853,16 +1024,24 @@ func (r *ResourceDefinition) PreflightPaths() []string {
 			if r.Verb == "OPTIONS" {
 				continue
 			}
-			found := false
-			fp := r.FullPath()
-			for _, p := range paths {
-				if fp == p {
-					found = true
-					break
+			fps := []string{r.FullPath()}
+			if len(Design.APIVersions) > 0 {
+				fps = nil
+				for _, v := range a.APIVersions() {
+					fps = append(fps, r.VersionPath(v))
 				}
 			}
-			if !found {
-				paths = append(paths, fp)
+			for _, fp := range fps {
+				found := false
+				for _, p := range paths {
+					if fp == p {
+						found = true
+						break
+					}
+				}
+				if !found {
+					paths = append(paths, fp)
+				}
 			}
 		}
 		return nil
@@ -954,6 +1133,98 @@ func (enc *EncodingDefinition) Context() string {
 	return fmt.Sprintf("encoding for %s", strings.Join(enc.MIMETypes, ", "))
 }
 
+// Context returns the generic definition name used in error messages.
+func (m *GoTypeMappingDefinition) Context() string {
+	var name string
+	if m.Type != nil {
+		name = m.Type.Name()
+		if ut, ok := m.Type.(*UserTypeDefinition); ok {
+			name = ut.TypeName
+		}
+	}
+	return fmt.Sprintf("Go type mapping of %s to %s", name, m.GoType)
+}
+
+// PackageName returns the name used to qualify the Go type, i.e. the part of GoType that
+// precedes the dot. It returns the empty string if the Go type is not qualified.
+func (m *GoTypeMappingDefinition) PackageName() string {
+	if i := strings.Index(strings.TrimLeft(m.GoType, "*[]"), "."); i > 0 {
+		return strings.TrimLeft(m.GoType, "*[]")[:i]
+	}
+	return ""
+}
+
+// ParseFunction returns the Go expression of the function used to parse string values into the
+// Go type.
+func (m *GoTypeMappingDefinition) ParseFunction() string {
+	if m.Function != "" {
+		return m.Function
+	}
+	return fmt.Sprintf("goa.ParseText[%s]", m.GoType)
+}
+
+// Context returns the generic definition name used in error messages.
+func (v *APIVersionDefinition) Context() string {
+	if v.Name != "" {
+		return fmt.Sprintf("API version %#v", v.Name)
+	}
+	return "unnamed API version"
+}
+
+// Context returns the generic definition name used in error messages.
+func (d *DeprecationDefinition) Context() string {
+	return "deprecation"
+}
+
+// SinceTime returns the time at which the definition was deprecated, the zero time if unknown.
+func (d *DeprecationDefinition) SinceTime() time.Time {
+	t, _ := parseDeprecationTime(d.Since)
+	return t
+}
+
+// SunsetTime returns the time at which the definition will be removed, the zero time if
+// unknown.
+func (d *DeprecationDefinition) SunsetTime() time.Time {
+	t, _ := parseDeprecationTime(d.Sunset)
+	return t
+}
+
+// SuccessorURL returns the replacement if it is a URL or an absolute path, the empty string
+// otherwise.
+func (d *DeprecationDefinition) SuccessorURL() string {
+	if strings.HasPrefix(d.Replacement, "/") || strings.Contains(d.Replacement, "://") {
+		return d.Replacement
+	}
+	return ""
+}
+
+// Message returns a human readable description of the deprecation.
+func (d *DeprecationDefinition) Message() string {
+	msg := "deprecated"
+	if d.Since != "" {
+		msg += " since " + d.Since
+	}
+	if d.Sunset != "" {
+		msg += ", will be removed on " + d.Sunset
+	}
+	if d.Replacement != "" {
+		msg += ", use " + d.Replacement + " instead"
+	}
+	return msg
+}
+
+// parseDeprecationTime parses a RFC3339 full-date or date-time value. It returns the zero time
+// if val is empty.
+func parseDeprecationTime(val string) (time.Time, error) {
+	if val == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", val); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, val)
+}
+
 // Context returns the generic definition name used in error messages.
 func (a *AttributeDefinition) Context() string {
 	return ""
@@ -1090,15 +1361,23 @@ func (a *AttributeDefinition) IsFile(attName string) bool {
 func (a *AttributeDefinition) SetExample(example interface{}) bool {
 	if example == nil {
 		a.Example = "-" // set it to something else than nil so we know not to generate one
+		a.explicitExample = true
 		return true
 	}
 	if a.Type == nil || a.Type.IsCompatible(example) {
 		a.Example = example
+		a.explicitExample = true
 		return true
 	}
 	return false
 }
 
+// HasExplicitExample returns true if the example of the attribute was set with SetExample, i.e.
+// with the Example or NoExample DSL, false if it is generated or inherited from a generated one.
+func (a *AttributeDefinition) HasExplicitExample() bool {
+	return a.explicitExample
+}
+
 // GenerateExample returns the value of the Example field if not nil. Otherwise it traverses the
 // attribute type and recursively generates an example. The result is saved in the Example field.
 func (a *AttributeDefinition) GenerateExample(rand *RandomGenerator, seen []string) interface{} {
@@ -1317,6 +1596,7 @@ func (a *AttributeDefinition) inheritRecursive(parent *AttributeDefinition, seen
 			}
 			if att.Example == nil {
 				att.Example = patt.Example
+				att.explicitExample = patt.explicitExample
 			}
 			if patt.Metadata != nil {
 				if att.Metadata == nil {
@@ -1421,6 +1701,7 @@ func (r *ResponseDefinition) Dup() *ResponseDefinition {
 		Description: r.Description,
 		MediaType:   r.MediaType,
 		ViewName:    r.ViewName,
+		Stream:      r.Stream,
 	}
 	if r.Headers != nil {
 		res.Headers = DupAtt(r.Headers)
@@ -1446,6 +1727,9 @@ func (r *ResponseDefinition) Merge(other *ResponseDefinition) {
 		r.MediaType = other.MediaType
 		r.ViewName = other.ViewName
 	}
+	if other.Stream {
+		r.Stream = true
+	}
 	if other.Headers != nil {
 		otherHeaders := other.Headers.Type.ToObject()
 		if len(otherHeaders) > 0 {
@@ -1518,6 +1802,26 @@ func (a *ActionDefinition) AllParams() *AttributeDefinition {
 	return res.Merge(Design.Params)
 }
 
+// SupportsVersion returns true if the action is exposed by the API version with the given name.
+func (a *ActionDefinition) SupportsVersion(name string) bool {
+	if len(a.Versions) > 0 || a.Parent == nil {
+		return supportsVersion(a.Versions, name)
+	}
+	return a.Parent.SupportsVersion(name)
+}
+
+// APIVersions returns the definitions of the API versions that expose the action in the order
+// they are declared. It returns nil if the API does not declare versions.
+func (a *ActionDefinition) APIVersions() []*APIVersionDefinition {
+	var versions []*APIVersionDefinition
+	for _, v := range Design.APIVersions {
+		if a.SupportsVersion(v.Name) {
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
+
 // HasAbsoluteRoutes returns true if all the action routes are absolute.
 func (a *ActionDefinition) HasAbsoluteRoutes() bool {
 	for _, r := range a.Routes {
@@ -1600,6 +1904,12 @@ func (a *ActionDefinition) Finalize() {
 	}
 
 	a.mergeResponses()
+	if a.Selection != nil {
+		a.Selection.Finalize()
+	}
+	if a.Criteria != nil {
+		a.Criteria.Finalize()
+	}
 	a.initImplicitParams()
 	a.initQueryParams()
 }
@@ -1877,6 +2187,42 @@ func (r *RouteDefinition) IsAbsolute() bool {
 	return strings.HasPrefix(r.Path, "//")
 }
 
+// VersionPath returns the route full path for the given API version, that is the full path with
+// the version base path inserted after the API base path. Absolute routes and routes of resources
+// with absolute base paths are not prefixed.
+func (r *RouteDefinition) VersionPath(v *APIVersionDefinition) string {
+	full := r.FullPath()
+	if v == nil || v.BasePath == "" || r.IsAbsolute() {
+		return full
+	}
+	if r.Parent != nil && r.Parent.Parent != nil && strings.HasPrefix(r.Parent.Parent.BasePath, "//") {
+		return full
+	}
+	base := httppath.Clean(Design.BasePath)
+	rel := strings.TrimPrefix(full, base)
+	if base != "/" && rel != "" && !strings.HasPrefix(rel, "/") {
+		return full
+	}
+	joinedPath := path.Join(base, v.BasePath, rel)
+	if strings.HasSuffix(full, "/") && full != "/" {
+		joinedPath += "/"
+	}
+	return httppath.Clean(joinedPath)
+}
+
+// supportsVersion returns true if versions is empty or contains name.
+func supportsVersion(versions []string, name string) bool {
+	if len(versions) == 0 {
+		return true
+	}
+	for _, v := range versions {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
 func iterateHeaders(headers *AttributeDefinition, isRequired func(name string) bool, it HeaderIterator) error {
 	if headers == nil || !headers.Type.IsObject() {
 		return nil
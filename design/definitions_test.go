@@ -537,3 +537,72 @@ var _ = Describe("IterateSets", func() {
 	})
 
 })
+
+var _ = Describe("ForVersion", func() {
+	var api, saved *design.APIDefinition
+	var show, rate *design.ActionDefinition
+
+	BeforeEach(func() {
+		saved = design.Design
+		resource := &design.ResourceDefinition{Name: "bottle", BasePath: "/bottles"}
+		show = &design.ActionDefinition{Name: "show", Parent: resource}
+		show.Routes = []*design.RouteDefinition{{Verb: "GET", Path: "/:id", Parent: show}}
+		rate = &design.ActionDefinition{Name: "rate", Parent: resource, Versions: []string{"v2"}}
+		rate.Routes = []*design.RouteDefinition{{Verb: "PUT", Path: "/:id/rate", Parent: rate}}
+		resource.Actions = map[string]*design.ActionDefinition{"show": show, "rate": rate}
+		api = &design.APIDefinition{
+			Name:     "test",
+			BasePath: "/api",
+			APIVersions: []*design.APIVersionDefinition{
+				{Name: "v1", BasePath: "/v1"},
+				{Name: "v2", Header: "X-Api-Version"},
+			},
+			Resources: map[string]*design.ResourceDefinition{"bottle": resource},
+		}
+		design.Design = api
+	})
+
+	AfterEach(func() {
+		design.Design = saved
+	})
+
+	It("computes the action versions", func() {
+		Ω(show.APIVersions()).Should(HaveLen(2))
+		Ω(rate.APIVersions()).Should(Equal([]*design.APIVersionDefinition{api.APIVersions[1]}))
+	})
+
+	It("prefixes the route paths with the version base path", func() {
+		Ω(show.Routes[0].VersionPath(api.APIVersions[0])).Should(Equal("/api/v1/bottles/:id"))
+		Ω(show.Routes[0].VersionPath(api.APIVersions[1])).Should(Equal("/api/bottles/:id"))
+	})
+
+	It("returns nil for unknown versions", func() {
+		Ω(api.ForVersion("v3")).Should(BeNil())
+	})
+
+	Context("with a version selected by path", func() {
+		var v1 *design.APIDefinition
+
+		BeforeEach(func() {
+			v1 = api.ForVersion("v1")
+		})
+
+		It("only keeps the actions of the version", func() {
+			Ω(v1.SelectedVersion).Should(Equal(api.APIVersions[0]))
+			Ω(v1.Version).Should(Equal("v1"))
+			Ω(v1.BasePath).Should(Equal("/api/v1"))
+			Ω(v1.Resources["bottle"].Actions).Should(HaveLen(1))
+			Ω(v1.Resources["bottle"].Actions).Should(HaveKey("show"))
+		})
+
+		It("does not modify the original API", func() {
+			Ω(api.BasePath).Should(Equal("/api"))
+			Ω(api.Resources["bottle"].Actions).Should(HaveLen(2))
+		})
+
+		It("produces consistent full paths", func() {
+			design.Design = v1
+			Ω(v1.Resources["bottle"].Actions["show"].Routes[0].FullPath()).Should(Equal("/api/v1/bottles/:id"))
+		})
+	})
+})
@@ -50,6 +50,8 @@ func (d *dupper) DupAttribute(att *AttributeDefinition) *AttributeDefinition {
 		View:              att.View,
 		DSLFunc:           att.DSLFunc,
 		Example:           att.Example,
+		Deprecation:       att.Deprecation,
+		explicitExample:   att.explicitExample,
 	}
 	return &dup
 }
@@ -0,0 +1,57 @@
+package design
+
+import (
+	"fmt"
+
+	"github.com/shogo82148/goa-v1/dslengine"
+)
+
+// DefaultKeepAlive is the default interval in seconds between the comments sent on idle event
+// streams to keep the connection alive.
+const DefaultKeepAlive = 15
+
+// EventStreamDefinition describes the Server-Sent Events streamed by an action.
+type EventStreamDefinition struct {
+	// Parent is the action that streams the events.
+	Parent *ActionDefinition
+	// MediaType is the identifier of the media type of the event data.
+	MediaType string
+	// KeepAlive is the interval in seconds between the comments sent on idle streams, 0
+	// disables them.
+	KeepAlive int
+}
+
+// Context returns the generic definition name used in error messages.
+func (e *EventStreamDefinition) Context() string {
+	if e.Parent != nil {
+		return fmt.Sprintf("event stream of %s", e.Parent.Context())
+	}
+	return "event stream"
+}
+
+// EventType returns the media type of the event data, nil if there is no media type with the
+// event stream identifier.
+func (e *EventStreamDefinition) EventType() *MediaTypeDefinition {
+	return Design.MediaTypeWithIdentifier(e.MediaType)
+}
+
+// Validate checks that the event media type exists, that the keepalive interval is valid and that
+// the action can stream events.
+func (e *EventStreamDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if e.EventType() == nil {
+		verr.Add(e, "unknown event media type %#v", e.MediaType)
+	}
+	if e.KeepAlive < 0 {
+		verr.Add(e, "keepalive interval cannot be negative")
+	}
+	if e.Parent != nil {
+		if e.Parent.WebSocket() {
+			verr.Add(e, "websocket actions cannot stream events")
+		}
+		if e.Parent.Payload != nil {
+			verr.Add(e, "actions streaming events cannot have a payload")
+		}
+	}
+	return verr.AsError()
+}
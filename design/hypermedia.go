@@ -0,0 +1,211 @@
+package design
+
+import (
+	"mime"
+	"strings"
+
+	"github.com/shogo82148/goa-v1/dslengine"
+)
+
+// HypermediaFormat identifies the format used to render a media type and its links.
+type HypermediaFormat string
+
+const (
+	// NoHypermedia renders media types as plain objects whose links are rendered in the "links"
+	// attribute.
+	NoHypermedia HypermediaFormat = "none"
+	// HALHypermedia renders media types as HAL documents: the href and the links of a resource
+	// are rendered in the "_links" object and the related resources in the "_embedded" object.
+	// See https://tools.ietf.org/html/draft-kelly-json-hal.
+	HALHypermedia HypermediaFormat = "hal"
+	// JSONAPIHypermedia renders media types as JSON:API documents: the resources are rendered
+	// in the "data" member with their "attributes" and "relationships" and the related
+	// resources in the "included" member. See https://jsonapi.org/format/.
+	JSONAPIHypermedia HypermediaFormat = "jsonapi"
+)
+
+const (
+	// HALContentType is the Content-Type header value of HAL documents.
+	HALContentType = "application/hal+json"
+	// JSONAPIContentType is the Content-Type header value of JSON:API documents.
+	JSONAPIContentType = "application/vnd.api+json"
+)
+
+// ContentType returns the Content-Type header value of the documents rendered with the format, the
+// empty string for NoHypermedia.
+func (f HypermediaFormat) ContentType() string {
+	switch f {
+	case HALHypermedia:
+		return HALContentType
+	case JSONAPIHypermedia:
+		return JSONAPIContentType
+	default:
+		return ""
+	}
+}
+
+// IsValid returns true if f is one of NoHypermedia, HALHypermedia or JSONAPIHypermedia.
+func (f HypermediaFormat) IsValid() bool {
+	return f == NoHypermedia || f == HALHypermedia || f == JSONAPIHypermedia
+}
+
+// HypermediaFormat returns the format used to render the media type. Collections are rendered
+// with the format of their elements, the other media types with the format set in their
+// definition, the API format if none. Media types that are not objects are always rendered as is.
+func (m *MediaTypeDefinition) HypermediaFormat() HypermediaFormat {
+	if m.Type == nil {
+		return NoHypermedia
+	}
+	if m.IsArray() {
+		if e, ok := m.ToArray().ElemType.Type.(*MediaTypeDefinition); ok {
+			return e.HypermediaFormat()
+		}
+		return NoHypermedia
+	}
+	if !m.Type.IsObject() {
+		return NoHypermedia
+	}
+	if m.Hypermedia != "" {
+		return m.Hypermedia
+	}
+	if Design == nil || Design.Hypermedia == "" {
+		return NoHypermedia
+	}
+	return Design.Hypermedia
+}
+
+// JSONAPIType returns the JSON:API type of the resources described by the media type: the value
+// of ResourceType if set, the value of the "jsonapi:type" metadata otherwise and by default the
+// last segment of the media type identifier subtype, e.g. "bottle" for
+// "application/vnd.goa.example.bottle+json".
+func (m *MediaTypeDefinition) JSONAPIType() string {
+	if m.ResourceType != "" {
+		return m.ResourceType
+	}
+	if t, ok := m.Metadata["jsonapi:type"]; ok && len(t) > 0 {
+		return t[0]
+	}
+	base, _, err := mime.ParseMediaType(m.Identifier)
+	if err != nil {
+		base = m.Identifier
+	}
+	if idx := strings.Index(base, "/"); idx >= 0 {
+		base = base[idx+1:]
+	}
+	if idx := strings.Index(base, "+"); idx >= 0 {
+		base = base[:idx]
+	}
+	return base[strings.LastIndex(base, ".")+1:]
+}
+
+// EmbeddedResources returns the media types of the related resources rendered by the projected
+// media type indexed by attribute name. Related resources are the attributes whose type is a
+// media type, an array of media types or a collection rendered with the same format. The
+// returned media types are the media types of the elements for arrays and collections.
+func (m *MediaTypeDefinition) EmbeddedResources() map[string]*MediaTypeDefinition {
+	o := m.Type.ToObject()
+	if o == nil {
+		return nil
+	}
+	format := m.HypermediaFormat()
+	res := make(map[string]*MediaTypeDefinition)
+	for n, att := range o {
+		t := att.Type
+		if a := t.ToArray(); a != nil {
+			t = a.ElemType.Type
+		}
+		mt, ok := t.(*MediaTypeDefinition)
+		if !ok {
+			continue
+		}
+		if mt.IsArray() {
+			if mt, ok = mt.ToArray().ElemType.Type.(*MediaTypeDefinition); !ok {
+				continue
+			}
+		}
+		if mt.HypermediaFormat() == format {
+			res[n] = mt
+		}
+	}
+	return res
+}
+
+// HypermediaLinks returns the media types of the links rendered by the projected media type in
+// its "links" attribute indexed by link name.
+func (m *MediaTypeDefinition) HypermediaLinks() map[string]*MediaTypeDefinition {
+	o := m.Type.ToObject()
+	if o == nil {
+		return nil
+	}
+	att, ok := o["links"]
+	if !ok {
+		return nil
+	}
+	ut, ok := att.Type.(*UserTypeDefinition)
+	if !ok || !ut.IsObject() {
+		return nil
+	}
+	res := make(map[string]*MediaTypeDefinition)
+	for n, l := range ut.ToObject() {
+		mt, ok := l.Type.(*MediaTypeDefinition)
+		if !ok {
+			return nil
+		}
+		res[n] = mt
+	}
+	return res
+}
+
+// validateHypermedia checks that the hypermedia format of the media type is valid and that the
+// media types rendered as JSON:API resources define an "id" attribute.
+func (m *MediaTypeDefinition) validateHypermedia(verr *dslengine.ValidationErrors) {
+	if m.Hypermedia != "" && !m.Hypermedia.IsValid() {
+		verr.Add(m, "invalid hypermedia format %#v, must be %#v, %#v or %#v", string(m.Hypermedia), NoHypermedia, HALHypermedia, JSONAPIHypermedia)
+		return
+	}
+	if m.IsArray() || m.HypermediaFormat() != JSONAPIHypermedia {
+		return
+	}
+	if _, ok := m.Type.ToObject()["id"]; !ok {
+		verr.Add(m, `JSON:API media types must define an "id" attribute`)
+	}
+}
+
+// addHypermediaEncodings adds the encoders and decoders of the hypermedia formats used by the API
+// media types to the Produces and Consumes fields.
+func (a *APIDefinition) addHypermediaEncodings() {
+	used := make(map[HypermediaFormat]bool)
+	if a.Hypermedia != "" && a.Hypermedia != NoHypermedia {
+		used[a.Hypermedia] = true
+	}
+	for _, mt := range a.MediaTypes {
+		if f := mt.HypermediaFormat(); f != NoHypermedia {
+			used[f] = true
+		}
+	}
+	for _, f := range []HypermediaFormat{HALHypermedia, JSONAPIHypermedia} {
+		if !used[f] {
+			continue
+		}
+		a.Produces = appendEncoding(a.Produces, f.ContentType(), "NewEncoder")
+		a.Consumes = appendEncoding(a.Consumes, f.ContentType(), "NewDecoder")
+	}
+}
+
+// appendEncoding appends the encoding definition of the known encoder or decoder for the given
+// MIME type to encs unless encs already handles it.
+func appendEncoding(encs []*EncodingDefinition, mimeType, fn string) []*EncodingDefinition {
+	for _, enc := range encs {
+		for _, m := range enc.MIMETypes {
+			if m == mimeType {
+				return encs
+			}
+		}
+	}
+	return append(encs, &EncodingDefinition{
+		MIMETypes:   []string{mimeType},
+		PackagePath: KnownEncoders[mimeType],
+		Function:    fn,
+		Encoder:     fn == "NewEncoder",
+	})
+}
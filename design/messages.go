@@ -0,0 +1,89 @@
+package design
+
+import (
+	"fmt"
+
+	"github.com/shogo82148/goa-v1/dslengine"
+)
+
+// DefaultPingInterval is the default interval in seconds between the pings sent on websocket
+// connections that exchange typed messages.
+const DefaultPingInterval = 30
+
+// MessagesDefinition describes the messages exchanged over the connections of a websocket action.
+type MessagesDefinition struct {
+	// Parent is the websocket action.
+	Parent *ActionDefinition
+	// Inbound is the type of the messages sent by the client, nil if the client does not send
+	// messages. It is either a user type or a media type.
+	Inbound DataType
+	// Outbound is the type of the messages sent by the server, nil if the server does not send
+	// messages. It is either a user type or a media type.
+	Outbound DataType
+	// PingInterval is the interval in seconds between the pings sent to keep the connection
+	// alive, 0 disables them.
+	PingInterval int
+}
+
+// Context returns the generic definition name used in error messages.
+func (m *MessagesDefinition) Context() string {
+	if m.Parent != nil {
+		return fmt.Sprintf("messages of %s", m.Parent.Context())
+	}
+	return "messages"
+}
+
+// InboundType returns the type used to generate the code that handles the messages sent by the
+// client: the projection of the default view for media types, nil if there are no such messages.
+func (m *MessagesDefinition) InboundType() (DataType, error) {
+	return messageType(m.Inbound)
+}
+
+// OutboundType returns the type used to generate the code that handles the messages sent by the
+// server: the projection of the default view for media types, nil if there are no such messages.
+func (m *MessagesDefinition) OutboundType() (DataType, error) {
+	return messageType(m.Outbound)
+}
+
+// Validate checks that the message types are object user types or media types and that the
+// action uses websockets.
+func (m *MessagesDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if m.Inbound == nil && m.Outbound == nil {
+		verr.Add(m, "at least one of the inbound or outbound message types must be set")
+	}
+	if m.Inbound != nil && !isMessageType(m.Inbound) {
+		verr.Add(m, "inbound message type must be a user type or a media type describing an object")
+	}
+	if m.Outbound != nil && !isMessageType(m.Outbound) {
+		verr.Add(m, "outbound message type must be a user type or a media type describing an object")
+	}
+	if m.PingInterval < 0 {
+		verr.Add(m, "ping interval cannot be negative")
+	}
+	if m.Parent != nil && !m.Parent.WebSocket() {
+		verr.Add(m, "messages can only be declared on websocket actions, use the ws or wss scheme")
+	}
+	return verr.AsError()
+}
+
+// messageType projects media types on their default view.
+func messageType(t DataType) (DataType, error) {
+	if mt, ok := t.(*MediaTypeDefinition); ok {
+		p, _, err := mt.Project(DefaultView)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+	return t, nil
+}
+
+// isMessageType returns true if t is a user type or a media type describing an object.
+func isMessageType(t DataType) bool {
+	switch t.(type) {
+	case *UserTypeDefinition, *MediaTypeDefinition:
+		return t.IsObject()
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package design
+
+import (
+	"fmt"
+
+	"github.com/shogo82148/goa-v1/dslengine"
+)
+
+const (
+	// OperationMediaIdentifier is the identifier of the built-in media type that describes the
+	// status of a long-running operation.
+	OperationMediaIdentifier = "application/vnd.goa.operation"
+
+	// ShowOperationAction is the name of the action added to the resources that define
+	// long-running actions to retrieve the status of an operation.
+	ShowOperationAction = "show_operation"
+
+	// CancelOperationAction is the name of the action added to the resources that define
+	// long-running actions to cancel an operation.
+	CancelOperationAction = "cancel_operation"
+
+	// OperationIDParam is the name of the path parameter that identifies an operation in the
+	// routes of the operation actions.
+	OperationIDParam = "operationID"
+
+	// OperationPath is the path of the operation actions relative to the resource base path.
+	OperationPath = "/operations/:" + OperationIDParam
+)
+
+// OperationMedia is the built-in media type that describes the status of a long-running
+// operation. It is used by the responses of the long-running actions and of the operation actions.
+var OperationMedia = &MediaTypeDefinition{
+	UserTypeDefinition: &UserTypeDefinition{
+		AttributeDefinition: &AttributeDefinition{
+			Type:        operationMediaType,
+			Description: "Status of a long-running operation",
+			Validation: &dslengine.ValidationDefinition{
+				Required: []string{"id", "status"},
+			},
+			Example: map[string]interface{}{
+				"id":     "d9d8b6e41c2a4f0f",
+				"status": "running",
+			},
+		},
+		TypeName: "GoaOperation",
+	},
+	Identifier: OperationMediaIdentifier,
+	Views:      map[string]*ViewDefinition{"default": operationMediaView},
+	Hypermedia: NoHypermedia,
+}
+
+var (
+	operationMediaType = Object{
+		"id": &AttributeDefinition{
+			Type:        String,
+			Description: "ID of the operation",
+			Example:     "d9d8b6e41c2a4f0f",
+		},
+		"status": &AttributeDefinition{
+			Type:        String,
+			Description: "Status of the operation",
+			Validation: &dslengine.ValidationDefinition{
+				Values: []interface{}{"running", "succeeded", "failed", "canceled"},
+			},
+			Example: "running",
+		},
+		"result": &AttributeDefinition{
+			Type:        Any,
+			Description: "Result of the operation once it succeeded",
+			Example:     map[string]interface{}{"href": "/exports/1"},
+		},
+		"error": &AttributeDefinition{
+			Type:        String,
+			Description: "Error message of the operation once it failed",
+			Example:     "export failed",
+		},
+	}
+
+	operationMediaView = &ViewDefinition{
+		AttributeDefinition: &AttributeDefinition{Type: operationMediaType},
+		Name:                "default",
+	}
+)
+
+func init() {
+	operationMediaView.Parent = OperationMedia
+}
+
+// LongRunningDefinition describes an action that starts a long-running operation.
+type LongRunningDefinition struct {
+	// RetryAfter is the number of seconds clients should wait before requesting the status of
+	// the operation, sent in the Retry-After header.
+	RetryAfter int
+	// Parent is the long-running action.
+	Parent *ActionDefinition
+}
+
+// Context returns the generic definition name used in error messages.
+func (l *LongRunningDefinition) Context() string {
+	return fmt.Sprintf("long-running operation of %s", l.Parent.Context())
+}
+
+// StatusAction returns the action that retrieves the status of the operations started by the
+// long-running action.
+func (l *LongRunningDefinition) StatusAction() *ActionDefinition {
+	return l.Parent.Parent.Actions[ShowOperationAction]
+}
+
+// CancelAction returns the action that cancels the operations started by the long-running action.
+func (l *LongRunningDefinition) CancelAction() *ActionDefinition {
+	return l.Parent.Parent.Actions[CancelOperationAction]
+}
+
+// Validate checks that the retry delay is positive and that the resource defines the operation
+// actions.
+func (l *LongRunningDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if l.RetryAfter < 0 {
+		verr.Add(l, "retry delay must be positive, got %d", l.RetryAfter)
+	}
+	if s := l.StatusAction(); s == nil || len(s.Routes) == 0 {
+		verr.Add(l, "resource %#v does not define the %#v action", l.Parent.Parent.Name, ShowOperationAction)
+	}
+	if c := l.CancelAction(); c == nil || len(c.Routes) == 0 {
+		verr.Add(l, "resource %#v does not define the %#v action", l.Parent.Parent.Name, CancelOperationAction)
+	}
+	if _, ok := l.Parent.Responses[Accepted]; !ok {
+		verr.Add(l, "missing %s response", Accepted)
+	}
+	return verr.AsError()
+}
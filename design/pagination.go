@@ -0,0 +1,95 @@
+package design
+
+import (
+	"fmt"
+
+	"github.com/shogo82148/goa-v1/dslengine"
+)
+
+// PaginationStyle is the style used by an action to paginate a collection.
+type PaginationStyle string
+
+const (
+	// CursorPagination paginates a collection with an opaque cursor that identifies the first
+	// item of the page and a limit.
+	CursorPagination PaginationStyle = "cursor"
+	// OffsetPagination paginates a collection with the offset of the first item of the page
+	// and a limit.
+	OffsetPagination PaginationStyle = "offset"
+)
+
+const (
+	// LimitParam is the name of the query string parameter that sets the maximum number of
+	// items returned by a paginated action.
+	LimitParam = "limit"
+	// OffsetParam is the name of the query string parameter that sets the offset of the first
+	// item returned by an action that uses offset pagination.
+	OffsetParam = "offset"
+	// CursorParam is the name of the query string parameter that identifies the first item
+	// returned by an action that uses cursor pagination.
+	CursorParam = "cursor"
+)
+
+// PaginationDefinition describes how an action paginates the collection it returns.
+type PaginationDefinition struct {
+	// Style is the pagination style.
+	Style PaginationStyle
+	// DefaultLimit is the number of items returned when the request does not set the limit.
+	DefaultLimit int
+	// MaxLimit is the maximum number of items returned by a single request.
+	MaxLimit int
+}
+
+// Context returns the generic definition name used in error messages.
+func (p *PaginationDefinition) Context() string {
+	return fmt.Sprintf("%s pagination", p.Style)
+}
+
+// Params returns the query string parameters used to select a page: the limit and the offset or
+// the cursor depending on the pagination style.
+func (p *PaginationDefinition) Params() *AttributeDefinition {
+	maxLimit := float64(p.MaxLimit)
+	minLimit := float64(1)
+	params := Object{
+		LimitParam: &AttributeDefinition{
+			Type:         Integer,
+			Description:  "Maximum number of items to return",
+			DefaultValue: p.DefaultLimit,
+			Validation:   &dslengine.ValidationDefinition{Minimum: &minLimit, Maximum: &maxLimit},
+			Metadata:     GeneratedMetadataDefinition(),
+		},
+	}
+	switch p.Style {
+	case OffsetPagination:
+		minOffset := float64(0)
+		params[OffsetParam] = &AttributeDefinition{
+			Type:         Integer,
+			Description:  "Offset of the first item to return",
+			DefaultValue: 0,
+			Validation:   &dslengine.ValidationDefinition{Minimum: &minOffset},
+			Metadata:     GeneratedMetadataDefinition(),
+		}
+	case CursorPagination:
+		params[CursorParam] = &AttributeDefinition{
+			Type:        String,
+			Description: "Cursor identifying the first item to return, returned in the Link header of the previous page",
+			Metadata:    GeneratedMetadataDefinition(),
+		}
+	}
+	return &AttributeDefinition{Type: params}
+}
+
+// Validate checks that the pagination style is known and that the limits are consistent.
+func (p *PaginationDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if p.Style != CursorPagination && p.Style != OffsetPagination {
+		verr.Add(p, "invalid pagination style, must be %#v or %#v", string(CursorPagination), string(OffsetPagination))
+	}
+	if p.DefaultLimit < 1 {
+		verr.Add(p, "default limit must be greater than 0")
+	}
+	if p.MaxLimit < p.DefaultLimit {
+		verr.Add(p, "maximum limit %d is lower than default limit %d", p.MaxLimit, p.DefaultLimit)
+	}
+	return verr.AsError()
+}
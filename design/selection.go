@@ -0,0 +1,237 @@
+package design
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shogo82148/goa-v1/dslengine"
+)
+
+// ResponseSelector identifies a query string parameter that selects the parts of the response
+// rendered by an action.
+type ResponseSelector string
+
+const (
+	// ViewSelector selects the media type view used to render the response.
+	ViewSelector ResponseSelector = "view"
+	// FieldsSelector selects the attributes of the response media type rendered in the
+	// response.
+	FieldsSelector ResponseSelector = "fields"
+)
+
+const (
+	// ViewParam is the name of the query string parameter that selects the view used to
+	// render the response of a selectable action.
+	ViewParam = "view"
+	// FieldsParam is the name of the query string parameter that lists the attributes rendered
+	// in the response of a selectable action.
+	FieldsParam = "fields"
+)
+
+// SelectionDefinition describes how the requests of an action select the parts of the "OK"
+// response they get.
+type SelectionDefinition struct {
+	// View is true if the requests may select the response view.
+	View bool
+	// Fields is true if the requests may select the response attributes.
+	Fields bool
+	// Parent is the selectable action.
+	Parent *ActionDefinition
+}
+
+// Context returns the generic definition name used in error messages.
+func (s *SelectionDefinition) Context() string {
+	return fmt.Sprintf("response selection of %s", s.Parent.Context())
+}
+
+// Params returns the query string parameters used to select the response: the view and/or the
+// fields parameters.
+func (s *SelectionDefinition) Params() *AttributeDefinition {
+	params := Object{}
+	if s.View {
+		params[ViewParam] = &AttributeDefinition{
+			Type:         String,
+			Description:  "Name of the view used to render the response",
+			DefaultValue: DefaultView,
+			Metadata:     GeneratedMetadataDefinition(),
+		}
+	}
+	if s.Fields {
+		params[FieldsParam] = &AttributeDefinition{
+			Type:        String,
+			Description: "Comma separated list of the attributes to render, nested attributes are separated by dots (e.g. id,owner.name)",
+			Metadata:    GeneratedMetadataDefinition(),
+		}
+	}
+	return &AttributeDefinition{Type: params}
+}
+
+// MediaType returns the media type of the "OK" response of the action if any.
+func (s *SelectionDefinition) MediaType() *MediaTypeDefinition {
+	resp, ok := s.Parent.Responses["OK"]
+	if !ok {
+		return nil
+	}
+	if mt, ok := resp.Type.(*MediaTypeDefinition); ok {
+		return mt
+	}
+	return Design.MediaTypeWithIdentifier(resp.MediaType)
+}
+
+// ResponseView returns the name of the view used to render the "OK" response when requests do not
+// select one.
+func (s *SelectionDefinition) ResponseView() string {
+	if resp, ok := s.Parent.Responses["OK"]; ok && resp.ViewName != "" {
+		return resp.ViewName
+	}
+	return DefaultView
+}
+
+// Views returns the sorted names of the views that requests may select, the response view only if
+// requests may not select the view.
+func (s *SelectionDefinition) Views() []string {
+	mt := s.MediaType()
+	if mt == nil {
+		return nil
+	}
+	if !s.View {
+		return []string{s.ResponseView()}
+	}
+	views := make([]string, 0, len(mt.Views))
+	for name := range mt.Views {
+		views = append(views, name)
+	}
+	sort.Strings(views)
+	return views
+}
+
+// ViewFields returns the sorted paths of the attributes rendered by the given view of the response
+// media type. The paths of nested attributes are made of the names of the enclosing attributes
+// separated by dots.
+func (s *SelectionDefinition) ViewFields(view string) ([]string, error) {
+	mt := s.MediaType()
+	if mt == nil {
+		return nil, fmt.Errorf("%s does not define a media type", s.Parent.Context())
+	}
+	p, _, err := mt.Project(view)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	attributePaths(p.AttributeDefinition, "", make(map[string]bool), &paths)
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Finalize sets the allowed values of the view parameter to the names of the response media type
+// views.
+func (s *SelectionDefinition) Finalize() {
+	if !s.View || s.Parent.Params == nil {
+		return
+	}
+	param, ok := s.Parent.Params.Type.ToObject()[ViewParam]
+	if !ok || s.MediaType() == nil {
+		return
+	}
+	views := s.Views()
+	values := make([]interface{}, len(views))
+	for i, v := range views {
+		values[i] = v
+	}
+	if param.Validation == nil {
+		param.Validation = &dslengine.ValidationDefinition{}
+	}
+	param.Validation.Values = values
+}
+
+// Validate checks that the action is a GET action with an "OK" response described by a media type.
+// If requests may select the view it also checks that the views of the media type only render
+// attributes also rendered by the default view.
+func (s *SelectionDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if !s.View && !s.Fields {
+		verr.Add(s, "must select the view, the fields or both")
+	}
+	for _, r := range s.Parent.Routes {
+		if r.Verb != "GET" {
+			verr.Add(s, "route %s %s is not a GET route", r.Verb, r.FullPath())
+		}
+	}
+	resp, ok := s.Parent.Responses["OK"]
+	if !ok {
+		verr.Add(s, "missing OK response")
+		return verr.AsError()
+	}
+	if s.MediaType() == nil {
+		verr.Add(s, "OK response must be described by a media type")
+		return verr.AsError()
+	}
+	if s.View && resp.ViewName != "" {
+		verr.Add(s, "OK response view must not be set when the view is selected by requests")
+	}
+	if !s.View {
+		return verr.AsError()
+	}
+	def, err := s.ViewFields(DefaultView)
+	if err != nil {
+		verr.Add(s, "%s", err)
+		return verr.AsError()
+	}
+	rendered := make(map[string]bool, len(def))
+	for _, p := range def {
+		rendered[p] = true
+	}
+	for _, view := range s.Views() {
+		fields, err := s.ViewFields(view)
+		if err != nil {
+			verr.Add(s, "%s", err)
+			continue
+		}
+		for _, p := range fields {
+			if !rendered[p] {
+				verr.Add(s, "view %#v renders attribute %#v not rendered by the default view", view, p)
+			}
+		}
+	}
+	return verr.AsError()
+}
+
+// attributePaths appends the paths of the attributes of att prefixed with prefix to paths. seen
+// records the user types being walked to stop on recursive types.
+func attributePaths(att *AttributeDefinition, prefix string, seen map[string]bool, paths *[]string) {
+	t := att.Type
+	if a := t.ToArray(); a != nil {
+		attributePaths(a.ElemType, prefix, seen, paths)
+		return
+	}
+	if ut, ok := t.(*UserTypeDefinition); ok {
+		if seen[ut.TypeName] {
+			return
+		}
+		seen[ut.TypeName] = true
+		defer delete(seen, ut.TypeName)
+	} else if mt, ok := t.(*MediaTypeDefinition); ok {
+		if seen[mt.TypeName] {
+			return
+		}
+		seen[mt.TypeName] = true
+		defer delete(seen, mt.TypeName)
+	}
+	o := t.ToObject()
+	if o == nil || t.IsHash() {
+		return
+	}
+	names := make([]string, 0, len(o))
+	for n := range o {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		p := n
+		if prefix != "" {
+			p = prefix + "." + n
+		}
+		*paths = append(*paths, p)
+		attributePaths(o[n], p, seen, paths)
+	}
+}
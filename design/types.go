@@ -11,6 +11,7 @@ package design
 
 import (
 	"fmt"
+	"math/big"
 	"mime"
 	"reflect"
 	"sort"
@@ -135,6 +136,10 @@ type (
 		Views map[string]*ViewDefinition
 		// Resource this media type is the canonical representation for if any
 		Resource *ResourceDefinition
+		// Hypermedia is the format used to render the media type, defaults to the API format.
+		Hypermedia HypermediaFormat
+		// ResourceType is the JSON:API type of the resources described by the media type.
+		ResourceType string
 	}
 )
 
@@ -165,6 +170,14 @@ const (
 	MediaTypeKind
 	// FileKind represents a file.
 	FileKind
+	// DateKind represents a JSON string that is parsed as a goa.Date.
+	DateKind
+	// TimeOfDayKind represents a JSON string that is parsed as a goa.TimeOfDay.
+	TimeOfDayKind
+	// DurationKind represents a JSON string that is parsed as a goa.Duration.
+	DurationKind
+	// DecimalKind represents a JSON number that is parsed as a goa.Decimal.
+	DecimalKind
 )
 
 const (
@@ -193,6 +206,21 @@ const (
 
 	// File is the type for a file. This type can only be used in a multipart definition.
 	File = Primitive(FileKind)
+
+	// Date is the type for a JSON string parsed as a goa.Date.
+	// Date expects an RFC3339 full-date formatted value (e.g. "2006-01-02").
+	Date = Primitive(DateKind)
+
+	// TimeOfDay is the type for a JSON string parsed as a goa.TimeOfDay.
+	// TimeOfDay expects an RFC3339 partial-time formatted value (e.g. "15:04:05").
+	TimeOfDay = Primitive(TimeOfDayKind)
+
+	// Duration is the type for a JSON string parsed as a goa.Duration.
+	// Duration expects a value formatted using the Go duration syntax (e.g. "1h30m").
+	Duration = Primitive(DurationKind)
+
+	// Decimal is the type for an arbitrary precision JSON number parsed as a goa.Decimal.
+	Decimal = Primitive(DecimalKind)
 )
 
 // DataType implementation
@@ -209,8 +237,10 @@ func (p Primitive) Name() string {
 		return "integer"
 	case Number:
 		return "number"
-	case String, DateTime, UUID:
+	case String, DateTime, UUID, Date, TimeOfDay, Duration:
 		return "string"
+	case Decimal:
+		return "number"
 	case Any:
 		return "any"
 	case File:
@@ -247,7 +277,7 @@ func (p Primitive) ToHash() *Hash { return nil }
 // CanHaveDefault returns whether the primitive can have a default value.
 func (p Primitive) CanHaveDefault() (ok bool) {
 	switch p {
-	case Boolean, Integer, Number, String, DateTime:
+	case Boolean, Integer, Number, String, DateTime, Date, TimeOfDay, Duration, Decimal:
 		ok = true
 	}
 	return
@@ -255,7 +285,9 @@ func (p Primitive) CanHaveDefault() (ok bool) {
 
 // IsCompatible returns true if val is compatible with p.
 func (p Primitive) IsCompatible(val interface{}) bool {
-	if p != Boolean && p != Integer && p != Number && p != String && p != DateTime && p != UUID && p != Any {
+	switch p {
+	case Boolean, Integer, Number, String, DateTime, UUID, Any, Date, TimeOfDay, Duration, Decimal:
+	default:
 		panic("unknown primitive type") // bug
 	}
 	if p == Any {
@@ -265,9 +297,9 @@ func (p Primitive) IsCompatible(val interface{}) bool {
 	case bool:
 		return p == Boolean
 	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
-		return p == Integer || p == Number
+		return p == Integer || p == Number || p == Decimal
 	case float32, float64:
-		return p == Number
+		return p == Number || p == Decimal
 	case string:
 		if p == String {
 			return true
@@ -280,6 +312,22 @@ func (p Primitive) IsCompatible(val interface{}) bool {
 			_, err := uuid.FromString(val)
 			return err == nil
 		}
+		if p == Date {
+			_, err := time.Parse("2006-01-02", val)
+			return err == nil
+		}
+		if p == TimeOfDay {
+			_, err := time.Parse("15:04:05.999999999", val)
+			return err == nil
+		}
+		if p == Duration {
+			_, err := time.ParseDuration(val)
+			return err == nil
+		}
+		if p == Decimal {
+			_, ok := new(big.Rat).SetString(val)
+			return ok && !strings.ContainsRune(val, '/')
+		}
 	}
 	return false
 }
@@ -306,6 +354,14 @@ func (p Primitive) GenerateExample(r *RandomGenerator, seen []string) interface{
 		return anyPrimitive[r.Int()%len(anyPrimitive)].GenerateExample(r, seen)
 	case File:
 		return r.File()
+	case Date:
+		return r.DateTime().Format("2006-01-02")
+	case TimeOfDay:
+		return r.DateTime().Format("15:04:05")
+	case Duration:
+		return (time.Duration(r.Int()%86400) * time.Second).String()
+	case Decimal:
+		return fmt.Sprintf("%.2f", r.Float64())
 	default:
 		panic("unknown primitive type") // bug
 	}
@@ -853,13 +909,16 @@ func (m *MediaTypeDefinition) projectSingle(view, canonical string) (p *MediaTyp
 	desc += " (" + view + " view)"
 
 	p = &MediaTypeDefinition{
-		Identifier: m.projectIdentifier(view),
+		Identifier:   m.projectIdentifier(view),
+		Hypermedia:   m.HypermediaFormat(),
+		ResourceType: m.JSONAPIType(),
 		UserTypeDefinition: &UserTypeDefinition{
 			TypeName: m.projectTypeName(view),
 			AttributeDefinition: &AttributeDefinition{
 				Description: desc,
 				Type:        Dup(v.Type),
 				Validation:  val,
+				Deprecation: m.Deprecation,
 			},
 		},
 	}
@@ -942,6 +1001,7 @@ func (m *MediaTypeDefinition) projectCollection(view string) (*MediaTypeDefiniti
 	desc := m.TypeName + " is the media type for an array of " + e.TypeName + " (" + view + " view)"
 	p := &MediaTypeDefinition{
 		Identifier: m.projectIdentifier(view),
+		Hypermedia: pe.Hypermedia,
 		UserTypeDefinition: &UserTypeDefinition{
 			AttributeDefinition: &AttributeDefinition{
 				Description: desc,
@@ -1080,7 +1140,7 @@ func toReflectType(dtype DataType) reflect.Type {
 		return reflect.TypeOf(int(0))
 	case NumberKind:
 		return reflect.TypeOf(float64(0))
-	case UUIDKind, StringKind:
+	case UUIDKind, StringKind, DateKind, TimeOfDayKind, DurationKind, DecimalKind:
 		return reflect.TypeOf("")
 	case DateTimeKind:
 		return reflect.TypeOf(time.Time{})
@@ -602,4 +602,39 @@ var _ = Describe("GenerateExample", func() {
 			Ω(h.GenerateExample(rand, nil)).Should(BeAssignableToTypeOf(map[string]string{"foo": "bar"}))
 		})
 	})
+
+	Context("Given a date, time of day, duration or decimal", func() {
+		It("generates compatible examples", func() {
+			rand := design.NewRandomGenerator("foo")
+			for _, p := range []design.Primitive{design.Date, design.TimeOfDay, design.Duration, design.Decimal} {
+				ex := p.GenerateExample(rand, nil)
+				Ω(ex).Should(BeAssignableToTypeOf("foo"))
+				Ω(p.IsCompatible(ex)).Should(BeTrue())
+			}
+		})
+	})
+})
+
+var _ = Describe("IsCompatible", func() {
+	It("validates date values", func() {
+		Ω(design.Date.IsCompatible("2016-02-29")).Should(BeTrue())
+		Ω(design.Date.IsCompatible("2016-02-29T10:00:00Z")).Should(BeFalse())
+	})
+
+	It("validates time of day values", func() {
+		Ω(design.TimeOfDay.IsCompatible("23:59:59.25")).Should(BeTrue())
+		Ω(design.TimeOfDay.IsCompatible("24:00")).Should(BeFalse())
+	})
+
+	It("validates duration values", func() {
+		Ω(design.Duration.IsCompatible("1h30m")).Should(BeTrue())
+		Ω(design.Duration.IsCompatible("forever")).Should(BeFalse())
+	})
+
+	It("validates decimal values", func() {
+		Ω(design.Decimal.IsCompatible("10.25")).Should(BeTrue())
+		Ω(design.Decimal.IsCompatible(10.25)).Should(BeTrue())
+		Ω(design.Decimal.IsCompatible(10)).Should(BeTrue())
+		Ω(design.Decimal.IsCompatible("1/3")).Should(BeFalse())
+	})
 })
@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"sort"
 	"strings"
@@ -14,6 +15,10 @@ import (
 	"github.com/shogo82148/goa-v1/dslengine"
 )
 
+// versionNameRegex matches valid API version names. Version names are used as directory names by
+// the code generators.
+var versionNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
 type routeInfo struct {
 	Key       string
 	Resource  *ResourceDefinition
@@ -85,6 +90,15 @@ func (a *APIDefinition) Validate() error {
 	a.validateLicense(verr)
 	a.validateDocs(verr)
 	a.validateOrigins(verr)
+	a.validateVersions(verr)
+	a.validateEnumTypes(verr)
+	if a.Hypermedia != "" && !a.Hypermedia.IsValid() {
+		verr.Add(a, "invalid hypermedia format %#v, must be %#v, %#v or %#v", string(a.Hypermedia), NoHypermedia, HALHypermedia, JSONAPIHypermedia)
+	}
+	iterateWebhooks(a.Webhooks, func(w *WebhookDefinition) error {
+		verr.Merge(w.Validate())
+		return nil
+	})
 
 	var allRoutes []*routeInfo
 	a.IterateResources(func(r *ResourceDefinition) error {
@@ -208,6 +222,102 @@ func (a *APIDefinition) validateOrigins(verr *dslengine.ValidationErrors) {
 	}
 }
 
+func (a *APIDefinition) validateVersions(verr *dslengine.ValidationErrors) {
+	var selector *APIVersionDefinition
+	for i, v := range a.APIVersions {
+		verr.Merge(v.Validate())
+		for _, other := range a.APIVersions[:i] {
+			if other.Name == v.Name {
+				verr.Add(v, "duplicate API version")
+			}
+		}
+		if v.BasePath != "" {
+			continue
+		}
+		// Versions that are not selected by path share the same routes.
+		if selector == nil {
+			selector = v
+		} else if v.Header != selector.Header || v.MediaTypeParam != selector.MediaTypeParam {
+			verr.Add(v, "version must be selected with the same header or media type parameter as %s", selector.Context())
+		}
+	}
+}
+
+// validateEnumTypes checks that the attributes sharing the same "struct:enum" metadata value define
+// the same enum: the code generators produce a single Go type per name.
+func (a *APIDefinition) validateEnumTypes(verr *dslengine.ValidationErrors) {
+	type enumDef struct {
+		att *AttributeDefinition
+		ctx string
+	}
+	var (
+		defs = make(map[string]*enumDef)
+		seen = make(map[DataType]bool)
+		walk func(att *AttributeDefinition, ctx string)
+	)
+	walk = func(att *AttributeDefinition, ctx string) {
+		if att == nil || att.Type == nil {
+			return
+		}
+		if meta := att.Metadata["struct:enum"]; len(meta) > 0 && meta[0] != "" && att.Validation != nil && len(att.Validation.Values) > 0 {
+			name := meta[0]
+			if def, ok := defs[name]; !ok {
+				defs[name] = &enumDef{att: att, ctx: ctx}
+			} else if def.att.Type.Kind() != att.Type.Kind() || !reflect.DeepEqual(def.att.Validation.Values, att.Validation.Values) {
+				verr.Add(a, "enum type %#v of %s is defined differently by %s", name, ctx, def.ctx)
+			}
+		}
+		switch actual := att.Type.(type) {
+		case *UserTypeDefinition:
+			if !seen[actual] {
+				seen[actual] = true
+				walk(actual.AttributeDefinition, actual.Context())
+			}
+		case *MediaTypeDefinition:
+			if !seen[actual] {
+				seen[actual] = true
+				walk(actual.AttributeDefinition, actual.Context())
+			}
+		case Object:
+			names := make([]string, 0, len(actual))
+			for n := range actual {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			for _, n := range names {
+				walk(actual[n], fmt.Sprintf("field %s of %s", n, ctx))
+			}
+		case *Array:
+			walk(actual.ElemType, ctx)
+		case *Hash:
+			walk(actual.KeyType, ctx)
+			walk(actual.ElemType, ctx)
+		}
+	}
+	a.IterateUserTypes(func(ut *UserTypeDefinition) error {
+		walk(&AttributeDefinition{Type: ut}, ut.Context())
+		return nil
+	})
+	a.IterateMediaTypes(func(mt *MediaTypeDefinition) error {
+		walk(&AttributeDefinition{Type: mt}, mt.Context())
+		return nil
+	})
+	a.IterateResources(func(r *ResourceDefinition) error {
+		return r.IterateActions(func(ac *ActionDefinition) error {
+			if ac.Payload != nil {
+				walk(&AttributeDefinition{Type: ac.Payload}, ac.Context()+" payload")
+			}
+			return nil
+		})
+	})
+	a.IterateWebhooks(func(w *WebhookDefinition) error {
+		if w.Payload != nil {
+			walk(&AttributeDefinition{Type: w.Payload}, w.Context()+" payload")
+		}
+		return nil
+	})
+}
+
 // Validate tests whether the resource definition is consistent: action names are valid and each action is
 // valid.
 func (r *ResourceDefinition) Validate() *dslengine.ValidationErrors {
@@ -228,6 +338,7 @@ func (r *ResourceDefinition) Validate() *dslengine.ValidationErrors {
 	for _, origin := range r.Origins {
 		verr.Merge(origin.Validate())
 	}
+	validateVersionNames(r, r.Versions, verr)
 	return verr.AsError()
 }
 
@@ -319,6 +430,55 @@ func (enc *EncodingDefinition) Validate() *dslengine.ValidationErrors {
 	return verr
 }
 
+// Validate checks that the version has a valid name and is selected by exactly one of a base path,
+// a header or a media type parameter.
+func (v *APIVersionDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if !versionNameRegex.MatchString(v.Name) {
+		verr.Add(v, "invalid version name, must only contain letters, digits, dots, dashes and underscores")
+	}
+	selectors := 0
+	for _, s := range []string{v.BasePath, v.Header, v.MediaTypeParam} {
+		if s != "" {
+			selectors++
+		}
+	}
+	if selectors != 1 {
+		verr.Add(v, "version must be selected by exactly one of BasePath, VersionHeader or VersionParam")
+	}
+	if v.BasePath != "" && len(ExtractWildcards(v.BasePath)) > 0 {
+		verr.Add(v, "version base path cannot contain wildcards")
+	}
+	return verr.AsError()
+}
+
+// validateVersionNames checks that the given version names correspond to API versions.
+func validateVersionNames(def dslengine.Definition, versions []string, verr *dslengine.ValidationErrors) {
+	for _, name := range versions {
+		if Design.VersionDefinition(name) == nil {
+			verr.Add(def, "unknown API version %#v", name)
+		}
+	}
+}
+
+// Validate checks that the deprecation and sunset dates are valid RFC3339 dates and that the
+// sunset date does not precede the deprecation date.
+func (d *DeprecationDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	since, err := parseDeprecationTime(d.Since)
+	if err != nil {
+		verr.Add(d, "invalid deprecation date %#v, must be a RFC3339 date or date-time", d.Since)
+	}
+	sunset, err := parseDeprecationTime(d.Sunset)
+	if err != nil {
+		verr.Add(d, "invalid sunset date %#v, must be a RFC3339 date or date-time", d.Sunset)
+	}
+	if !since.IsZero() && !sunset.IsZero() && sunset.Before(since) {
+		verr.Add(d, "sunset date %s precedes deprecation date %s", d.Sunset, d.Since)
+	}
+	return verr.AsError()
+}
+
 // Validate tests whether the action definition is consistent: parameters have unique names and it has at least
 // one response.
 func (a *ActionDefinition) Validate() *dslengine.ValidationErrors {
@@ -350,6 +510,35 @@ func (a *ActionDefinition) Validate() *dslengine.ValidationErrors {
 	if a.Parent == nil {
 		verr.Add(a, "missing parent resource")
 	}
+	if a.Deprecation != nil {
+		verr.Merge(a.Deprecation.Validate())
+	}
+	validateVersionNames(a, a.Versions, verr)
+	if a.Events != nil {
+		verr.Merge(a.Events.Validate())
+	}
+	if a.Messages != nil {
+		verr.Merge(a.Messages.Validate())
+	}
+	iterateWebhooks(a.Webhooks, func(w *WebhookDefinition) error {
+		verr.Merge(w.Validate())
+		return nil
+	})
+	if a.LongRunning != nil {
+		verr.Merge(a.LongRunning.Validate())
+	}
+	if a.Selection != nil {
+		verr.Merge(a.Selection.Validate())
+	}
+	if a.Criteria != nil {
+		verr.Merge(a.Criteria.Validate())
+	}
+	if a.Pagination != nil {
+		verr.Merge(a.Pagination.Validate())
+		if a.Payload != nil {
+			verr.Add(a, "paginated actions cannot have a payload")
+		}
+	}
 	if a.Params != nil {
 		for n, p := range a.Params.Type.ToObject() {
 			if p.Type.IsPrimitive() {
@@ -466,6 +655,17 @@ func (a *AttributeDefinition) Validate(ctx string, parent dslengine.Definition)
 	if ctx != "" {
 		ctx += " - "
 	}
+	if a.Deprecation != nil {
+		verr.Merge(a.Deprecation.Validate())
+	}
+	// The Go types of these primitives cannot be compared with the enum values by the generated
+	// validation code.
+	if a.Validation != nil && len(a.Validation.Values) > 0 {
+		switch a.Type.Kind() {
+		case DateKind, TimeOfDayKind, DurationKind, DecimalKind:
+			verr.Add(parent, "%senum validation is not supported on Date, TimeOfDay, Duration and Decimal attributes", ctx)
+		}
+	}
 	// If both Default and Enum are given, make sure the Default value is one of Enum values.
 	// TODO: We only do the default value and enum check just for primitive types.
 	// Issue 388 (https://github.com/shogo82148/goa-v1/issues/388) will address this for other types.
@@ -519,6 +719,11 @@ func (r *ResponseDefinition) Validate() *dslengine.ValidationErrors {
 	if r.Status == 0 {
 		verr.Add(r, "response status not defined")
 	}
+	if r.Stream {
+		if mt := Design.MediaTypeWithIdentifier(r.MediaType); mt == nil || !mt.IsArray() {
+			verr.Add(r, "streamed response media type %#v is not a collection", r.MediaType)
+		}
+	}
 	return verr.AsError()
 }
 
@@ -594,6 +799,7 @@ func (m *MediaTypeDefinition) Validate() *dslengine.ValidationErrors {
 	for _, l := range m.Links {
 		verr.Merge(l.Validate())
 	}
+	m.validateHypermedia(verr)
 	return verr.AsError()
 }
 
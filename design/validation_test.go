@@ -82,6 +82,70 @@ var _ = Describe("Validation", func() {
 			})
 		})
 
+		Context("with an enum validation on a Date attribute", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					apidsl.Attribute(attName, design.Date, func() {
+						apidsl.Enum("2024-01-01", "2024-12-31")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(Equal(
+					`type "bar": field attName - enum validation is not supported on Date, TimeOfDay, Duration and Decimal attributes`))
+			})
+		})
+
+		Context("with an enum validation on a TimeOfDay attribute", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					apidsl.Attribute(attName, design.TimeOfDay, func() {
+						apidsl.Enum("09:00:00", "17:00:00")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(Equal(
+					`type "bar": field attName - enum validation is not supported on Date, TimeOfDay, Duration and Decimal attributes`))
+			})
+		})
+
+		Context("with an enum validation on a Duration attribute", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					apidsl.Attribute(attName, design.Duration, func() {
+						apidsl.Enum("1h", "2h")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(Equal(
+					`type "bar": field attName - enum validation is not supported on Date, TimeOfDay, Duration and Decimal attributes`))
+			})
+		})
+
+		Context("with an enum validation on a Decimal attribute", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					apidsl.Attribute(attName, design.Decimal, func() {
+						apidsl.Enum("1.5", "2.5")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(Equal(
+					`type "bar": field attName - enum validation is not supported on Date, TimeOfDay, Duration and Decimal attributes`))
+			})
+		})
+
 		Context("with a valid format validation", func() {
 			BeforeEach(func() {
 				dsl = func() {
@@ -311,6 +375,49 @@ var _ = Describe("Validation", func() {
 		})
 	})
 
+	Context("with attributes sharing an enum type", func() {
+		var values []interface{}
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			apidsl.Type("Paint", func() {
+				apidsl.Attribute("color", design.String, func() {
+					apidsl.Enum("red", "green")
+					apidsl.Metadata("struct:enum", "Color")
+				})
+			})
+			apidsl.Type("Light", func() {
+				apidsl.Attribute("color", design.String, func() {
+					apidsl.Enum(values...)
+					apidsl.Metadata("struct:enum", "Color")
+				})
+			})
+			dslengine.Run()
+		})
+
+		Context("with the same values", func() {
+			BeforeEach(func() {
+				values = []interface{}{"red", "green"}
+			})
+
+			It("is valid", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with different values", func() {
+			BeforeEach(func() {
+				values = []interface{}{"red", "blue"}
+			})
+
+			It("reports both definitions", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(
+					`enum type "Color" of field color of type "Paint" is defined differently by field color of type "Light"`))
+			})
+		})
+	})
+
 	Context("with an action", func() {
 		var dsl func()
 
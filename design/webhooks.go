@@ -0,0 +1,127 @@
+package design
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shogo82148/goa-v1/dslengine"
+)
+
+type (
+	// WebhookDefinition describes a HTTP request sent by the API to a URL provided by a third
+	// party, for example to notify it that a resource changed. Webhooks are defined on the
+	// API or on the action that triggers them.
+	WebhookDefinition struct {
+		// Name is the webhook name, e.g. "order.created".
+		Name string
+		// Description of webhook
+		Description string
+		// Payload is the type of the webhook request body.
+		Payload *UserTypeDefinition
+		// Headers describes the webhook request headers if any.
+		Headers *AttributeDefinition
+		// Parent is the API or the action that defines the webhook.
+		Parent dslengine.Definition
+	}
+
+	// WebhookIterator is the type of functions given to IterateWebhooks.
+	WebhookIterator func(w *WebhookDefinition) error
+)
+
+// Context returns the generic definition name used in error messages.
+func (w *WebhookDefinition) Context() string {
+	var prefix, suffix string
+	if w.Name != "" {
+		prefix = fmt.Sprintf("webhook %#v", w.Name)
+	} else {
+		prefix = "unnamed webhook"
+	}
+	if a, ok := w.Parent.(*ActionDefinition); ok {
+		suffix = fmt.Sprintf(" of %s", a.Context())
+	}
+	return prefix + suffix
+}
+
+// Action returns the action that triggers the webhook, nil if the webhook is defined on the API.
+func (w *WebhookDefinition) Action() *ActionDefinition {
+	a, _ := w.Parent.(*ActionDefinition)
+	return a
+}
+
+// IterateHeaders calls the given iterator passing in each webhook header sorted in alphabetical
+// order. Iteration stops if an iterator returns an error and in this case IterateHeaders returns
+// that error.
+func (w *WebhookDefinition) IterateHeaders(it HeaderIterator) error {
+	if w.Headers == nil {
+		return nil
+	}
+	headers := w.Headers.Type.ToObject()
+	names := make([]string, 0, len(headers))
+	for n := range headers {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if err := it(n, w.Headers.IsRequired(n), headers[n]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks that the webhook has a name and an object payload and that its headers are
+// valid primitives.
+func (w *WebhookDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if w.Name == "" {
+		verr.Add(w, "webhook name cannot be empty")
+	}
+	if w.Payload == nil {
+		verr.Add(w, "webhook payload not defined")
+	} else {
+		if !w.Payload.IsObject() {
+			verr.Add(w, "webhook payload must be an object")
+		}
+		verr.Merge(w.Payload.Validate("webhook payload", w))
+	}
+	if w.Headers != nil {
+		verr.Merge(w.Headers.Validate("webhook headers", w))
+		w.IterateHeaders(func(name string, _ bool, h *AttributeDefinition) error {
+			if !h.Type.IsPrimitive() {
+				verr.Add(w, "webhook header %s must be a primitive", name)
+			}
+			return nil
+		})
+	}
+	return verr.AsError()
+}
+
+// IterateWebhooks calls the given iterator passing in each webhook defined on the API sorted by
+// name followed by the webhooks defined on the actions sorted by resource, action and name.
+// Iteration stops if an iterator returns an error and in this case IterateWebhooks returns that
+// error.
+func (a *APIDefinition) IterateWebhooks(it WebhookIterator) error {
+	if err := iterateWebhooks(a.Webhooks, it); err != nil {
+		return err
+	}
+	return a.IterateResources(func(r *ResourceDefinition) error {
+		return r.IterateActions(func(act *ActionDefinition) error {
+			return iterateWebhooks(act.Webhooks, it)
+		})
+	})
+}
+
+// iterateWebhooks calls it with each webhook of webhooks sorted by name.
+func iterateWebhooks(webhooks map[string]*WebhookDefinition, it WebhookIterator) error {
+	names := make([]string, 0, len(webhooks))
+	for n := range webhooks {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if err := it(webhooks[n]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -21,6 +21,9 @@ var (
 
 	// DSL package paths used to compute error locations (skip the frames in these packages)
 	dslPackages map[string]bool
+
+	// Locations of the DSL functions that initialized the definitions indexed by definition
+	locations map[Definition]*location
 )
 
 type (
@@ -38,6 +41,12 @@ type (
 
 	// DSL evaluation contexts stack
 	contextStack []Definition
+
+	// location is a position in the user DSL code.
+	location struct {
+		file string
+		line int
+	}
 )
 
 func init() {
@@ -72,6 +81,7 @@ func Reset() {
 		r.Reset()
 	}
 	Errors = nil
+	locations = nil
 }
 
 // Run runs the given root definitions. It iterates over the definition sets
@@ -127,6 +137,7 @@ func Execute(dsl func(), def Definition) bool {
 	if dsl == nil {
 		return true
 	}
+	recordLocation(dsl, def)
 	initCount := len(Errors)
 	ctxStack = append(ctxStack, def)
 	dsl()
@@ -134,6 +145,52 @@ func Execute(dsl func(), def Definition) bool {
 	return len(Errors) <= initCount
 }
 
+// Location returns the name of the file and the line number of the DSL function that initialized
+// the given definition. It returns an empty string and 0 if the definition was not initialized
+// with a DSL function defined in user code, e.g. attributes defined without DSL.
+func Location(def Definition) (file string, line int) {
+	if loc, ok := locations[def]; ok {
+		return loc.file, loc.line
+	}
+	return "", 0
+}
+
+// recordLocation records the location of the given DSL function if it is the first DSL
+// function executed to initialize the definition and it is defined in user code.
+func recordLocation(dsl func(), def Definition) {
+	if reflect.TypeOf(def).Kind() != reflect.Ptr {
+		return
+	}
+	if _, ok := locations[def]; ok {
+		return
+	}
+	file, line := FuncLocation(dsl)
+	if file == "" {
+		return
+	}
+	if locations == nil {
+		locations = make(map[Definition]*location)
+	}
+	locations[def] = &location{file: file, line: line}
+}
+
+// FuncLocation returns the name of the file and the line number where the given DSL function is
+// defined. It returns an empty string and 0 if the function is nil or is not defined in user code.
+func FuncLocation(dsl func()) (file string, line int) {
+	if dsl == nil {
+		return "", 0
+	}
+	fn := runtime.FuncForPC(reflect.ValueOf(dsl).Pointer())
+	if fn == nil {
+		return "", 0
+	}
+	file, line = fn.FileLine(fn.Entry())
+	if isDSLFile(file) {
+		return "", 0
+	}
+	return relativePath(file), line
+}
+
 // CurrentDefinition returns the definition whose initialization DSL is currently being executed.
 func CurrentDefinition() Definition {
 	current := ctxStack.Current()
@@ -250,38 +307,46 @@ func (s contextStack) Current() Definition {
 // When successful it returns the file name and line number, empty string and
 // 0 otherwise.
 func computeErrorLocation() (file string, line int) {
-	skipFunc := func(file string) bool {
-		if strings.HasSuffix(file, "_test.go") { // Be nice with tests
-			return false
-		}
-		file = filepath.ToSlash(file)
-		for pkg := range dslPackages {
-			if strings.Contains(file, pkg) {
-				return true
-			}
-		}
-		return false
-	}
 	depth := 2
 	_, file, line, _ = runtime.Caller(depth)
-	for skipFunc(file) {
+	for isDSLFile(file) {
 		depth++
 		_, file, line, _ = runtime.Caller(depth)
 	}
+	file = relativePath(file)
+	return
+}
+
+// isDSLFile returns true if the given file belongs to one of the DSL packages.
+func isDSLFile(file string) bool {
+	if strings.HasSuffix(file, "_test.go") { // Be nice with tests
+		return false
+	}
+	file = filepath.ToSlash(file)
+	for pkg := range dslPackages {
+		if strings.Contains(file, pkg) {
+			return true
+		}
+	}
+	return false
+}
+
+// relativePath returns the path of the given file relative to the working directory if
+// possible, the file path unchanged otherwise.
+func relativePath(file string) string {
 	wd, err := os.Getwd()
 	if err != nil {
-		return
+		return file
 	}
 	wd, err = filepath.Abs(wd)
 	if err != nil {
-		return
+		return file
 	}
 	f, err := filepath.Rel(wd, file)
 	if err != nil {
-		return
+		return file
 	}
-	file = f
-	return
+	return f
 }
 
 // runSet executes the DSL for all definitions in the given set. The definition DSLs may append to
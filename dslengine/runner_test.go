@@ -165,3 +165,38 @@ var _ = Describe("DSL errors", func() {
 		})
 	})
 })
+
+var _ = Describe("DSL locations", func() {
+	var resource *design.ResourceDefinition
+
+	// See NOTE below.
+	const lineNumber = 180
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		apidsl.API("foo", func() {})
+		// NOTE: moving the line below requires updating the
+		// constant above to match its number.
+		resource = apidsl.Resource("bar", func() {
+			apidsl.Action("baz", func() {
+				apidsl.Routing(apidsl.GET("/"))
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("records the location of the DSL functions", func() {
+		file, line := dslengine.Location(resource)
+		Ω(file).Should(HaveSuffix("runner_test.go"))
+		Ω(line).Should(Equal(lineNumber))
+		file, line = dslengine.Location(resource.Actions["baz"])
+		Ω(file).Should(HaveSuffix("runner_test.go"))
+		Ω(line).Should(Equal(lineNumber + 1))
+	})
+
+	It("returns no location for definitions without DSL", func() {
+		file, line := dslengine.Location(&design.AttributeDefinition{})
+		Ω(file).Should(BeEmpty())
+		Ω(line).Should(BeZero())
+	})
+})
@@ -186,6 +186,25 @@ func (p *decoderPool) Put(d Decoder) {
 // using the given writer.
 func (encoder *HTTPEncoder) Encode(v interface{}, resp io.Writer, accept string) error {
 	now := time.Now()
+	contentType, p, err := encoder.pool(accept)
+	defer MeasureSince([]string{"goa", "encode", contentType}, now)
+	if err != nil {
+		return err
+	}
+
+	// the encoderPool will handle whether or not a pool is actually in use
+	e := p.Get(resp)
+	if err := e.Encode(v); err != nil {
+		return err
+	}
+	p.Put(e)
+
+	return nil
+}
+
+// pool returns the content type negotiated for the given Accept header value and the pool of the
+// corresponding encoder.
+func (encoder *HTTPEncoder) pool(accept string) (string, *encoderPool, error) {
 	if accept == "" {
 		accept = "*/*"
 	}
@@ -196,23 +215,14 @@ func (encoder *HTTPEncoder) Encode(v interface{}, resp io.Writer, accept string)
 			break
 		}
 	}
-	defer MeasureSince([]string{"goa", "encode", contentType}, now)
 	p := encoder.pools[contentType]
 	if p == nil && contentType != "*/*" {
 		p = encoder.pools["*/*"]
 	}
 	if p == nil {
-		return fmt.Errorf("No encoder registered for %s and no default encoder", contentType)
-	}
-
-	// the encoderPool will handle whether or not a pool is actually in use
-	e := p.Get(resp)
-	if err := e.Encode(v); err != nil {
-		return err
+		return contentType, nil, fmt.Errorf("No encoder registered for %s and no default encoder", contentType)
 	}
-	p.Put(e)
-
-	return nil
+	return contentType, p, nil
 }
 
 // Register sets a specific encoder to be used for the specified content types. If an encoder is
@@ -18,6 +18,10 @@ The built-in encoder and decoder media types are:
 	- application/msgpack and application/x-msgpack
 	- application/binc and application/x-binc
 	- application/cbor and application/x-cbor
+	- application/hal+json and application/vnd.api+json
+
+The HAL and JSON:API encoders and decoders are registered automatically when the design uses the
+Hypermedia DSL to render media types as HAL or JSON:API documents.
 
 External encoders and decoders can also be specified via the DSL:
 
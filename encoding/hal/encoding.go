@@ -0,0 +1,66 @@
+package hal
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+
+	"github.com/shogo82148/goa-v1"
+)
+
+type (
+	// encoder renders the values that implement goa.HALMarshaler as HAL documents.
+	encoder struct {
+		enc *json.Encoder
+	}
+
+	// decoder converts HAL documents into the values of the rendered media types.
+	decoder struct {
+		dec *json.Decoder
+	}
+)
+
+// NewEncoder returns a HAL encoder. The encoder renders the values that implement
+// goa.HALMarshaler as HAL documents and the other values as JSON.
+func NewEncoder(w io.Writer) goa.Encoder {
+	return &encoder{enc: json.NewEncoder(w)}
+}
+
+// NewDecoder returns a HAL decoder. The decoder reverts the changes made by
+// goa.HypermediaSchemas.MarshalHAL so that HAL documents decode into the types generated for the
+// rendered media types.
+func NewDecoder(r io.Reader) goa.Decoder {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	return &decoder{dec: dec}
+}
+
+// Encode writes the HAL document rendering v.
+func (e *encoder) Encode(v interface{}) error {
+	if m, ok := v.(goa.HALMarshaler); ok {
+		doc, err := m.MarshalHAL()
+		if err != nil {
+			return err
+		}
+		v = doc
+	}
+	return e.enc.Encode(v)
+}
+
+// Decode reads the next HAL document and stores the rendered value in v.
+func (d *decoder) Decode(v interface{}) error {
+	var doc interface{}
+	if err := d.dec.Decode(&doc); err != nil {
+		return err
+	}
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	collection := t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array)
+	b, err := json.Marshal(goa.UnmarshalHAL(doc, collection))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,68 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+
+	"github.com/shogo82148/goa-v1"
+)
+
+type (
+	// encoder renders the values that implement goa.JSONAPIMarshaler as JSON:API documents.
+	encoder struct {
+		enc *json.Encoder
+	}
+
+	// decoder converts JSON:API documents into the values of the rendered media types.
+	decoder struct {
+		dec *json.Decoder
+	}
+)
+
+// NewEncoder returns a JSON:API encoder. The encoder renders the values that implement
+// goa.JSONAPIMarshaler as JSON:API documents and the other values as JSON.
+func NewEncoder(w io.Writer) goa.Encoder {
+	return &encoder{enc: json.NewEncoder(w)}
+}
+
+// NewDecoder returns a JSON:API decoder. The decoder reverts the changes made by
+// goa.HypermediaSchemas.MarshalJSONAPI so that JSON:API documents decode into the types generated
+// for the rendered media types.
+func NewDecoder(r io.Reader) goa.Decoder {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	return &decoder{dec: dec}
+}
+
+// Encode writes the JSON:API document rendering v.
+func (e *encoder) Encode(v interface{}) error {
+	if m, ok := v.(goa.JSONAPIMarshaler); ok {
+		doc, err := m.MarshalJSONAPI()
+		if err != nil {
+			return err
+		}
+		v = doc
+	}
+	return e.enc.Encode(v)
+}
+
+// Decode reads the next JSON:API document and stores the rendered value in v.
+func (d *decoder) Decode(v interface{}) error {
+	var doc interface{}
+	if err := d.dec.Decode(&doc); err != nil {
+		return err
+	}
+	val, err := goa.UnmarshalJSONAPI(doc)
+	if err != nil {
+		return err
+	}
+	if t := reflect.TypeOf(v); t != nil {
+		val = goa.RestoreIDs(val, t)
+	}
+	b, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
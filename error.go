@@ -209,6 +209,13 @@ func InvalidEnumValueError(ctx string, val interface{}, allowed []interface{}) e
 	return ErrInvalidRequest(msg, "attribute", ctx, "value", val, "expected", strings.Join(elems, ", "))
 }
 
+// UnknownFieldError is the error produced when the value of a parameter lists an attribute that
+// the response does not render.
+func UnknownFieldError(ctx, field string) error {
+	msg := fmt.Sprintf("%s lists unknown attribute %#v", ctx, field)
+	return ErrInvalidRequest(msg, "attribute", ctx, "value", field)
+}
+
 // InvalidFormatError is the error produced when the value of a parameter or payload field does not
 // match the format validation defined in the design.
 func InvalidFormatError(ctx, target string, format Format, formatError error) error {
@@ -0,0 +1,128 @@
+package goa
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventStream writes Server-Sent Events to the response of a request. Streams are created by the
+// generated contexts of actions that declare events.
+type EventStream struct {
+	resp *ResponseData
+	req  *RequestData
+	rc   *http.ResponseController
+
+	mu     sync.Mutex
+	closed bool
+	done   chan struct{}
+}
+
+// NewEventStream writes the headers of a Server-Sent Events response and returns a stream that
+// writes events to the response. If keepAlive is greater than zero the stream sends a comment every
+// keepAlive so that idle connections are not closed by proxies. The keepalive comments stop when the
+// client disconnects or when Close is called, Close should be called before the action returns.
+func NewEventStream(ctx context.Context, keepAlive time.Duration) (*EventStream, error) {
+	resp, req := ContextResponse(ctx), ContextRequest(ctx)
+	if resp == nil || req == nil {
+		return nil, fmt.Errorf("no request or response data in context")
+	}
+	s := &EventStream{
+		resp: resp,
+		req:  req,
+		rc:   http.NewResponseController(resp.ResponseWriter),
+		done: make(chan struct{}),
+	}
+	h := resp.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no")
+	resp.WriteHeader(http.StatusOK)
+	if err := s.rc.Flush(); err != nil {
+		return nil, fmt.Errorf("event streams are not supported by the response writer: %s", err)
+	}
+	if keepAlive > 0 {
+		go s.keepAlive(keepAlive)
+	}
+	return s, nil
+}
+
+// LastEventID returns the ID of the last event received by the client before it reconnected, the
+// empty string if the client did not send the Last-Event-ID header.
+func (s *EventStream) LastEventID() string {
+	return s.req.Header.Get("Last-Event-ID")
+}
+
+// Send encodes data with the service encoder and writes it to the stream together with the event
+// ID and type if not empty. The response is flushed once the event is written.
+func (s *EventStream) Send(id, event string, data interface{}) error {
+	var buf bytes.Buffer
+	if err := s.resp.Service.Encoder.Encode(data, &buf, ""); err != nil {
+		return err
+	}
+	var msg strings.Builder
+	if id != "" {
+		msg.WriteString("id: " + eventField(id) + "\n")
+	}
+	if event != "" {
+		msg.WriteString("event: " + eventField(event) + "\n")
+	}
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\r\n"), "\n") {
+		msg.WriteString("data: " + strings.TrimSuffix(line, "\r") + "\n")
+	}
+	msg.WriteString("\n")
+	return s.write(msg.String())
+}
+
+// Close stops the keepalive comments. The stream cannot be used anymore once closed.
+func (s *EventStream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.done)
+	}
+}
+
+// write writes msg to the response and flushes it.
+func (s *EventStream) write(msg string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return fmt.Errorf("event stream is closed")
+	}
+	if err := s.req.Context().Err(); err != nil {
+		return err
+	}
+	if _, err := s.resp.Write([]byte(msg)); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+// keepAlive sends a comment every interval until the stream is closed or the client disconnects.
+func (s *EventStream) keepAlive(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.write(": keepalive\n\n"); err != nil {
+				return
+			}
+		case <-s.done:
+			return
+		case <-s.req.Context().Done():
+			return
+		}
+	}
+}
+
+// eventField removes the line breaks from the value of an event field.
+func eventField(v string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(v)
+}
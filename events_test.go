@@ -0,0 +1,80 @@
+package goa_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1"
+)
+
+var _ = Describe("EventStream", func() {
+	var service *goa.Service
+	var rw *httptest.ResponseRecorder
+	var req *http.Request
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var keepAlive time.Duration
+	var stream *goa.EventStream
+	var err error
+
+	BeforeEach(func() {
+		service = goa.New("test")
+		service.Encoder.Register(goa.NewJSONEncoder, "*/*")
+		rw = httptest.NewRecorder()
+		var reqCtx context.Context
+		reqCtx, cancel = context.WithCancel(context.Background())
+		req, err = http.NewRequestWithContext(reqCtx, "GET", "/events", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		req.Header.Set("Last-Event-ID", "41")
+		keepAlive = 0
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	JustBeforeEach(func() {
+		ctx = goa.NewContext(context.Background(), rw, req, url.Values{})
+		goa.ContextResponse(ctx).Service = service
+		stream, err = goa.NewEventStream(ctx, keepAlive)
+	})
+
+	It("writes the event stream headers", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Code).Should(Equal(200))
+		Ω(rw.Header().Get("Content-Type")).Should(Equal("text/event-stream"))
+		Ω(rw.Header().Get("Cache-Control")).Should(Equal("no-cache"))
+		Ω(rw.Flushed).Should(BeTrue())
+	})
+
+	It("returns the last event ID", func() {
+		Ω(stream.LastEventID()).Should(Equal("41"))
+	})
+
+	It("sends encoded events", func() {
+		Ω(stream.Send("42", "update", map[string]int{"count": 1})).ShouldNot(HaveOccurred())
+		Ω(rw.Body.String()).Should(Equal("id: 42\nevent: update\ndata: {\"count\":1}\n\n"))
+	})
+
+	It("fails to send events once the client is gone", func() {
+		cancel()
+		Ω(stream.Send("", "", 1)).Should(HaveOccurred())
+	})
+
+	Context("with keepalives", func() {
+		BeforeEach(func() {
+			keepAlive = 10 * time.Millisecond
+		})
+
+		It("sends keepalive comments", func() {
+			time.Sleep(50 * time.Millisecond)
+			stream.Close()
+			Ω(rw.Body.String()).Should(ContainSubstring(": keepalive\n\n"))
+		})
+	})
+})
@@ -0,0 +1,196 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/shogo82148/goa-v1/design"
+)
+
+// EnumTypeKey is the name of the metadata used to generate a named Go type for an attribute that
+// defines an Enum validation. The first metadata value is the name of the generated Go type.
+const EnumTypeKey = "struct:enum"
+
+type (
+	// EnumType describes a named Go type generated for an Enum validation.
+	EnumType struct {
+		// Name is the name of the Go type.
+		Name string
+		// Description is the description of the attribute or user type.
+		Description string
+		// Native is the Go built-in type underlying the generated type.
+		Native string
+		// Values lists the enum values and the names of the corresponding constants.
+		Values []*EnumValue
+	}
+
+	// EnumValue describes a single constant of a generated enum type.
+	EnumValue struct {
+		// Name is the name of the Go constant.
+		Name string
+		// Value is the literal Go value of the constant.
+		Value string
+	}
+)
+
+// EnumTypeName returns the name of the Go type generated for the given attribute enum validation,
+// the empty string if the attribute does not opt into enum type generation.
+func EnumTypeName(att *design.AttributeDefinition) string {
+	if att == nil || !isEnumPrimitive(att) {
+		return ""
+	}
+	meta, ok := att.Metadata[EnumTypeKey]
+	if !ok {
+		return ""
+	}
+	if len(meta) == 0 || meta[0] == "" {
+		return ""
+	}
+	return Goify(meta[0], true)
+}
+
+// EnumTypes returns the enum types generated for the given API sorted by name. The types are
+// collected from the user types, media types and action payloads. The constants whose names clash
+// with the name of another type or constant are suffixed with a number.
+func EnumTypes(api *design.APIDefinition) []*EnumType {
+	var (
+		enums = make(map[string]*EnumType)
+		seen  = make(map[design.DataType]bool)
+		walk  func(att *design.AttributeDefinition)
+	)
+	walk = func(att *design.AttributeDefinition) {
+		if att == nil || att.Type == nil {
+			return
+		}
+		if name := EnumTypeName(att); name != "" {
+			if _, ok := enums[name]; !ok {
+				enums[name] = newEnumType(name, att)
+			}
+		}
+		switch actual := att.Type.(type) {
+		case *design.UserTypeDefinition:
+			if seen[actual] {
+				return
+			}
+			seen[actual] = true
+			walk(actual.AttributeDefinition)
+		case *design.MediaTypeDefinition:
+			if seen[actual] {
+				return
+			}
+			seen[actual] = true
+			walk(actual.AttributeDefinition)
+		case design.Object:
+			for _, n := range sortedKeys(actual) {
+				walk(actual[n])
+			}
+		case *design.Array:
+			walk(actual.ElemType)
+		case *design.Hash:
+			walk(actual.KeyType)
+			walk(actual.ElemType)
+		}
+	}
+	api.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		walk(&design.AttributeDefinition{Type: ut})
+		return nil
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		walk(&design.AttributeDefinition{Type: mt})
+		return nil
+	})
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if a.Payload != nil {
+				walk(&design.AttributeDefinition{Type: a.Payload})
+			}
+			return nil
+		})
+	})
+	api.IterateWebhooks(func(w *design.WebhookDefinition) error {
+		if w.Payload != nil {
+			walk(&design.AttributeDefinition{Type: w.Payload})
+		}
+		return nil
+	})
+	names := make([]string, 0, len(enums))
+	for n := range enums {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	res := make([]*EnumType, len(names))
+	used := make(map[string]bool, len(names))
+	for i, n := range names {
+		res[i] = enums[n]
+		used[n] = true
+	}
+	var clashes []*EnumValue
+	for _, e := range res {
+		for _, v := range e.Values {
+			if used[v.Name] {
+				clashes = append(clashes, v)
+				continue
+			}
+			used[v.Name] = true
+		}
+	}
+	for _, v := range clashes {
+		v.Name = uniqueName(v.Name, used)
+		used[v.Name] = true
+	}
+	return res
+}
+
+// uniqueName returns name if it is not used, name followed by the smallest number greater than one
+// that makes it unused otherwise.
+func uniqueName(name string, used map[string]bool) string {
+	if !used[name] {
+		return name
+	}
+	for i := 2; ; i++ {
+		if n := name + strconv.Itoa(i); !used[n] {
+			return n
+		}
+	}
+}
+
+// newEnumType builds the enum type with the given name from the attribute enum validation.
+func newEnumType(name string, att *design.AttributeDefinition) *EnumType {
+	values := make([]*EnumValue, len(att.Validation.Values))
+	for i, v := range att.Validation.Values {
+		values[i] = &EnumValue{
+			Name:  name + Goify(fmt.Sprintf("%v", v), true),
+			Value: fmt.Sprintf("%#v", v),
+		}
+	}
+	return &EnumType{
+		Name:        name,
+		Description: att.Description,
+		Native:      GoNativeType(att.Type),
+		Values:      values,
+	}
+}
+
+// isEnumPrimitive returns true if the attribute is a boolean, integer, number or string with an
+// Enum validation.
+func isEnumPrimitive(att *design.AttributeDefinition) bool {
+	if att.Validation == nil || len(att.Validation.Values) == 0 || att.Type == nil {
+		return false
+	}
+	switch att.Type.Kind() {
+	case design.BooleanKind, design.IntegerKind, design.NumberKind, design.StringKind:
+		return true
+	}
+	return false
+}
+
+// sortedKeys returns the names of the object attributes in alphabetical order.
+func sortedKeys(o design.Object) []string {
+	keys := make([]string, 0, len(o))
+	for n := range o {
+		keys = append(keys, n)
+	}
+	sort.Strings(keys)
+	return keys
+}
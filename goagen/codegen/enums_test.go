@@ -0,0 +1,106 @@
+package codegen_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1/design"
+	"github.com/shogo82148/goa-v1/dslengine"
+	"github.com/shogo82148/goa-v1/goagen/codegen"
+)
+
+var _ = Describe("EnumTypes", func() {
+	var api *design.APIDefinition
+	var enums []*codegen.EnumType
+
+	BeforeEach(func() {
+		api = &design.APIDefinition{Name: "test"}
+	})
+
+	JustBeforeEach(func() {
+		enums = codegen.EnumTypes(api)
+	})
+
+	Context("with an attribute that opts into enum type generation", func() {
+		var object design.Object
+
+		BeforeEach(func() {
+			object = design.Object{
+				"color": &design.AttributeDefinition{
+					Type:       design.String,
+					Validation: &dslengine.ValidationDefinition{Values: []interface{}{"red", "dark-blue"}},
+					Metadata:   dslengine.MetadataDefinition{codegen.EnumTypeKey: []string{"color"}},
+				},
+				"size": &design.AttributeDefinition{
+					Type:       design.Integer,
+					Validation: &dslengine.ValidationDefinition{Values: []interface{}{1, 2}},
+				},
+			}
+			ut := &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{Type: object},
+				TypeName:            "Paint",
+			}
+			api.Types = map[string]*design.UserTypeDefinition{"Paint": ut}
+		})
+
+		It("generates the enum type", func() {
+			Ω(enums).Should(HaveLen(1))
+			Ω(enums[0].Name).Should(Equal("Color"))
+			Ω(enums[0].Native).Should(Equal("string"))
+			Ω(enums[0].Values).Should(HaveLen(2))
+			Ω(enums[0].Values[0].Name).Should(Equal("ColorRed"))
+			Ω(enums[0].Values[0].Value).Should(Equal(`"red"`))
+			Ω(enums[0].Values[1].Name).Should(Equal("ColorDarkBlue"))
+		})
+
+		It("uses the enum type in the struct definition", func() {
+			def := codegen.GoTypeDef(&design.AttributeDefinition{Type: object}, 0, false, false)
+			Ω(def).Should(ContainSubstring("Color *Color"))
+			Ω(def).Should(ContainSubstring("Size *int"))
+		})
+	})
+
+	Context("with values that map to the same constant name", func() {
+		BeforeEach(func() {
+			object := design.Object{
+				"color": &design.AttributeDefinition{
+					Type:       design.String,
+					Validation: &dslengine.ValidationDefinition{Values: []interface{}{"dark-blue", "dark_blue", "dark_blue2"}},
+					Metadata:   dslengine.MetadataDefinition{codegen.EnumTypeKey: []string{"color"}},
+				},
+			}
+			ut := &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{Type: object},
+				TypeName:            "Paint",
+			}
+			api.Types = map[string]*design.UserTypeDefinition{"Paint": ut}
+		})
+
+		It("suffixes the clashing names", func() {
+			Ω(enums).Should(HaveLen(1))
+			Ω(enums[0].Values).Should(HaveLen(3))
+			Ω(enums[0].Values[0].Name).Should(Equal("ColorDarkBlue"))
+			Ω(enums[0].Values[1].Name).Should(Equal("ColorDarkBlue3"))
+			Ω(enums[0].Values[2].Name).Should(Equal("ColorDarkBlue2"))
+		})
+	})
+
+	Context("with no enum metadata", func() {
+		BeforeEach(func() {
+			object := design.Object{
+				"letter": &design.AttributeDefinition{
+					Type:       design.String,
+					Validation: &dslengine.ValidationDefinition{Values: []interface{}{"a"}},
+				},
+			}
+			ut := &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{Type: object},
+				TypeName:            "letter",
+			}
+			api.Types = map[string]*design.UserTypeDefinition{"letter": ut}
+		})
+
+		It("does not generate enum types", func() {
+			Ω(enums).Should(BeEmpty())
+		})
+	})
+})
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"text/template"
+	"time"
 
 	"github.com/shogo82148/goa-v1/design"
 )
@@ -62,8 +63,16 @@ func (f *Finalizer) recurse(root, att *design.AttributeDefinition, target string
 		f.seen[root] = map[*design.AttributeDefinition]*bytes.Buffer{att: buf}
 	}
 
+	// Values of types mapped to existing Go types are not finalized
+	if GoTypeMapping(att.Type) != nil {
+		return buf
+	}
+
 	if o := att.Type.ToObject(); o != nil {
 		o.IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+			if GoTypeMapping(catt.Type) != nil {
+				return nil
+			}
 			if att.HasDefaultValue(n) {
 				data := map[string]interface{}{
 					"target":     target,
@@ -124,6 +133,18 @@ func PrintVal(t design.DataType, val interface{}) string {
 			s = fmt.Sprintf("%f", v)
 		case design.DateTime:
 			s = fmt.Sprintf("time.Parse(time.RFC3339, %s)", s)
+		case design.Date:
+			d, _ := time.Parse("2006-01-02", fmt.Sprint(val))
+			s = fmt.Sprintf("goa.Date{Year: %d, Month: %d, Day: %d}", d.Year(), d.Month(), d.Day())
+		case design.TimeOfDay:
+			t, _ := time.Parse("15:04:05.999999999", fmt.Sprint(val))
+			s = fmt.Sprintf("goa.TimeOfDay{Hour: %d, Minute: %d, Second: %d, Nanosecond: %d}",
+				t.Hour(), t.Minute(), t.Second(), t.Nanosecond())
+		case design.Duration:
+			d, _ := time.ParseDuration(fmt.Sprint(val))
+			s = fmt.Sprintf("goa.Duration(%d)", int64(d))
+		case design.Decimal:
+			s = fmt.Sprintf("goa.Decimal(%q)", fmt.Sprint(val))
 		}
 		return s
 	case t.IsHash():
@@ -55,6 +55,42 @@ var _ = Describe("Struct finalize code generation", func() {
 		})
 	})
 
+	Context("given an object with a primitive Date field", func() {
+		BeforeEach(func() {
+			att = &design.AttributeDefinition{
+				Type: &design.Object{
+					"foo": &design.AttributeDefinition{
+						Type:         design.Date,
+						DefaultValue: "2016-02-29",
+					},
+				},
+			}
+			target = "ut"
+		})
+		It("finalizes the fields", func() {
+			code := finalizer.Code(att, target, 0)
+			Ω(code).Should(Equal(dateAssignmentCode))
+		})
+	})
+
+	Context("given an object with a primitive Decimal field", func() {
+		BeforeEach(func() {
+			att = &design.AttributeDefinition{
+				Type: &design.Object{
+					"foo": &design.AttributeDefinition{
+						Type:         design.Decimal,
+						DefaultValue: 1.25,
+					},
+				},
+			}
+			target = "ut"
+		})
+		It("finalizes the fields", func() {
+			code := finalizer.Code(att, target, 0)
+			Ω(code).Should(Equal(decimalAssignmentCode))
+		})
+	})
+
 	Context("given an object with a primitive Number field with a int default value", func() {
 		BeforeEach(func() {
 			att = &design.AttributeDefinition{
@@ -203,6 +239,16 @@ if ut.Foo == nil {
 	ut.Foo = &defaultFoo
 }`
 
+	dateAssignmentCode = `var defaultFoo goa.Date = goa.Date{Year: 2016, Month: 2, Day: 29}
+if ut.Foo == nil {
+	ut.Foo = &defaultFoo
+}`
+
+	decimalAssignmentCode = `var defaultFoo goa.Decimal = goa.Decimal("1.25")
+if ut.Foo == nil {
+	ut.Foo = &defaultFoo
+}`
+
 	arrayAssignmentCode = `if ut.Foo == nil {
 	ut.Foo = []string{"bar", "baz"}
 }`
@@ -27,6 +27,23 @@ func CheckVersion(ver string) error {
 	return nil
 }
 
+// ForEachVersion calls gen once for each version declared by api passing the definition of the API
+// restricted to the version (see design.APIDefinition.ForVersion). design.Design is set to the
+// version definition while gen runs so that the code generation helpers produce the version
+// specific paths. Iteration stops if gen returns an error and in this case ForEachVersion returns
+// that error.
+func ForEachVersion(api *design.APIDefinition, gen func(*design.APIDefinition) error) error {
+	saved := design.Design
+	defer func() { design.Design = saved }()
+	for _, v := range api.APIVersions {
+		design.Design = api.ForVersion(v.Name)
+		if err := gen(design.Design); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // CommandLine return the command used to run this process.
 func CommandLine() string {
 	// We don't use the full path to the tool so that running goagen multiple times doesn't
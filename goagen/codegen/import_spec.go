@@ -70,6 +70,21 @@ func AttributeImports(att *design.AttributeDefinition, imports []*ImportSpec, se
 	return imports
 }
 
+// GoTypeMappingImports returns a new ImportsSpec slice that contains the given imports and the
+// imports of the packages that define the Go types primitives and user types are mapped to.
+func GoTypeMappingImports(api *design.APIDefinition, imports []*ImportSpec) []*ImportSpec {
+	if api == nil {
+		return imports
+	}
+	var mapped []*ImportSpec
+	for _, m := range api.GoTypeMappings {
+		if m.PackagePath != "" {
+			mapped = append(mapped, NewImport(m.PackageName(), m.PackagePath))
+		}
+	}
+	return appendImports(imports, mapped)
+}
+
 // appendImports appends two ImportSpec slices and preserves uniqueness
 func appendImports(i, a []*ImportSpec) []*ImportSpec {
 	for _, v := range a {
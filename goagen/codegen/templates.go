@@ -0,0 +1,419 @@
+package codegen
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"text/template/parse"
+)
+
+type (
+	// Template is a parsed code generation template. It is the user override of a built-in
+	// template if there is one, see LoadTemplateOverrides.
+	Template struct {
+		*template.Template
+		override *templateOverride
+		funcMap  template.FuncMap
+		mu       sync.Mutex
+		checked  map[reflect.Type]error
+	}
+
+	// templateOverride is a user provided template replacing a built-in template.
+	templateOverride struct {
+		// name is the name of the overridden template, e.g. "ctxT".
+		name string
+		// path is the path to the override file.
+		path string
+		// source is the override source.
+		source string
+	}
+)
+
+// TemplateExt is the extension of template override files.
+const TemplateExt = ".tmpl"
+
+var (
+	// builtinTemplates maps generator names to the names and sources of their templates.
+	builtinTemplates = make(map[string]map[string]string)
+	// overrides maps the keys of the overridden built-in templates to their overrides, see
+	// TemplateKey.
+	overrides = make(map[string]*templateOverride)
+)
+
+// RegisterTemplates registers the built-in templates of the given generator so that users may
+// override them with LoadTemplateOverrides. templates maps the template names, usually the names
+// of the Go constants holding them, to their sources. The registered templates must be parsed with
+// their key as name, see TemplateKey.
+func RegisterTemplates(gen string, templates map[string]string) {
+	m, ok := builtinTemplates[gen]
+	if !ok {
+		m = make(map[string]string, len(templates))
+		builtinTemplates[gen] = m
+	}
+	for name, source := range templates {
+		m[name] = source
+	}
+}
+
+// LoadTemplateOverrides loads the overrides of the templates of the given generator. Overrides
+// are files located in the subdirectory of dir named after the generator, each file is named
+// after the template it overrides with the ".tmpl" extension, for example "app/ctxT.tmpl".
+//
+// An override replaces the built-in template. It is executed with the same data and functions,
+// including the DefaultFuncMap functions such as "goify" and "gotyperef". The built-in template
+// is available to the override as the "builtin" template so that overrides may extend it:
+//
+//	// Traced by the acme tracer.
+//	{{ template "builtin" . }}
+//
+// LoadTemplateOverrides replaces the overrides previously loaded for the generator, an empty dir
+// removes them.
+func LoadTemplateOverrides(dir, gen string) error {
+	builtins := builtinTemplates[gen]
+	for name := range builtins {
+		delete(overrides, TemplateKey(gen, name))
+	}
+	if dir == "" {
+		return nil
+	}
+	genDir := filepath.Join(dir, gen)
+	paths, err := filepath.Glob(filepath.Join(genDir, "*"+TemplateExt))
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		if _, err := os.Stat(dir); err != nil {
+			return fmt.Errorf("invalid template override directory: %s", err)
+		}
+		return nil
+	}
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), TemplateExt)
+		if _, ok := builtins[name]; !ok {
+			names := make([]string, 0, len(builtins))
+			for n := range builtins {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("%s: unknown %s template %q, must be one of %s", path, gen, name, strings.Join(names, ", "))
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		source := string(b)
+		// Check the syntax now, functions are only known when the template is parsed.
+		tree := parse.New(path)
+		tree.Mode = parse.SkipFuncCheck
+		if _, err := tree.Parse(source, "", "", make(map[string]*parse.Tree)); err != nil {
+			return fmt.Errorf("invalid %s template override: %s", name, err)
+		}
+		overrides[TemplateKey(gen, name)] = &templateOverride{name: name, path: path, source: source}
+	}
+	return nil
+}
+
+// TemplateKey returns the key of the built-in template with the given name registered by the
+// generator gen, for example "app/ctxT".
+func TemplateKey(gen, name string) string {
+	return gen + "/" + name
+}
+
+// ParseTemplate parses the template with the given name and built-in source using the
+// DefaultFuncMap functions and the given functions. It parses the user override of the template
+// instead if there is one, the name of registered templates is their key (see TemplateKey).
+func ParseTemplate(name, source string, funcMap template.FuncMap) (*Template, error) {
+	o, ok := overrides[name]
+	if !ok {
+		t, err := template.New(name).Funcs(DefaultFuncMap).Funcs(funcMap).Parse(source)
+		if err != nil {
+			return nil, err
+		}
+		return &Template{Template: t}, nil
+	}
+	// Name the template after the override file so that errors point to it.
+	t := template.New(o.path).Funcs(DefaultFuncMap).Funcs(funcMap)
+	if _, err := t.New("builtin").Parse(source); err != nil {
+		return nil, err
+	}
+	if _, err := t.Parse(o.source); err != nil {
+		return nil, fmt.Errorf("invalid %s template override: %s", o.name, err)
+	}
+	return &Template{Template: t, override: o, funcMap: funcMap}, nil
+}
+
+// Execute applies the template to the given data and writes the output to w. Overrides are
+// validated against the type of the data before they are executed.
+func (t *Template) Execute(w io.Writer, data interface{}) error {
+	if t.override == nil {
+		return t.Template.Execute(w, data)
+	}
+	if err := t.validate(reflect.TypeOf(data)); err != nil {
+		return fmt.Errorf("invalid %s template override: %s", t.override.name, err)
+	}
+	if err := t.Template.Execute(w, data); err != nil {
+		return fmt.Errorf("%s template override: %s", t.override.name, err)
+	}
+	return nil
+}
+
+// validate checks that the fields and methods used by the override exist in the data type.
+func (t *Template) validate(typ reflect.Type) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err, ok := t.checked[typ]; ok {
+		return err
+	}
+	if t.checked == nil {
+		t.checked = make(map[reflect.Type]error)
+	}
+	c := &templateChecker{
+		tmpl:    t.Template,
+		funcs:   []template.FuncMap{t.funcMap, DefaultFuncMap},
+		visited: make(map[string]bool),
+	}
+	err := c.checkTemplate(t.Template.Tree, typ)
+	t.checked[typ] = err
+	return err
+}
+
+// templateChecker checks template trees against data types. It resolves the types of field,
+// method and function results statically. Values whose type cannot be known statically such as
+// interface values are not checked.
+type templateChecker struct {
+	tmpl    *template.Template
+	funcs   []template.FuncMap
+	vars    []map[string]reflect.Type
+	visited map[string]bool
+}
+
+// checkTemplate checks the given template tree executed with data of the given type.
+func (c *templateChecker) checkTemplate(tree *parse.Tree, dot reflect.Type) error {
+	if tree == nil || tree.Root == nil {
+		return nil
+	}
+	c.vars = append(c.vars, map[string]reflect.Type{"$": dot})
+	defer func() { c.vars = c.vars[:len(c.vars)-1] }()
+	return c.checkNode(tree, tree.Root, dot)
+}
+
+func (c *templateChecker) checkNode(tree *parse.Tree, node parse.Node, dot reflect.Type) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := c.checkNode(tree, child, dot); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		_, err := c.pipeType(tree, n.Pipe, dot)
+		return err
+	case *parse.IfNode:
+		return c.checkBranch(tree, &n.BranchNode, dot, false)
+	case *parse.WithNode:
+		return c.checkBranch(tree, &n.BranchNode, dot, true)
+	case *parse.RangeNode:
+		typ, err := c.pipeType(tree, n.Pipe, dot)
+		if err != nil {
+			return err
+		}
+		key, elem := rangeTypes(typ)
+		if len(n.Pipe.Decl) == 2 {
+			c.setVar(n.Pipe.Decl[0].Ident[0], key)
+			c.setVar(n.Pipe.Decl[1].Ident[0], elem)
+		} else if len(n.Pipe.Decl) == 1 {
+			c.setVar(n.Pipe.Decl[0].Ident[0], elem)
+		}
+		if err := c.checkNode(tree, n.List, elem); err != nil {
+			return err
+		}
+		return c.checkNode(tree, n.ElseList, dot)
+	case *parse.TemplateNode:
+		typ := dot
+		if n.Pipe != nil {
+			var err error
+			if typ, err = c.pipeType(tree, n.Pipe, dot); err != nil {
+				return err
+			}
+		}
+		// The built-in template is trusted, check each other template once per type.
+		if n.Name == "builtin" {
+			return nil
+		}
+		key := fmt.Sprintf("%s/%v", n.Name, typ)
+		if c.visited[key] {
+			return nil
+		}
+		c.visited[key] = true
+		if t := c.tmpl.Lookup(n.Name); t != nil {
+			return c.checkTemplate(t.Tree, typ)
+		}
+	}
+	return nil
+}
+
+// checkBranch checks an if or with node, with sets dot to the pipeline value.
+func (c *templateChecker) checkBranch(tree *parse.Tree, n *parse.BranchNode, dot reflect.Type, with bool) error {
+	typ, err := c.pipeType(tree, n.Pipe, dot)
+	if err != nil {
+		return err
+	}
+	inner := dot
+	if with {
+		inner = typ
+	}
+	if err := c.checkNode(tree, n.List, inner); err != nil {
+		return err
+	}
+	return c.checkNode(tree, n.ElseList, dot)
+}
+
+// pipeType returns the type of the pipeline value, nil if it is not known statically.
+func (c *templateChecker) pipeType(tree *parse.Tree, pipe *parse.PipeNode, dot reflect.Type) (reflect.Type, error) {
+	if pipe == nil {
+		return nil, nil
+	}
+	var typ reflect.Type
+	for _, cmd := range pipe.Cmds {
+		var err error
+		if typ, err = c.cmdType(tree, cmd, dot); err != nil {
+			return nil, err
+		}
+	}
+	for _, v := range pipe.Decl {
+		c.setVar(v.Ident[0], typ)
+	}
+	return typ, nil
+}
+
+// cmdType returns the type of the command result, nil if it is not known statically.
+func (c *templateChecker) cmdType(tree *parse.Tree, cmd *parse.CommandNode, dot reflect.Type) (reflect.Type, error) {
+	for _, arg := range cmd.Args[1:] {
+		if _, err := c.argType(tree, arg, dot); err != nil {
+			return nil, err
+		}
+	}
+	return c.argType(tree, cmd.Args[0], dot)
+}
+
+// argType returns the type of the given operand, nil if it is not known statically.
+func (c *templateChecker) argType(tree *parse.Tree, arg parse.Node, dot reflect.Type) (reflect.Type, error) {
+	switch n := arg.(type) {
+	case *parse.DotNode:
+		return dot, nil
+	case *parse.FieldNode:
+		return c.fieldType(tree, n, dot, n.Ident)
+	case *parse.VariableNode:
+		return c.fieldType(tree, n, c.varType(n.Ident[0]), n.Ident[1:])
+	case *parse.ChainNode:
+		typ, err := c.argType(tree, n.Node, dot)
+		if err != nil {
+			return nil, err
+		}
+		return c.fieldType(tree, n, typ, n.Field)
+	case *parse.PipeNode:
+		return c.pipeType(tree, n, dot)
+	case *parse.IdentifierNode:
+		for _, funcs := range c.funcs {
+			if fn, ok := funcs[n.Ident]; ok {
+				if ft := reflect.TypeOf(fn); ft != nil && ft.Kind() == reflect.Func && ft.NumOut() > 0 {
+					return ft.Out(0), nil
+				}
+				return nil, nil
+			}
+		}
+	case *parse.StringNode:
+		return reflect.TypeOf(""), nil
+	case *parse.BoolNode:
+		return reflect.TypeOf(false), nil
+	}
+	return nil, nil
+}
+
+// fieldType resolves the given chain of field and method names on typ.
+func (c *templateChecker) fieldType(tree *parse.Tree, node parse.Node, typ reflect.Type, idents []string) (reflect.Type, error) {
+	for _, ident := range idents {
+		if typ == nil || typ.Kind() == reflect.Interface {
+			return nil, nil
+		}
+		if m, ok := typ.MethodByName(ident); ok {
+			typ = nil
+			if m.Type.NumOut() > 0 {
+				typ = m.Type.Out(0)
+			}
+			continue
+		}
+		if typ.Kind() != reflect.Ptr {
+			if m, ok := reflect.PtrTo(typ).MethodByName(ident); ok {
+				typ = nil
+				if m.Type.NumOut() > 0 {
+					typ = m.Type.Out(0)
+				}
+				continue
+			}
+		}
+		base := typ
+		if base.Kind() == reflect.Ptr {
+			base = base.Elem()
+		}
+		switch base.Kind() {
+		case reflect.Struct:
+			f, ok := base.FieldByName(ident)
+			if !ok || f.PkgPath != "" {
+				loc, _ := tree.ErrorContext(node)
+				return nil, fmt.Errorf("%s: can't evaluate field %s in type %s", loc, ident, typ)
+			}
+			typ = f.Type
+		case reflect.Map:
+			if base.Key().Kind() != reflect.String {
+				loc, _ := tree.ErrorContext(node)
+				return nil, fmt.Errorf("%s: can't evaluate field %s in type %s", loc, ident, typ)
+			}
+			typ = base.Elem()
+		default:
+			loc, _ := tree.ErrorContext(node)
+			return nil, fmt.Errorf("%s: can't evaluate field %s in type %s", loc, ident, typ)
+		}
+	}
+	return typ, nil
+}
+
+// setVar records the type of the given variable in the current template scope.
+func (c *templateChecker) setVar(name string, typ reflect.Type) {
+	c.vars[len(c.vars)-1][name] = typ
+}
+
+// varType returns the type of the given variable, nil if it is not known statically.
+func (c *templateChecker) varType(name string) reflect.Type {
+	return c.vars[len(c.vars)-1][name]
+}
+
+// rangeTypes returns the types of the keys and elements produced by ranging over typ.
+func rangeTypes(typ reflect.Type) (key, elem reflect.Type) {
+	if typ == nil {
+		return nil, nil
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array:
+		return reflect.TypeOf(0), typ.Elem()
+	case reflect.Map:
+		return typ.Key(), typ.Elem()
+	case reflect.Chan:
+		return typ.Elem(), typ.Elem()
+	case reflect.Int:
+		return typ, typ
+	}
+	return nil, nil
+}
@@ -0,0 +1,168 @@
+package codegen_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1/goagen/codegen"
+)
+
+var _ = Describe("LoadTemplateOverrides", func() {
+	const (
+		gen      = "test"
+		greetT   = `{{ greeting }} {{ .Name }}!`
+		farewell = `Bye {{ .Name }}.`
+	)
+
+	type greetData struct {
+		Name  string
+		Items []struct{ Label string }
+	}
+
+	var (
+		dir, override string
+		loadErr       error
+		data          interface{}
+		out           string
+		err           error
+	)
+
+	funcs := template.FuncMap{"greeting": func() string { return "Hello" }}
+
+	codegen.RegisterTemplates(gen, map[string]string{"greetT": greetT, "farewellT": farewell})
+
+	BeforeEach(func() {
+		dir, err = os.MkdirTemp("", "templates")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(os.Mkdir(filepath.Join(dir, gen), 0755)).ShouldNot(HaveOccurred())
+		override = ""
+		data = &greetData{Name: "goa"}
+		out = ""
+	})
+
+	JustBeforeEach(func() {
+		if override != "" {
+			path := filepath.Join(dir, gen, "greetT"+codegen.TemplateExt)
+			Ω(os.WriteFile(path, []byte(override), 0644)).ShouldNot(HaveOccurred())
+		}
+		loadErr = codegen.LoadTemplateOverrides(dir, gen)
+		if loadErr != nil {
+			return
+		}
+		var tmpl *codegen.Template
+		tmpl, err = codegen.ParseTemplate(codegen.TemplateKey(gen, "greetT"), greetT, funcs)
+		if err != nil {
+			return
+		}
+		var b bytes.Buffer
+		err = tmpl.Execute(&b, data)
+		out = b.String()
+	})
+
+	AfterEach(func() {
+		Ω(codegen.LoadTemplateOverrides("", gen)).ShouldNot(HaveOccurred())
+		os.RemoveAll(dir)
+	})
+
+	Context("with no override", func() {
+		It("executes the built-in template", func() {
+			Ω(loadErr).ShouldNot(HaveOccurred())
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(out).Should(Equal("Hello goa!"))
+		})
+	})
+
+	Context("with an override replacing the template", func() {
+		BeforeEach(func() {
+			override = `{{ greeting }} {{ goify .Name true }}{{ range .Items }} {{ .Label }}{{ end }}`
+		})
+
+		It("executes the override with the built-in functions", func() {
+			Ω(loadErr).ShouldNot(HaveOccurred())
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(out).Should(Equal("Hello Goa"))
+		})
+	})
+
+	Context("with a template of another generator with the same source", func() {
+		BeforeEach(func() {
+			codegen.RegisterTemplates("other", map[string]string{"greetT": greetT})
+			override = `Bye {{ .Name }}`
+		})
+
+		It("only overrides the template of the generator", func() {
+			Ω(loadErr).ShouldNot(HaveOccurred())
+			Ω(out).Should(Equal("Bye goa"))
+			tmpl, err := codegen.ParseTemplate(codegen.TemplateKey("other", "greetT"), greetT, funcs)
+			Ω(err).ShouldNot(HaveOccurred())
+			var b bytes.Buffer
+			Ω(tmpl.Execute(&b, data)).ShouldNot(HaveOccurred())
+			Ω(b.String()).Should(Equal("Hello goa!"))
+		})
+	})
+
+	Context("with an override extending the template", func() {
+		BeforeEach(func() {
+			override = `// traced
+{{ template "builtin" . }}`
+		})
+
+		It("executes the built-in template from the override", func() {
+			Ω(loadErr).ShouldNot(HaveOccurred())
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(out).Should(Equal("// traced\nHello goa!"))
+		})
+	})
+
+	Context("with an override of an unknown template", func() {
+		BeforeEach(func() {
+			Ω(os.WriteFile(filepath.Join(dir, gen, "ctxT.tmpl"), []byte(""), 0644)).ShouldNot(HaveOccurred())
+		})
+
+		It("lists the templates that can be overridden", func() {
+			Ω(loadErr).Should(MatchError(HaveSuffix(`unknown test template "ctxT", must be one of farewellT, greetT`)))
+		})
+	})
+
+	Context("with an invalid override", func() {
+		BeforeEach(func() {
+			override = `{{ if .Name }}`
+		})
+
+		It("reports the syntax error", func() {
+			Ω(loadErr).Should(MatchError(ContainSubstring("invalid greetT template override: template: ")))
+			Ω(loadErr).Should(MatchError(ContainSubstring("greetT.tmpl:1: unexpected EOF")))
+		})
+	})
+
+	Context("with an override using a field missing from the data", func() {
+		BeforeEach(func() {
+			override = `{{ .Name }}
+{{ range .Items }}{{ .Title }}{{ end }}`
+		})
+
+		It("reports the field and its location before executing the override", func() {
+			Ω(loadErr).ShouldNot(HaveOccurred())
+			Ω(err).Should(MatchError(ContainSubstring("invalid greetT template override: ")))
+			Ω(err).Should(MatchError(ContainSubstring("greetT.tmpl:2:")))
+			Ω(err).Should(MatchError(HaveSuffix("can't evaluate field Title in type struct { Label string }")))
+			Ω(out).Should(BeEmpty())
+		})
+	})
+
+	Context("with an override using an unknown function", func() {
+		BeforeEach(func() {
+			override = `{{ salute .Name }}`
+		})
+
+		It("fails to parse the override", func() {
+			Ω(loadErr).ShouldNot(HaveOccurred())
+			Ω(err).Should(MatchError(ContainSubstring(`invalid greetT template override: `)))
+			Ω(err).Should(MatchError(ContainSubstring(`function "salute" not defined`)))
+		})
+	})
+})
@@ -61,6 +61,9 @@ func GoTypeDef(ds design.DataStructure, tabs int, jsonTags, private bool) string
 			return tname[0]
 		}
 	}
+	if ename := EnumTypeName(def); ename != "" {
+		return ename
+	}
 	t := def.Type
 	switch actual := t.(type) {
 	case design.Primitive:
@@ -184,6 +187,9 @@ func GoTypeRef(t design.DataType, required []string, tabs int, private bool) str
 // case the type (Object) does not carry the required field information defined in the parent
 // (anonymous) attribute.
 func GoTypeName(t design.DataType, required []string, tabs int, private bool) string {
+	if m := GoTypeMapping(t); m != nil {
+		return m.GoType
+	}
 	switch actual := t.(type) {
 	case design.Primitive:
 		return GoNativeType(t)
@@ -214,6 +220,13 @@ func GoTypeName(t design.DataType, required []string, tabs int, private bool) st
 	}
 }
 
+// GoTypeMapping returns the mapping of t to an existing Go type defined in the API design, nil
+// if t is not mapped. The generated code uses the mapped Go type in place of the Go type it would
+// otherwise generate for t and does not validate values of mapped types.
+func GoTypeMapping(t design.DataType) *design.GoTypeMappingDefinition {
+	return design.Design.GoTypeMapping(t)
+}
+
 // GoNativeType returns the Go built-in type from which instances of t can be initialized.
 func GoNativeType(t design.DataType) string {
 	switch actual := t.(type) {
@@ -235,6 +248,14 @@ func GoNativeType(t design.DataType) string {
 			return "interface{}"
 		case design.FileKind:
 			return "multipart.FileHeader"
+		case design.DateKind:
+			return "goa.Date"
+		case design.TimeOfDayKind:
+			return "goa.TimeOfDay"
+		case design.DurationKind:
+			return "goa.Duration"
+		case design.DecimalKind:
+			return "goa.Decimal"
 		default:
 			panic(fmt.Sprintf("goa bug: unknown primitive type %#v", actual))
 		}
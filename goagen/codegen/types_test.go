@@ -278,6 +278,34 @@ var _ = Describe("code generation", func() {
 					})
 				})
 
+				Context("using Go type mappings", func() {
+					var api *design.APIDefinition
+
+					BeforeEach(func() {
+						api = design.Design
+						design.Design = &design.APIDefinition{
+							GoTypeMappings: []*design.GoTypeMappingDefinition{
+								{Type: design.DateTime, GoType: "civil.DateTime", PackagePath: "cloud.google.com/go/civil"},
+							},
+						}
+					})
+
+					AfterEach(func() {
+						design.Design = api
+					})
+
+					It("uses the mapped Go type", func() {
+						expected := "struct {\n" +
+							"	Bar *string `form:\"bar,omitempty\" json:\"bar,omitempty\" yaml:\"bar,omitempty\" xml:\"bar,omitempty\"`\n" +
+							"	Baz *civil.DateTime `form:\"baz,omitempty\" json:\"baz,omitempty\" yaml:\"baz,omitempty\" xml:\"baz,omitempty\"`\n" +
+							"	Foo *int `form:\"foo,omitempty\" json:\"foo,omitempty\" yaml:\"foo,omitempty\" xml:\"foo,omitempty\"`\n" +
+							"	Qux *uuid.UUID `form:\"qux,omitempty\" json:\"qux,omitempty\" yaml:\"qux,omitempty\" xml:\"qux,omitempty\"`\n" +
+							"	Quz interface{} `form:\"quz,omitempty\" json:\"quz,omitempty\" yaml:\"quz,omitempty\" xml:\"quz,omitempty\"`\n" +
+							"}"
+						Ω(st).Should(Equal(expected))
+					})
+				})
+
 				Context("that are required", func() {
 					BeforeEach(func() {
 						required = &dslengine.ValidationDefinition{
@@ -169,6 +169,11 @@ func (v *Validator) recurse(att *design.AttributeDefinition, nonzero, required,
 		first = true
 	)
 
+	// Values of types mapped to existing Go types are not validated
+	if GoTypeMapping(att.Type) != nil {
+		return buf
+	}
+
 	// Break infinite recursions
 	switch dt := att.Type.(type) {
 	case *design.MediaTypeDefinition:
@@ -219,6 +224,9 @@ func (v *Validator) recurse(att *design.AttributeDefinition, nonzero, required,
 
 func (v *Validator) recurseAttribute(att, catt *design.AttributeDefinition, n, target, context string, depth int, private bool) string {
 	var validation string
+	if GoTypeMapping(catt.Type) != nil {
+		return ""
+	}
 	if _, ok := catt.Type.(design.DataStructure); ok {
 		validation = RunTemplate(v.userValT, map[string]interface{}{
 			"depth":  depth,
@@ -257,7 +265,7 @@ func (v *Validator) recurseAttribute(att, catt *design.AttributeDefinition, n, t
 // error. It initializes that variable in case a validation fails.
 // Note: we do not want to recurse here, recursion is done by the marshaler/unmarshaler code.
 func ValidationChecker(att *design.AttributeDefinition, nonzero, required, hasDefault bool, target, context string, depth int, private bool) string {
-	if att.Validation == nil {
+	if att.Validation == nil || GoTypeMapping(att.Type) != nil {
 		return ""
 	}
 	t := target
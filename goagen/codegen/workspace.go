@@ -30,6 +30,9 @@ type (
 		gopath string
 		// isModuleMode indicates whether the Module mode is enabled.
 		isModuleMode bool
+		// sourceDir is the original value of the GOAGEN_SOURCE_DIR environment variable, set for
+		// Module mode scratch workspaces only.
+		sourceDir *string
 	}
 
 	// Package represents a temporary Go package
@@ -51,6 +54,12 @@ type (
 	}
 )
 
+// sourceDirEnv is the name of the environment variable set by ScratchPackageFor in Module mode.
+// Its value is the root directory of the module the scratch workspace stands in for,
+// PackageSourcePath resolves import paths from that directory rather than from the current
+// directory.
+const sourceDirEnv = "GOAGEN_SOURCE_DIR"
+
 var (
 	// Template used to render Go source file headers.
 	headerTmpl = template.Must(template.New("header").Funcs(DefaultFuncMap).Parse(headerT))
@@ -66,6 +75,7 @@ var (
 		"gotypedef":           GoTypeDef,
 		"gotypename":          GoTypeName,
 		"gotypedesc":          GoTypeDesc,
+		"gotypemapping":       GoTypeMapping,
 		"gotyperef":           GoTypeRef,
 		"join":                strings.Join,
 		"recursivePublicizer": RecursivePublicizer,
@@ -144,6 +154,9 @@ func (w *Workspace) Delete() {
 	if w.gopath != "" {
 		os.Setenv("GOPATH", w.gopath)
 	}
+	if w.sourceDir != nil {
+		os.Setenv(sourceDirEnv, *w.sourceDir)
+	}
 	os.RemoveAll(w.Path)
 }
 
@@ -195,6 +208,55 @@ func PackageFor(source string) (*Package, error) {
 	return &Package{Workspace: w, Path: filepath.ToSlash(path)}, nil
 }
 
+// ScratchPackageFor returns a package in a new temporary workspace that has the same import path
+// as the package in the given directory. In GOPATH mode the temporary workspace is added to
+// GOPATH, in Module mode it contains a copy of the go.mod and go.sum files and the
+// GOAGEN_SOURCE_DIR environment variable is set to the module root so that the other packages of
+// the module still resolve to their sources. Use Delete on the package workspace to delete the
+// temporary directory when done.
+func ScratchPackageFor(dir string) (*Package, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		absDir = dir
+	}
+	p, err := PackageFor(filepath.Join(absDir, "_"))
+	if err != nil {
+		return nil, err
+	}
+	var w *Workspace
+	if p.Workspace.isModuleMode {
+		tmp, err := os.MkdirTemp("", "goagen")
+		if err != nil {
+			return nil, err
+		}
+		sourceDir := os.Getenv(sourceDirEnv)
+		w = &Workspace{Path: tmp, isModuleMode: true, sourceDir: &sourceDir}
+		for _, name := range []string{"go.mod", "go.sum"} {
+			b, err := os.ReadFile(filepath.Join(p.Workspace.Path, name))
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				w.Delete()
+				return nil, err
+			}
+			if err := os.WriteFile(filepath.Join(tmp, name), b, 0644); err != nil {
+				w.Delete()
+				return nil, err
+			}
+		}
+		os.Setenv(sourceDirEnv, p.Workspace.Path)
+	} else if w, err = NewWorkspace("goagen"); err != nil {
+		return nil, err
+	}
+	pkg := &Package{Path: p.Path, Workspace: w}
+	if err := os.MkdirAll(pkg.Abs(), 0755); err != nil {
+		w.Delete()
+		return nil, err
+	}
+	return pkg, nil
+}
+
 // Abs returns the absolute path to the package source directory
 func (p *Package) Abs() string {
 	elem := "src" // GOPATH mode.
@@ -328,11 +390,12 @@ func (f *SourceFile) Abs() string {
 	return filepath.Join(f.Package.Abs(), f.Name)
 }
 
-// ExecuteTemplate executes the template and writes the output to the file.
+// ExecuteTemplate executes the template and writes the output to the file. It executes the user
+// override of the template instead if there is one, see LoadTemplateOverrides.
 func (f *SourceFile) ExecuteTemplate(name, source string, funcMap template.FuncMap, data interface{}) error {
-	tmpl, err := template.New(name).Funcs(DefaultFuncMap).Funcs(funcMap).Parse(source)
+	tmpl, err := ParseTemplate(name, source, funcMap)
 	if err != nil {
-		panic(err) // bug
+		return err
 	}
 	return tmpl.Execute(f, data)
 }
@@ -372,7 +435,9 @@ func PackagePath(path string) (string, error) {
 	return "", fmt.Errorf("%s does not contain a Go package", absPath)
 }
 
-// PackageSourcePath returns the absolute path to the given package source.
+// PackageSourcePath returns the absolute path to the given package source. The import path is
+// resolved from the directory set in the GOAGEN_SOURCE_DIR environment variable if any, from the
+// current directory otherwise.
 func PackageSourcePath(pkg string) (string, error) {
 	buildCtx := build.Default
 	buildCtx.GOPATH = envOr("GOPATH", build.Default.GOPATH) // Reevaluate each time to be nice to tests
@@ -380,6 +445,10 @@ func PackageSourcePath(pkg string) (string, error) {
 	if err != nil {
 		wd = "."
 	}
+	if dir := os.Getenv(sourceDirEnv); dir != "" {
+		wd = dir
+		buildCtx.Dir = dir
+	}
 	p, err := buildCtx.Import(pkg, wd, 0)
 	if err != nil {
 		return "", err
@@ -801,4 +801,43 @@ var _ = Describe("Workspace", func() {
 		})
 	})
 
+	Describe("ScratchPackageFor", func() {
+		var (
+			modDir         string
+			oldGOPATH      = build.Default.GOPATH
+			oldGO111MODULE = os.Getenv("GO111MODULE")
+		)
+		BeforeEach(func() {
+			var err error
+			modDir, err = os.MkdirTemp("", "mod")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(os.WriteFile(filepath.Join(modDir, "go.mod"), []byte("module example.com/mod\n"), 0644)).ShouldNot(HaveOccurred())
+			os.Setenv("GOPATH", abs("xx"))
+			os.Unsetenv("GO111MODULE")
+		})
+		AfterEach(func() {
+			os.RemoveAll(modDir)
+			os.Setenv("GOPATH", oldGOPATH)
+			os.Setenv("GO111MODULE", oldGO111MODULE)
+		})
+
+		It("should return a package with the same import path in a new Module mode workspace", func() {
+			pkg, err := codegen.ScratchPackageFor(filepath.Join(modDir, "bar", "xx"))
+			Ω(err).ShouldNot(HaveOccurred())
+			defer pkg.Workspace.Delete()
+			Expect(pkg.Workspace.Path).NotTo(Equal(modDir))
+			Expect(pkg.Abs()).To(BeADirectory())
+			p, err := codegen.PackagePath(pkg.Abs())
+			Ω(err).ShouldNot(HaveOccurred())
+			Expect(p).To(Equal("example.com/mod/bar/xx"))
+		})
+
+		It("should resolve the import paths from the module root until the workspace is deleted", func() {
+			pkg, err := codegen.ScratchPackageFor(filepath.Join(modDir, "bar", "xx"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Expect(os.Getenv("GOAGEN_SOURCE_DIR")).To(Equal(modDir))
+			pkg.Workspace.Delete()
+			Expect(os.Getenv("GOAGEN_SOURCE_DIR")).To(BeEmpty())
+		})
+	})
 })
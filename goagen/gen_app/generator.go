@@ -37,8 +37,8 @@ type Generator struct {
 // Generate is the generator entry point called by the meta generator.
 func Generate() (files []string, err error) {
 	var (
-		outDir, toolDir, target, ver string
-		notest, notool, regen        bool
+		outDir, toolDir, target, ver, templates string
+		notest, notool, regen                   bool
 	)
 
 	set := flag.NewFlagSet("app", flag.PanicOnError)
@@ -51,12 +51,17 @@ func Generate() (files []string, err error) {
 	set.BoolVar(&notool, "notool", false, "")
 	set.BoolVar(&regen, "regen", false, "")
 	set.Bool("force", false, "")
+	set.StringVar(&templates, "templates", "", "")
+	set.String("app-pkg", "", "")
 	set.Parse(os.Args[1:])
 	outDir = filepath.Join(outDir, target)
 
 	if err := codegen.CheckVersion(ver); err != nil {
 		return nil, err
 	}
+	if err := codegen.LoadTemplateOverrides(templates, "app"); err != nil {
+		return nil, err
+	}
 
 	target = codegen.Goify(target, false)
 	g := &Generator{OutDir: outDir, Target: target, NoTest: notest, API: design.Design, validator: codegen.NewValidator()}
@@ -104,6 +109,12 @@ func (g *Generator) Generate() (_ []string, err error) {
 	if err := g.generateUserTypes(); err != nil {
 		return nil, err
 	}
+	if err := g.generateEnums(); err != nil {
+		return nil, err
+	}
+	if err := g.generateWebhooks(); err != nil {
+		return nil, err
+	}
 	if !g.NoTest {
 		if err := g.generateResourceTest(); err != nil {
 			return nil, err
@@ -145,7 +156,9 @@ func (g *Generator) generateContexts() (err error) {
 	title := fmt.Sprintf("%s: Application Contexts", g.API.Context())
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("io"),
 		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("net/url"),
 		codegen.SimpleImport("strconv"),
 		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("time"),
@@ -153,6 +166,7 @@ func (g *Generator) generateContexts() (err error) {
 		codegen.NewImport("goa", "github.com/shogo82148/goa-v1"),
 		codegen.NewImport("uuid", "github.com/gofrs/uuid"),
 		codegen.SimpleImport("context"),
+		codegen.SimpleImport("golang.org/x/net/websocket"),
 	}
 	g.API.IterateResources(func(r *design.ResourceDefinition) error {
 		return r.IterateActions(func(a *design.ActionDefinition) error {
@@ -163,6 +177,8 @@ func (g *Generator) generateContexts() (err error) {
 		})
 	})
 
+	imports = codegen.GoTypeMappingImports(g.API, imports)
+
 	g.genfiles = append(g.genfiles, ctxFile)
 	if err = ctxWr.WriteHeader(title, g.Target, imports); err != nil {
 		return
@@ -207,6 +223,66 @@ func (g *Generator) generateContexts() (err error) {
 				API:          g.API,
 				DefaultPkg:   g.Target,
 				Security:     a.Security,
+				Pagination:   a.Pagination,
+			}
+			if l := a.LongRunning; l != nil {
+				status := l.StatusAction()
+				if status == nil || len(status.Routes) == 0 {
+					return fmt.Errorf("resource %#v does not define the %#v action", r.Name, design.ShowOperationAction)
+				}
+				path := status.Routes[0].FullPath()
+				ctxData.LongRunning = l
+				ctxData.StatusPath = design.WildcardRegex.ReplaceAllLiteralString(path, "/%v")
+				for _, p := range design.ExtractWildcards(path) {
+					arg := "op.ID"
+					if p != design.OperationIDParam {
+						arg = fmt.Sprintf("ctx.Params.Get(%q)", p)
+					}
+					ctxData.StatusArgs = append(ctxData.StatusArgs, "url.PathEscape("+arg+")")
+				}
+			}
+			if c := a.Criteria; c != nil {
+				ctxData.Criteria = c
+				ctxData.CriteriaName = codegen.Goify(a.Name, true) + codegen.Goify(r.Name, true) + "Criteria"
+				ctxData.Filters, ctxData.RangeChecks = criteriaFields(c, params)
+			}
+			if sel := a.Selection; sel != nil {
+				ctxData.Selection = sel
+				ctxData.SelectionVar = codegen.Goify(a.Name, false) + codegen.Goify(r.Name, true) + "ViewFields"
+				ctxData.ViewFields = make(map[string][]string)
+				for _, view := range sel.Views() {
+					fields, err := sel.ViewFields(view)
+					if err != nil {
+						return err
+					}
+					ctxData.ViewFields[view] = fields
+				}
+			}
+			if a.Events != nil {
+				mt := a.Events.EventType()
+				if mt == nil {
+					return fmt.Errorf("unknown event media type %#v", a.Events.MediaType)
+				}
+				p, _, err := mt.Project(design.DefaultView)
+				if err != nil {
+					return err
+				}
+				ctxData.Events = a.Events
+				ctxData.EventType = p
+			}
+			if m := a.Messages; m != nil {
+				in, err := m.InboundType()
+				if err != nil {
+					return err
+				}
+				out, err := m.OutboundType()
+				if err != nil {
+					return err
+				}
+				ctxData.Messages = m
+				ctxData.ConnName = codegen.Goify(a.Name, true) + codegen.Goify(a.Parent.Name, true) + "Conn"
+				ctxData.Inbound = in
+				ctxData.Outbound = out
 			}
 			return ctxWr.Execute(&ctxData)
 		})
@@ -272,6 +348,7 @@ func (g *Generator) generateControllers() (err error) {
 	for _, packagePath := range packagePaths {
 		imports = append(imports, codegen.SimpleImport(packagePath))
 	}
+	imports = codegen.GoTypeMappingImports(g.API, imports)
 	if err = ctlWr.WriteHeader(title, g.Target, imports); err != nil {
 		return err
 	}
@@ -318,6 +395,7 @@ func (g *Generator) generateControllers() (err error) {
 				"PayloadOptional":  a.PayloadOptional,
 				"PayloadMultipart": a.PayloadMultipart,
 				"Security":         a.Security,
+				"Deprecation":      a.Deprecation,
 			}
 			data.Actions = append(data.Actions, action)
 			return nil
@@ -454,6 +532,7 @@ func (g *Generator) generateMediaTypes() (err error) {
 	for _, v := range g.API.MediaTypes {
 		imports = codegen.AttributeImports(v.AttributeDefinition, imports, nil)
 	}
+	imports = codegen.GoTypeMappingImports(g.API, imports)
 	if err = mtWr.WriteHeader(title, g.Target, imports); err != nil {
 		return err
 	}
@@ -467,6 +546,10 @@ func (g *Generator) generateMediaTypes() (err error) {
 		}
 		return nil
 	})
+	if err != nil {
+		return
+	}
+	err = mtWr.WriteHypermediaSchemas()
 	return
 }
 
@@ -502,12 +585,188 @@ func (g *Generator) generateUserTypes() (err error) {
 	for _, v := range g.API.Types {
 		imports = codegen.AttributeImports(v.AttributeDefinition, imports, nil)
 	}
+	imports = codegen.GoTypeMappingImports(g.API, imports)
 	if err = utWr.WriteHeader(title, g.Target, imports); err != nil {
 		return err
 	}
 	g.genfiles = append(g.genfiles, utFile)
 	err = g.API.IterateUserTypes(func(t *design.UserTypeDefinition) error {
+		if codegen.GoTypeMapping(t) != nil {
+			// Types mapped to existing Go types are not generated
+			return nil
+		}
 		return utWr.Execute(t)
 	})
 	return
 }
+
+// generateWebhooks generates the payload types of the webhooks and the functions that send and
+// verify them.
+func (g *Generator) generateWebhooks() (err error) {
+	var webhooks []*WebhookTemplateData
+	g.API.IterateWebhooks(func(w *design.WebhookDefinition) error {
+		webhooks = append(webhooks, webhookData(w))
+		return nil
+	})
+	if len(webhooks) == 0 {
+		return nil
+	}
+	var (
+		whFile string
+		whWr   *WebhooksWriter
+	)
+	{
+		whFile = filepath.Join(g.OutDir, "webhooks.go")
+		whWr, err = NewWebhooksWriter(whFile)
+		if err != nil {
+			return
+		}
+	}
+	defer func() {
+		whWr.Close()
+		if err == nil {
+			err = whWr.FormatCode()
+		}
+	}()
+	title := fmt.Sprintf("%s: Application Webhooks", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("context"),
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("time"),
+		codegen.SimpleImport("unicode/utf8"),
+		codegen.NewImport("goa", "github.com/shogo82148/goa-v1"),
+		codegen.NewImport("goaclient", "github.com/shogo82148/goa-v1/client"),
+		codegen.NewImport("uuid", "github.com/gofrs/uuid"),
+	}
+	for _, w := range webhooks {
+		imports = codegen.AttributeImports(w.Payload.AttributeDefinition, imports, nil)
+	}
+	imports = codegen.GoTypeMappingImports(g.API, imports)
+	if err = whWr.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, whFile)
+	for _, w := range webhooks {
+		if err = whWr.Execute(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// webhookData builds the template data of the given webhook.
+func webhookData(w *design.WebhookDefinition) *WebhookTemplateData {
+	name := codegen.Goify(w.Name, true)
+	if a := w.Action(); a != nil {
+		name = codegen.Goify(a.Name, true) + codegen.Goify(a.Parent.Name, true) + name
+	}
+	var headers []*WebhookHeaderData
+	w.IterateHeaders(func(n string, required bool, h *design.AttributeDefinition) error {
+		headers = append(headers, &WebhookHeaderData{
+			Name:     n,
+			VarName:  codegen.Goify(n, false),
+			Type:     codegen.GoNativeType(h.Type),
+			Pointer:  !required && h.DefaultValue == nil,
+			IsString: h.Type.Kind() == design.StringKind,
+		})
+		return nil
+	})
+	return &WebhookTemplateData{
+		Name:     name,
+		Webhook:  w,
+		Payload:  w.Payload,
+		Inline:   design.Design.Types[w.Payload.TypeName] != w.Payload,
+		Validate: codegen.GoTypeMapping(w.Payload) == nil,
+		Headers:  headers,
+	}
+}
+
+// criteriaFields builds the fields of the criteria struct of the given filterable action and the
+// checks of its range filters. params are the action parameters.
+func criteriaFields(c *design.CriteriaDefinition, params *design.AttributeDefinition) ([]*CriteriaFieldData, []*RangeCheckData) {
+	var (
+		fields []*CriteriaFieldData
+		checks []*RangeCheckData
+		names  = make(map[string]string)
+	)
+	for _, p := range c.FilterParams() {
+		att := params.Type.ToObject()[p.Name]
+		typ := codegen.GoTypeRef(att.Type, nil, 0, false)
+		if att.Type.IsPrimitive() && params.IsPrimitivePointer(p.Name) {
+			typ = "*" + typ
+		}
+		var desc string
+		switch p.Operator {
+		case design.EqualFilter:
+			desc = fmt.Sprintf("filters the items whose %s is equal to the value if not nil.", p.Attribute)
+		case design.InFilter:
+			desc = fmt.Sprintf("filters the items whose %s is equal to one of the values if not empty.", p.Attribute)
+		case design.PrefixFilter:
+			desc = fmt.Sprintf("filters the items whose %s starts with the value if not nil.", p.Attribute)
+		case design.RangeFilter:
+			desc = fmt.Sprintf("filters the items whose %s is greater than or equal to the value if not nil.", p.Attribute)
+			if p.Max {
+				desc = fmt.Sprintf("filters the items whose %s is less than or equal to the value if not nil.", p.Attribute)
+			}
+		}
+		name := codegen.GoifyAtt(att, p.Name, true)
+		names[p.Name] = name
+		fields = append(fields, &CriteriaFieldData{Name: name, Type: typ, Description: desc})
+	}
+	for _, p := range c.FilterParams() {
+		if p.Operator != design.RangeFilter || p.Max {
+			continue
+		}
+		// Values of types mapped to existing Go types are not validated.
+		t := params.Type.ToObject()[p.Name].Type
+		if codegen.GoTypeMapping(t) != nil {
+			continue
+		}
+		checks = append(checks, &RangeCheckData{
+			Min:   names[p.Name],
+			Max:   names[p.Attribute+"_max"],
+			Param: p.Name,
+			Time:  t.Kind() == design.DateTimeKind || t.Kind() == design.DateKind,
+		})
+	}
+	return fields, checks
+}
+
+// generateEnums generates the named types of the enum attributes and user types.
+func (g *Generator) generateEnums() (err error) {
+	enums := codegen.EnumTypes(g.API)
+	if len(enums) == 0 {
+		return nil
+	}
+	var (
+		enumFile string
+		enumWr   *EnumsWriter
+	)
+	{
+		enumFile = filepath.Join(g.OutDir, "enums.go")
+		enumWr, err = NewEnumsWriter(enumFile)
+		if err != nil {
+			return
+		}
+	}
+	defer func() {
+		enumWr.Close()
+		if err == nil {
+			err = enumWr.FormatCode()
+		}
+	}()
+	title := fmt.Sprintf("%s: Application Enum Types", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("strconv"),
+		codegen.NewImport("goa", "github.com/shogo82148/goa-v1"),
+	}
+	if err = enumWr.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, enumFile)
+	return enumWr.Execute(enums)
+}
@@ -45,6 +45,16 @@ type TestMethod struct {
 	Headers           []*ObjectType
 	Payload           *ObjectType
 	reservedNames     map[string]bool
+	Conn              *WSTestConn // Typed websocket connection returned by websocket test helpers if any
+}
+
+// WSTestConn describes the typed connection returned by the test helpers of websocket actions that
+// declare messages.
+type WSTestConn struct {
+	Name     string      // Name of the connection type, e.g. "ChatRoomTestConn"
+	Declare  bool        // Whether the helper declares the connection type
+	Inbound  *ObjectType // Type of the messages sent to the controller if any
+	Outbound *ObjectType // Type of the messages sent by the controller if any
 }
 
 // Escape escapes given string.
@@ -72,7 +82,6 @@ func (g *Generator) generateResourceTest() error {
 	funcs := template.FuncMap{
 		"isSlice": isSlice,
 	}
-	testTmpl := template.Must(template.New("test").Funcs(funcs).Parse(testTmpl))
 	outDir, err := makeTestDir(g, g.API.Name)
 	if err != nil {
 		return err
@@ -98,7 +107,9 @@ func (g *Generator) generateResourceTest() error {
 		codegen.SimpleImport("github.com/shogo82148/goa-v1/goatest"),
 		codegen.SimpleImport("context"),
 		codegen.NewImport("uuid", "github.com/gofrs/uuid"),
+		codegen.SimpleImport("golang.org/x/net/websocket"),
 	}
+	imports = codegen.GoTypeMappingImports(g.API, imports)
 
 	return g.API.IterateResources(func(res *design.ResourceDefinition) (err error) {
 		filename := filepath.Join(outDir, codegen.SnakeCase(res.Name)+"_testing.go")
@@ -118,11 +129,18 @@ func (g *Generator) generateResourceTest() error {
 			return err
 		}
 
-		var methods []*TestMethod
+		var methods, wsMethods []*TestMethod
 
 		if err = res.IterateActions(func(action *design.ActionDefinition) error {
 			if err := action.IterateResponses(func(response *design.ResponseDefinition) error {
-				if response.Status == 101 { // SwitchingProtocols, Don't currently handle WebSocket endpoints
+				if response.Status == 101 { // SwitchingProtocols, websocket endpoints
+					for routeIndex, route := range action.Routes {
+						m, err := g.createWSTestMethod(res, action, response, route, routeIndex)
+						if err != nil {
+							return err
+						}
+						wsMethods = append(wsMethods, m)
+					}
 					return nil
 				}
 				for routeIndex, route := range action.Routes {
@@ -147,11 +165,46 @@ func (g *Generator) generateResourceTest() error {
 			return err
 		}
 		g.genfiles = append(g.genfiles, filename)
-		err = testTmpl.Execute(file, methods)
+		if err = file.ExecuteTemplate("app/testTmpl", testTmpl, funcs, methods); err != nil {
+			return err
+		}
+		err = file.ExecuteTemplate("app/wsTestTmpl", wsTestTmpl, funcs, wsMethods)
 		return
 	})
 }
 
+// createWSTestMethod creates the test helper that connects to the given websocket action.
+func (g *Generator) createWSTestMethod(resource *design.ResourceDefinition, action *design.ActionDefinition,
+	response *design.ResponseDefinition, route *design.RouteDefinition, routeIndex int) (*TestMethod, error) {
+
+	m := g.createTestMethod(resource, action, response, route, routeIndex, nil, nil)
+	m.Comment = "runs the method " + m.ActionName + " of the given controller behind a test server\n" +
+		"// and returns a websocket connection to it. The connection and the server are closed when the test ends."
+	if action.Messages == nil {
+		return m, nil
+	}
+	conn := &WSTestConn{
+		Name:    m.ActionName + m.ResourceName + "TestConn",
+		Declare: routeIndex == 0,
+	}
+	in, err := action.Messages.InboundType()
+	if err != nil {
+		return nil, err
+	}
+	out, err := action.Messages.OutboundType()
+	if err != nil {
+		return nil, err
+	}
+	if in != nil {
+		conn.Inbound = &ObjectType{Type: fmt.Sprintf("%s.%s", g.Target, codegen.GoTypeName(in, nil, 0, false)), Pointer: "*"}
+	}
+	if out != nil {
+		conn.Outbound = &ObjectType{Type: fmt.Sprintf("%s.%s", g.Target, codegen.GoTypeName(out, nil, 0, false)), Pointer: "*"}
+	}
+	m.Conn = conn
+	return m, nil
+}
+
 func (g *Generator) createTestMethod(resource *design.ResourceDefinition, action *design.ActionDefinition,
 	response *design.ResponseDefinition, route *design.RouteDefinition, routeIndex int,
 	mediaType *design.MediaTypeDefinition, view *design.ViewDefinition) *TestMethod {
@@ -463,3 +516,105 @@ func {{ $test.Name }}(t testing.TB, ctx context.Context, service *goa.Service, c
 	return {{ $rw }}{{ if $test.ReturnType }}, mt{{ end }}
 }
 {{ end }}`
+
+var wsTestTmpl = `{{ define "convertParam" }}` + convertParamTmpl + `{{ end }}` + `
+{{ range $test := . }}{{ with $test.Conn }}{{ if .Declare }}
+// {{ .Name }} is a connection to the {{ $test.ActionName }} action of the {{ $test.ResourceName }} controller under test.
+type {{ .Name }} struct {
+	*goa.WSConn
+}
+{{ with .Inbound }}
+// Send sends msg to the controller, msg is not validated so that tests may send invalid messages.
+func (c *{{ $test.Conn.Name }}) Send(msg {{ .Pointer }}{{ .Type }}) error {
+	return c.WSConn.Send(msg)
+}
+{{ end }}{{ with .Outbound }}
+// Recv receives a message sent by the controller and validates it.
+func (c *{{ $test.Conn.Name }}) Recv() ({{ .Pointer }}{{ .Type }}, error) {
+	var msg {{ .Type }}
+	if err := c.WSConn.Recv(&msg); err != nil {
+		return nil, err
+	}
+	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+{{ end }}{{ end }}{{ end }}
+// {{ $test.Name }} {{ $test.Comment }}
+// If ctx is nil then context.Background() is used.
+// If service is nil then a default service is created.
+func {{ $test.Name }}(t testing.TB, ctx context.Context, service *goa.Service, ctrl {{ $test.ControllerName}}{{/*
+*/}}{{ range $param := $test.Params }}, {{ $param.Name }} {{ $param.Pointer }}{{ $param.Type }}{{ end }}{{/*
+*/}}{{ range $param := $test.QueryParams }}, {{ $param.Name }} {{ $param.Pointer }}{{ $param.Type }}{{ end }}{{/*
+*/}}{{ range $header := $test.Headers }}, {{ $header.Name }} {{ $header.Pointer }}{{ $header.Type }}{{ end }}{{/*
+*/}}) {{ if $test.Conn }}*{{ $test.Conn.Name }}{{ else }}*websocket.Conn{{ end }} {
+	t.Helper()
+
+	// Setup service
+	var (
+		{{ $logBuf := $test.Escape "logBuf" }}{{ $logBuf }} strings.Builder
+
+		{{ $respSetter := $test.Escape "respSetter" }}{{ $respSetter }} goatest.ResponseSetterFunc = func(r interface{}) {}
+	)
+	if service == nil {
+		service = goatest.Service(&{{ $logBuf }}, {{ $respSetter }})
+	} else {
+		{{ $logger := $test.Escape "logger" }}{{ $logger }} := log.New(&{{ $logBuf }}, "", log.Ltime)
+		service.WithLogger(goa.NewLogger({{ $logger }}))
+	}
+
+	// Setup request context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+{{ $query := $test.Escape "query" }}{{ if $test.QueryParams}}	{{ $query }} := url.Values{}
+{{ range $param := $test.QueryParams }}{{ if $param.Pointer }}	if {{ $param.Name }} != nil {{ end }}{
+{{ template "convertParam" $param }}
+		{{ $query }}[{{ printf "%q" $param.Label }}] = sliceVal
+	}
+{{ end }}{{ end }}	{{ $u := $test.Escape "u" }}{{ $u }} := &url.URL{
+		Path: fmt.Sprintf({{ printf "%q" $test.FullPath }}{{ range $param := $test.Params }}, {{ $param.Name }}{{ end }}),
+{{ if $test.QueryParams }}		RawQuery: {{ $query }}.Encode(),
+{{ end }}	}
+	{{ $prms := $test.Escape "prms" }}{{ $prms }} := url.Values{}
+{{ range $param := $test.Params }}	{{ $prms }}["{{ $param.Label }}"] = []string{fmt.Sprintf("%v",{{ $param.Name}})}
+{{ end }}{{ range $param := $test.QueryParams }}{{ if $param.Pointer }} if {{ $param.Name }} != nil {{ end }} {
+{{ template "convertParam" $param }}
+		{{ $prms }}[{{ printf "%q" $param.Label }}] = sliceVal
+	}
+{{ end }}
+	// Run the action behind a test server
+	{{ $srv := $test.Escape "srv" }}{{ $srv }} := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		{{ $goaCtx := $test.Escape "goaCtx" }}{{ $goaCtx }} := goa.NewContext(goa.WithAction(ctx, "{{ $test.ResourceName }}Test"), rw, req, {{ $prms }})
+		{{ $test.ContextVarName }}, {{ $err := $test.Escape "err" }}{{ $err }} := {{ $test.ContextType }}({{ $goaCtx }}, req, service)
+		if {{ $err }} != nil {
+			t.Errorf("unexpected parameter validation error: %+v", {{ $err }})
+			return
+		}
+		if {{ $err }} := ctrl.{{ $test.ActionName }}({{ $test.ContextVarName }}); {{ $err }} != nil {
+			t.Errorf("controller returned %+v, logs:\n%s", {{ $err }}, {{ $logBuf }}.String())
+		}
+	}))
+	t.Cleanup({{ $srv }}.Close)
+
+	// Connect
+	{{ $cfg := $test.Escape "cfg" }}{{ $cfg }}, {{ $err := $test.Escape "err" }}{{ $err }} := websocket.NewConfig("ws://"+{{ $srv }}.Listener.Addr().String()+{{ $u }}.String(), {{ $srv }}.URL)
+	if {{ $err }} != nil {
+		t.Fatalf("invalid websocket configuration: %s", {{ $err }})
+	}
+{{ range $header := $test.Headers }}{{ if $header.Pointer }}	if {{ $header.Name }} != nil {{ end }}{
+{{ template "convertParam" $header }}
+		{{ $cfg }}.Header[{{ printf "%q" $header.Label }}] = sliceVal
+	}
+{{ end }}	{{ $ws := $test.Escape "ws" }}{{ $ws }}, {{ $err }} := websocket.DialConfig({{ $cfg }})
+	if {{ $err }} != nil {
+		t.Fatalf("failed to establish websocket connection: %s", {{ $err }})
+	}
+{{ if $test.Conn }}	{{ $conn := $test.Escape "conn" }}{{ $conn }} := &{{ $test.Conn.Name }}{WSConn: goa.NewWSConn({{ $ws }}, 0)}
+	t.Cleanup(func() { {{ $conn }}.Close() })
+	return {{ $conn }}
+{{ else }}	t.Cleanup(func() { {{ $ws }}.Close() })
+	return {{ $ws }}
+{{ end }}}
+{{ end }}`
@@ -59,6 +59,9 @@ type (
 		*codegen.SourceFile
 		MediaTypeTmpl *template.Template
 		Validator     *codegen.Validator
+
+		// schemas lists the hypermedia schemas of the media types written so far.
+		schemas []*HypermediaSchemaData
 	}
 
 	// UserTypesWriter generate code for a goa application user types.
@@ -70,6 +73,41 @@ type (
 		Validator    *codegen.Validator
 	}
 
+	// WebhooksWriter generate code for the webhooks sent by a goa application.
+	// Webhooks are defined in the DSL with "Webhook".
+	WebhooksWriter struct {
+		*codegen.SourceFile
+		Finalizer *codegen.Finalizer
+		Validator *codegen.Validator
+	}
+
+	// WebhookTemplateData contains the information used by the template to render the code of a
+	// webhook.
+	WebhookTemplateData struct {
+		Name     string                     // e.g. "OrderCreated"
+		Webhook  *design.WebhookDefinition  // Webhook definition
+		Payload  *design.UserTypeDefinition // Webhook payload type
+		Inline   bool                       // Whether the payload type is defined by the webhook
+		Validate bool                       // Whether the payload type has a Validate method
+		Headers  []*WebhookHeaderData       // Webhook headers sorted by name
+	}
+
+	// WebhookHeaderData describes a webhook header.
+	WebhookHeaderData struct {
+		Name     string // Header name, e.g. "X-Tenant"
+		VarName  string // Go variable name, e.g. "xTenant"
+		Type     string // Go type of the header value
+		Pointer  bool   // Whether the header is optional
+		IsString bool   // Whether the header value is a string
+	}
+
+	// EnumsWriter generate code for the named types of enum attributes and user types.
+	// Enum types are opted into with the "struct:enum" metadata.
+	EnumsWriter struct {
+		*codegen.SourceFile
+		EnumTmpl *template.Template
+	}
+
 	// ContextTemplateData contains all the information used by the template to render the context
 	// code for an action.
 	ContextTemplateData struct {
@@ -84,6 +122,39 @@ type (
 		API          *design.APIDefinition
 		DefaultPkg   string
 		Security     *design.SecurityDefinition
+		Pagination   *design.PaginationDefinition
+		Events       *design.EventStreamDefinition
+		EventType    *design.MediaTypeDefinition // Projection of the event media type on its default view
+		Messages     *design.MessagesDefinition
+		ConnName     string          // e.g. "ChatRoomConn"
+		Inbound      design.DataType // Type of the messages sent by the client if any
+		Outbound     design.DataType // Type of the messages sent by the server if any
+		LongRunning  *design.LongRunningDefinition
+		StatusPath   string   // fmt.Sprintf format of the operation status path, e.g. "/jobs/%v/operations/%v"
+		StatusArgs   []string // Go expressions of the values used to format StatusPath
+		Selection    *design.SelectionDefinition
+		SelectionVar string              // Name of the variable listing the attributes rendered by each view, e.g. "showBottleViewFields"
+		ViewFields   map[string][]string // Paths of the attributes rendered by each selectable view
+		Criteria     *design.CriteriaDefinition
+		CriteriaName string               // e.g. "ListBottleCriteria"
+		Filters      []*CriteriaFieldData // Fields of the criteria struct holding the filters
+		RangeChecks  []*RangeCheckData    // Range filters whose bounds must be checked
+	}
+
+	// CriteriaFieldData describes a field of the criteria struct of a filterable action.
+	CriteriaFieldData struct {
+		Name        string // Name of the field, e.g. "RatingMin"
+		Type        string // Go type of the field, e.g. "*int"
+		Description string // Description of the field used in its doc comment
+	}
+
+	// RangeCheckData describes the check that the lower bound of a range filter is lower than
+	// its upper bound.
+	RangeCheckData struct {
+		Min   string // Name of the field holding the lower bound, e.g. "RatingMin"
+		Max   string // Name of the field holding the upper bound, e.g. "RatingMax"
+		Param string // Name of the query string parameter holding the lower bound
+		Time  bool   // true if the bounds are time.Time or goa.Date values
 	}
 
 	// ControllerTemplateData contains the information required to generate an action handler.
@@ -122,6 +193,21 @@ type (
 		// Default is true if this encoder/decoder should be set as the default.
 		Default bool
 	}
+
+	// HypermediaSchemaData contains the data needed to render the hypermedia schema of a
+	// projected media type.
+	HypermediaSchemaData struct {
+		// Name is the name of the schema, the name of the projected media type.
+		Name string
+		// Type is the JSON:API type of the media type resources.
+		Type string
+		// Embedded maps the names of the attributes holding related resources to the names
+		// of their schemas.
+		Embedded map[string]string
+		// Links maps the names of the links to the names of the schemas of the linked
+		// resources.
+		Links map[string]string
+	}
 )
 
 // IsPathParam returns true if the given parameter name corresponds to a path parameter for all
@@ -205,7 +291,7 @@ func NewContextsWriter(filename string) (*ContextsWriter, error) {
 
 // Execute writes the code for the context types to the writer.
 func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
-	if err := w.ExecuteTemplate("context", ctxT, nil, data); err != nil {
+	if err := w.ExecuteTemplate("app/ctxT", ctxT, nil, data); err != nil {
 		return err
 	}
 	fn := template.FuncMap{
@@ -217,9 +303,39 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 		"valueTypeOf":        valueTypeOf,
 		"fromString":         fromString,
 	}
-	if err := w.ExecuteTemplate("new", ctxNewT, fn, data); err != nil {
+	if err := w.ExecuteTemplate("app/ctxNewT", ctxNewT, fn, data); err != nil {
 		return err
 	}
+	if data.Messages != nil {
+		if err := w.ExecuteTemplate("app/ctxMessagesT", ctxMessagesT, nil, data); err != nil {
+			return err
+		}
+	}
+	if data.Events != nil {
+		if err := w.ExecuteTemplate("app/ctxEventsT", ctxEventsT, nil, data); err != nil {
+			return err
+		}
+	}
+	if data.Pagination != nil {
+		if err := w.ExecuteTemplate("app/ctxPageLinksT", ctxPageLinksT, nil, data); err != nil {
+			return err
+		}
+	}
+	if data.LongRunning != nil {
+		if err := w.ExecuteTemplate("app/ctxOperationT", ctxOperationT, nil, data); err != nil {
+			return err
+		}
+	}
+	if data.Selection != nil {
+		if err := w.ExecuteTemplate("app/ctxSelectionT", ctxSelectionT, nil, data); err != nil {
+			return err
+		}
+	}
+	if data.Criteria != nil {
+		if err := w.ExecuteTemplate("app/ctxCriteriaT", ctxCriteriaT, nil, data); err != nil {
+			return err
+		}
+	}
 	if data.Payload != nil {
 		found := false
 		for _, t := range design.Design.Types {
@@ -233,7 +349,7 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 				"finalizeCode":   w.Finalizer.Code,
 				"validationCode": w.Validator.Code,
 			}
-			if err := w.ExecuteTemplate("payload", payloadT, fn, data); err != nil {
+			if err := w.ExecuteTemplate("app/payloadT", payloadT, fn, data); err != nil {
 				return err
 			}
 		}
@@ -249,7 +365,7 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 			if mt, ok = resp.Type.(*design.MediaTypeDefinition); !ok {
 				respData["Type"] = resp.Type
 				respData["ContentType"] = resp.MediaType
-				return w.ExecuteTemplate("response", ctxTRespT, nil, respData)
+				return w.ExecuteTemplate("app/ctxTRespT", ctxTRespT, nil, respData)
 			}
 		} else {
 			mt = design.Design.MediaTypeWithIdentifier(resp.MediaType)
@@ -274,21 +390,35 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 				}
 				respData["Projected"] = projected
 				respData["ViewName"] = view
+				respData["Selected"] = data.Selection != nil && resp.Name == "OK" &&
+					(view == resp.ViewName || resp.ViewName == "" && view == design.DefaultView)
 				respData["MediaType"] = mt
 				respData["ContentType"] = mt.ContentType
+				respData["Hypermedia"] = ""
+				if format := projected.HypermediaFormat(); format != design.NoHypermedia {
+					respData["Hypermedia"] = hypermediaMethod(format)
+					respData["Schema"] = HypermediaSchemaName(projected)
+					respData["ContentType"] = format.ContentType()
+				}
 				if view == "default" {
 					respData["RespName"] = codegen.Goify(resp.Name, true)
 				} else {
 					base := fmt.Sprintf("%s%s", resp.Name, strings.Title(view))
 					respData["RespName"] = codegen.Goify(base, true)
 				}
-				if err := w.ExecuteTemplate("response", ctxMTRespT, fn, respData); err != nil {
+				if err := w.ExecuteTemplate("app/ctxMTRespT", ctxMTRespT, fn, respData); err != nil {
 					return err
 				}
+				if resp.Stream && projected.IsArray() {
+					respData["Elem"] = projected.Type.ToArray().ElemType
+					if err := w.ExecuteTemplate("app/ctxStreamRespT", ctxStreamRespT, fn, respData); err != nil {
+						return err
+					}
+				}
 			}
 			return nil
 		}
-		return w.ExecuteTemplate("response", ctxNoMTRespT, nil, respData)
+		return w.ExecuteTemplate("app/ctxNoMTRespT", ctxNoMTRespT, nil, respData)
 	})
 }
 
@@ -313,7 +443,7 @@ func (w *ControllersWriter) WriteInitService(encoders, decoders []*EncoderTempla
 		"Encoders": encoders,
 		"Decoders": decoders,
 	}
-	return w.ExecuteTemplate("service", serviceT, nil, ctx)
+	return w.ExecuteTemplate("app/serviceT", serviceT, nil, ctx)
 }
 
 // Execute writes the handlers GoGenerator
@@ -322,14 +452,18 @@ func (w *ControllersWriter) Execute(data []*ControllerTemplateData) error {
 		return nil
 	}
 	for _, d := range data {
-		if err := w.ExecuteTemplate("controller", ctrlT, nil, d); err != nil {
+		if err := w.ExecuteTemplate("app/ctrlT", ctrlT, nil, d); err != nil {
 			return err
 		}
-		if err := w.ExecuteTemplate("mount", mountT, nil, d); err != nil {
+		mountFn := template.FuncMap{
+			"deprecation":   deprecation,
+			"versionMounts": versionMounts,
+		}
+		if err := w.ExecuteTemplate("app/mountT", mountT, mountFn, d); err != nil {
 			return err
 		}
 		if len(d.Origins) > 0 {
-			if err := w.ExecuteTemplate("handleCORS", handleCORST, nil, d); err != nil {
+			if err := w.ExecuteTemplate("app/handleCORST", handleCORST, nil, d); err != nil {
 				return err
 			}
 		}
@@ -341,7 +475,7 @@ func (w *ControllersWriter) Execute(data []*ControllerTemplateData) error {
 			"valueTypeOf":    valueTypeOf,
 			"fromString":     fromString,
 		}
-		if err := w.ExecuteTemplate("unmarshal", unmarshalT, fn, d); err != nil {
+		if err := w.ExecuteTemplate("app/unmarshalT", unmarshalT, fn, d); err != nil {
 			return err
 		}
 	}
@@ -360,7 +494,7 @@ func NewSecurityWriter(filename string) (*SecurityWriter, error) {
 
 // Execute adds the different security schemes and middleware supporting functions.
 func (w *SecurityWriter) Execute(schemes []*design.SecuritySchemeDefinition) error {
-	return w.ExecuteTemplate("security_schemes", securitySchemesT, nil, schemes)
+	return w.ExecuteTemplate("app/securitySchemesT", securitySchemesT, nil, schemes)
 }
 
 // NewResourcesWriter returns a contexts code writer.
@@ -375,7 +509,7 @@ func NewResourcesWriter(filename string) (*ResourcesWriter, error) {
 
 // Execute writes the code for the context types to the writer.
 func (w *ResourcesWriter) Execute(data *ResourceData) error {
-	return w.ExecuteTemplate("resource", resourceT, nil, data)
+	return w.ExecuteTemplate("app/resourceT", resourceT, nil, data)
 }
 
 // NewMediaTypesWriter returns a contexts code writer.
@@ -402,19 +536,82 @@ func (w *MediaTypesWriter) Execute(mt *design.MediaTypeDefinition) error {
 		if err != nil {
 			return err
 		}
-		return w.ExecuteTemplate("mediatype", mediaTypeT, fn, p)
+		if err := w.ExecuteTemplate("app/mediaTypeT", mediaTypeT, fn, p); err != nil {
+			return err
+		}
+		return w.executeHypermedia(p)
 	})
 	if err != nil {
 		return err
 	}
 	if mLinks != nil {
-		if err := w.ExecuteTemplate("mediatypelink", mediaTypeLinkT, fn, mLinks); err != nil {
+		if err := w.ExecuteTemplate("app/mediaTypeLinkT", mediaTypeLinkT, fn, mLinks); err != nil {
 			return err
 		}
 	}
+	if mt == design.OperationMedia {
+		return w.ExecuteTemplate("app/operationMediaT", operationMediaT, nil, mt)
+	}
 	return nil
 }
 
+// executeHypermedia writes the methods that render the projected media type p as a hypermedia
+// document and records its hypermedia schema.
+func (w *MediaTypesWriter) executeHypermedia(p *design.MediaTypeDefinition) error {
+	format := p.HypermediaFormat()
+	if format == design.NoHypermedia {
+		return nil
+	}
+	if !p.IsArray() {
+		schema := &HypermediaSchemaData{
+			Name:     p.TypeName,
+			Type:     p.JSONAPIType(),
+			Embedded: make(map[string]string),
+			Links:    make(map[string]string),
+		}
+		for n, e := range p.EmbeddedResources() {
+			schema.Embedded[n] = e.TypeName
+		}
+		for n, l := range p.HypermediaLinks() {
+			schema.Links[n] = l.TypeName
+		}
+		w.schemas = append(w.schemas, schema)
+	}
+	data := map[string]interface{}{
+		"MediaType": p,
+		"Method":    hypermediaMethod(format),
+		"Schema":    HypermediaSchemaName(p),
+	}
+	return w.ExecuteTemplate("app/mediaTypeHypermediaT", mediaTypeHypermediaT, nil, data)
+}
+
+// WriteHypermediaSchemas writes the hypermedia schemas of the media types written so far, if any.
+func (w *MediaTypesWriter) WriteHypermediaSchemas() error {
+	if len(w.schemas) == 0 {
+		return nil
+	}
+	return w.ExecuteTemplate("app/hypermediaSchemasT", hypermediaSchemasT, nil, w.schemas)
+}
+
+// HypermediaSchemaName returns the name of the hypermedia schema used to render the projected
+// media type p, the name of the schema of its elements if p is a collection.
+func HypermediaSchemaName(p *design.MediaTypeDefinition) string {
+	if p.IsArray() {
+		if e, ok := p.ToArray().ElemType.Type.(*design.MediaTypeDefinition); ok {
+			return e.TypeName
+		}
+	}
+	return p.TypeName
+}
+
+// hypermediaMethod returns the name of the method that renders media types with the given format.
+func hypermediaMethod(format design.HypermediaFormat) string {
+	if format == design.JSONAPIHypermedia {
+		return "MarshalJSONAPI"
+	}
+	return "MarshalHAL"
+}
+
 // NewUserTypesWriter returns a contexts code writer.
 // User types contain custom data structured defined in the DSL with "Type".
 func NewUserTypesWriter(filename string) (*UserTypesWriter, error) {
@@ -435,7 +632,49 @@ func (w *UserTypesWriter) Execute(t *design.UserTypeDefinition) error {
 		"finalizeCode":   w.Finalizer.Code,
 		"validationCode": w.Validator.Code,
 	}
-	return w.ExecuteTemplate("types", userTypeT, fn, t)
+	return w.ExecuteTemplate("app/userTypeT", userTypeT, fn, t)
+}
+
+// NewWebhooksWriter returns a webhooks code writer.
+func NewWebhooksWriter(filename string) (*WebhooksWriter, error) {
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhooksWriter{
+		SourceFile: file,
+		Finalizer:  codegen.NewFinalizer(),
+		Validator:  codegen.NewValidator(),
+	}, nil
+}
+
+// Execute writes the code of the webhook payload type if defined by the webhook and of the
+// functions that send the webhook and verify received webhooks.
+func (w *WebhooksWriter) Execute(data *WebhookTemplateData) error {
+	fn := template.FuncMap{
+		"finalizeCode":   w.Finalizer.Code,
+		"validationCode": w.Validator.Code,
+	}
+	if data.Inline {
+		if err := w.ExecuteTemplate("app/userTypeT", userTypeT, fn, data.Payload); err != nil {
+			return err
+		}
+	}
+	return w.ExecuteTemplate("app/webhookT", webhookT, fn, data)
+}
+
+// NewEnumsWriter returns an enum types code writer.
+func NewEnumsWriter(filename string) (*EnumsWriter, error) {
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &EnumsWriter{SourceFile: file}, nil
+}
+
+// Execute writes the code for the enum types to the writer.
+func (w *EnumsWriter) Execute(enums []*codegen.EnumType) error {
+	return w.ExecuteTemplate("app/enumT", enumT, nil, enums)
 }
 
 // newCoerceData is a helper function that creates a map that can be given to the "Coerce" template.
@@ -450,6 +689,84 @@ func newCoerceData(name string, att *design.AttributeDefinition, pointer bool, p
 	}
 }
 
+// deprecation returns the Go code that initializes the goa.Deprecation value describing the
+// given deprecation.
+func deprecation(d *design.DeprecationDefinition) string {
+	var fields []string
+	if t := d.SinceTime(); !t.IsZero() {
+		fields = append(fields, fmt.Sprintf("Since: time.Unix(%d, 0)", t.Unix()))
+	}
+	if t := d.SunsetTime(); !t.IsZero() {
+		fields = append(fields, fmt.Sprintf("Sunset: time.Unix(%d, 0)", t.Unix()))
+	}
+	if u := d.SuccessorURL(); u != "" {
+		fields = append(fields, fmt.Sprintf("Successor: %q", u))
+	}
+	return "goa.Deprecation{" + strings.Join(fields, ", ") + "}"
+}
+
+// versionMount describes how a route is mounted for a set of API versions.
+type versionMount struct {
+	// Path is the path the route is mounted on.
+	Path string
+	// Selector is the Go expression that builds the goa.VersionSelector used to select the
+	// version, empty if the API does not declare versions.
+	Selector string
+	// Versions lists the names of the versions served by the mount.
+	Versions []string
+}
+
+// versionMounts returns the paths the route is mounted on. Routes are mounted once for each
+// version selected by path and once for all the versions selected by header or media type
+// parameter. Routes of APIs that don't declare versions are mounted on their full path.
+func versionMounts(r *design.RouteDefinition) []*versionMount {
+	if len(design.Design.APIVersions) == 0 {
+		return []*versionMount{{Path: r.FullPath()}}
+	}
+	var (
+		mounts []*versionMount
+		shared *versionMount
+	)
+	find := func(p string) *versionMount {
+		for _, m := range mounts {
+			if m.Path == p {
+				return m
+			}
+		}
+		return nil
+	}
+	for _, v := range r.Parent.APIVersions() {
+		if v.BasePath != "" {
+			p := r.VersionPath(v)
+			if m := find(p); m != nil {
+				// Absolute routes are not prefixed with the version base path.
+				m.Versions = append(m.Versions, v.Name)
+				continue
+			}
+			mounts = append(mounts, &versionMount{
+				Path:     p,
+				Selector: fmt.Sprintf("goa.FixedVersion(%q)", v.Name),
+				Versions: []string{v.Name},
+			})
+			continue
+		}
+		if shared == nil {
+			if m := find(r.FullPath()); m != nil {
+				m.Versions = append(m.Versions, v.Name)
+				continue
+			}
+			selector := fmt.Sprintf("goa.HeaderVersion(%q)", v.Header)
+			if v.MediaTypeParam != "" {
+				selector = fmt.Sprintf("goa.MediaTypeVersion(%q)", v.MediaTypeParam)
+			}
+			shared = &versionMount{Path: r.FullPath(), Selector: selector}
+			mounts = append(mounts, shared)
+		}
+		shared.Versions = append(shared.Versions, v.Name)
+	}
+	return mounts
+}
+
 // arrayAttribute returns the array element attribute definition.
 func arrayAttribute(a *design.AttributeDefinition) *design.AttributeDefinition {
 	return a.Type.(*design.Array).ElemType
@@ -462,6 +779,9 @@ func hashAttribute(a *design.AttributeDefinition) (*design.AttributeDefinition,
 
 // valueTypeOf returns the golang type definition string from attribute definition
 func valueTypeOf(prefix string, att *design.AttributeDefinition) string {
+	if m := codegen.GoTypeMapping(att.Type); m != nil {
+		return prefix + m.GoType
+	}
 	switch att.Type.Kind() {
 	case design.BooleanKind:
 		return prefix + "bool"
@@ -473,6 +793,14 @@ func valueTypeOf(prefix string, att *design.AttributeDefinition) string {
 		return prefix + "string"
 	case design.DateTimeKind:
 		return prefix + "time.Time"
+	case design.DateKind:
+		return prefix + "goa.Date"
+	case design.TimeOfDayKind:
+		return prefix + "goa.TimeOfDay"
+	case design.DurationKind:
+		return prefix + "goa.Duration"
+	case design.DecimalKind:
+		return prefix + "goa.Decimal"
 	case design.ArrayKind:
 		return valueTypeOf(prefix+"[]", arrayAttribute(att))
 	case design.HashKind:
@@ -484,6 +812,9 @@ func valueTypeOf(prefix string, att *design.AttributeDefinition) string {
 
 // fromString returns the gocode expression to convert string typed varName value to go-type defined in the attribute
 func fromString(att *design.AttributeDefinition, varName string) string {
+	if m := codegen.GoTypeMapping(att.Type); m != nil {
+		return m.ParseFunction() + "(" + varName + ")"
+	}
 	switch att.Type.Kind() {
 	case design.BooleanKind:
 		return "strconv.ParseBool(" + varName + ")"
@@ -493,6 +824,14 @@ func fromString(att *design.AttributeDefinition, varName string) string {
 		return "strconv.ParseFloat(" + varName + ")"
 	case design.StringKind:
 		return varName + ", (error)(nil)"
+	case design.DateKind:
+		return "goa.ParseDate(" + varName + ")"
+	case design.TimeOfDayKind:
+		return "goa.ParseTimeOfDay(" + varName + ")"
+	case design.DurationKind:
+		return "goa.ParseDuration(" + varName + ")"
+	case design.DecimalKind:
+		return "goa.ParseDecimal(" + varName + ")"
 	case design.ArrayKind:
 	case design.HashKind:
 		return valueTypeOf("", att) + "{}, (error)(nil)"
@@ -500,6 +839,43 @@ func fromString(att *design.AttributeDefinition, varName string) string {
 	return "(" + valueTypeOf("", att) + ")(nil), (error)(nil)"
 }
 
+// Register the templates so that the app template overrides may replace them, including the
+// test helper templates.
+func init() {
+	codegen.RegisterTemplates("app", map[string]string{
+		"ctxT":                 ctxT,
+		"ctxNewT":              ctxNewT,
+		"ctxPageLinksT":        ctxPageLinksT,
+		"ctxOperationT":        ctxOperationT,
+		"ctxSelectionT":        ctxSelectionT,
+		"ctxCriteriaT":         ctxCriteriaT,
+		"ctxEventsT":           ctxEventsT,
+		"ctxMessagesT":         ctxMessagesT,
+		"ctxMTRespT":           ctxMTRespT,
+		"ctxStreamRespT":       ctxStreamRespT,
+		"ctxTRespT":            ctxTRespT,
+		"ctxNoMTRespT":         ctxNoMTRespT,
+		"payloadT":             payloadT,
+		"ctrlT":                ctrlT,
+		"serviceT":             serviceT,
+		"mountT":               mountT,
+		"handleCORST":          handleCORST,
+		"unmarshalT":           unmarshalT,
+		"resourceT":            resourceT,
+		"mediaTypeT":           mediaTypeT,
+		"mediaTypeHypermediaT": mediaTypeHypermediaT,
+		"hypermediaSchemasT":   hypermediaSchemasT,
+		"mediaTypeLinkT":       mediaTypeLinkT,
+		"userTypeT":            userTypeT,
+		"webhookT":             webhookT,
+		"operationMediaT":      operationMediaT,
+		"enumT":                enumT,
+		"securitySchemesT":     securitySchemesT,
+		"testTmpl":             testTmpl,
+		"wsTestTmpl":           wsTestTmpl,
+	})
+}
+
 const (
 	// ctxT generates the code for the context data type.
 	// template input: *ContextTemplateData
@@ -513,12 +889,24 @@ type {{ .Name }} struct {
 {{ end }}{{ end }}{{ end }}{{ if .Params }}{{ range $name, $att := .Params.Type.ToObject }}{{/*
 */}}	{{ goifyatt $att $name true }} {{ if and $att.Type.IsPrimitive ($.Params.IsPrimitivePointer $name) }}*{{ end }}{{ gotyperef .Type nil 0 false }}
 {{ end }}{{ end }}{{ if .Payload }}	Payload {{ gotyperef .Payload nil 0 false }}
+{{ end }}{{ if .Events }}	events *goa.EventStream
 {{ end }}}
 `
 	// coerceT generates the code that coerces the generic deserialized
 	// data to the actual type.
 	// template input: map[string]interface{} as returned by newCoerceData
-	coerceT = `{{ if eq .Attribute.Type.Kind 1 }}{{/*
+	coerceT = `{{ if gotypemapping .Attribute.Type }}{{/*
+
+*/}}{{/* Type mapped to an existing Go type */}}{{/*
+*/}}{{ $mapping := gotypemapping .Attribute.Type }}{{/*
+*/}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := {{ $mapping.ParseFunction }}(raw{{ goifyatt .Attribute .Name true }}); err2 == nil {
+{{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ .VarName }}
+{{ end }}{{ tabs .Depth }}	{{ .Pkg }} = {{ $varName }}
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goifyatt .Attribute .Name true }}, "{{ $mapping.GoType }}"))
+{{ tabs .Depth }}}
+{{ else if eq .Attribute.Type.Kind 1 }}{{/*
 
 */}}{{/* BooleanType */}}{{/*
 */}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
@@ -586,7 +974,7 @@ type {{ .Name }} struct {
 */}}{{/* ArrayType */}}{{/*
 */}}{{ tabs .Depth }}tmp{{ goifyatt .Attribute .Name true }} := make({{ valueTypeOf "" .Attribute }}, len(raw{{ goifyatt .Attribute .Name true }}))
 {{ tabs .Depth }}for i := 0; i < len(raw{{ goifyatt .Attribute .Name true }}); i++ {
-{{ if eq (arrayAttribute .Attribute).Type.Kind 4 }}{{ tabs .Depth}}	tmp := raw{{ goifyatt .Attribute .Name true }}[i]{{ else }}{{/*
+{{ if and (eq (arrayAttribute .Attribute).Type.Kind 4) (not (gotypemapping (arrayAttribute .Attribute).Type)) }}{{ tabs .Depth}}	tmp := raw{{ goifyatt .Attribute .Name true }}[i]{{ else }}{{/*
 */}}{{ tabs .Depth }}	tmp, err2 := {{ fromString (arrayAttribute .Attribute) (printf "raw%s[i]" (goifyatt .Attribute .Name true)) }}
 {{ tabs .Depth }}	if err2 != nil {
 {{ tabs .Depth }}		err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goifyatt .Attribute .Name true }}, "{{ valueTypeOf "" .Attribute }}"))
@@ -596,6 +984,46 @@ type {{ .Name }} struct {
 {{ tabs .Depth}}}
 {{ tabs .Depth }}{{ .Pkg }} = tmp{{ goifyatt .Attribute .Name true }}{{/*
 */}}
+{{ else if eq .Attribute.Type.Kind 14 }}{{/*
+
+*/}}{{/* DateType */}}{{/*
+*/}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := goa.ParseDate(raw{{ goifyatt .Attribute .Name true }}); err2 == nil {
+{{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ .VarName }}
+{{ end }}{{ tabs .Depth }}	{{ .Pkg }} = {{ $varName }}
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goifyatt .Attribute .Name true }}, "date"))
+{{ tabs .Depth }}}
+{{ else if eq .Attribute.Type.Kind 15 }}{{/*
+
+*/}}{{/* TimeOfDayType */}}{{/*
+*/}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := goa.ParseTimeOfDay(raw{{ goifyatt .Attribute .Name true }}); err2 == nil {
+{{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ .VarName }}
+{{ end }}{{ tabs .Depth }}	{{ .Pkg }} = {{ $varName }}
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goifyatt .Attribute .Name true }}, "timeofday"))
+{{ tabs .Depth }}}
+{{ else if eq .Attribute.Type.Kind 16 }}{{/*
+
+*/}}{{/* DurationType */}}{{/*
+*/}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := goa.ParseDuration(raw{{ goifyatt .Attribute .Name true }}); err2 == nil {
+{{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ .VarName }}
+{{ end }}{{ tabs .Depth }}	{{ .Pkg }} = {{ $varName }}
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goifyatt .Attribute .Name true }}, "duration"))
+{{ tabs .Depth }}}
+{{ else if eq .Attribute.Type.Kind 17 }}{{/*
+
+*/}}{{/* DecimalType */}}{{/*
+*/}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := goa.ParseDecimal(raw{{ goifyatt .Attribute .Name true }}); err2 == nil {
+{{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ .VarName }}
+{{ end }}{{ tabs .Depth }}	{{ .Pkg }} = {{ $varName }}
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goifyatt .Attribute .Name true }}, "decimal"))
+{{ tabs .Depth }}}
 {{ else if eq .Attribute.Type.Kind 13 }}{{/*
 
 */}}{{/* FileType */}}{{/*
@@ -625,7 +1053,7 @@ func New{{ .Name }}(ctx context.Context, r *http.Request, service *goa.Service)
 	} else {
 {{ else }}	if len(header{{ goifyatt $att $name true }}) > 0 {
 {{ end }}{{/* if $mustValidate */}}{{ if $att.Type.IsArray }}		req.Params["{{ $name }}"] = header{{ goifyatt $att $name true }}
-{{ if eq (arrayAttribute $att).Type.Kind 4 }}		headers := header{{ goifyatt $att $name true }}
+{{ if and (eq (arrayAttribute $att).Type.Kind 4) (not (gotypemapping (arrayAttribute $att).Type)) }}		headers := header{{ goifyatt $att $name true }}
 {{ else }}		headers := make({{ gotypedef $att 2 true false }}, len(header{{ goifyatt $att $name true }}))
 		for i, raw{{ goifyatt $att $name true }} := range header{{ goifyatt $att $name true }} {
 {{ template "Coerce" (newCoerceData $name (arrayAttribute $att) ($.Headers.IsPrimitivePointer $name) "headers[i]" 3) }}{{/*
@@ -649,7 +1077,7 @@ func New{{ .Name }}(ctx context.Context, r *http.Request, service *goa.Service)
 {{ if eq (valueTypeOf "" $att ) "time.Time" }}		{{printf "rctx.%s, err" (goifyatt $att $name true)}} = {{ printVal $att.Type $att.DefaultValue }}{{ else }}		{{printf "rctx.%s" (goifyatt $att $name true) }} = {{ printVal $att.Type $att.DefaultValue }}{{ end }}
 	} else {
 {{ else }}	if len(param{{ goifyatt $att $name true }}) > 0 {
-{{ end }}{{ end }}{{/* if $mustValidate */}}{{ if $att.Type.IsArray }}{{ if eq (arrayAttribute $att).Type.Kind 4 }}		params := param{{ goifyatt $att $name true }}
+{{ end }}{{ end }}{{/* if $mustValidate */}}{{ if $att.Type.IsArray }}{{ if and (eq (arrayAttribute $att).Type.Kind 4) (not (gotypemapping (arrayAttribute $att).Type)) }}		params := param{{ goifyatt $att $name true }}
 {{ else }}		params := make({{ gotypedef $att 2 true false }}, len(param{{ goifyatt $att $name true }}))
 		for i, raw{{ goifyatt $att $name true }} := range param{{ goifyatt $att $name true }} {
 {{ template "Coerce" (newCoerceData $name (arrayAttribute $att) ($.Params.IsPrimitivePointer $name) "params[i]" 3) }}{{/*
@@ -663,7 +1091,174 @@ func New{{ .Name }}(ctx context.Context, r *http.Request, service *goa.Service)
 	}{{ end }}{{/*
 */}}{{ else }}{{ $validation := validationChecker $att ($.Params.IsNonZero $name) ($.Params.IsRequired $name) ($.Params.HasDefaultValue $name) (printf "rctx.%s" (goifyatt $att $name true)) $name 2 false }}{{/*
 */}}{{ if $validation }}{{ $validation }}{{ end }}{{ end }}	}
-{{ end }}{{ end }}{{/* if .Params */}}	return &rctx, err
+{{ end }}{{ end }}{{/* if .Params */}}{{ if .Selection }}	if err == nil {
+		_, err = rctx.selectedFields()
+	}
+{{ end }}{{ if .RangeChecks }}	if err == nil {
+		err = rctx.Criteria().Validate()
+	}
+{{ end }}	return &rctx, err
+}
+`
+
+	// ctxPageLinksT generates the helper that sets the Link header of paginated actions.
+	// template input: *ContextTemplateData
+	ctxPageLinksT = `{{ if eq .Pagination.Style "cursor" }}// SetPageLinks adds the Link response header with links to the first and next pages of the
+// collection. next is the cursor of the next page, the empty string if there are no more items.
+func (ctx *{{ .Name }}) SetPageLinks(next string) {
+	ctx.ResponseData.Header().Add("Link", goa.CursorPageLinks(ctx.RequestData.URL, next))
+}
+{{ else }}// SetPageLinks adds the Link response header with links to the first, previous, next and last
+// pages of the collection. total is the number of items in the collection.
+func (ctx *{{ .Name }}) SetPageLinks(total int) {
+	ctx.ResponseData.Header().Add("Link", goa.OffsetPageLinks(ctx.RequestData.URL, ctx.Offset, ctx.Limit, total))
+}
+{{ end }}`
+
+	// ctxOperationT generates the helper that responds to the requests that start a long-running
+	// operation.
+	// template input: *ContextTemplateData
+	ctxOperationT = `// AcceptedOperation sends a HTTP response with status code 202 whose body is the status of the
+// long-running operation op. The Location header is set to the path of the operation status.
+func (ctx *{{ .Name }}) AcceptedOperation(op *goa.Operation) error {
+	location := fmt.Sprintf("{{ .StatusPath }}"{{ range .StatusArgs }}, {{ . }}{{ end }})
+	ctx.ResponseData.Header().Set("Location", location){{ if .LongRunning.RetryAfter }}
+	ctx.ResponseData.Header().Set("Retry-After", "{{ .LongRunning.RetryAfter }}"){{ end }}
+	return ctx.Accepted(NewGoaOperation(op))
+}
+`
+
+	// ctxSelectionT generates the helper that computes the attributes of the response selected by
+	// the requests of selectable actions.
+	// template input: *ContextTemplateData
+	ctxSelectionT = `// {{ .SelectionVar }} lists the paths of the attributes rendered by each view of the OK response
+// of the {{ .ActionName }} action of the {{ .ResourceName }} resource.
+var {{ .SelectionVar }} = map[string][]string{
+{{ range $view, $fields := .ViewFields }}	{{ printf "%q" $view }}: { {{ range $i, $f := $fields }}{{ if $i }}, {{ end }}{{ printf "%q" $f }}{{ end }} },
+{{ end }}}
+
+// selectedFields returns the paths of the attributes of the OK response selected by the request,
+// nil if the response is rendered in full.
+func (ctx *{{ .Name }}) selectedFields() ([]string, error) {
+	return goa.SelectedFields({{ .SelectionVar }}, {{ if .Selection.View }}ctx.View{{ else }}{{ printf "%q" .Selection.ResponseView }}{{ end }}, {{ if .Selection.Fields }}ctx.Fields{{ else }}nil{{ end }})
+}
+`
+
+	// ctxCriteriaT generates the struct that holds the filters and the sort order of the requests
+	// made to filterable and sortable actions.
+	// template input: *ContextTemplateData
+	ctxCriteriaT = `// {{ .CriteriaName }} lists the filters and the sort order selected by the requests made to the
+// {{ .ActionName }} action of the {{ .ResourceName }} resource.
+type {{ .CriteriaName }} struct {
+{{ range .Filters }}	// {{ .Name }} {{ .Description }}
+	{{ .Name }} {{ .Type }}
+{{ end }}{{ if .Criteria.Sorts }}	// SortBy is the name of the attribute used to sort the items, nil if the items are not sorted.
+	SortBy *string
+	// Descending is true if the items are sorted in descending order.
+	Descending bool
+{{ end }}}
+{{ if .RangeChecks }}
+// Validate checks that the lower bounds of the range filters are lower than their upper bounds.
+func (c *{{ .CriteriaName }}) Validate() (err error) {
+{{ range .RangeChecks }}	if c.{{ .Min }} != nil && c.{{ .Max }} != nil && {{ if .Time }}c.{{ .Min }}.After(*c.{{ .Max }}){{ else }}*c.{{ .Min }} > *c.{{ .Max }}{{ end }} {
+		err = goa.MergeErrors(err, goa.InvalidRangeError("{{ .Param }}", *c.{{ .Min }}, *c.{{ .Max }}, false))
+	}
+{{ end }}	return
+}
+{{ end }}
+// Criteria returns the filters and the sort order selected by the request.
+func (ctx *{{ .Name }}) Criteria() *{{ .CriteriaName }} {
+	return &{{ .CriteriaName }}{
+{{ range .Filters }}		{{ .Name }}: ctx.{{ .Name }},
+{{ end }}{{ if .Criteria.Sorts }}		SortBy:     ctx.Sort,
+		Descending: ctx.Order == "desc",
+{{ end }}	}
+}
+`
+
+	// ctxEventsT generates the helpers that stream the Server-Sent Events of an action.
+	// template input: *ContextTemplateData
+	ctxEventsT = `{{ $eventType := gotyperef .EventType .EventType.AllRequired 0 false }}// OpenStream writes the response headers and starts the event stream. It is called by Send if
+// needed, call it explicitly to start the keepalive comments before the first event is sent.
+func (ctx *{{ .Name }}) OpenStream() error {
+	if ctx.events != nil {
+		return nil
+	}
+	events, err := goa.NewEventStream(ctx.Context, {{ .Events.KeepAlive }}*time.Second)
+	if err != nil {
+		return err
+	}
+	ctx.events = events
+	return nil
+}
+
+// Send sends an event to the client, id is the event ID sent back by reconnecting clients in the
+// Last-Event-ID header. The event data is encoded with the service encoder.
+func (ctx *{{ .Name }}) Send(id string, event {{ $eventType }}) error {
+	if err := ctx.OpenStream(); err != nil {
+		return err
+	}
+	return ctx.events.Send(id, "", event)
+}
+
+// LastEventID returns the ID of the last event received by the client before it reconnected, the
+// empty string if the client is not resuming a stream.
+func (ctx *{{ .Name }}) LastEventID() string {
+	return ctx.RequestData.Header.Get("Last-Event-ID")
+}
+
+// CloseStream stops the keepalive comments of the event stream, it should be called before the
+// action returns.
+func (ctx *{{ .Name }}) CloseStream() {
+	if ctx.events != nil {
+		ctx.events.Close()
+	}
+}
+`
+
+	// ctxMessagesT generates the typed connection of websocket actions that declare messages.
+	// template input: *ContextTemplateData
+	ctxMessagesT = `// {{ .ConnName }} is a connection of the {{ .ActionName }} action of the {{ .ResourceName }} resource.
+type {{ .ConnName }} struct {
+	*goa.WSConn
+}
+{{ if .Outbound }}
+// Send validates msg and sends it to the client.
+func (c *{{ .ConnName }}) Send(msg {{ gotyperef .Outbound .Outbound.AllRequired 0 false }}) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+	return c.WSConn.Send(msg)
+}
+{{ end }}{{ if .Inbound }}
+// Recv receives a message sent by the client and validates it. It returns io.EOF when the client
+// closes the connection.
+func (c *{{ .ConnName }}) Recv() ({{ gotyperef .Inbound .Inbound.AllRequired 0 false }}, error) {
+	var msg {{ gotypename .Inbound .Inbound.AllRequired 0 false }}
+	if err := c.WSConn.Recv(&msg); err != nil {
+		return nil, err
+	}
+	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+{{ end }}
+// Handler returns the websocket handler that runs fn with the connections of the {{ .ActionName }}
+// action. The connection is closed with the status code corresponding to the error returned by fn,
+// see goa.WSCloseStatus.
+func (ctx *{{ .Name }}) Handler(fn func(*{{ .ConnName }}) error) websocket.Handler {
+	return func(ws *websocket.Conn) {
+		conn := &{{ .ConnName }}{WSConn: goa.NewWSConn(ws, {{ .Messages.PingInterval }}*time.Second)}
+		err := fn(conn)
+		if err == io.EOF {
+			err = nil
+		}
+		if err != nil {
+			goa.LogError(ctx, "websocket handler failed", "err", err)
+		}
+		conn.CloseWithStatus(goa.WSCloseStatus(err), "")
+	}
 }
 `
 
@@ -677,8 +1272,68 @@ func (ctx *{{ .Context.Name }}) {{ goify .RespName true }}(r {{ gotyperef .Proje
 {{ if .Projected.Type.IsArray }}	if r == nil {
 		r = {{ gotyperef .Projected .Projected.AllRequired 0 false }}{}
 	}
+{{ end }}{{ if .Hypermedia }}{{ if .Selected }}	var body interface{} = r
+	fields, err := ctx.selectedFields()
+	if err != nil {
+		return err
+	}
+	if fields != nil {
+		if body, err = goa.SelectFields(r, fields); err != nil {
+			return err
+		}
+	}
+{{ end }}	doc, err := hypermediaSchemas.{{ .Hypermedia }}({{ if .Selected }}body{{ else }}r{{ end }}, {{ printf "%q" .Schema }})
+	if err != nil {
+		return err
+	}
+	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, doc)
+}
+{{ else }}{{ if .Selected }}	fields, err := ctx.selectedFields()
+	if err != nil {
+		return err
+	}
+	if fields != nil {
+		body, err := goa.SelectFields(r, fields)
+		if err != nil {
+			return err
+		}
+		return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, body)
+	}
 {{ end }}	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, r)
 }
+{{ end }}`
+
+	// ctxStreamRespT generates the response helpers for streamed collection responses.
+	// template input: map[string]interface{}
+	ctxStreamRespT = `{{ $elem := gotyperef .Elem.Type .Elem.AllRequired 0 false }}{{/*
+*/}}// {{ goify .RespName true }}Stream sends a HTTP response with status code {{ .Response.Status }} whose body is the
+// collection of the elements returned by next. next returns io.EOF once all the elements have been
+// returned. The elements are encoded and written one at a time.
+func (ctx *{{ .Context.Name }}) {{ goify .RespName true }}Stream(next func() ({{ $elem }}, error)) error {
+	if ctx.ResponseData.Header().Get("Content-Type") == "" {
+		ctx.ResponseData.Header().Set("Content-Type", "{{ .ContentType }}")
+	}
+	return ctx.ResponseData.Service.SendStream(ctx.Context, {{ .Response.Status }}, func() (interface{}, error) {
+		return next()
+	})
+}
+
+// {{ goify .RespName true }}Chan sends a HTTP response with status code {{ .Response.Status }} whose body is the
+// collection of the elements received from ch. The response is complete once ch is closed. The
+// sender should stop once the request context is done.
+func (ctx *{{ .Context.Name }}) {{ goify .RespName true }}Chan(ch <-chan {{ $elem }}) error {
+	return ctx.{{ goify .RespName true }}Stream(func() ({{ $elem }}, error) {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return nil, io.EOF
+			}
+			return v, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+}
 `
 
 	// ctxTRespT generates the response helpers for responses with overridden types.
@@ -799,10 +1454,15 @@ func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Co
 {{ end }}		return ctrl.{{ .Name }}(rctx)
 	}
 {{ if .Security }}	h = handleSecurity({{ printf "%q" .Security.Scheme.SchemeName }}, h{{ range .Security.Scopes }}, {{ printf "%q" . }}{{ end }})
+{{ end }}{{ with .Deprecation }}	h = goa.Deprecated({{ deprecation . }})(h)
 {{ end }}{{ if $.Origins }}	h = handle{{ $res }}Origin(h)
-{{ end }}{{ range .Routes }}	service.Mux.Handle("{{ .Verb }}", {{ printf "%q" .FullPath }}, ctrl.MuxHandler({{ printf "%q" $action.DesignName }}, h, {{ if $action.Payload }}{{ $action.Unmarshal }}{{ else }}nil{{ end }}))
-	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "action", {{ printf "%q" $action.Name }}, "route", {{ printf "%q" (printf "%s %s" .Verb .FullPath) }}{{ with $action.Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
-{{ end }}{{ end }}{{ range .FileServers }}
+{{ end }}{{ range .Routes }}{{ $route := . }}{{ range versionMounts . }}{{/*
+*/}}	service.Mux.Handle("{{ $route.Verb }}", {{ printf "%q" .Path }}, ctrl.MuxHandler({{ printf "%q" $action.DesignName }}, {{/*
+*/}}{{ if .Selector }}goa.SelectVersion({{ .Selector }}{{ range .Versions }}, {{ printf "%q" . }}{{ end }})(h){{ else }}h{{ end }}, {{/*
+*/}}{{ if $action.Payload }}{{ $action.Unmarshal }}{{ else }}nil{{ end }}))
+	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "action", {{ printf "%q" $action.Name }}, "route", {{ printf "%q" (printf "%s %s" $route.Verb .Path) }}{{/*
+*/}}{{ if .Versions }}, "versions", {{ printf "%q" (join .Versions ", ") }}{{ end }}{{ with $action.Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
+{{ end }}{{ end }}{{ end }}{{ range .FileServers }}
 	h = ctrl.FileHandler({{ printf "%q" .RequestPath }}, {{ printf "%q" .FilePath }})
 {{ if .Security }}	h = handleSecurity({{ printf "%q" .Security.Scheme.SchemeName }}, h{{ range .Security.Scopes }}, {{ printf "%q" . }}{{ end }})
 {{ end }}{{ if $.Origins }}	h = handle{{ $res }}Origin(h)
@@ -899,6 +1559,32 @@ func (mt {{ gotyperef . .AllRequired 0 false }}) Validate() (err error) {
 {{ $validation }}
 	return
 }
+`
+
+	// mediaTypeHypermediaT generates the method that renders a media type as a hypermedia
+	// document.
+	// template input: map[string]interface{}
+	mediaTypeHypermediaT = `{{ $typeName := gotypename .MediaType .MediaType.AllRequired 0 false }}{{/*
+*/}}// {{ .Method }} returns the document rendering the {{ $typeName }} media type instance.
+func (mt {{ gotyperef .MediaType .MediaType.AllRequired 0 false }}) {{ .Method }}() (interface{}, error) {
+	return hypermediaSchemas.{{ .Method }}(mt, {{ printf "%q" .Schema }})
+}
+`
+
+	// hypermediaSchemasT generates the hypermedia schemas of the media types.
+	// template input: []*HypermediaSchemaData
+	hypermediaSchemasT = `// hypermediaSchemas describes how the media types are rendered as hypermedia documents.
+var hypermediaSchemas = goa.HypermediaSchemas{
+{{ range . }}	{{ printf "%q" .Name }}: {
+		Type: {{ printf "%q" .Type }},
+{{ if .Embedded }}		Embedded: map[string]string{
+{{ range $n, $s := .Embedded }}			{{ printf "%q" $n }}: {{ printf "%q" $s }},
+{{ end }}		},
+{{ end }}{{ if .Links }}		Links: map[string]string{
+{{ range $n, $s := .Links }}			{{ printf "%q" $n }}: {{ printf "%q" $s }},
+{{ end }}		},
+{{ end }}	},
+{{ end }}}
 `
 
 	// mediaTypeLinkT generates the code for a media type link.
@@ -945,6 +1631,132 @@ func (ut {{ gotyperef . .AllRequired 0 false }}) Validate() (err error) {
 }
 `
 
+	// webhookT generates the functions that send and verify a webhook.
+	// template input: *WebhookTemplateData
+	webhookT = `{{ $payload := gotyperef .Payload nil 0 false }}
+// Send{{ .Name }}Webhook sends the {{ printf "%q" .Webhook.Name }} webhook to url.{{ with .Webhook.Action }}
+// The webhook is triggered by the {{ .Name }} action of the {{ .Parent.Name }} resource.{{ end }}
+// The payload is {{ if .Validate }}validated, {{ end }}encoded in JSON and signed by d which also retries failed deliveries.
+func Send{{ .Name }}Webhook(ctx context.Context, d *goaclient.WebhookDispatcher, url string, payload {{ $payload }}{{ range .Headers }}, {{ .VarName }} {{ if .Pointer }}*{{ end }}{{ .Type }}{{ end }}) error {
+{{ if .Validate }}	if err := payload.Validate(); err != nil {
+		return err
+	}
+{{ end }}	header := make(http.Header)
+{{ range .Headers }}{{ if .Pointer }}	if {{ .VarName }} != nil {
+		header.Set("{{ .Name }}", {{ if .IsString }}*{{ .VarName }}{{ else }}fmt.Sprint(*{{ .VarName }}){{ end }})
+	}
+{{ else }}	header.Set("{{ .Name }}", {{ if .IsString }}{{ .VarName }}{{ else }}fmt.Sprint({{ .VarName }}){{ end }})
+{{ end }}{{ end }}	return d.Dispatch(ctx, url, payload, header)
+}
+
+// Verify{{ .Name }}Webhook checks the signature of a received {{ printf "%q" .Webhook.Name }} webhook request
+// and returns its payload. secret is the key used to sign the webhook.
+func Verify{{ .Name }}Webhook(req *http.Request, secret []byte) ({{ $payload }}, error) {
+	body, err := goa.VerifyWebhook(req, secret, goa.DefaultWebhookTolerance)
+	if err != nil {
+		return nil, err
+	}
+{{ if .Validate }}	var payload {{ gotypename .Payload nil 1 true }}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, goa.ErrBadRequest(err)
+	}{{ $assignment := finalizeCode .Payload.AttributeDefinition "payload" 1 }}{{ if $assignment }}
+	payload.Finalize(){{ end }}{{ $validation := validationCode .Payload.AttributeDefinition false false false "payload" "raw" 1 true }}{{ if $validation }}
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}{{ end }}
+	return payload.Publicize(), nil
+{{ else }}	var payload {{ gotypename .Payload nil 0 false }}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, goa.ErrBadRequest(err)
+	}
+	return &payload, nil
+{{ end }}}
+`
+
+	// operationMediaT generates the function that builds the operation media type from the state
+	// of an operation.
+	// template input: *design.MediaTypeDefinition
+	operationMediaT = `// New{{ .TypeName }} returns the media type that describes the status of the long-running
+// operation op.
+func New{{ .TypeName }}(op *goa.Operation) *{{ .TypeName }} {
+	mt := &{{ .TypeName }}{ID: op.ID, Status: string(op.Status), Result: op.Result}
+	if op.Error != "" {
+		mt.Error = &op.Error
+	}
+	return mt
+}
+`
+
+	// enumT generates the code for the enum types.
+	// template input: []*codegen.EnumType
+	enumT = `{{ range . }}{{ $enum := . }}{{ if .Description }}{{ comment .Description }}{{ else }}// {{ .Name }} enum type.{{ end }}
+type {{ .Name }} {{ .Native }}
+
+// {{ .Name }} values.
+const (
+{{ range .Values }}	{{ .Name }} {{ $enum.Name }} = {{ .Value }}
+{{ end }})
+
+// Valid returns true if e is one of the {{ .Name }} values.
+func (e {{ .Name }}) Valid() bool {
+	switch e {
+	case {{ range $i, $v := .Values }}{{ if $i }}, {{ end }}{{ $v.Name }}{{ end }}:
+		return true
+	}
+	return false
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (e {{ .Name }}) MarshalText() ([]byte, error) {
+{{ if eq .Native "string" }}	return []byte(e), nil
+{{ else }}	return []byte(fmt.Sprint({{ .Native }}(e))), nil
+{{ end }}}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (e *{{ .Name }}) UnmarshalText(text []byte) error {
+{{ if eq .Native "string" }}	v := {{ .Name }}(text)
+{{ else if eq .Native "int" }}	i, err := strconv.Atoi(string(text))
+	if err != nil {
+		return err
+	}
+	v := {{ .Name }}(i)
+{{ else if eq .Native "float64" }}	f, err := strconv.ParseFloat(string(text), 64)
+	if err != nil {
+		return err
+	}
+	v := {{ .Name }}(f)
+{{ else }}	b, err := strconv.ParseBool(string(text))
+	if err != nil {
+		return err
+	}
+	v := {{ .Name }}(b)
+{{ end }}	if !v.Valid() {
+		return goa.InvalidEnumValueError("{{ .Name }}", v, []interface{}{ {{ range $i, $v := .Values }}{{ if $i }}, {{ end }}{{ $v.Value }}{{ end }} })
+	}
+	*e = v
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e {{ .Name }}) MarshalJSON() ([]byte, error) {
+	return json.Marshal({{ .Native }}(e))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *{{ .Name }}) UnmarshalJSON(data []byte) error {
+	var raw {{ .Native }}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	v := {{ .Name }}(raw)
+	if !v.Valid() {
+		return goa.InvalidEnumValueError("{{ .Name }}", v, []interface{}{ {{ range $i, $v := .Values }}{{ if $i }}, {{ end }}{{ $v.Value }}{{ end }} })
+	}
+	*e = v
+	return nil
+}
+{{ end }}`
+
 	// securitySchemesT generates the code for the security module.
 	// template input: []*design.SecuritySchemeDefinition
 	securitySchemesT = `
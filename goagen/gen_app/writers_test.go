@@ -1651,7 +1651,121 @@ var _ = Describe("UserTypesWriter", func() {
 	})
 })
 
+var _ = Describe("EnumsWriter", func() {
+	var writer *genapp.EnumsWriter
+	var workspace *codegen.Workspace
+	var filename string
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		pkg, err := workspace.NewPackage("controllers")
+		Ω(err).ShouldNot(HaveOccurred())
+		src, err := pkg.CreateSourceFile("test.go")
+		Ω(err).ShouldNot(HaveOccurred())
+		defer src.Close()
+		filename = src.Abs()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		writer, err = genapp.NewEnumsWriter(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with an enum attribute", func() {
+		var enums []*codegen.EnumType
+
+		BeforeEach(func() {
+			enums = []*codegen.EnumType{{
+				Name:   "Color",
+				Native: "string",
+				Values: []*codegen.EnumValue{
+					{Name: "ColorRed", Value: `"red"`},
+					{Name: "ColorBlue", Value: `"blue"`},
+				},
+			}}
+		})
+
+		It("writes the enum type code", func() {
+			err := writer.Execute(enums)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := os.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring(enumType))
+		})
+	})
+})
+
+var _ = Describe("WebhooksWriter", func() {
+	var writer *genapp.WebhooksWriter
+	var workspace *codegen.Workspace
+	var filename string
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		pkg, err := workspace.NewPackage("controllers")
+		Ω(err).ShouldNot(HaveOccurred())
+		src, err := pkg.CreateSourceFile("test.go")
+		Ω(err).ShouldNot(HaveOccurred())
+		defer src.Close()
+		filename = src.Abs()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		writer, err = genapp.NewWebhooksWriter(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with a payload without validations", func() {
+		var data *genapp.WebhookTemplateData
+
+		BeforeEach(func() {
+			data = &genapp.WebhookTemplateData{
+				Name:    "OrderCreated",
+				Webhook: &design.WebhookDefinition{Name: "order_created"},
+				Payload: &design.UserTypeDefinition{
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{"id": &design.AttributeDefinition{Type: design.Integer}},
+					},
+					TypeName: "Order",
+				},
+				Validate: true,
+			}
+		})
+
+		It("validates the payload before sending it", func() {
+			err := writer.Execute(data)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := os.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(ContainSubstring(webhookSender))
+		})
+	})
+})
+
 const (
+	webhookSender = `// The payload is validated, encoded in JSON and signed by d which also retries failed deliveries.
+func SendOrderCreatedWebhook(ctx context.Context, d *goaclient.WebhookDispatcher, url string, payload *Order) error {
+	if err := payload.Validate(); err != nil {
+		return err
+	}
+	header := make(http.Header)
+`
+
 	emptyContext = `
 type ListBottleContext struct {
 	context.Context
@@ -2780,5 +2894,39 @@ func (ut *ComplexPayload) Validate() (err error) {
 
 	return
 }
+`
+
+	enumType = `// Color enum type.
+type Color string
+
+// Color values.
+const (
+	ColorRed Color = "red"
+	ColorBlue Color = "blue"
+)
+
+// Valid returns true if e is one of the Color values.
+func (e Color) Valid() bool {
+	switch e {
+	case ColorRed, ColorBlue:
+		return true
+	}
+	return false
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (e Color) MarshalText() ([]byte, error) {
+	return []byte(e), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (e *Color) UnmarshalText(text []byte) error {
+	v := Color(text)
+	if !v.Valid() {
+		return goa.InvalidEnumValueError("Color", v, []interface{}{ "red", "blue" })
+	}
+	*e = v
+	return nil
+}
 `
 )
@@ -32,7 +32,7 @@ func (g *Generator) generateMain(mainFile string, clientPkg, cliPkg string, func
 		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("os"),
 		codegen.SimpleImport("time"),
-		codegen.SimpleImport(clientPkg),
+		g.clientImport(clientPkg),
 		codegen.SimpleImport(cliPkg),
 		codegen.SimpleImport("github.com/spf13/cobra"),
 		codegen.NewImport("goaclient", "github.com/shogo82148/goa-v1/client"),
@@ -89,7 +89,7 @@ func (g *Generator) generateMain(mainFile string, clientPkg, cliPkg string, func
 		HasAPIKeySigners:    hasAPIKeySigners,
 		HasTokenSigners:     hasTokenSigners,
 	}
-	err = file.ExecuteTemplate("main", mainTmpl, funcs, data)
+	err = file.ExecuteTemplate("client/mainTmpl", mainTmpl, funcs, data)
 	return
 }
 
@@ -117,12 +117,7 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 	funcs["formatExample"] = formatExample
 	funcs["shouldAddExample"] = shouldAddExample
 	funcs["kebabCase"] = codegen.KebabCase
-
-	commandTypesTmpl := template.Must(template.New("commandTypes").Funcs(funcs).Parse(commandTypesTmpl))
-	commandsTmpl := template.Must(template.New("commands").Funcs(funcs).Parse(commandsTmpl))
-	commandsTmplWS := template.Must(template.New("commandsWS").Funcs(funcs).Parse(commandsTmplWS))
-	downloadCommandTmpl := template.Must(template.New("download").Funcs(funcs).Parse(downloadCommandTmpl))
-	registerTmpl := template.Must(template.New("register").Funcs(funcs).Parse(registerTmpl))
+	funcs["deprecationNotice"] = deprecationNotice
 
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("encoding/json"),
@@ -137,7 +132,7 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 		codegen.SimpleImport("time"),
 		codegen.NewImport("goa", "github.com/shogo82148/goa-v1"),
 		codegen.SimpleImport("github.com/spf13/cobra"),
-		codegen.SimpleImport(clientPkg),
+		g.clientImport(clientPkg),
 		codegen.SimpleImport("context"),
 		codegen.SimpleImport("golang.org/x/net/websocket"),
 		codegen.NewImport("uuid", "github.com/shogo82148/goa-v1/uuid"),
@@ -145,6 +140,7 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 	if len(g.API.Resources) > 0 {
 		imports = append(imports, codegen.NewImport("goaclient", "github.com/shogo82148/goa-v1/client"))
 	}
+	imports = codegen.GoTypeMappingImports(g.API, imports)
 	title := fmt.Sprintf("%s: CLI Commands", g.API.Context())
 	if err = file.WriteHeader(title, "cli", imports); err != nil {
 		return err
@@ -156,7 +152,7 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 	if err = g.API.IterateResources(func(res *design.ResourceDefinition) error {
 		fs = append(fs, res.FileServers...)
 		return res.IterateActions(func(action *design.ActionDefinition) error {
-			return commandTypesTmpl.Execute(file, action)
+			return file.ExecuteTemplate("client/commandTypesTmpl", commandTypesTmpl, funcs, action)
 		})
 	}); err != nil {
 		return err
@@ -191,7 +187,7 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 		Package:      g.Target,
 		HasDownloads: hasDownloads,
 	}
-	if err = file.ExecuteTemplate("registerCmds", registerCmdsT, funcs, data); err != nil {
+	if err = file.ExecuteTemplate("client/registerCmdsT", registerCmdsT, funcs, data); err != nil {
 		return err
 	}
 
@@ -228,7 +224,7 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 			Package:     g.Target,
 			FileServers: fsdata,
 		}
-		if err = downloadCommandTmpl.Execute(file, data); err != nil {
+		if err = file.ExecuteTemplate("client/downloadCommandTmpl", downloadCommandTmpl, funcs, data); err != nil {
 			return err
 		}
 	}
@@ -242,15 +238,15 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 			}
 			var err error
 			if action.WebSocket() {
-				err = commandsTmplWS.Execute(file, data)
+				err = file.ExecuteTemplate("client/commandsTmplWS", commandsTmplWS, funcs, data)
 			} else {
-				err = commandsTmpl.Execute(file, data)
+				err = file.ExecuteTemplate("client/commandsTmpl", commandsTmpl, funcs, data)
 
 			}
 			if err != nil {
 				return err
 			}
-			err = registerTmpl.Execute(file, data)
+			err = file.ExecuteTemplate("client/registerTmpl", registerTmpl, funcs, data)
 			return err
 		})
 	})
@@ -343,15 +339,27 @@ func joinNames(useNil bool, atts ...*design.AttributeDefinition) string {
 	return strings.Join(elems, ", ")
 }
 
+// clientImport returns the import spec of the generated client package. The package name is
+// explicit when generating the client of an API version as it differs from the directory name.
+func (g *Generator) clientImport(clientPkg string) *codegen.ImportSpec {
+	if g.versionDir != "" {
+		return codegen.NewImport(g.Target, clientPkg)
+	}
+	return codegen.SimpleImport(clientPkg)
+}
+
 // resolve non required, non array Param/QueryParam for access via CII flags.
 // Some types need conversion from string to 'Type' before calling rich client Commands.
 func flagTypeVal(a *design.AttributeDefinition, key string, field string) string {
+	if codegen.GoTypeMapping(a.Type) != nil {
+		return "%s"
+	}
 	switch a.Type {
 	case design.Integer:
 		return `intFlagVal("` + key + `", ` + field + ")"
 	case design.String:
 		return `stringFlagVal("` + key + `", ` + field + ")"
-	case design.Number, design.Boolean, design.UUID, design.DateTime, design.Any:
+	case design.Number, design.Boolean, design.UUID, design.DateTime, design.Any, design.Date, design.TimeOfDay, design.Duration, design.Decimal:
 		return "%s"
 	default:
 		return "&" + field
@@ -363,8 +371,11 @@ func flagTypeVal(a *design.AttributeDefinition, key string, field string) string
 // Special types like Number/UUID need to be converted from String
 // %s maps to specialTypeResult.Temps
 func flagRequiredTypeVal(a *design.AttributeDefinition, field string) string {
+	if codegen.GoTypeMapping(a.Type) != nil {
+		return "*%s"
+	}
 	switch a.Type {
-	case design.Number, design.Boolean, design.UUID, design.DateTime, design.Any:
+	case design.Number, design.Boolean, design.UUID, design.DateTime, design.Any, design.Date, design.TimeOfDay, design.Duration, design.Decimal:
 		return "*%s"
 	default:
 		return field
@@ -375,8 +386,11 @@ func flagRequiredTypeVal(a *design.AttributeDefinition, field string) string {
 // Special types like Number/UUID need to be converted from String
 // %s maps to specialTypeResult.Temps
 func flagTypeArrayVal(a *design.AttributeDefinition, field string) string {
+	if elemTypeMapping(a.Type) != nil {
+		return "%s"
+	}
 	switch a.Type.ToArray().ElemType.Type {
-	case design.Number, design.Boolean, design.UUID, design.DateTime, design.Any:
+	case design.Number, design.Boolean, design.UUID, design.DateTime, design.Any, design.Date, design.TimeOfDay, design.Duration, design.Decimal:
 		return "%s"
 	}
 	return field
@@ -428,8 +442,17 @@ func handleSpecialTypes(atts ...*design.AttributeDefinition) specialTypeResult {
 			a := obj[n]
 			field := fmt.Sprintf("cmd.%s", codegen.Goify(n, true))
 			typ := cmdFieldType(a.Type, true)
+			args := field
 			var typeHandler, nilVal string
-			if !a.Type.IsArray() {
+			if m := codegen.GoTypeMapping(a.Type); m != nil {
+				nilVal = `""`
+				typeHandler = "mappedVal"
+				args = fmt.Sprintf("%s, %s", field, m.ParseFunction())
+			} else if m := elemTypeMapping(a.Type); m != nil {
+				nilVal = "nil"
+				typeHandler = "mappedArray"
+				args = fmt.Sprintf("%s, %s", field, m.ParseFunction())
+			} else if !a.Type.IsArray() {
 				nilVal = `""`
 				switch a.Type {
 				case design.Number:
@@ -442,6 +465,14 @@ func handleSpecialTypes(atts ...*design.AttributeDefinition) specialTypeResult {
 					typeHandler = "timeVal"
 				case design.Any:
 					typeHandler = "jsonVal"
+				case design.Date:
+					typeHandler = "dateVal"
+				case design.TimeOfDay:
+					typeHandler = "timeOfDayVal"
+				case design.Duration:
+					typeHandler = "durationVal"
+				case design.Decimal:
+					typeHandler = "decimalVal"
 				}
 
 			} else if a.Type.IsArray() {
@@ -457,6 +488,14 @@ func handleSpecialTypes(atts ...*design.AttributeDefinition) specialTypeResult {
 					typeHandler = "timeArray"
 				case design.Any:
 					typeHandler = "jsonArray"
+				case design.Date:
+					typeHandler = "dateArray"
+				case design.TimeOfDay:
+					typeHandler = "timeOfDayArray"
+				case design.Duration:
+					typeHandler = "durationArray"
+				case design.Decimal:
+					typeHandler = "decimalArray"
 				}
 			}
 			if typeHandler != "" {
@@ -477,7 +516,7 @@ func handleSpecialTypes(atts ...*design.AttributeDefinition) specialTypeResult {
 			goa.LogError(ctx, "failed to parse flag into %s value", "flag", "--%s", "err", err)
 			return err
 		}
-	}`, tmpVar, typ, field, nilVal, tmpVar, typeHandler, field, typ, n)
+	}`, tmpVar, typ, field, nilVal, tmpVar, typeHandler, args, typ, n)
 				if att.IsRequired(n) {
 					result.Output += fmt.Sprintf(`
 	if %s == nil {
@@ -553,8 +592,24 @@ func signerArgs(sec *design.SecuritySchemeDefinition) string {
 	}
 }
 
+// deprecationNotice returns the message printed by the CLI when a deprecated command or flag is
+// used. cobra prefixes the message with the name of the command or flag and "is deprecated".
+func deprecationNotice(d *design.DeprecationDefinition) string {
+	notice := strings.TrimLeft(strings.TrimPrefix(d.Message(), "deprecated"), " ,")
+	if notice == "" {
+		notice = "it will be removed in a future version"
+	}
+	return notice
+}
+
 // flagType returns the flag type for the given (basic type) attribute definition.
 func flagType(att *design.AttributeDefinition) string {
+	if codegen.GoTypeMapping(att.Type) != nil {
+		return "String"
+	}
+	if elemTypeMapping(att.Type) != nil {
+		return "StringSlice"
+	}
 	switch att.Type.Kind() {
 	case design.IntegerKind:
 		return "Int"
@@ -570,6 +625,8 @@ func flagType(att *design.AttributeDefinition) string {
 		return "String"
 	case design.AnyKind:
 		return "String"
+	case design.DateKind, design.TimeOfDayKind, design.DurationKind, design.DecimalKind:
+		return "String"
 	case design.ArrayKind:
 		switch att.Type.ToArray().ElemType.Type.Kind() {
 		case design.NumberKind:
@@ -803,14 +860,17 @@ func (cmd *{{ $cmdName }}) RegisterFlags(cc *cobra.Command, c *{{ .Package }}.Cl
 */}}{{ if not $pparam.DefaultValue }}	var {{ $tmp }} {{ cmdFieldType $pparam.Type false }}
 {{ end }}	cc.Flags().{{ flagType $pparam }}Var(&cmd.{{ goify $pname true }}, "{{ $pname }}", {{/*
 */}}{{ if $pparam.DefaultValue }}{{ defaultVal $pparam }}{{ else }}{{ $tmp }}{{ end }}, ` + "`" + `{{ escapeBackticks $pparam.Description }}` + "`" + `)
-{{ end }}{{ end }}{{ $params := .Action.QueryParams }}{{ if $params }}{{ range $name, $param := $params.Type.ToObject }}{{ $tmp := goify $name false }}{{/*
+{{ with $pparam.Deprecation }}	cc.Flags().MarkDeprecated("{{ $pname }}", {{ printf "%q" (deprecationNotice .) }})
+{{ end }}{{ end }}{{ end }}{{ $params := .Action.QueryParams }}{{ if $params }}{{ range $name, $param := $params.Type.ToObject }}{{ $tmp := goify $name false }}{{/*
 */}}{{ if not $param.DefaultValue }}	var {{ $tmp }} {{ cmdFieldType $param.Type false }}
 {{ end }}	cc.Flags().{{ flagType $param }}Var(&cmd.{{ goify $name true }}, "{{ $name }}", {{/*
 */}}{{ if $param.DefaultValue }}{{ defaultVal $param }}{{ else }}{{ $tmp }}{{ end }}, ` + "`" + `{{ escapeBackticks $param.Description }}` + "`" + `)
-{{ end }}{{ end }}{{ $headers := .Action.Headers }}{{ if $headers }}{{ range $name, $header := $headers.Type.ToObject }}{{/*
+{{ with $param.Deprecation }}	cc.Flags().MarkDeprecated("{{ $name }}", {{ printf "%q" (deprecationNotice .) }})
+{{ end }}{{ end }}{{ end }}{{ $headers := .Action.Headers }}{{ if $headers }}{{ range $name, $header := $headers.Type.ToObject }}{{/*
 */}} cc.Flags().StringVar(&cmd.{{ goify $name true }}, "{{ $name }}", {{/*
 */}}{{ if $header.DefaultValue }}{{ defaultVal $header }}{{ else }}""{{ end }}, ` + "`" + `{{ escapeBackticks $header.Description }}` + "`" + `)
-{{ end }}{{ end }}}`
+{{ with $header.Deprecation }}	cc.Flags().MarkDeprecated("{{ $name }}", {{ printf "%q" (deprecationNotice .) }})
+{{ end }}{{ end }}{{ end }}}`
 
 const commandsTmpl = `
 {{ $cmdName := goify (printf "%s%sCommand" .Action.Name (title (kebabCase .Resource.Name))) true }}// Run makes the HTTP request corresponding to the {{ $cmdName }} command.
@@ -841,9 +901,10 @@ func (cmd *{{ $cmdName }}) Run(c *{{ .Package }}.Client, args []string) error {
 		return err
 	}
 
-	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+{{ if .Action.Events }}	return goaclient.HandleEvents(c.Client, resp, cmd.PrettyPrint)
+{{ else }}	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
 	return nil
-}
+{{ end }}}
 `
 
 // Takes map[string][]*design.ActionDefinition as input
@@ -863,7 +924,8 @@ func RegisterCommands(app *cobra.Command, c *{{ .Package }}.Client) {
 
 Payload example:
 
-{{ formatExample $action.Payload.Example }}` + "`" + `,{{ end }}
+{{ formatExample $action.Payload.Example }}` + "`" + `,{{ end }}{{ with $action.Deprecation }}
+		Deprecated: {{ printf "%q" (deprecationNotice .) }},{{ end }}
 		RunE:  func(cmd *cobra.Command, args []string) error { return {{ $tmp }}.Run(c, args) },
 	}
 	{{ $tmp }}.RegisterFlags(sub, c)
@@ -1034,4 +1096,119 @@ func boolArray(ins []string) ([]bool, error) {
 		vals = append(vals, *val)
 	}
 	return vals, nil
+}
+
+func dateVal(val string) (*goa.Date, error) {
+	t, err := goa.ParseDate(val)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func dateArray(ins []string) ([]goa.Date, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	var vals []goa.Date
+	for _, id := range ins {
+		val, err := dateVal(id)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, *val)
+	}
+	return vals, nil
+}
+
+func timeOfDayVal(val string) (*goa.TimeOfDay, error) {
+	t, err := goa.ParseTimeOfDay(val)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func timeOfDayArray(ins []string) ([]goa.TimeOfDay, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	var vals []goa.TimeOfDay
+	for _, id := range ins {
+		val, err := timeOfDayVal(id)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, *val)
+	}
+	return vals, nil
+}
+
+func durationVal(val string) (*goa.Duration, error) {
+	t, err := goa.ParseDuration(val)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func durationArray(ins []string) ([]goa.Duration, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	var vals []goa.Duration
+	for _, id := range ins {
+		val, err := durationVal(id)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, *val)
+	}
+	return vals, nil
+}
+
+func decimalVal(val string) (*goa.Decimal, error) {
+	t, err := goa.ParseDecimal(val)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func decimalArray(ins []string) ([]goa.Decimal, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	var vals []goa.Decimal
+	for _, id := range ins {
+		val, err := decimalVal(id)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, *val)
+	}
+	return vals, nil
+}
+
+func mappedVal[T any](val string, parse func(string) (T, error)) (*T, error) {
+	t, err := parse(val)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func mappedArray[T any](ins []string, parse func(string) (T, error)) ([]T, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	var vals []T
+	for _, id := range ins {
+		val, err := mappedVal(id, parse)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, *val)
+	}
+	return vals, nil
 }`
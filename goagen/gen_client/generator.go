@@ -39,14 +39,16 @@ type Generator struct {
 	ToolDirName string                // Name of tool directory where CLI main is generated once
 	Tool        string                // Name of CLI tool
 	NoTool      bool                  // Whether to skip tool generation
+	AppPkg      string                // Import path of the app package defining the enum types, if any
 	genfiles    []string
+	versionDir  string // Name of the API version subdirectory if generating a version client
 }
 
 // Generate is the generator entry point called by the meta generator.
 func Generate() (files []string, err error) {
 	var (
-		outDir, target, toolDir, tool, ver string
-		notool, regen                      bool
+		outDir, target, toolDir, tool, ver, templates, appPkg string
+		notool, regen                                         bool
 	)
 	dtool := defaultToolName(design.Design)
 
@@ -61,16 +63,21 @@ func Generate() (files []string, err error) {
 	set.String("design", "", "")
 	set.Bool("force", false, "")
 	set.Bool("notest", false, "")
+	set.StringVar(&templates, "templates", "", "")
+	set.StringVar(&appPkg, "app-pkg", "", "")
 	set.Parse(os.Args[1:])
 
 	// First check compatibility
 	if err := codegen.CheckVersion(ver); err != nil {
 		return nil, err
 	}
+	if err := codegen.LoadTemplateOverrides(templates, "client"); err != nil {
+		return nil, err
+	}
 
 	// Now proceed
 	target = codegen.Goify(target, false)
-	g := &Generator{OutDir: outDir, Target: target, ToolDirName: toolDir, Tool: tool, NoTool: notool, API: design.Design}
+	g := &Generator{OutDir: outDir, Target: target, ToolDirName: toolDir, Tool: tool, NoTool: notool, AppPkg: appPkg, API: design.Design}
 
 	return g.Generate()
 }
@@ -104,18 +111,35 @@ func (g *Generator) Generate() (_ []string, err error) {
 
 	codegen.Reserved[g.Target] = true
 
+	if len(g.API.APIVersions) > 0 && g.API.SelectedVersion == nil {
+		// Generate one client and CLI per API version
+		err = codegen.ForEachVersion(g.API, func(api *design.APIDefinition) error {
+			vg := *g
+			vg.API = api
+			vg.versionDir = api.SelectedVersion.Name
+			vg.genfiles = nil
+			files, err := vg.Generate()
+			g.genfiles = append(g.genfiles, files...)
+			return err
+		})
+		if err != nil {
+			return
+		}
+		return g.genfiles, nil
+	}
+
 	// Setup output directories as needed
 	var pkgDir, toolDir, cliDir string
 	{
 		if !g.NoTool {
-			toolDir = filepath.Join(g.OutDir, g.ToolDirName, g.Tool)
+			toolDir = filepath.Join(g.OutDir, g.ToolDirName, g.versionDir, g.Tool)
 			if _, err = os.Stat(toolDir); err != nil {
 				if err = os.MkdirAll(toolDir, 0755); err != nil {
 					return
 				}
 			}
 
-			cliDir = filepath.Join(g.OutDir, g.ToolDirName, "cli")
+			cliDir = filepath.Join(g.OutDir, g.ToolDirName, g.versionDir, "cli")
 			if err = os.RemoveAll(cliDir); err != nil {
 				return
 			}
@@ -124,7 +148,7 @@ func (g *Generator) Generate() (_ []string, err error) {
 			}
 		}
 
-		pkgDir = filepath.Join(g.OutDir, g.Target)
+		pkgDir = filepath.Join(g.OutDir, g.Target, g.versionDir)
 		if err = os.RemoveAll(pkgDir); err != nil {
 			return
 		}
@@ -158,6 +182,8 @@ func (g *Generator) Generate() (_ []string, err error) {
 			"tempvar":            codegen.Tempvar,
 			"title":              strings.Title,
 			"toString":           toString,
+			"pathToString":       pathToString,
+			"toStringOnError":    toStringOnError,
 			"toValueTypeName":    toValueTypeName,
 			"typeName":           typeName,
 			"format":             format,
@@ -236,7 +262,6 @@ func (g *Generator) generateClient(clientFile string, clientPkg string, funcs te
 			err = file.FormatCode()
 		}
 	}()
-	clientTmpl := template.Must(template.New("client").Funcs(funcs).Parse(clientTmpl))
 
 	// Compute list of encoders and decoders
 	encoders, err := genapp.BuildEncoders(g.API.Produces, true)
@@ -288,7 +313,7 @@ func (g *Generator) generateClient(clientFile string, clientPkg string, funcs te
 		Encoders: encoders,
 		Decoders: decoders,
 	}
-	err = clientTmpl.Execute(file, data)
+	err = file.ExecuteTemplate("client/clientTmpl", clientTmpl, funcs, data)
 	return
 }
 
@@ -302,14 +327,14 @@ func (g *Generator) generateClientResources(pkgDir, clientPkg string, funcs temp
 	if err := g.generateUserTypes(pkgDir); err != nil {
 		return err
 	}
+	if err := g.generateEnums(pkgDir); err != nil {
+		return err
+	}
 
 	return g.generateMediaTypes(pkgDir, funcs)
 }
 
 func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDefinition, funcs template.FuncMap) (err error) {
-	payloadTmpl := template.Must(template.New("payload").Funcs(funcs).Parse(payloadTmpl))
-	pathTmpl := template.Must(template.New("pathTemplate").Funcs(funcs).Parse(pathTmpl))
-
 	resFilename := codegen.SnakeCase(res.Name)
 	if resFilename == typesFileName {
 		// Avoid clash with datatypes.go
@@ -344,8 +369,11 @@ func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDe
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("context"),
 		codegen.SimpleImport("golang.org/x/net/websocket"),
+		codegen.NewImport("goa", "github.com/shogo82148/goa-v1"),
+		codegen.NewImport("goaclient", "github.com/shogo82148/goa-v1/client"),
 		codegen.NewImport("uuid", "github.com/shogo82148/goa-v1/uuid"),
 	}
+	imports = codegen.GoTypeMappingImports(g.API, imports)
 	title := fmt.Sprintf("%s: %s Resource Client", g.API.Context(), res.Name)
 	if err = file.WriteHeader(title, g.Target, imports); err != nil {
 		return err
@@ -370,7 +398,7 @@ func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDe
 				}
 			}
 			if !found {
-				if err := payloadTmpl.Execute(file, action); err != nil {
+				if err := file.ExecuteTemplate("client/payloadTmpl", payloadTmpl, funcs, action); err != nil {
 					return err
 				}
 			}
@@ -400,7 +428,7 @@ func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDe
 				Index:  i,
 				Params: pd,
 			}
-			if err := pathTmpl.Execute(file, data); err != nil {
+			if err := file.ExecuteTemplate("client/pathTmpl", pathTmpl, funcs, data); err != nil {
 				return err
 			}
 		}
@@ -413,7 +441,6 @@ func (g *Generator) generateFileServer(file *codegen.SourceFile, fs *design.File
 	var (
 		dir string
 
-		fsTmpl = template.Must(template.New("fileserver").Funcs(funcs).Parse(fsTmpl))
 		name   = g.fileServerMethod(fs)
 		wcs    = design.ExtractWildcards(fs.RequestPath)
 		scheme = "http"
@@ -448,20 +475,49 @@ func (g *Generator) generateFileServer(file *codegen.SourceFile, fs *design.File
 		RequestDir:      requestDir,
 		CanonicalScheme: scheme,
 	}
-	return fsTmpl.Execute(file, data)
+	return file.ExecuteTemplate("client/fsTmpl", fsTmpl, funcs, data)
 }
 
 func (g *Generator) generateActionClient(action *design.ActionDefinition, file *codegen.SourceFile, funcs template.FuncMap) error {
 	var (
-		params        []string
-		names         []string
-		queryParams   []*paramData
-		headers       []*paramData
-		signer        string
-		clientsTmpl   = template.Must(template.New("clients").Funcs(funcs).Parse(clientsTmpl))
-		requestsTmpl  = template.Must(template.New("requests").Funcs(funcs).Parse(requestsTmpl))
-		clientsWSTmpl = template.Must(template.New("clientsws").Funcs(funcs).Parse(clientsWSTmpl))
+		params      []string
+		names       []string
+		queryParams []*paramData
+		headers     []*paramData
+		signer      string
+		eventType   *design.MediaTypeDefinition
+		stream      *design.ResponseDefinition
+		streamElem  design.DataType
+		inbound     design.DataType
+		outbound    design.DataType
 	)
+	if action.Events != nil {
+		mt := action.Events.EventType()
+		if mt == nil {
+			return fmt.Errorf("unknown event media type %#v", action.Events.MediaType)
+		}
+		p, _, err := mt.Project(design.DefaultView)
+		if err != nil {
+			return err
+		}
+		eventType = p
+	}
+	if stream = streamedResponse(action); stream != nil {
+		elem, err := streamElemType(stream)
+		if err != nil {
+			return err
+		}
+		streamElem = elem
+	}
+	if m := action.Messages; m != nil {
+		var err error
+		if inbound, err = m.InboundType(); err != nil {
+			return err
+		}
+		if outbound, err = m.OutboundType(); err != nil {
+			return err
+		}
+	}
 	if action.Payload != nil {
 		params = append(params, "payload "+codegen.GoTypeRef(action.Payload, action.Payload.AllRequired(), 1, false))
 		names = append(names, "payload")
@@ -506,6 +562,16 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 		Signer             string
 		QueryParams        []*paramData
 		Headers            []*paramData
+		Deprecation        *design.DeprecationDefinition
+		Pagination         *design.PaginationDefinition
+		Events             *design.EventStreamDefinition
+		EventType          *design.MediaTypeDefinition
+		Messages           *design.MessagesDefinition
+		Inbound            design.DataType
+		Outbound           design.DataType
+		Stream             *design.ResponseDefinition
+		StreamElem         design.DataType
+		Criteria           *criteriaData
 	}{
 		Name:               action.Name,
 		ResourceName:       action.Parent.Name,
@@ -522,14 +588,151 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 		Signer:             signer,
 		QueryParams:        queryParams,
 		Headers:            headers,
+		Deprecation:        action.Deprecation,
+		Pagination:         action.Pagination,
+		Events:             action.Events,
+		EventType:          eventType,
+		Messages:           action.Messages,
+		Inbound:            inbound,
+		Outbound:           outbound,
+		Stream:             stream,
+		StreamElem:         streamElem,
+	}
+	if action.Criteria != nil {
+		data.Criteria = newCriteriaData(action.Criteria, queryParams, params, names)
 	}
 	if action.WebSocket() {
-		return clientsWSTmpl.Execute(file, data)
+		return file.ExecuteTemplate("client/clientsWSTmpl", clientsWSTmpl, funcs, data)
 	}
-	if err := clientsTmpl.Execute(file, data); err != nil {
+	if err := file.ExecuteTemplate("client/clientsTmpl", clientsTmpl, funcs, data); err != nil {
 		return err
 	}
-	return requestsTmpl.Execute(file, data)
+	if action.Events != nil {
+		if err := file.ExecuteTemplate("client/eventsTmpl", eventsTmpl, funcs, data); err != nil {
+			return err
+		}
+	}
+	if stream != nil {
+		if err := file.ExecuteTemplate("client/streamTmpl", streamTmpl, funcs, data); err != nil {
+			return err
+		}
+	}
+	if action.LongRunning != nil {
+		if err := file.ExecuteTemplate("client/operationTmpl", operationTmpl, funcs, data); err != nil {
+			return err
+		}
+	}
+	if data.Criteria != nil {
+		if err := file.ExecuteTemplate("client/criteriaTmpl", criteriaTmpl, funcs, data); err != nil {
+			return err
+		}
+	}
+	return file.ExecuteTemplate("client/requestsTmpl", requestsTmpl, funcs, data)
+}
+
+// criteriaData is the data structure holding the information needed to generate the criteria
+// builder of filterable and sortable actions.
+type criteriaData struct {
+	// Fields lists the fields of the builder holding the filters, one per filter parameter.
+	Fields []*criteriaFieldData
+	// Sort and Order are the fields of the builder holding the sort parameters if the action is
+	// sortable.
+	Sort, Order string
+	// Params is the signature of the parameters of the action that are not set by the builder.
+	Params string
+	// Args lists the arguments of the request method, the parameters set by the builder are
+	// read from the builder fields.
+	Args string
+}
+
+// criteriaFieldData describes a field of a criteria builder.
+type criteriaFieldData struct {
+	Param    *design.FilterParamDefinition
+	Field    string // Name of the builder field, e.g. "ratingMin"
+	Setter   string // Name of the builder method that sets the field, e.g. "RatingMin"
+	ArgType  string // Type of the method argument, the type of the elements for "in" filters
+	Variadic bool   // true if the method accepts a variable number of values
+}
+
+// newCriteriaData builds the criteria builder data of an action given its query parameters and
+// the signature and names of the arguments of its client methods.
+func newCriteriaData(c *design.CriteriaDefinition, queryParams []*paramData, params, names []string) *criteriaData {
+	data := &criteriaData{}
+	byName := make(map[string]*paramData, len(queryParams))
+	for _, p := range queryParams {
+		byName[p.Name] = p
+	}
+	fields := make(map[string]bool)
+	for _, fp := range c.FilterParams() {
+		p, ok := byName[fp.Name]
+		if !ok {
+			continue
+		}
+		f := &criteriaFieldData{Param: fp, Field: p.VarName, Setter: codegen.Goify(fp.Name, true)}
+		if p.IsArray {
+			f.ArgType = cmdFieldType(p.ElemAttribute.Type, false)
+			f.Variadic = true
+		} else {
+			f.ArgType = cmdFieldType(p.Attribute.Type, false)
+		}
+		data.Fields = append(data.Fields, f)
+		fields[p.VarName] = true
+	}
+	if len(c.Sorts) > 0 {
+		if p, ok := byName[design.SortParam]; ok {
+			data.Sort = p.VarName
+			fields[p.VarName] = true
+		}
+		if p, ok := byName[design.OrderParam]; ok {
+			data.Order = p.VarName
+			fields[p.VarName] = true
+		}
+	}
+	var sig, args []string
+	for i, n := range names {
+		if fields[n] {
+			args = append(args, "criteria."+n)
+			continue
+		}
+		sig = append(sig, params[i])
+		args = append(args, n)
+	}
+	data.Params = strings.Join(sig, ", ")
+	data.Args = strings.Join(args, ", ")
+	return data
+}
+
+// streamedResponse returns the streamed response of the given action with the lowest status code,
+// nil if the action has none.
+func streamedResponse(action *design.ActionDefinition) *design.ResponseDefinition {
+	var stream *design.ResponseDefinition
+	for _, resp := range action.Responses {
+		if resp.Stream && (stream == nil || resp.Status < stream.Status) {
+			stream = resp
+		}
+	}
+	return stream
+}
+
+// streamElemType returns the type of the elements of the collection streamed by resp projected on
+// the response view.
+func streamElemType(resp *design.ResponseDefinition) (design.DataType, error) {
+	mt := design.Design.MediaTypeWithIdentifier(resp.MediaType)
+	if mt == nil {
+		return nil, fmt.Errorf("unknown streamed media type %#v", resp.MediaType)
+	}
+	view := resp.ViewName
+	if view == "" {
+		view = design.DefaultView
+	}
+	p, _, err := mt.Project(view)
+	if err != nil {
+		return nil, err
+	}
+	if !p.IsArray() {
+		return nil, fmt.Errorf("streamed media type %#v is not a collection", resp.MediaType)
+	}
+	return p.Type.ToArray().ElemType.Type, nil
 }
 
 // fileServerMethod returns the name of the client method for downloading assets served by the given
@@ -567,7 +770,6 @@ func (g *Generator) fileServerMethod(fs *design.FileServerDefinition) string {
 func (g *Generator) generateMediaTypes(pkgDir string, funcs template.FuncMap) (err error) {
 	funcs["decodegotyperef"] = decodeGoTypeRef
 	funcs["decodegotypename"] = decodeGoTypeName
-	typeDecodeTmpl := template.Must(template.New("typeDecode").Funcs(funcs).Parse(typeDecodeTmpl))
 	var (
 		mtFile string
 		mtWr   *genapp.MediaTypesWriter
@@ -597,6 +799,7 @@ func (g *Generator) generateMediaTypes(pkgDir string, funcs template.FuncMap) (e
 	for _, v := range g.API.MediaTypes {
 		imports = codegen.AttributeImports(v.AttributeDefinition, imports, nil)
 	}
+	imports = codegen.GoTypeMappingImports(g.API, imports)
 	if err = mtWr.WriteHeader(title, g.Target, imports); err != nil {
 		return err
 	}
@@ -612,10 +815,14 @@ func (g *Generator) generateMediaTypes(pkgDir string, funcs template.FuncMap) (e
 			if err != nil {
 				return err
 			}
-			return typeDecodeTmpl.Execute(mtWr.SourceFile, p)
+			return mtWr.SourceFile.ExecuteTemplate("client/typeDecodeTmpl", typeDecodeTmpl, funcs, p)
 		})
 		return err
 	})
+	if err != nil {
+		return
+	}
+	err = mtWr.WriteHypermediaSchemas()
 	return
 }
 
@@ -650,11 +857,16 @@ func (g *Generator) generateUserTypes(pkgDir string) (err error) {
 	for _, v := range g.API.Types {
 		imports = codegen.AttributeImports(v.AttributeDefinition, imports, nil)
 	}
+	imports = codegen.GoTypeMappingImports(g.API, imports)
 	if err = utWr.WriteHeader(title, g.Target, imports); err != nil {
 		return err
 	}
 	g.genfiles = append(g.genfiles, utFile)
 	err = g.API.IterateUserTypes(func(t *design.UserTypeDefinition) error {
+		if codegen.GoTypeMapping(t) != nil {
+			// Types mapped to existing Go types are not generated
+			return nil
+		}
 		o := t.Type.ToObject()
 		for _, att := range o {
 			if att.Type.Kind() == design.FileKind {
@@ -666,6 +878,92 @@ func (g *Generator) generateUserTypes(pkgDir string) (err error) {
 	return
 }
 
+// generateEnums generates the named types of the enum attributes and user types. The types are
+// aliases of the types generated in the app package if one is given with --app-pkg so that the
+// values can be shared between the two packages.
+func (g *Generator) generateEnums(pkgDir string) (err error) {
+	enums := codegen.EnumTypes(g.API)
+	if len(enums) == 0 {
+		return nil
+	}
+	if g.AppPkg != "" {
+		return g.generateEnumAliases(pkgDir, enums)
+	}
+	var (
+		enumFile string
+		enumWr   *genapp.EnumsWriter
+	)
+	{
+		enumFile = filepath.Join(pkgDir, "enums.go")
+		enumWr, err = genapp.NewEnumsWriter(enumFile)
+		if err != nil {
+			return
+		}
+	}
+	defer func() {
+		enumWr.Close()
+		if err == nil {
+			err = enumWr.FormatCode()
+		}
+	}()
+	title := fmt.Sprintf("%s: Application Enum Types", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("strconv"),
+		codegen.NewImport("goa", "github.com/shogo82148/goa-v1"),
+	}
+	if err = enumWr.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, enumFile)
+	return enumWr.Execute(enums)
+}
+
+// generateEnumAliases generates the aliases of the enum types and constants defined in the app
+// package.
+func (g *Generator) generateEnumAliases(pkgDir string, enums []*codegen.EnumType) (err error) {
+	elems := strings.Split(g.AppPkg, "/")
+	pkgName := elems[len(elems)-1]
+	var imp string
+	if _, err := codegen.PackageSourcePath(g.AppPkg); err == nil {
+		imp = g.AppPkg
+	} else {
+		imp, err = codegen.PackagePath(g.OutDir)
+		if err != nil {
+			return err
+		}
+		imp = path.Join(filepath.ToSlash(imp), g.AppPkg)
+	}
+
+	enumFile := filepath.Join(pkgDir, "enums.go")
+	var file *codegen.SourceFile
+	file, err = codegen.SourceFileFor(enumFile)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		file.Close()
+		if err == nil {
+			err = file.FormatCode()
+		}
+	}()
+	title := fmt.Sprintf("%s: Application Enum Types", g.API.Context())
+	imports := []*codegen.ImportSpec{codegen.SimpleImport(imp)}
+	if err = file.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, enumFile)
+	data := struct {
+		Pkg   string
+		Enums []*codegen.EnumType
+	}{
+		Pkg:   pkgName,
+		Enums: enums,
+	}
+	return file.ExecuteTemplate("client/enumAliasesTmpl", enumAliasesTmpl, nil, data)
+}
+
 // join is a code generation helper function that generates a function signature built from
 // concatenating the properties (name type) of the given attribute type (assuming it's an object).
 // join accepts an optional slice of strings which indicates the order in which the parameters
@@ -748,7 +1046,13 @@ func cmdFieldType(t design.DataType, point bool) string {
 	if point && !t.IsArray() {
 		pointer = "*"
 	}
-	suffix = codegen.GoNativeType(t)
+	if m := codegen.GoTypeMapping(t); m != nil {
+		suffix = m.GoType
+	} else if m := elemTypeMapping(t); m != nil {
+		suffix = "[]" + m.GoType
+	} else {
+		suffix = codegen.GoNativeType(t)
+	}
 	return pointer + suffix
 }
 
@@ -758,9 +1062,11 @@ func cmdFieldTypeString(t design.DataType, point bool) string {
 	if point && !t.IsArray() {
 		pointer = "*"
 	}
-	if t.Kind() == design.UUIDKind || t.Kind() == design.DateTimeKind || t.Kind() == design.AnyKind || t.Kind() == design.NumberKind || t.Kind() == design.BooleanKind {
+	kinds := []design.Kind{design.UUIDKind, design.DateTimeKind, design.AnyKind, design.NumberKind, design.BooleanKind,
+		design.DateKind, design.TimeOfDayKind, design.DurationKind, design.DecimalKind}
+	if isKind(t, kinds...) || codegen.GoTypeMapping(t) != nil {
 		suffix = "string"
-	} else if isArrayOfType(t, design.UUIDKind, design.DateTimeKind, design.AnyKind, design.NumberKind, design.BooleanKind) {
+	} else if isArrayOfType(t, kinds...) || elemTypeMapping(t) != nil {
 		suffix = "[]string"
 	} else {
 		suffix = codegen.GoNativeType(t)
@@ -768,6 +1074,24 @@ func cmdFieldTypeString(t design.DataType, point bool) string {
 	return pointer + suffix
 }
 
+// elemTypeMapping returns the Go type mapping of the element type of the given array type, nil if t
+// is not an array or if its element type is not mapped.
+func elemTypeMapping(t design.DataType) *design.GoTypeMappingDefinition {
+	if !t.IsArray() {
+		return nil
+	}
+	return codegen.GoTypeMapping(t.ToArray().ElemType.Type)
+}
+
+func isKind(t design.DataType, kinds ...design.Kind) bool {
+	for _, k := range kinds {
+		if t.Kind() == k {
+			return true
+		}
+	}
+	return false
+}
+
 func isArrayOfType(array design.DataType, kinds ...design.Kind) bool {
 	if !array.IsArray() {
 		return false
@@ -786,7 +1110,30 @@ func isArrayOfType(array design.DataType, kinds ...design.Kind) bool {
 var arrayToStringTmpl *template.Template
 
 // toString generates Go code that converts the given simple type attribute into a string.
+// The generated code returns the error of the conversion of mapped Go types.
 func toString(name, target string, att *design.AttributeDefinition) string {
+	return toStringOnError(name, target, att, "return nil, err")
+}
+
+// pathToString generates Go code that converts the given path parameter into a string. Path
+// functions do not return errors so the generated code panics if a mapped Go type cannot be
+// marshaled.
+func pathToString(name, target string, att *design.AttributeDefinition) string {
+	return toStringOnError(name, target, att, "panic(err)")
+}
+
+// toStringOnError generates Go code that converts the given simple type attribute into a string.
+// Mapped Go types are converted with MarshalText so that the server parses them back with
+// UnmarshalText, onErr is the statement executed when MarshalText fails.
+func toStringOnError(name, target string, att *design.AttributeDefinition, onErr string) string {
+	if codegen.GoTypeMapping(att.Type) != nil {
+		if strings.HasPrefix(name, "*") {
+			name = "(" + name + ")"
+		}
+		tmp := codegen.Tempvar()
+		return fmt.Sprintf("%s, err := %s.MarshalText()\n\tif err != nil {\n\t\t%s\n\t}\n\t%s := string(%s)",
+			tmp, name, onErr, target, tmp)
+	}
 	switch actual := att.Type.(type) {
 	case design.Primitive:
 		switch actual.Kind() {
@@ -800,7 +1147,7 @@ func toString(name, target string, att *design.AttributeDefinition) string {
 			return fmt.Sprintf("%s := %s", target, name)
 		case design.DateTimeKind:
 			return fmt.Sprintf("%s := %s.Format(time.RFC3339)", target, strings.Replace(name, "*", "", -1)) // remove pointer if present
-		case design.UUIDKind:
+		case design.UUIDKind, design.DateKind, design.TimeOfDayKind, design.DurationKind, design.DecimalKind:
 			return fmt.Sprintf("%s := %s.String()", target, strings.Replace(name, "*", "", -1)) // remove pointer if present
 		case design.AnyKind:
 			return fmt.Sprintf("%s := fmt.Sprintf(\"%%v\", %s)", target, name)
@@ -814,6 +1161,7 @@ func toString(name, target string, att *design.AttributeDefinition) string {
 			"Name":     name,
 			"Target":   target,
 			"ElemType": actual.ElemType,
+			"OnError":  onErr,
 		}
 		return codegen.RunTemplate(arrayToStringTmpl, data)
 	default:
@@ -898,7 +1246,7 @@ func initParams(att *design.AttributeDefinition) ([]*paramData, []*paramData) {
 			Attribute: q,
 		}
 		if q.Type.IsPrimitive() {
-			param.MustToString = q.Type.Kind() != design.StringKind
+			param.MustToString = q.Type.Kind() != design.StringKind || codegen.GoTypeMapping(q.Type) != nil
 			param.ValueName = toValueTypeName(varName, n, att)
 			if att.IsRequired(n) {
 				reqParamData = append(reqParamData, param)
@@ -944,14 +1292,50 @@ func (b byParamName) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
 func (b byParamName) Less(i, j int) bool { return b[i].Name < b[j].Name }
 func (b byParamName) Len() int           { return len(b) }
 
+// Register the client package and CLI templates, see codegen.LoadTemplateOverrides.
+func init() {
+	codegen.RegisterTemplates("client", map[string]string{
+		"clientTmpl":          clientTmpl,
+		"enumAliasesTmpl":     enumAliasesTmpl,
+		"payloadTmpl":         payloadTmpl,
+		"typeDecodeTmpl":      typeDecodeTmpl,
+		"pathTmpl":            pathTmpl,
+		"clientsTmpl":         clientsTmpl,
+		"clientsWSTmpl":       clientsWSTmpl,
+		"eventsTmpl":          eventsTmpl,
+		"streamTmpl":          streamTmpl,
+		"operationTmpl":       operationTmpl,
+		"criteriaTmpl":        criteriaTmpl,
+		"fsTmpl":              fsTmpl,
+		"requestsTmpl":        requestsTmpl,
+		"mainTmpl":            mainTmpl,
+		"commandTypesTmpl":    commandTypesTmpl,
+		"commandsTmpl":        commandsTmpl,
+		"commandsTmplWS":      commandsTmplWS,
+		"downloadCommandTmpl": downloadCommandTmpl,
+		"registerTmpl":        registerTmpl,
+		"registerCmdsT":       registerCmdsT,
+	})
+}
+
 const (
 	arrayToStringT = `	{{ $tmp := tempvar }}{{ $tmp }} := make([]string, len({{ .Name }}))
 	for i, e := range {{ .Name }} {
-		{{ $tmp2 := tempvar }}{{ toString "e" $tmp2 .ElemType }}
+		{{ $tmp2 := tempvar }}{{ toStringOnError "e" $tmp2 .ElemType .OnError }}
 		{{ $tmp }}[i] = {{ $tmp2 }}
 	}
 	{{ .Target }} := strings.Join({{ $tmp }}, ",")`
 
+	enumAliasesTmpl = `{{ range .Enums }}// {{ .Name }} is the {{ .Name }} enum type of the {{ $.Pkg }} package.
+type {{ .Name }} = {{ $.Pkg }}.{{ .Name }}
+
+// {{ .Name }} values.
+const (
+{{ range .Values }}	{{ .Name }} = {{ $.Pkg }}.{{ .Name }}
+{{ end }})
+
+{{ end }}`
+
 	payloadTmpl = `// {{ gotypename .Payload nil 0 false }} is the {{ .Parent.Name }} {{ .Name }} action payload.
 type {{ gotypename .Payload nil 1 false }} {{ gotypedef .Payload 0 true false }}
 `
@@ -968,7 +1352,7 @@ func (c *Client) {{ $funcName }}(resp *http.Response) ({{ decodegotyperef . .All
 */}}// {{ $funcName }} computes a request path to the {{ .Route.Parent.Name }} action of {{ .Route.Parent.Parent.Name }}.
 func {{ $funcName }}({{ pathParams .Route }}) string {
 	{{- range $i, $param := .Params -}}
-	{{ toString $param.VarName (printf "param%d" $i) $param.Attribute }}{{"\n"}}
+	{{ pathToString $param.VarName (printf "param%d" $i) $param.Attribute }}{{"\n"}}
 	{{- end -}}
 	return fmt.Sprintf({{ printf "%q" (pathTemplate .Route) }}{{ range $i, $param := .Params }}, {{ printf "param%d" $i }}{{ end }})
 }
@@ -976,7 +1360,9 @@ func {{ $funcName }}({{ pathParams .Route }}) string {
 
 	clientsTmpl = `{{ $funcName := goify (printf "%s%s" .Name (title .ResourceName)) true }}{{ $desc := .Description }}{{/*
 */}}{{ if $desc }}{{ multiComment $desc }}{{ else }}{{/*
-*/}}// {{ $funcName }} makes a request to the {{ .Name }} action endpoint of the {{ .ResourceName }} resource{{ end }}
+*/}}// {{ $funcName }} makes a request to the {{ .Name }} action endpoint of the {{ .ResourceName }} resource{{ end }}{{ with .Deprecation }}
+//
+// Deprecated: the {{ $.Name }} action of the {{ $.ResourceName }} resource is {{ .Message }}.{{ end }}
 func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }}, {{ .Params }}{{ end }}{{ if and .HasPayload .HasMultiContent }}, contentType string{{ end }}) (*http.Response, error) {
 	req, err := c.New{{ $funcName }}Request(ctx, path{{ if .ParamNames }}, {{ .ParamNames }}{{ end }}{{ if and .HasPayload .HasMultiContent }}, contentType{{ end }})
 	if err != nil {
@@ -984,10 +1370,187 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }
 	}
 	return c.Client.Do(ctx, req)
 }
+{{ if .Pagination }}
+// {{ $funcName }}Pages makes a request to the {{ .Name }} action endpoint of the {{ .ResourceName }} resource
+// and calls fn with the response of each page of the collection until there are no more pages, fn
+// returns an error or ctx is canceled.{{ with .Deprecation }}
+//
+// Deprecated: the {{ $.Name }} action of the {{ $.ResourceName }} resource is {{ .Message }}.{{ end }}
+func (c *Client) {{ $funcName }}Pages(ctx context.Context, path string{{ if .Params }}, {{ .Params }}{{ end }}, fn func(*http.Response) error) error {
+	req, err := c.New{{ $funcName }}Request(ctx, path{{ if .ParamNames }}, {{ .ParamNames }}{{ end }})
+	if err != nil {
+		return err
+	}
+	return c.Client.WalkPages(ctx, req, fn)
+}
+{{ end }}`
+
+	eventsTmpl = `{{ $funcName := goify (printf "%s%s" .Name (title .ResourceName)) true }}{{/*
+*/}}{{ $readerName := printf "%sEventReader" $funcName }}
+// {{ $funcName }}Events makes a request to the {{ .Name }} action endpoint of the {{ .ResourceName }} resource
+// and returns a reader of the events it streams. The stream resumes after the event with ID
+// lastEventID if not empty.{{ with .Deprecation }}
+//
+// Deprecated: the {{ $.Name }} action of the {{ $.ResourceName }} resource is {{ .Message }}.{{ end }}
+func (c *Client) {{ $funcName }}Events(ctx context.Context, path string{{ if .Params }}, {{ .Params }}{{ end }}, lastEventID string) (*{{ $readerName }}, error) {
+	req, err := c.New{{ $funcName }}Request(ctx, path{{ if .ParamNames }}, {{ .ParamNames }}{{ end }})
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := c.Client.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return &{{ $readerName }}{EventReader: goaclient.NewEventReader(resp.Body), client: c}, nil
+}
+
+// {{ $readerName }} reads the events streamed by the {{ .Name }} action of the {{ .ResourceName }} resource.
+type {{ $readerName }} struct {
+	*goaclient.EventReader
+	client *Client
+}
+
+// Next returns the ID and the data of the next event. It returns io.EOF when the server closes the
+// stream.
+func (r *{{ $readerName }}) Next() (string, {{ gotyperef .EventType .EventType.AllRequired 0 false }}, error) {
+	event, err := r.EventReader.Next()
+	if err != nil {
+		return "", nil, err
+	}
+	var decoded {{ gotypename .EventType .EventType.AllRequired 0 false }}
+	if err := r.client.Decoder.Decode(&decoded, bytes.NewReader(event.Data), ""); err != nil {
+		return event.ID, nil, err
+	}
+	return event.ID, {{ if .EventType.IsObject }}&{{ end }}decoded, nil
+}
+`
+
+	streamTmpl = `{{ $funcName := goify (printf "%s%s" .Name (title .ResourceName)) true }}{{/*
+*/}}{{ $readerName := printf "%sStreamReader" $funcName }}
+// {{ $funcName }}Stream makes a request to the {{ .Name }} action endpoint of the {{ .ResourceName }} resource
+// and returns a reader of the elements of the collection it streams. The reader must be closed.{{ with .Deprecation }}
+//
+// Deprecated: the {{ $.Name }} action of the {{ $.ResourceName }} resource is {{ .Message }}.{{ end }}
+func (c *Client) {{ $funcName }}Stream(ctx context.Context, path string{{ if .Params }}, {{ .Params }}{{ end }}{{ if and .HasPayload .HasMultiContent }}, contentType string{{ end }}) (*{{ $readerName }}, error) {
+	req, err := c.New{{ $funcName }}Request(ctx, path{{ if .ParamNames }}, {{ .ParamNames }}{{ end }}{{ if and .HasPayload .HasMultiContent }}, contentType{{ end }})
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", goa.NDJSONContentType)
+	resp, err := c.Client.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != {{ .Stream.Status }} {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return &{{ $readerName }}{CollectionReader: goaclient.NewCollectionReader(resp)}, nil
+}
+
+// {{ $readerName }} reads the elements of the collection streamed by the {{ .Name }} action of the
+// {{ .ResourceName }} resource.
+type {{ $readerName }} struct {
+	*goaclient.CollectionReader
+}
+
+// Next returns the next element of the collection. It returns io.EOF once all the elements have
+// been read.
+func (r *{{ $readerName }}) Next() ({{ gotyperef .StreamElem nil 0 false }}, error) {
+	var decoded {{ gotypename .StreamElem nil 0 false }}
+	if err := r.CollectionReader.Next(&decoded); err != nil {
+		return nil, err
+	}
+	return {{ if .StreamElem.IsObject }}&{{ end }}decoded, nil
+}
+`
+
+	operationTmpl = `{{ $funcName := goify (printf "%s%s" .Name (title .ResourceName)) true }}{{/*
+*/}}{{ $showFunc := goify (printf "New%s%sRequest" (title "show_operation") (title .ResourceName)) true }}
+// WaitFor{{ $funcName }} waits for the completion of the long-running operation started by the {{ .Name }}
+// action of the {{ .ResourceName }} resource. resp is the response returned by the action. The status
+// of the operation is polled until it is done or ctx is canceled, the last status is returned.
+func (c *Client) WaitFor{{ $funcName }}(ctx context.Context, resp *http.Response) (*GoaOperation, error) {
+	newRequest := func(ctx context.Context, u *url.URL) (*http.Request, error) {
+		return c.{{ $showFunc }}(ctx, u.Path)
+	}
+	var op *GoaOperation
+	err := c.Client.PollOperation(ctx, resp, newRequest, func(resp *http.Response) (bool, error) {
+		if resp.StatusCode != 200 && resp.StatusCode != 202 {
+			return false, fmt.Errorf("unexpected response status %s", resp.Status)
+		}
+		var err error
+		if op, err = c.DecodeGoaOperation(resp); err != nil {
+			return false, err
+		}
+		return op.Status != string(goa.OperationRunning), nil
+	})
+	return op, err
+}
+`
+
+	criteriaTmpl = `{{ $funcName := goify (printf "%s%s" .Name (title .ResourceName)) true }}{{/*
+*/}}{{ $builder := printf "%sCriteria" $funcName }}
+// {{ $builder }} builds the filters and the sort order of the requests made to the {{ .Name }} action
+// of the {{ .ResourceName }} resource.
+type {{ $builder }} struct {
+{{ range .Criteria.Fields }}	{{ .Field }} {{ if .Variadic }}[]{{ else }}*{{ end }}{{ .ArgType }}
+{{ end }}{{ with .Criteria.Sort }}	{{ . }} *string
+{{ end }}{{ with .Criteria.Order }}	{{ . }} *string
+{{ end }}}
+
+// New{{ $builder }} returns criteria that neither filter nor sort the items.
+func New{{ $builder }}() *{{ $builder }} {
+	return &{{ $builder }}{}
+}
+{{ range .Criteria.Fields }}{{ $att := .Param.Attribute }}
+{{ if .Variadic }}// {{ .Setter }} filters the items whose {{ $att }} is equal to one of the values.
+func (c *{{ $builder }}) {{ .Setter }}(values ...{{ .ArgType }}) *{{ $builder }} {
+	c.{{ .Field }} = append(c.{{ .Field }}, values...)
+	return c
+}
+{{ else }}// {{ .Setter }} filters the items whose {{ $att }} {{ if eq (print .Param.Operator) "prefix" }}starts with{{ else if eq (print .Param.Operator) "eq" }}is equal to{{ else if .Param.Max }}is less than or equal to{{ else }}is greater than or equal to{{ end }} value.
+func (c *{{ $builder }}) {{ .Setter }}(value {{ .ArgType }}) *{{ $builder }} {
+	c.{{ .Field }} = &value
+	return c
+}
+{{ end }}{{ end }}{{ if .Criteria.Sort }}
+// SortBy sorts the items by the given attribute in ascending order or in descending order if desc
+// is true.
+func (c *{{ $builder }}) SortBy(attribute string, desc bool) *{{ $builder }} {
+	order := "asc"
+	if desc {
+		order = "desc"
+	}
+	c.{{ .Criteria.Sort }} = &attribute
+	c.{{ .Criteria.Order }} = &order
+	return c
+}
+{{ end }}
+// {{ $funcName }}WithCriteria makes a request to the {{ .Name }} action endpoint of the {{ .ResourceName }} resource
+// using the filters and the sort order built by criteria.{{ with .Deprecation }}
+//
+// Deprecated: the {{ $.Name }} action of the {{ $.ResourceName }} resource is {{ .Message }}.{{ end }}
+func (c *Client) {{ $funcName }}WithCriteria(ctx context.Context, path string, criteria *{{ $builder }}{{ if .Criteria.Params }}, {{ .Criteria.Params }}{{ end }}{{ if and .HasPayload .HasMultiContent }}, contentType string{{ end }}) (*http.Response, error) {
+	if criteria == nil {
+		criteria = New{{ $builder }}()
+	}
+	return c.{{ $funcName }}(ctx, path, {{ .Criteria.Args }}{{ if and .HasPayload .HasMultiContent }}, contentType{{ end }})
+}
 `
 
 	clientsWSTmpl = `{{ $funcName := goify (printf "%s%s" .Name (title .ResourceName)) true }}{{ $desc := .Description }}{{/*
-*/}}{{ if $desc }}{{ multiComment $desc }}{{ else }}// {{ $funcName }} establishes a websocket connection to the {{ .Name }} action endpoint of the {{ .ResourceName }} resource{{ end }}
+*/}}{{ if $desc }}{{ multiComment $desc }}{{ else }}// {{ $funcName }} establishes a websocket connection to the {{ .Name }} action endpoint of the {{ .ResourceName }} resource{{ end }}{{ with .Deprecation }}
+//
+// Deprecated: the {{ $.Name }} action of the {{ $.ResourceName }} resource is {{ .Message }}.{{ end }}
 func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }}, {{ .Params }}{{ end }}) (*websocket.Conn, error) {
 	scheme := c.Scheme
 	if scheme == "" {
@@ -1019,11 +1582,49 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }
 	if err != nil {
 		return nil, err
 	}
-{{ range $header := .Headers }}{{ $tmp := tempvar }}	{{ toString $header.VarName $tmp $header.Attribute }}
-	cfg.Header["{{ $header.Name }}"] = []string{ {{ $tmp }} }
-{{ end }}	return websocket.DialConfig(cfg)
+{{ range $header := .Headers }}{{ if .CheckNil }}	if {{ .VarName }} != nil {
+{{ end }}{{ $tmp := tempvar }}	{{ toString .ValueName $tmp .Attribute }}
+	cfg.Header["{{ .Name }}"] = []string{ {{ $tmp }} }
+{{ if .CheckNil }}	}
+{{ end }}{{ end }}	return websocket.DialConfig(cfg)
+}
+{{ with .Messages }}
+// Dial{{ $funcName }} establishes a websocket connection to the {{ $.Name }} action endpoint of the {{ $.ResourceName }} resource
+// that exchanges typed messages.
+func (c *Client) Dial{{ $funcName }}(ctx context.Context, path string{{ if $.Params }}, {{ $.Params }}{{ end }}) (*{{ $funcName }}Conn, error) {
+	ws, err := c.{{ $funcName }}(ctx, path{{ if $.ParamNames }}, {{ $.ParamNames }}{{ end }})
+	if err != nil {
+		return nil, err
+	}
+	return &{{ $funcName }}Conn{WSConn: goa.NewWSConn(ws, {{ .PingInterval }}*time.Second)}, nil
 }
-`
+
+// {{ $funcName }}Conn is a connection to the {{ $.Name }} action endpoint of the {{ $.ResourceName }} resource.
+type {{ $funcName }}Conn struct {
+	*goa.WSConn
+}
+{{ with $.Inbound }}
+// Send validates msg and sends it to the server.
+func (c *{{ $funcName }}Conn) Send(msg {{ gotyperef . nil 0 false }}) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+	return c.WSConn.Send(msg)
+}
+{{ end }}{{ with $.Outbound }}
+// Recv receives a message sent by the server and validates it. It returns io.EOF when the server
+// closes the connection.
+func (c *{{ $funcName }}Conn) Recv() ({{ gotyperef . nil 0 false }}, error) {
+	var msg {{ gotypename . nil 0 false }}
+	if err := c.WSConn.Recv(&msg); err != nil {
+		return nil, err
+	}
+	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+{{ end }}{{ end }}`
 
 	fsTmpl = `// {{ .Name }} downloads {{ if .DirName }}{{ .DirName }}files with the given filename{{ else }}{{ .FileName }}{{ end }} and writes it to the file dest.
 // It returns the number of bytes downloaded in case of success.
@@ -1199,7 +1800,13 @@ func New(c goaclient.Doer) *Client {
 {{ end }}{{ end }}{{ range .Decoders }}{{ if .Default }}{{/*
 */}}	client.Decoder.Register({{ .PackageName }}.{{ .Function }}, "*/*")
 {{ end }}{{ end }}
-{{ end }}	return client
+{{ end }}{{ with .API.SelectedVersion }}{{ if .Header }}	// Select the API version
+	client.Doer = goaclient.HeaderVersionDoer(client.Doer, {{ printf "%q" .Header }}, {{ printf "%q" .Name }})
+
+{{ else if .MediaTypeParam }}	// Select the API version
+	client.Doer = goaclient.MediaTypeVersionDoer(client.Doer, {{ printf "%q" .MediaTypeParam }}, {{ printf "%q" .Name }})
+
+{{ end }}{{ end }}	return client
 }
 
 {{range $security := .API.SecuritySchemes }}{{ $signer := signerType $security }}{{ if $signer }}{{/*
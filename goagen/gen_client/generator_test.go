@@ -235,6 +235,60 @@ var _ = Describe("Generate", func() {
 		})
 	})
 
+	Context("with params of a mapped Go type", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			p := design.Object{
+				"addr": &design.AttributeDefinition{Type: design.String},
+			}
+			q := design.Object{
+				"cc": &design.AttributeDefinition{Type: design.String},
+			}
+			design.Design = &design.APIDefinition{
+				Name:     "testapi",
+				Consumes: design.DefaultEncoders,
+				GoTypeMappings: []*design.GoTypeMappingDefinition{
+					{Type: design.String, GoType: "email.Email", PackagePath: "example.com/email"},
+				},
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"show": {
+								Name:        "show",
+								Params:      &design.AttributeDefinition{Type: p},
+								QueryParams: &design.AttributeDefinition{Type: q},
+								Routes: []*design.RouteDefinition{
+									{Verb: "GET", Path: "/:addr"},
+								},
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			showAct := fooRes.Actions["show"]
+			showAct.Parent = fooRes
+			showAct.Routes[0].Parent = showAct
+		})
+
+		It("converts them with MarshalText", func() {
+			Ω(genErr).Should(BeNil())
+			c, err := os.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			content := string(c)
+			Ω(content).Should(ContainSubstring(`tmp3, err := addr.MarshalText()
+	if err != nil {
+		panic(err)
+	}
+	param0 := string(tmp3)`))
+			Ω(content).Should(ContainSubstring(`, err := (*cc).MarshalText()
+		if err != nil {
+			return nil, err
+		}`))
+		})
+	})
+
 	Context("with jsonapi like querystring params", func() {
 		BeforeEach(func() {
 			codegen.TempCount = 0
@@ -569,6 +623,55 @@ var _ = Describe("Generate", func() {
 		})
 	})
 
+	Context("with a user type defining an enum type", func() {
+		BeforeEach(func() {
+			testType := &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"color": &design.AttributeDefinition{
+							Type:       design.String,
+							Validation: &dslengine.ValidationDefinition{Values: []interface{}{"red", "green"}},
+							Metadata:   dslengine.MetadataDefinition{"struct:enum": {"Color"}},
+						},
+					},
+				},
+				TypeName: "TestType",
+			}
+			design.Design = &design.APIDefinition{
+				Types: map[string]*design.UserTypeDefinition{
+					"TestType": testType,
+				},
+				Name:        "testapi",
+				Title:       "dummy API with no resource",
+				Description: "I told you it's dummy",
+			}
+		})
+
+		It("generates the enum type", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := os.ReadFile(filepath.Join(outDir, "client", "enums.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("type Color string"))
+		})
+
+		Context("with --app-pkg", func() {
+			BeforeEach(func() {
+				os.Args = append(os.Args, "--app-pkg=app")
+			})
+
+			It("aliases the enum type of the app package", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := os.ReadFile(filepath.Join(outDir, "client", "enums.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				pkg, err := codegen.PackagePath(filepath.Join(outDir, "app"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`"` + pkg + `"`))
+				Ω(string(content)).Should(ContainSubstring("type Color = app.Color"))
+				Ω(string(content)).Should(ContainSubstring("ColorRed   = app.ColorRed"))
+			})
+		})
+	})
+
 	Context("with a multipartform action with a user type payload", func() {
 		BeforeEach(func() {
 			codegen.TempCount = 0
@@ -701,6 +804,7 @@ var _ = Describe("NewGenerator", func() {
 		toolDirName string
 		tool        string
 		noTool      bool
+		appPkg      string
 	}{
 		api: &design.APIDefinition{
 			Name: "test api",
@@ -709,6 +813,7 @@ var _ = Describe("NewGenerator", func() {
 		toolDirName: "test_dir",
 		tool:        "mycli",
 		noTool:      true,
+		appPkg:      "app",
 	}
 
 	Context("with options all options set", func() {
@@ -721,6 +826,7 @@ var _ = Describe("NewGenerator", func() {
 				genclient.ToolDirName(args.toolDirName),
 				genclient.Tool(args.tool),
 				genclient.NoTool(args.noTool),
+				genclient.AppPkg(args.appPkg),
 			)
 		})
 
@@ -732,6 +838,7 @@ var _ = Describe("NewGenerator", func() {
 			Ω(generator.ToolDirName).Should(Equal(args.toolDirName))
 			Ω(generator.Tool).Should(Equal(args.tool))
 			Ω(generator.NoTool).Should(Equal(args.noTool))
+			Ω(generator.AppPkg).Should(Equal(args.appPkg))
 		})
 
 	})
@@ -48,3 +48,10 @@ func NoTool(noTool bool) Option {
 		g.NoTool = noTool
 	}
 }
+
+//AppPkg Import path of the app package defining the enum types, if any
+func AppPkg(appPkg string) Option {
+	return func(g *Generator) {
+		g.AppPkg = appPkg
+	}
+}
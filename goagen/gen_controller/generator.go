@@ -42,8 +42,8 @@ type Generator struct {
 // Generate is the generator entry point called by the meta generator.
 func Generate() (files []string, err error) {
 	var (
-		outDir, designPkg, appPkg, ver, res, pkg string
-		force, regen                             bool
+		outDir, designPkg, appPkg, ver, res, pkg, templates string
+		force, regen                                        bool
 	)
 
 	set := flag.NewFlagSet("controller", flag.PanicOnError)
@@ -56,11 +56,15 @@ func Generate() (files []string, err error) {
 	set.BoolVar(&force, "force", false, "")
 	set.BoolVar(&regen, "regen", false, "")
 	set.Bool("notest", false, "")
+	set.StringVar(&templates, "templates", "", "")
 	set.Parse(os.Args[1:])
 
 	if err := codegen.CheckVersion(ver); err != nil {
 		return nil, err
 	}
+	if err := codegen.LoadTemplateOverrides(templates, "main"); err != nil {
+		return nil, err
+	}
 
 	g := &Generator{OutDir: outDir, DesignPkg: designPkg, AppPkg: appPkg, Force: force, Regen: regen, API: design.Design, Pkg: pkg, Resource: res}
 
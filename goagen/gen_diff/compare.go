@@ -0,0 +1,331 @@
+package gendiff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Change describes a difference between two versions of an API design.
+type Change struct {
+	// Breaking is true if the change may break existing clients.
+	Breaking bool `json:"breaking"`
+	// Path identifies the changed definition, e.g. "bottle#show.params.id".
+	Path string `json:"path"`
+	// Message describes the change.
+	Message string `json:"message"`
+}
+
+// comparer accumulates the changes found while comparing two snapshots.
+type comparer struct {
+	changes []*Change
+}
+
+// Compare returns the changes made to the base snapshot to produce the head snapshot. The
+// breaking changes come first, the changes are then sorted by path.
+func Compare(base, head *Snapshot) []*Change {
+	c := &comparer{changes: []*Change{}}
+	for _, n := range union(base.Resources, head.Resources) {
+		b, h := base.Resources[n], head.Resources[n]
+		switch {
+		case h == nil:
+			c.add(true, n, "resource removed")
+		case b == nil:
+			c.add(false, n, "resource added")
+		default:
+			c.compareResource(n, b, h)
+		}
+	}
+	for _, n := range union(base.MediaTypes, head.MediaTypes) {
+		b, h := base.MediaTypes[n], head.MediaTypes[n]
+		switch {
+		case h == nil:
+			c.add(true, n, "media type removed")
+		case b == nil:
+			c.add(false, n, "media type added")
+		default:
+			c.compareMediaType(n, b, h)
+		}
+	}
+	for _, n := range union(base.Types, head.Types) {
+		b, h := base.Types[n], head.Types[n]
+		switch {
+		case h == nil:
+			c.add(true, n, "type removed")
+		case b == nil:
+			c.add(false, n, "type added")
+		default:
+			// User types may be used in requests, compare them conservatively.
+			c.compareAttribute(n, b, h, true)
+		}
+	}
+	sort.SliceStable(c.changes, func(i, j int) bool {
+		ci, cj := c.changes[i], c.changes[j]
+		if ci.Breaking != cj.Breaking {
+			return ci.Breaking
+		}
+		return ci.Path < cj.Path
+	})
+	return c.changes
+}
+
+func (c *comparer) add(breaking bool, path, format string, args ...interface{}) {
+	c.changes = append(c.changes, &Change{Breaking: breaking, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *comparer) compareResource(path string, base, head *Resource) {
+	for _, n := range union(base.Actions, head.Actions) {
+		b, h := base.Actions[n], head.Actions[n]
+		p := path + "#" + n
+		switch {
+		case h == nil:
+			c.add(true, p, "action removed")
+		case b == nil:
+			c.add(false, p, "action added")
+		default:
+			c.compareAction(p, b, h)
+		}
+	}
+}
+
+func (c *comparer) compareAction(path string, base, head *Action) {
+	for _, r := range base.Routes {
+		if !contains(head.Routes, r) {
+			c.add(true, path, "route %s removed", r)
+		}
+	}
+	for _, r := range head.Routes {
+		if !contains(base.Routes, r) {
+			c.add(false, path, "route %s added", r)
+		}
+	}
+	c.compareAttribute(path+".params", base.Params, head.Params, true)
+	c.compareAttribute(path+".headers", base.Headers, head.Headers, true)
+	switch {
+	case base.Payload == nil && head.Payload != nil:
+		c.add(!head.PayloadOptional, path+".payload", "payload added")
+	case base.Payload != nil && head.Payload == nil:
+		c.add(true, path+".payload", "payload removed")
+	case base.Payload != nil:
+		if base.PayloadOptional && !head.PayloadOptional {
+			c.add(true, path+".payload", "payload now required")
+		}
+		c.compareAttribute(path+".payload", base.Payload, head.Payload, true)
+	}
+	for _, n := range union(base.Responses, head.Responses) {
+		b, h := base.Responses[n], head.Responses[n]
+		p := path + ".responses." + n
+		switch {
+		case h == nil:
+			c.add(true, p, "response removed")
+		case b == nil:
+			c.add(false, p, "response added")
+		default:
+			c.compareResponse(p, b, h)
+		}
+	}
+}
+
+func (c *comparer) compareResponse(path string, base, head *Response) {
+	if base.Status != head.Status {
+		c.add(true, path, "status code changed from %d to %d", base.Status, head.Status)
+	}
+	if base.MediaType != head.MediaType {
+		c.add(true, path, "media type changed from %s to %s", orNone(base.MediaType), orNone(head.MediaType))
+	} else if base.View != head.View {
+		c.add(true, path, "view changed from %s to %s", orNone(base.View), orNone(head.View))
+	}
+	c.compareAttribute(path+".headers", base.Headers, head.Headers, false)
+}
+
+func (c *comparer) compareMediaType(path string, base, head *MediaType) {
+	c.compareAttribute(path, base.Attribute, head.Attribute, false)
+	for _, n := range union(base.Views, head.Views) {
+		b, h := base.Views[n], head.Views[n]
+		p := path + ".views." + n
+		switch {
+		case h == nil:
+			c.add(true, p, "view removed")
+		case b == nil:
+			c.add(false, p, "view added")
+		default:
+			for _, a := range b {
+				if !contains(h, a) {
+					c.add(true, p, "attribute %q no longer rendered", a)
+				}
+			}
+			for _, a := range h {
+				if !contains(b, a) {
+					c.add(false, p, "attribute %q now rendered", a)
+				}
+			}
+		}
+	}
+}
+
+// compareAttribute compares the attributes of a request if request is true, of a response
+// otherwise. Tightening the validations of a request attribute is a breaking change, loosening
+// the validations of a response attribute is one as well.
+func (c *comparer) compareAttribute(path string, base, head *Attribute, request bool) {
+	if base == nil && head == nil {
+		return
+	}
+	if base == nil {
+		base = &Attribute{Type: head.Type}
+	}
+	if head == nil {
+		head = &Attribute{Type: base.Type}
+	}
+	if base.Type != head.Type {
+		c.add(true, path, "type changed from %s to %s", base.Type, head.Type)
+		return
+	}
+	c.compareAttribute(path+".key", base.Key, head.Key, request)
+	c.compareAttribute(path+"[]", base.Elem, head.Elem, request)
+	for _, n := range union(base.Fields, head.Fields) {
+		b, h := base.Fields[n], head.Fields[n]
+		p := path + "." + n
+		breq, hreq := contains(base.Required, n), contains(head.Required, n)
+		switch {
+		case h == nil:
+			c.add(true, p, "attribute removed")
+		case b == nil:
+			if hreq {
+				c.add(request, p, "required attribute added")
+			} else {
+				c.add(false, p, "attribute added")
+			}
+		default:
+			if !breq && hreq {
+				c.add(request, p, "attribute now required")
+			} else if breq && !hreq {
+				c.add(!request, p, "attribute no longer required")
+			}
+			c.compareAttribute(p, b, h, request)
+		}
+	}
+	c.compareValidations(path, base, head, request)
+}
+
+func (c *comparer) compareValidations(path string, base, head *Attribute, request bool) {
+	// validation records a tightened or loosened validation.
+	validation := func(tightened bool, format string, args ...interface{}) {
+		c.add(tightened == request, path, format, args...)
+	}
+	switch {
+	case len(base.Enum) == 0 && len(head.Enum) > 0:
+		validation(true, "enum %s added", formatValues(head.Enum))
+	case len(base.Enum) > 0 && len(head.Enum) == 0:
+		validation(false, "enum removed")
+	default:
+		if removed := difference(base.Enum, head.Enum); len(removed) > 0 {
+			validation(true, "enum values %s removed", formatValues(removed))
+		}
+		if added := difference(head.Enum, base.Enum); len(added) > 0 {
+			validation(false, "enum values %s added", formatValues(added))
+		}
+	}
+	switch {
+	case base.Format == head.Format:
+	case base.Format == "":
+		validation(true, "format %s added", head.Format)
+	case head.Format == "":
+		validation(false, "format %s removed", base.Format)
+	default:
+		c.add(true, path, "format changed from %s to %s", base.Format, head.Format)
+	}
+	switch {
+	case base.Pattern == head.Pattern:
+	case base.Pattern == "":
+		validation(true, "pattern %q added", head.Pattern)
+	case head.Pattern == "":
+		validation(false, "pattern %q removed", base.Pattern)
+	default:
+		c.add(true, path, "pattern changed from %q to %q", base.Pattern, head.Pattern)
+	}
+	compareBound(validation, "minimum", base.Minimum, head.Minimum, true)
+	compareBound(validation, "maximum", base.Maximum, head.Maximum, false)
+	compareBound(validation, "minimum length", toFloat(base.MinLength), toFloat(head.MinLength), true)
+	compareBound(validation, "maximum length", toFloat(base.MaxLength), toFloat(head.MaxLength), false)
+}
+
+// compareBound compares the values of a lower bound if lower is true, of an upper bound
+// otherwise.
+func compareBound(validation func(bool, string, ...interface{}), name string, base, head *float64, lower bool) {
+	switch {
+	case base == nil && head == nil:
+	case base == nil:
+		validation(true, "%s %v added", name, *head)
+	case head == nil:
+		validation(false, "%s %v removed", name, *base)
+	case *base != *head:
+		validation((*head > *base) == lower, "%s changed from %v to %v", name, *base, *head)
+	}
+}
+
+// union returns the sorted keys of the given maps.
+func union[T any](a, b map[string]T) []string {
+	set := make(map[string]struct{}, len(a)+len(b))
+	for k := range a {
+		set[k] = struct{}{}
+	}
+	for k := range b {
+		set[k] = struct{}{}
+	}
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// difference returns the values of a that are not in b.
+func difference(a, b []interface{}) []interface{} {
+	var res []interface{}
+	for _, v := range a {
+		found := false
+		for _, w := range b {
+			if fmt.Sprint(v) == fmt.Sprint(w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+func contains(vals []string, val string) bool {
+	for _, v := range vals {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
+
+func formatValues(vals []interface{}) string {
+	strs := make([]string, len(vals))
+	for i, v := range vals {
+		strs[i] = fmt.Sprintf("%#v", v)
+	}
+	return strings.Join(strs, ", ")
+}
+
+func toFloat(v *int) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := float64(*v)
+	return &f
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
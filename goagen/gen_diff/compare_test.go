@@ -0,0 +1,253 @@
+package gendiff_test
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1/design"
+	"github.com/shogo82148/goa-v1/design/apidsl"
+	"github.com/shogo82148/goa-v1/dslengine"
+	gendiff "github.com/shogo82148/goa-v1/goagen/gen_diff"
+)
+
+// snapshotOf evaluates the given design and returns its snapshot as read back from JSON.
+func snapshotOf(dsl func()) *gendiff.Snapshot {
+	dslengine.Reset()
+	dsl()
+	Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+	b, err := json.Marshal(gendiff.NewSnapshot(design.Design))
+	Ω(err).ShouldNot(HaveOccurred())
+	var s gendiff.Snapshot
+	Ω(json.Unmarshal(b, &s)).ShouldNot(HaveOccurred())
+	return &s
+}
+
+var _ = Describe("Compare", func() {
+	var baseDSL, headDSL func()
+	var changes []*gendiff.Change
+
+	JustBeforeEach(func() {
+		base := snapshotOf(baseDSL)
+		head := snapshotOf(headDSL)
+		changes = gendiff.Compare(base, head)
+	})
+
+	// bottleDesign returns a design whose show action and media type are customized by the
+	// given functions.
+	bottleDesign := func(params, attributes func()) func() {
+		return func() {
+			apidsl.API("cellar", nil)
+			bottle := apidsl.MediaType("application/vnd.bottle", func() {
+				apidsl.Attributes(func() {
+					apidsl.Attribute("id", design.Integer)
+					attributes()
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("id")
+				})
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/bottles/:id"))
+					apidsl.Params(func() {
+						apidsl.Param("id", design.Integer)
+						params()
+					})
+					apidsl.Response(design.OK, bottle)
+				})
+			})
+		}
+	}
+
+	Context("with identical designs", func() {
+		BeforeEach(func() {
+			baseDSL = bottleDesign(func() {}, func() {})
+			headDSL = baseDSL
+		})
+
+		It("returns no change", func() {
+			Ω(changes).Should(BeEmpty())
+		})
+	})
+
+	Context("with a removed action", func() {
+		BeforeEach(func() {
+			baseDSL = bottleDesign(func() {}, func() {})
+			headDSL = func() {
+				apidsl.API("cellar", nil)
+				apidsl.Resource("bottle", func() {
+					apidsl.Action("list", func() {
+						apidsl.Routing(apidsl.GET("/bottles"))
+						apidsl.Response(design.OK)
+					})
+				})
+			}
+		})
+
+		It("reports a breaking change", func() {
+			Ω(changes).Should(ContainElement(&gendiff.Change{Breaking: true, Path: "bottle#show", Message: "action removed"}))
+			Ω(changes).Should(ContainElement(&gendiff.Change{Breaking: false, Path: "bottle#list", Message: "action added"}))
+			Ω(changes[0].Breaking).Should(BeTrue())
+		})
+	})
+
+	Context("with a new required parameter", func() {
+		BeforeEach(func() {
+			baseDSL = bottleDesign(func() {}, func() {})
+			headDSL = bottleDesign(func() {
+				apidsl.Param("vintage", design.Integer)
+				apidsl.Required("vintage")
+			}, func() {})
+		})
+
+		It("reports a breaking change", func() {
+			Ω(changes).Should(ConsistOf(&gendiff.Change{Breaking: true, Path: "bottle#show.params.vintage", Message: "required attribute added"}))
+		})
+	})
+
+	Context("with a new optional parameter", func() {
+		BeforeEach(func() {
+			baseDSL = bottleDesign(func() {}, func() {})
+			headDSL = bottleDesign(func() {
+				apidsl.Param("vintage", design.Integer)
+			}, func() {})
+		})
+
+		It("reports a non-breaking change", func() {
+			Ω(changes).Should(ConsistOf(&gendiff.Change{Breaking: false, Path: "bottle#show.params.vintage", Message: "attribute added"}))
+		})
+	})
+
+	Context("with a narrowed enum", func() {
+		BeforeEach(func() {
+			baseDSL = bottleDesign(func() {
+				apidsl.Param("color", design.String, func() { apidsl.Enum("red", "white", "rose") })
+			}, func() {})
+			headDSL = bottleDesign(func() {
+				apidsl.Param("color", design.String, func() { apidsl.Enum("red", "white") })
+			}, func() {})
+		})
+
+		It("reports a breaking change", func() {
+			Ω(changes).Should(ConsistOf(&gendiff.Change{Breaking: true, Path: "bottle#show.params.color", Message: `enum values "rose" removed`}))
+		})
+	})
+
+	Context("with a changed type", func() {
+		BeforeEach(func() {
+			baseDSL = bottleDesign(func() {
+				apidsl.Param("vintage", design.Integer)
+			}, func() {})
+			headDSL = bottleDesign(func() {
+				apidsl.Param("vintage", design.String)
+			}, func() {})
+		})
+
+		It("reports a breaking change", func() {
+			Ω(changes).Should(ConsistOf(&gendiff.Change{Breaking: true, Path: "bottle#show.params.vintage", Message: "type changed from integer to string"}))
+		})
+	})
+
+	Context("with validations", func() {
+		BeforeEach(func() {
+			baseDSL = bottleDesign(func() {
+				apidsl.Param("vintage", design.Integer, func() { apidsl.Minimum(1900) })
+			}, func() {
+				apidsl.Attribute("name", design.String, func() { apidsl.MaxLength(10) })
+			})
+		})
+
+		Context("tightened", func() {
+			BeforeEach(func() {
+				headDSL = bottleDesign(func() {
+					apidsl.Param("vintage", design.Integer, func() { apidsl.Minimum(1950) })
+				}, func() {
+					apidsl.Attribute("name", design.String, func() { apidsl.MaxLength(5) })
+				})
+			})
+
+			It("reports breaking changes for requests only", func() {
+				Ω(changes).Should(ConsistOf(
+					&gendiff.Change{Breaking: true, Path: "bottle#show.params.vintage", Message: "minimum changed from 1900 to 1950"},
+					&gendiff.Change{Breaking: false, Path: "application/vnd.bottle.name", Message: "maximum length changed from 10 to 5"},
+				))
+			})
+		})
+
+		Context("loosened", func() {
+			BeforeEach(func() {
+				headDSL = bottleDesign(func() {
+					apidsl.Param("vintage", design.Integer)
+				}, func() {
+					apidsl.Attribute("name", design.String, func() { apidsl.MaxLength(20) })
+				})
+			})
+
+			It("reports breaking changes for responses only", func() {
+				Ω(changes).Should(ConsistOf(
+					&gendiff.Change{Breaking: false, Path: "bottle#show.params.vintage", Message: "minimum 1900 removed"},
+					&gendiff.Change{Breaking: true, Path: "application/vnd.bottle.name", Message: "maximum length changed from 10 to 20"},
+				))
+			})
+		})
+	})
+
+	Context("with a changed status code", func() {
+		BeforeEach(func() {
+			baseDSL = func() {
+				apidsl.API("cellar", nil)
+				apidsl.Resource("bottle", func() {
+					apidsl.Action("create", func() {
+						apidsl.Routing(apidsl.POST("/bottles"))
+						apidsl.Response("Done", func() { apidsl.Status(202) })
+					})
+				})
+			}
+			headDSL = func() {
+				apidsl.API("cellar", nil)
+				apidsl.Resource("bottle", func() {
+					apidsl.Action("create", func() {
+						apidsl.Routing(apidsl.POST("/bottles"))
+						apidsl.Response("Done", func() { apidsl.Status(201) })
+					})
+				})
+			}
+		})
+
+		It("reports a breaking change", func() {
+			Ω(changes).Should(ConsistOf(&gendiff.Change{Breaking: true, Path: "bottle#create.responses.Done", Message: "status code changed from 202 to 201"}))
+		})
+	})
+})
+
+var _ = Describe("Report", func() {
+	var report *gendiff.Report
+	var buf bytes.Buffer
+
+	BeforeEach(func() {
+		buf.Reset()
+		report = &gendiff.Report{
+			Breaking: 1,
+			Changes: []*gendiff.Change{
+				{Breaking: true, Path: "bottle#show", Message: "action removed"},
+				{Breaking: false, Path: "bottle#list", Message: "action added"},
+			},
+		}
+	})
+
+	It("writes human readable text", func() {
+		Ω(report.WriteText(&buf)).ShouldNot(HaveOccurred())
+		Ω(buf.String()).Should(Equal("breaking      bottle#show: action removed\n" +
+			"non-breaking  bottle#list: action added\n" +
+			"2 change(s), 1 breaking\n"))
+	})
+
+	It("writes JSON", func() {
+		Ω(report.WriteJSON(&buf)).ShouldNot(HaveOccurred())
+		var r gendiff.Report
+		Ω(json.Unmarshal(buf.Bytes(), &r)).ShouldNot(HaveOccurred())
+		Ω(r).Should(Equal(*report))
+	})
+})
@@ -0,0 +1,200 @@
+package gendiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/shogo82148/goa-v1/goagen/codegen"
+	"github.com/shogo82148/goa-v1/goagen/meta"
+)
+
+// Report lists the changes made to a design package.
+type Report struct {
+	// Breaking is the number of breaking changes.
+	Breaking int `json:"breaking"`
+	// Changes lists the changes, breaking changes first.
+	Changes []*Change `json:"changes"`
+}
+
+// Diff compares the design package with the given import path with its base version. base is
+// either the import path of another design package or a git reference, in which case the base
+// version is the design package as it is at that reference.
+func Diff(designPkg, base string, debug bool) (*Report, error) {
+	if designPkg == "" {
+		return nil, fmt.Errorf("missing design package flag")
+	}
+	if base == "" {
+		return nil, fmt.Errorf("missing base flag")
+	}
+	head, err := snapshot(designPkg, debug)
+	if err != nil {
+		return nil, err
+	}
+	basePkg := base
+	if _, err := codegen.PackageSourcePath(base); err != nil {
+		pkg, cleanup, err := checkout(designPkg, base)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+		basePkg = pkg
+	}
+	prev, err := snapshot(basePkg, debug)
+	if err != nil {
+		return nil, err
+	}
+	r := &Report{Changes: Compare(prev, head)}
+	for _, c := range r.Changes {
+		if c.Breaking {
+			r.Breaking++
+		}
+	}
+	return r, nil
+}
+
+// WriteText writes the human readable version of the report.
+func (r *Report) WriteText(w io.Writer) error {
+	for _, c := range r.Changes {
+		kind := "non-breaking"
+		if c.Breaking {
+			kind = "breaking"
+		}
+		if _, err := fmt.Fprintf(w, "%-12s  %s: %s\n", kind, c.Path, c.Message); err != nil {
+			return err
+		}
+	}
+	if len(r.Changes) == 0 {
+		_, err := fmt.Fprintln(w, "no changes")
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%d change(s), %d breaking\n", len(r.Changes), r.Breaking)
+	return err
+}
+
+// WriteJSON writes the JSON representation of the report.
+func (r *Report) WriteJSON(w io.Writer) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+// snapshot evaluates the design package with the given import path and returns its snapshot.
+func snapshot(designPkg string, debug bool) (*Snapshot, error) {
+	out, err := os.MkdirTemp("", "goagen")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(out)
+	gen, err := meta.NewGenerator(
+		"gendiff.Generate",
+		[]*codegen.ImportSpec{codegen.SimpleImport("github.com/shogo82148/goa-v1/goagen/gen_diff")},
+		map[string]string{"out": out, "design": designPkg, "debug": strconv.FormatBool(debug)},
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := gen.Generate(); err != nil {
+		return nil, err
+	}
+	return LoadSnapshot(filepath.Join(out, "snapshot.json"))
+}
+
+// checkout extracts the sources of the design package with the given import path as they are at
+// the given git reference. It returns the import path of the extracted package and a function
+// that deletes it. The imports of the design package and of its sub-packages are rewritten so
+// that the extracted sources are self-contained.
+func checkout(designPkg, ref string) (string, func(), error) {
+	src, err := codegen.PackageSourcePath(designPkg)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid design package import path: %s", err)
+	}
+	top, err := git(src, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", nil, fmt.Errorf("%s is not a design package import path and %s is not in a git repository", ref, src)
+	}
+	top = strings.TrimSpace(top)
+	if _, err := git(top, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+		return "", nil, fmt.Errorf("%s is neither a design package import path nor a git reference", ref)
+	}
+	rel, err := filepath.Rel(top, src)
+	if err != nil {
+		return "", nil, err
+	}
+	rel = filepath.ToSlash(rel)
+	list, err := git(top, "ls-tree", "-r", "--name-only", "--full-name", ref, "--", rel)
+	if err != nil {
+		return "", nil, err
+	}
+	var files []string
+	for _, f := range strings.Split(list, "\n") {
+		if strings.HasSuffix(f, ".go") && !strings.HasSuffix(f, "_test.go") {
+			files = append(files, f)
+		}
+	}
+	if len(files) == 0 {
+		return "", nil, fmt.Errorf("design package %s does not exist at %s", designPkg, ref)
+	}
+
+	// The package must be extracted in the current module so that its imports resolve.
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", nil, err
+	}
+	tmpDir, err := os.MkdirTemp(wd, "goagen")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(tmpDir) }
+	root := filepath.Join(tmpDir, filepath.Base(src))
+	if err := os.MkdirAll(root, 0755); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	pkg, err := codegen.PackagePath(root)
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	for _, f := range files {
+		content, err := git(top, "show", ref+":"+f)
+		if err != nil {
+			cleanup()
+			return "", nil, err
+		}
+		content = strings.ReplaceAll(content, `"`+designPkg+`"`, `"`+pkg+`"`)
+		content = strings.ReplaceAll(content, `"`+designPkg+`/`, `"`+pkg+`/`)
+		dest := filepath.Join(root, filepath.FromSlash(strings.TrimPrefix(f, rel+"/")))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			cleanup()
+			return "", nil, err
+		}
+		if err := os.WriteFile(dest, []byte(content), 0644); err != nil {
+			cleanup()
+			return "", nil, err
+		}
+	}
+	return pkg, cleanup, nil
+}
+
+// git runs the git command with the given arguments in dir and returns its output.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(string(ee.Stderr)))
+		}
+		return "", err
+	}
+	return string(out), nil
+}
@@ -0,0 +1,14 @@
+/*
+Package gendiff provides a generator that detects the breaking changes made to an API design.
+
+The generator evaluates two versions of a design package and compares the resulting API
+definitions. Each difference is classified as breaking or non-breaking: removing a resource, an
+action, a route, a response or an attribute, changing the type of an attribute or the status code
+of a response, requiring new parameters, narrowing enums or tightening validations of the request
+data are breaking changes. Changes to the media types rendered in responses are classified the
+other way around: clients only break when a response may contain values they did not expect.
+
+The base version of the design package is either another design package or a git reference, in
+which case the design package sources are extracted from the repository at that reference.
+*/
+package gendiff
@@ -0,0 +1,87 @@
+package gendiff
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/shogo82148/goa-v1/design"
+	"github.com/shogo82148/goa-v1/goagen/codegen"
+	"github.com/shogo82148/goa-v1/goagen/utils"
+)
+
+//NewGenerator returns an initialized instance of a design snapshot generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the design snapshot generator, the snapshots are compared by Diff.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+	set := flag.NewFlagSet("diff", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate writes the snapshot of the API definition.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	b, err := json.MarshalIndent(NewSnapshot(g.API), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(g.OutDir, 0755); err != nil {
+		return nil, err
+	}
+	snapshotFile := filepath.Join(g.OutDir, "snapshot.json")
+	if err = os.WriteFile(snapshotFile, b, 0644); err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, snapshotFile)
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invocation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
@@ -0,0 +1,250 @@
+package gendiff
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+
+	"github.com/shogo82148/goa-v1/design"
+)
+
+type (
+	// Snapshot describes the parts of an API design that its clients depend on. Snapshots
+	// are serialized so that designs evaluated by different processes can be compared.
+	Snapshot struct {
+		// Name of the API.
+		Name string `json:"name"`
+		// Resources indexed by name.
+		Resources map[string]*Resource `json:"resources,omitempty"`
+		// MediaTypes indexed by identifier.
+		MediaTypes map[string]*MediaType `json:"media_types,omitempty"`
+		// Types lists the user types indexed by type name.
+		Types map[string]*Attribute `json:"types,omitempty"`
+	}
+
+	// Resource describes a resource.
+	Resource struct {
+		// Actions indexed by name.
+		Actions map[string]*Action `json:"actions,omitempty"`
+	}
+
+	// Action describes the requests and responses of an action.
+	Action struct {
+		// Routes lists the action routes formatted as "VERB /full/path".
+		Routes []string `json:"routes,omitempty"`
+		// Params describes the path and query string parameters.
+		Params *Attribute `json:"params,omitempty"`
+		// Headers describes the request headers.
+		Headers *Attribute `json:"headers,omitempty"`
+		// Payload describes the request body if any.
+		Payload *Attribute `json:"payload,omitempty"`
+		// PayloadOptional is true if the request body may be omitted.
+		PayloadOptional bool `json:"payload_optional,omitempty"`
+		// Responses indexed by name.
+		Responses map[string]*Response `json:"responses,omitempty"`
+	}
+
+	// Response describes an action response.
+	Response struct {
+		// Status is the HTTP status code.
+		Status int `json:"status"`
+		// MediaType is the identifier of the response media type if any.
+		MediaType string `json:"media_type,omitempty"`
+		// View is the name of the view used to render the response media type if any.
+		View string `json:"view,omitempty"`
+		// Headers describes the response headers.
+		Headers *Attribute `json:"headers,omitempty"`
+	}
+
+	// MediaType describes a media type and its views.
+	MediaType struct {
+		// Attribute describes the media type attributes.
+		Attribute *Attribute `json:"attribute"`
+		// Views lists the names of the attributes rendered by each view indexed by view name.
+		Views map[string][]string `json:"views,omitempty"`
+	}
+
+	// Attribute describes the type and validations of a data structure. User types and media
+	// types are referred to by type name and identifier respectively.
+	Attribute struct {
+		// Type is the name of the primitive type, "array", "hash" or "object", the type
+		// name of a user type or the identifier of a media type.
+		Type string `json:"type"`
+		// Key is the type of the hash keys.
+		Key *Attribute `json:"key,omitempty"`
+		// Elem is the type of the array elements or hash values.
+		Elem *Attribute `json:"elem,omitempty"`
+		// Fields lists the object attributes indexed by name.
+		Fields map[string]*Attribute `json:"fields,omitempty"`
+		// Required lists the names of the required object attributes.
+		Required []string `json:"required,omitempty"`
+		// Enum lists the allowed values.
+		Enum []interface{} `json:"enum,omitempty"`
+		// Format is the format validation.
+		Format string `json:"format,omitempty"`
+		// Pattern is the regular expression validation.
+		Pattern string `json:"pattern,omitempty"`
+		// Minimum is the minimum value validation.
+		Minimum *float64 `json:"minimum,omitempty"`
+		// Maximum is the maximum value validation.
+		Maximum *float64 `json:"maximum,omitempty"`
+		// MinLength is the minimum length validation.
+		MinLength *int `json:"min_length,omitempty"`
+		// MaxLength is the maximum length validation.
+		MaxLength *int `json:"max_length,omitempty"`
+	}
+)
+
+// NewSnapshot builds the snapshot of the given API definition.
+func NewSnapshot(api *design.APIDefinition) *Snapshot {
+	s := &Snapshot{
+		Name:       api.Name,
+		Resources:  make(map[string]*Resource),
+		MediaTypes: make(map[string]*MediaType),
+		Types:      make(map[string]*Attribute),
+	}
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		res := &Resource{Actions: make(map[string]*Action)}
+		r.IterateActions(func(a *design.ActionDefinition) error {
+			res.Actions[a.Name] = newAction(a)
+			return nil
+		})
+		s.Resources[r.Name] = res
+		return nil
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		m := &MediaType{
+			Attribute: newAttribute(mt.AttributeDefinition),
+			Views:     make(map[string][]string),
+		}
+		mt.IterateViews(func(v *design.ViewDefinition) error {
+			var names []string
+			if v.AttributeDefinition != nil && v.Type.IsObject() {
+				for n := range v.Type.ToObject() {
+					names = append(names, n)
+				}
+			}
+			sort.Strings(names)
+			m.Views[v.Name] = names
+			return nil
+		})
+		s.MediaTypes[mt.Identifier] = m
+		return nil
+	})
+	api.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		s.Types[ut.TypeName] = newAttribute(ut.AttributeDefinition)
+		return nil
+	})
+	return s
+}
+
+// LoadSnapshot reads the snapshot written by the generator to the given file.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Snapshot
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// newAction builds the description of the given action.
+func newAction(a *design.ActionDefinition) *Action {
+	act := &Action{
+		Params:          newAttribute(a.AllParams()),
+		PayloadOptional: a.PayloadOptional,
+		Responses:       make(map[string]*Response),
+	}
+	for _, r := range a.Routes {
+		act.Routes = append(act.Routes, r.Verb+" "+r.FullPath())
+	}
+	sort.Strings(act.Routes)
+	headers := &Attribute{Type: "object", Fields: make(map[string]*Attribute)}
+	a.IterateHeaders(func(name string, isRequired bool, h *design.AttributeDefinition) error {
+		headers.Fields[name] = newAttribute(h)
+		if isRequired {
+			headers.Required = append(headers.Required, name)
+		}
+		return nil
+	})
+	act.Headers = headers
+	if a.Payload != nil {
+		act.Payload = newAttribute(a.Payload.AttributeDefinition)
+	}
+	a.IterateResponses(func(r *design.ResponseDefinition) error {
+		resp := &Response{Status: r.Status, MediaType: r.MediaType, View: r.ViewName}
+		if r.Headers != nil {
+			resp.Headers = newAttribute(r.Headers)
+		}
+		act.Responses[r.Name] = resp
+		return nil
+	})
+	return act
+}
+
+// newAttribute builds the description of the given attribute. The attributes of user types and
+// media types are not described, the types are referred to by name instead.
+func newAttribute(att *design.AttributeDefinition) *Attribute {
+	if att == nil || att.Type == nil {
+		return nil
+	}
+	a := new(Attribute)
+	switch t := att.Type.(type) {
+	case design.Primitive:
+		a.Type = primitiveName(t)
+	case *design.Array:
+		a.Type = "array"
+		a.Elem = newAttribute(t.ElemType)
+	case *design.Hash:
+		a.Type = "hash"
+		a.Key = newAttribute(t.KeyType)
+		a.Elem = newAttribute(t.ElemType)
+	case design.Object:
+		a.Type = "object"
+		a.Fields = make(map[string]*Attribute, len(t))
+		for n, f := range t {
+			a.Fields[n] = newAttribute(f)
+		}
+	case *design.MediaTypeDefinition:
+		a.Type = t.Identifier
+	case *design.UserTypeDefinition:
+		a.Type = t.TypeName
+	}
+	if v := att.Validation; v != nil {
+		if a.Type == "object" {
+			a.Required = append([]string(nil), v.Required...)
+			sort.Strings(a.Required)
+		}
+		a.Enum = v.Values
+		a.Format = v.Format
+		a.Pattern = v.Pattern
+		a.Minimum = v.Minimum
+		a.Maximum = v.Maximum
+		a.MinLength = v.MinLength
+		a.MaxLength = v.MaxLength
+	}
+	return a
+}
+
+// primitiveName returns the name of the given primitive type as used in the design DSL.
+func primitiveName(p design.Primitive) string {
+	switch p {
+	case design.DateTime:
+		return "datetime"
+	case design.UUID:
+		return "uuid"
+	case design.Date:
+		return "date"
+	case design.TimeOfDay:
+		return "timeofday"
+	case design.Duration:
+		return "duration"
+	case design.Decimal:
+		return "decimal"
+	default:
+		return p.Name()
+	}
+}
@@ -0,0 +1,15 @@
+/*
+Package genimport provides a generator that reverses the other generators: it reads a Swagger 2.0
+or OpenAPI 3 specification and produces a goa design package using the apidsl package.
+
+The definitions whose title identifies a media type, as generated by the genswagger generator, are
+mapped onto media types and their views, the definitions used by responses onto media types
+and the other definitions onto user types. The operations are grouped into resources using
+their "resource#action" operation ID when present, their first tag or the first segment of their
+path otherwise. The actions whose Accepted response renders the status of an operation are defined
+with LongRunning, which replaces the operation actions of their resource. The x-pagination,
+x-webhooks and x-callbacks extensions written by the genswagger generator are mapped onto
+Paginated and Webhook. The parts of the specification that cannot be expressed with the DSL are
+reported as warnings.
+*/
+package genimport
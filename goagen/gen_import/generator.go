@@ -0,0 +1,101 @@
+package genimport
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/shogo82148/goa-v1/goagen/codegen"
+	"github.com/shogo82148/goa-v1/goagen/utils"
+)
+
+//NewGenerator returns an initialized instance of a design package generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{OutDir: ".", Target: "design"}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the design package generator.
+type Generator struct {
+	Spec     string   // Path to the Swagger or OpenAPI specification file
+	OutDir   string   // Path to output directory
+	Target   string   // Name of generated design package
+	Force    bool     // Whether to override existing files
+	Warnings []string // Parts of the specification that could not be imported
+	genfiles []string // Generated files
+}
+
+// Generate produces the design package.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.Spec == "" {
+		return nil, fmt.Errorf("missing specification file")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	doc, warnings, err := loadDocument(g.Spec)
+	if err != nil {
+		return nil, err
+	}
+	imp := newImporter(doc, warnings)
+	imp.analyze()
+	code := imp.write()
+	g.Warnings = imp.warnings
+
+	designDir := filepath.Join(g.OutDir, g.Target)
+	designFile := filepath.Join(designDir, "design.go")
+	if _, err := os.Stat(designFile); err == nil {
+		if !g.Force {
+			return nil, fmt.Errorf("%s already exists, use --force to overwrite it", designFile)
+		}
+		os.Remove(designFile)
+	}
+	_, statErr := os.Stat(designDir)
+	if err = os.MkdirAll(designDir, 0755); err != nil {
+		return nil, err
+	}
+	file, err := codegen.SourceFileFor(designFile)
+	if err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, designFile)
+	if statErr != nil {
+		// Remove the directory after the file on cleanup.
+		g.genfiles = append(g.genfiles, designDir)
+	}
+	imports := []*codegen.ImportSpec{
+		codegen.NewImport(".", "github.com/shogo82148/goa-v1/design"),
+		codegen.NewImport(".", "github.com/shogo82148/goa-v1/design/apidsl"),
+	}
+	if err = file.WriteHeader("", codegen.Goify(g.Target, false), imports); err != nil {
+		return nil, err
+	}
+	if _, err = file.Write(code); err != nil {
+		return nil, err
+	}
+	file.Close()
+	if err = file.FormatCode(); err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invocation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
@@ -0,0 +1,539 @@
+package genimport_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1/goagen/codegen"
+	genimport "github.com/shogo82148/goa-v1/goagen/gen_import"
+)
+
+var _ = Describe("Generate", func() {
+	var workspace *codegen.Workspace
+	var outDir, spec, content string
+	var force bool
+	var gen *genimport.Generator
+	var files []string
+	var genErr error
+	oldGO111MODULE := os.Getenv("GO111MODULE")
+
+	BeforeEach(func() {
+		os.Setenv("GO111MODULE", "off")
+
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		outDir, err = os.MkdirTemp(filepath.Join(workspace.Path, "src"), "")
+		Ω(err).ShouldNot(HaveOccurred())
+		force = false
+	})
+
+	JustBeforeEach(func() {
+		specFile := filepath.Join(outDir, "spec.yaml")
+		Ω(os.WriteFile(specFile, []byte(spec), 0644)).ShouldNot(HaveOccurred())
+		gen = genimport.NewGenerator(
+			genimport.Spec(specFile),
+			genimport.OutDir(outDir),
+			genimport.Target("design"),
+			genimport.Force(force),
+		)
+		files, genErr = gen.Generate()
+		if genErr == nil {
+			b, err := os.ReadFile(filepath.Join(outDir, "design", "design.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			content = string(b)
+		}
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+		os.Setenv("GO111MODULE", oldGO111MODULE)
+	})
+
+	Context("with a Swagger 2.0 specification", func() {
+		BeforeEach(func() {
+			spec = swaggerSpec
+		})
+
+		It("generates the design package", func() {
+			Ω(genErr).ShouldNot(HaveOccurred())
+			Ω(files).Should(ConsistOf(
+				filepath.Join(outDir, "design", "design.go"),
+				filepath.Join(outDir, "design"),
+			))
+			Ω(content).Should(ContainSubstring("package design"))
+			Ω(content).Should(ContainSubstring(`. "github.com/shogo82148/goa-v1/design/apidsl"`))
+			Ω(content).Should(ContainSubstring(`var _ = API("the-wine-cellar-api", func() {`))
+			Ω(content).Should(ContainSubstring(`Title("The wine cellar API")`))
+			Ω(content).Should(ContainSubstring(`BasePath("/cellar")`))
+		})
+
+		It("generates the resources and actions", func() {
+			Ω(genErr).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring(`var _ = Resource("bottle", func() {`))
+			Ω(content).Should(ContainSubstring(`Action("show", func() {`))
+			Ω(content).Should(ContainSubstring(`Description("Retrieve bottle with given id")`))
+			Ω(content).Should(ContainSubstring(`Routing(GET("/bottles/:id"))`))
+			Ω(content).Should(ContainSubstring(`Param("id", Integer, "Bottle ID")`))
+			Ω(content).Should(ContainSubstring(`Response(OK, BottleMedia)`))
+			Ω(content).Should(ContainSubstring(`Response(NotFound)`))
+			Ω(content).Should(ContainSubstring(`Payload(CreateBottlePayload)`))
+		})
+
+		It("generates the media types and types", func() {
+			Ω(genErr).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring(`var BottleMedia = MediaType("application/vnd.bottle", func() {`))
+			Ω(content).Should(ContainSubstring(`View("tiny", func() {`))
+			Ω(content).Should(ContainSubstring(`var CreateBottlePayload = Type("CreateBottlePayload", func() {`))
+			Ω(content).Should(ContainSubstring(`Attribute("name", String, "Name of bottle", func() {`))
+			Ω(content).Should(ContainSubstring(`MinLength(2)`))
+			Ω(content).Should(ContainSubstring(`Example("Number 8")`))
+			Ω(content).Should(ContainSubstring(`Required("name")`))
+		})
+
+		It("reports the parts that could not be imported", func() {
+			Ω(genErr).ShouldNot(HaveOccurred())
+			Ω(gen.Warnings).Should(ContainElement(ContainSubstring("multipleOf")))
+		})
+
+		Context("when the design package already exists", func() {
+			BeforeEach(func() {
+				Ω(os.MkdirAll(filepath.Join(outDir, "design"), 0755)).ShouldNot(HaveOccurred())
+				Ω(os.WriteFile(filepath.Join(outDir, "design", "design.go"), []byte("package design\n"), 0644)).ShouldNot(HaveOccurred())
+			})
+
+			It("refuses to overwrite it", func() {
+				Ω(genErr).Should(HaveOccurred())
+				Ω(genErr.Error()).Should(ContainSubstring("already exists"))
+			})
+
+			Context("with the force option", func() {
+				BeforeEach(func() {
+					force = true
+				})
+
+				It("overwrites it", func() {
+					Ω(genErr).ShouldNot(HaveOccurred())
+					Ω(files).Should(ConsistOf(filepath.Join(outDir, "design", "design.go")))
+					Ω(content).Should(ContainSubstring(`var _ = API("the-wine-cellar-api", func() {`))
+				})
+			})
+		})
+	})
+
+	Context("with an OpenAPI 3 specification", func() {
+		BeforeEach(func() {
+			spec = openAPISpec
+		})
+
+		It("generates the design package", func() {
+			Ω(genErr).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring(`Host("example.com")`))
+			Ω(content).Should(ContainSubstring(`Scheme("https")`))
+			Ω(content).Should(ContainSubstring(`BasePath("/v1")`))
+			Ω(content).Should(ContainSubstring(`var JWTScheme = APIKeySecurity("jwt", func() {`))
+			Ω(content).Should(ContainSubstring(`Routing(POST("/pets"))`))
+			Ω(content).Should(ContainSubstring(`Payload(PetType)`))
+			Ω(content).Should(ContainSubstring(`Response(Created, PetMedia)`))
+			Ω(content).Should(ContainSubstring(`Attribute("born", DateTime)`))
+		})
+
+		It("reports the parts that could not be imported", func() {
+			Ω(genErr).ShouldNot(HaveOccurred())
+			Ω(gen.Warnings).Should(ContainElement(ContainSubstring("API keys located in cookies are not supported")))
+		})
+	})
+
+	Context("with long-running actions", func() {
+		BeforeEach(func() {
+			spec = longRunningSpec
+		})
+
+		It("defines the actions with LongRunning", func() {
+			Ω(genErr).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring(`BasePath("/jobs")`))
+			Ω(content).Should(ContainSubstring(`Routing(POST("/export"))`))
+			Ω(content).Should(ContainSubstring(`LongRunning(5)`))
+			Ω(content).Should(ContainSubstring(`Routing(GET("//api/health"))`))
+			Ω(content).ShouldNot(ContainSubstring(`Response(Accepted`))
+			Ω(content).ShouldNot(ContainSubstring(`show_operation`))
+			Ω(content).ShouldNot(ContainSubstring(`cancel_operation`))
+		})
+	})
+
+	Context("with paginated actions and webhooks", func() {
+		BeforeEach(func() {
+			spec = paginationWebhookSpec
+		})
+
+		It("defines the actions with Paginated", func() {
+			Ω(genErr).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring(`Paginated(CursorPagination, 20, 100)`))
+			Ω(content).Should(ContainSubstring(`Paginated(OffsetPagination, 10, 50)`))
+			Ω(content).Should(ContainSubstring(`Param("q", String)`))
+			Ω(content).ShouldNot(ContainSubstring(`Param("limit"`))
+			Ω(content).ShouldNot(ContainSubstring(`Param("cursor"`))
+			Ω(content).ShouldNot(ContainSubstring(`Param("offset"`))
+			Ω(content).ShouldNot(ContainSubstring(`Header("Link"`))
+		})
+
+		It("defines the webhooks with Webhook", func() {
+			Ω(genErr).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring(`Webhook("order.created", func() {`))
+			Ω(content).Should(ContainSubstring(`Description("Sent when an order is created")`))
+			Ω(content).Should(ContainSubstring(`Payload(OrderCreatedWebhookPayload)`))
+			Ω(content).Should(ContainSubstring(`Header("X-Tenant", String)`))
+			Ω(content).Should(ContainSubstring(`Webhook("order.shipped", func() {`))
+			Ω(content).Should(ContainSubstring(`Payload(ShipOrderOrderShippedWebhookPayload)`))
+			Ω(content).ShouldNot(ContainSubstring(`X-Webhook-Signature`))
+		})
+
+		It("does not report the extensions", func() {
+			Ω(genErr).ShouldNot(HaveOccurred())
+			Ω(gen.Warnings).Should(BeEmpty())
+		})
+	})
+
+	Context("with an unknown specification format", func() {
+		BeforeEach(func() {
+			spec = "foo: bar\n"
+		})
+
+		It("fails", func() {
+			Ω(genErr).Should(HaveOccurred())
+		})
+	})
+})
+
+const swaggerSpec = `
+swagger: "2.0"
+info:
+  title: The wine cellar API
+  version: "1.0"
+host: localhost:8080
+basePath: /cellar
+paths:
+  /bottles/{id}:
+    get:
+      tags: [bottle]
+      summary: show bottle
+      description: Retrieve bottle with given id
+      operationId: bottle#show
+      parameters:
+      - name: id
+        in: path
+        description: Bottle ID
+        required: true
+        type: integer
+      responses:
+        "200":
+          description: OK
+          schema:
+            $ref: "#/definitions/Bottle"
+        "404":
+          description: Not Found
+  /bottles:
+    post:
+      tags: [bottle]
+      summary: create bottle
+      operationId: bottle#create
+      parameters:
+      - name: payload
+        in: body
+        required: true
+        schema:
+          $ref: "#/definitions/CreateBottlePayload"
+      responses:
+        "201":
+          description: Created
+definitions:
+  Bottle:
+    title: "Mediatype identifier: application/vnd.bottle; view=default"
+    description: Bottle media type (default view)
+    type: object
+    properties:
+      id:
+        type: integer
+      name:
+        type: string
+      vintage:
+        type: integer
+        multipleOf: 5
+    required: [id, name]
+  BottleTiny:
+    title: "Mediatype identifier: application/vnd.bottle; view=tiny"
+    description: Bottle media type (tiny view)
+    type: object
+    properties:
+      id:
+        type: integer
+    required: [id]
+  CreateBottlePayload:
+    title: CreateBottlePayload
+    type: object
+    properties:
+      name:
+        type: string
+        description: Name of bottle
+        minLength: 2
+        example: Number 8
+    required: [name]
+`
+
+const openAPISpec = `
+openapi: 3.0.3
+info:
+  title: Pets
+  version: "1.0"
+servers:
+- url: https://example.com/v1
+paths:
+  /pets:
+    post:
+      operationId: pet#create
+      security:
+      - jwt: []
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/Pet"
+      responses:
+        "201":
+          description: Created
+          content:
+            application/vnd.pet:
+              schema:
+                $ref: "#/components/schemas/PetMedia"
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+        born:
+          type: string
+          format: date-time
+    PetMedia:
+      title: "Mediatype identifier: application/vnd.pet; view=default"
+      type: object
+      properties:
+        name:
+          type: string
+  securitySchemes:
+    jwt:
+      type: http
+      scheme: bearer
+    session:
+      type: apiKey
+      in: cookie
+      name: session
+`
+
+const longRunningSpec = `
+swagger: "2.0"
+info:
+  title: Jobs
+  version: "1.0"
+basePath: /api
+paths:
+  /jobs/export:
+    post:
+      operationId: job#export
+      responses:
+        "202":
+          description: Accepted
+          schema:
+            $ref: "#/definitions/GoaOperation"
+          headers:
+            Location:
+              type: string
+            Retry-After:
+              type: integer
+              default: 5
+  /health:
+    get:
+      operationId: job#health
+      responses:
+        "204":
+          description: No Content
+  /jobs/operations/{operationID}:
+    get:
+      operationId: job#show_operation
+      parameters:
+      - name: operationID
+        in: path
+        required: true
+        type: string
+      responses:
+        "200":
+          description: OK
+          schema:
+            $ref: "#/definitions/GoaOperation"
+    delete:
+      operationId: job#cancel_operation
+      parameters:
+      - name: operationID
+        in: path
+        required: true
+        type: string
+      responses:
+        "200":
+          description: OK
+          schema:
+            $ref: "#/definitions/GoaOperation"
+definitions:
+  GoaOperation:
+    title: "Mediatype identifier: application/vnd.goa.operation; view=default"
+    type: object
+    properties:
+      id:
+        type: string
+      status:
+        type: string
+`
+
+const paginationWebhookSpec = `
+swagger: "2.0"
+info:
+  title: shop
+  version: "1.0"
+paths:
+  /orders:
+    get:
+      operationId: order#list
+      parameters:
+      - name: cursor
+        in: query
+        type: string
+      - name: limit
+        in: query
+        type: integer
+        default: 20
+        minimum: 1
+        maximum: 100
+      responses:
+        "200":
+          description: OK
+          headers:
+            Link:
+              description: RFC 8288 links to the other pages of the collection
+              type: string
+          schema:
+            $ref: "#/definitions/OrderCollection"
+      x-pagination:
+        style: cursor
+        defaultLimit: 20
+        maxLimit: 100
+  /orders/browse:
+    get:
+      operationId: order#browse
+      parameters:
+      - name: limit
+        in: query
+        type: integer
+        default: 10
+        minimum: 1
+        maximum: 50
+      - name: offset
+        in: query
+        type: integer
+        default: 0
+        minimum: 0
+      - name: q
+        in: query
+        type: string
+      responses:
+        "200":
+          description: OK
+          headers:
+            Link:
+              description: RFC 8288 links to the other pages of the collection
+              type: string
+          schema:
+            $ref: "#/definitions/OrderCollection"
+      x-pagination:
+        style: offset
+        defaultLimit: 10
+        maxLimit: 50
+  /orders/{id}/ship:
+    post:
+      operationId: order#ship
+      parameters:
+      - name: id
+        in: path
+        required: true
+        type: integer
+      responses:
+        "204":
+          description: No Content
+      x-callbacks:
+        order.shipped:
+          post:
+            summary: order.shipped
+            parameters:
+            - name: X-Webhook-Signature
+              in: header
+              required: true
+              type: string
+            - name: payload
+              in: body
+              required: true
+              schema:
+                $ref: "#/definitions/ShipOrderOrderShippedWebhookPayload"
+            responses:
+              "200":
+                description: Webhook received
+definitions:
+  Order:
+    title: "Mediatype identifier: application/vnd.order; view=default"
+    type: object
+    properties:
+      id:
+        type: integer
+  OrderCollection:
+    title: "Mediatype identifier: application/vnd.order; type=collection; view=default"
+    type: array
+    items:
+      $ref: "#/definitions/Order"
+  OrderCreatedWebhookPayload:
+    title: OrderCreatedWebhookPayload
+    type: object
+    properties:
+      id:
+        type: integer
+      total:
+        type: number
+    required: [id]
+  ShipOrderOrderShippedWebhookPayload:
+    title: ShipOrderOrderShippedWebhookPayload
+    type: object
+    properties:
+      carrier:
+        type: string
+x-webhooks:
+  order.created:
+    post:
+      summary: order.created
+      description: Sent when an order is created
+      parameters:
+      - name: X-Tenant
+        in: header
+        type: string
+      - name: X-Webhook-Signature
+        in: header
+        required: true
+        type: string
+      - name: payload
+        in: body
+        required: true
+        schema:
+          $ref: "#/definitions/OrderCreatedWebhookPayload"
+      responses:
+        "200":
+          description: Webhook received
+`
@@ -0,0 +1,665 @@
+package genimport
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shogo82148/goa-v1/design"
+	"github.com/shogo82148/goa-v1/goagen/codegen"
+)
+
+type (
+	// importer maps the content of a Swagger document onto design definitions.
+	importer struct {
+		doc *document
+		// warnings lists the parts of the document that could not be mapped.
+		warnings []string
+		// defs indexes the definitions by name.
+		defs map[string]*definition
+		// synthesized lists the user types created for inline objects.
+		synthesized []*definition
+		// names records the Go identifiers already in use.
+		names map[string]bool
+		// schemes indexes the Go identifiers of the security schemes by name.
+		schemes map[string]string
+		// resources lists the resources built from the document paths.
+		resources []*resource
+	}
+
+	// definition describes how a Swagger definition maps onto the design.
+	definition struct {
+		Name   string
+		Kind   definitionKind
+		Schema *schema
+		// VarName is the name of the Go variable holding the type or media type.
+		VarName string
+		// Identifier is the media type identifier.
+		Identifier string
+		// TypeName is the media type type name.
+		TypeName string
+		// View is the name of the view rendered by a projection or a collection.
+		View string
+		// Base is the media type of a projection or the element media type of a collection.
+		Base *definition
+		// Views lists the definitions of the media type views indexed by view name.
+		Views map[string]*definition
+	}
+
+	// definitionKind enumerates the possible mappings of a Swagger definition.
+	definitionKind int
+
+	// resource groups the actions built from the operations tagged with the same resource.
+	resource struct {
+		Name        string
+		Description string
+		// BasePath is the base path of the resource routes, only set for resources defining
+		// long-running actions whose operation actions are relative to the base path.
+		BasePath string
+		// Security is the security of the resource, only set for resources defining
+		// long-running actions whose operation actions are secured differently from the API.
+		Security *[]map[string][]string
+		// SecurityContext identifies the security requirements in the warnings.
+		SecurityContext string
+		Actions         []*action
+	}
+
+	// action describes the operations mapped onto a single action.
+	action struct {
+		Name      string
+		Resource  string
+		Operation *operation
+		// Context identifies the operation in the warnings.
+		Context string
+		Routes  []route
+		Params  []*parameter
+		Headers []*parameter
+		Body    *parameter
+		Form    []*parameter
+		// LongRunning is true if the action starts a long-running operation, its Accepted
+		// response is then defined with LongRunning.
+		LongRunning bool
+		// RetryAfter is the retry delay of the long-running operation in seconds.
+		RetryAfter int
+		// Pagination is the pagination of the action if it is defined with Paginated.
+		Pagination *pagination
+	}
+
+	// route is an action route.
+	route struct {
+		Verb string
+		Path string
+	}
+)
+
+const (
+	// userTypeKind identifies the definitions mapped onto user types.
+	userTypeKind definitionKind = iota + 1
+	// inlineKind identifies the definitions that are not objects and are inlined where used.
+	inlineKind
+	// mediaTypeKind identifies the definitions mapped onto media types.
+	mediaTypeKind
+	// projectionKind identifies the definitions rendering a view of a media type.
+	projectionKind
+	// collectionKind identifies the definitions rendering a collection of media types.
+	collectionKind
+	// builtinKind identifies the definitions of media types defined by goa.
+	builtinKind
+)
+
+// reserved lists the identifiers exported by the design and apidsl packages that the generated
+// variable names could clash with.
+var reserved = map[string]bool{
+	"ContentType": true, "DataType": true, "DefaultMedia": true, "DupType": true,
+	"ErrorMedia": true, "GoType": true, "Media": true, "MediaType": true,
+	"OperationMedia": true, "OptionalPayload": true, "Payload": true, "Scheme": true,
+	"Type": true, "UnsupportedMediaType": true,
+}
+
+// newImporter returns an importer for doc.
+func newImporter(doc *document, warnings []string) *importer {
+	return &importer{
+		doc:      doc,
+		warnings: warnings,
+		defs:     make(map[string]*definition),
+		names:    make(map[string]bool),
+		schemes:  make(map[string]string),
+	}
+}
+
+// warn records a part of the document that could not be mapped.
+func (i *importer) warn(ctx, format string, args ...interface{}) {
+	i.warnings = append(i.warnings, ctx+": "+fmt.Sprintf(format, args...))
+}
+
+// analyze classifies the definitions and builds the resources.
+func (i *importer) analyze() {
+	i.classify()
+	i.buildResources()
+	for _, r := range i.resources {
+		i.findLongRunning(r)
+		for _, a := range r.Actions {
+			i.findPagination(a)
+		}
+	}
+	for _, n := range sortedNames(i.doc.SecurityDefinitions) {
+		i.schemes[n] = i.varName(n, "Scheme")
+	}
+	for _, n := range sortedNames(i.defs) {
+		d := i.defs[n]
+		switch d.Kind {
+		case userTypeKind:
+			d.VarName = i.varName(d.Name, "Type")
+		case mediaTypeKind:
+			d.VarName = i.varName(d.TypeName, "Media")
+		}
+	}
+}
+
+// classify maps each definition onto a user type or a media type. The definitions whose title
+// identifies a media type - as generated by goa - are grouped by media type, the definitions
+// used by responses are media types and the other definitions are user types.
+func (i *importer) classify() {
+	groups := make(map[string][]*definition)
+	var ids []string
+	for _, n := range sortedNames(i.doc.Definitions) {
+		s := i.doc.Definitions[n]
+		d := &definition{Name: n, Schema: s}
+		i.defs[n] = d
+		id, view, coll := parseMediaTypeTitle(s.Title)
+		if id == "" {
+			continue
+		}
+		d.View = view
+		switch design.CanonicalIdentifier(id) {
+		case design.CanonicalIdentifier(design.ErrorMediaIdentifier):
+			d.Kind, d.VarName = builtinKind, "ErrorMedia"
+			continue
+		case design.CanonicalIdentifier(design.OperationMedia.Identifier):
+			d.Kind, d.VarName = builtinKind, "OperationMedia"
+			continue
+		}
+		if coll {
+			d.Kind = collectionKind
+			continue
+		}
+		if _, ok := groups[id]; !ok {
+			ids = append(ids, id)
+		}
+		groups[id] = append(groups[id], d)
+	}
+	for _, id := range ids {
+		defs := groups[id]
+		base := defs[0]
+		for _, d := range defs {
+			if d.View == design.DefaultView {
+				base = d
+				break
+			}
+		}
+		base.Kind = mediaTypeKind
+		base.Identifier = id
+		base.TypeName = strings.TrimSuffix(base.Name, codegen.Goify(base.View, true))
+		if base.View == design.DefaultView {
+			base.TypeName = base.Name
+		}
+		base.Views = make(map[string]*definition)
+		for _, d := range defs {
+			base.Views[d.View] = d
+			if d != base {
+				d.Kind = projectionKind
+				d.Base = base
+			}
+		}
+	}
+
+	// Definitions used by responses describe media types.
+	i.iterateResponses(func(ctx string, r *response) {
+		if r.Schema == nil {
+			return
+		}
+		d := i.definitionFor(r.Schema.Ref)
+		if d == nil && r.Schema.Type == "array" && r.Schema.Items != nil {
+			if d = i.definitionFor(r.Schema.Items.Ref); d != nil && d.Kind == 0 && isObject(d.Schema) {
+				i.makeMediaType(d)
+			}
+			return
+		}
+		if d == nil || d.Kind != 0 {
+			return
+		}
+		if d.Schema.Type == "array" && d.Schema.Items != nil {
+			if e := i.definitionFor(d.Schema.Items.Ref); e != nil && (e.Kind == 0 || e.Kind == mediaTypeKind) && isObject(e.Schema) {
+				i.makeMediaType(e)
+				d.Kind = collectionKind
+			}
+			return
+		}
+		if isObject(d.Schema) {
+			i.makeMediaType(d)
+		}
+	})
+
+	for _, n := range sortedNames(i.defs) {
+		d := i.defs[n]
+		switch d.Kind {
+		case 0:
+			d.Kind = userTypeKind
+			if !isObject(d.Schema) {
+				d.Kind = inlineKind
+			}
+		case collectionKind:
+			if d.View == "" {
+				d.View = design.DefaultView
+			}
+			var e *definition
+			if d.Schema.Items != nil {
+				e = i.definitionFor(d.Schema.Items.Ref)
+			}
+			switch {
+			case e == nil:
+				i.warn("definitions."+n, "collection of unknown media type")
+				d.Kind = inlineKind
+			case e.Kind == projectionKind:
+				d.Base = e.Base
+				d.View = e.View
+			case e.Kind == mediaTypeKind:
+				d.Base = e
+			default:
+				i.warn("definitions."+n, "collection of %s which is not a media type", e.Name)
+				d.Kind = inlineKind
+			}
+		}
+	}
+}
+
+// makeMediaType turns the definition d into a media type whose identifier is derived from the
+// definition name.
+func (i *importer) makeMediaType(d *definition) {
+	if d.Kind == mediaTypeKind {
+		return
+	}
+	d.Kind = mediaTypeKind
+	d.TypeName = d.Name
+	d.Identifier = "application/vnd." + strings.ToLower(codegen.KebabCase(d.Name)) + "+json"
+	d.View = design.DefaultView
+	d.Views = map[string]*definition{design.DefaultView: d}
+}
+
+// definitionFor returns the definition referred to by ref if any.
+func (i *importer) definitionFor(ref string) *definition {
+	if !strings.HasPrefix(ref, "#/definitions/") {
+		return nil
+	}
+	return i.defs[strings.TrimPrefix(ref, "#/definitions/")]
+}
+
+// iterateResponses calls fn for each response of the document.
+func (i *importer) iterateResponses(fn func(ctx string, r *response)) {
+	for _, n := range sortedNames(i.doc.Responses) {
+		fn("responses."+n, i.doc.Responses[n])
+	}
+	for _, p := range sortedNames(i.doc.Paths) {
+		ops := i.doc.Paths[p].operations()
+		for _, verb := range verbs {
+			op, ok := ops[verb]
+			if !ok {
+				continue
+			}
+			for _, code := range sortedNames(op.Responses) {
+				fn(fmt.Sprintf("paths.%s.%s.responses.%s", p, strings.ToLower(verb), code), op.Responses[code])
+			}
+		}
+	}
+}
+
+// verbs lists the HTTP verbs of the Swagger operations in the order actions are generated.
+var verbs = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+// buildResources groups the operations into resources and actions. Operations whose ID follows
+// the "resource#action" convention used by goa are mapped accordingly, the others are grouped by
+// tag or by first path segment.
+func (i *importer) buildResources() {
+	resources := make(map[string]*resource)
+	actions := make(map[string]*action)
+	for _, p := range sortedNames(i.doc.Paths) {
+		item := i.doc.Paths[p]
+		ops := item.operations()
+		for _, verb := range verbs {
+			op, ok := ops[verb]
+			if !ok {
+				continue
+			}
+			ctx := fmt.Sprintf("paths.%s.%s", p, strings.ToLower(verb))
+			resName, actName, extraRoute := operationNames(op, verb, p)
+			res, ok := resources[resName]
+			if !ok {
+				res = &resource{Name: resName}
+				for _, t := range i.doc.Tags {
+					if t.Name == resName {
+						res.Description = t.Description
+					}
+				}
+				resources[resName] = res
+			}
+			key := resName + "#" + actName
+			act, ok := actions[key]
+			if ok && !extraRoute {
+				for n := 2; ok; n++ {
+					actName = fmt.Sprintf("%s%d", operationNameBase(actName), n)
+					key = resName + "#" + actName
+					_, ok = actions[key]
+				}
+			}
+			if !ok {
+				act = &action{Name: actName, Resource: resName, Operation: op, Context: ctx}
+				actions[key] = act
+				res.Actions = append(res.Actions, act)
+			}
+			act.Routes = append(act.Routes, route{Verb: verb, Path: goaPath(p)})
+			for _, e := range op.Extensions {
+				i.warn(ctx, "extension %s is not imported", e)
+			}
+			params := append(append([]*parameter{}, item.Parameters...), op.Parameters...)
+			for _, param := range params {
+				param = i.resolveParameter(ctx, param)
+				if param == nil {
+					continue
+				}
+				switch param.In {
+				case "path", "query":
+					act.Params = addParameter(act.Params, param)
+				case "header":
+					act.Headers = addParameter(act.Headers, param)
+				case "body":
+					act.Body = param
+				case "formData":
+					act.Form = addParameter(act.Form, param)
+				default:
+					i.warn(ctx, "parameter %s located in %q is not supported", param.Name, param.In)
+				}
+			}
+		}
+	}
+	for _, n := range sortedNames(resources) {
+		i.resources = append(i.resources, resources[n])
+	}
+}
+
+// findLongRunning flags the actions of r whose Accepted response renders the built-in operation
+// media type with a Location header as long-running. LongRunning defines the operation actions of
+// the resource so the imported ones are removed, the routes of the resource are made relative to
+// the base path of the operation actions.
+func (i *importer) findLongRunning(r *resource) {
+	var (
+		longRunning  bool
+		show, cancel *action
+		others       []*action
+	)
+	for _, a := range r.Actions {
+		switch a.Name {
+		case design.ShowOperationAction:
+			show = a
+			continue
+		case design.CancelOperationAction:
+			cancel = a
+			continue
+		}
+		others = append(others, a)
+		resp, ok := a.Operation.Responses[strconv.Itoa(http.StatusAccepted)]
+		if !ok {
+			continue
+		}
+		if resp = i.resolveResponse(a.Context, resp); resp == nil || resp.Schema == nil || resp.Headers["Location"] == nil {
+			continue
+		}
+		if d := i.definitionFor(resp.Schema.Ref); d == nil || d.Kind != builtinKind || d.VarName != "OperationMedia" {
+			continue
+		}
+		a.LongRunning = true
+		a.RetryAfter = 1
+		if h := resp.Headers["Retry-After"]; h != nil && h.Default != nil {
+			if n, err := strconv.Atoi(fmt.Sprint(h.Default)); err == nil {
+				a.RetryAfter = n
+			}
+		} else {
+			i.warn(a.Context, "retry delay of long-running operation is not documented, using %d second", a.RetryAfter)
+		}
+		longRunning = true
+	}
+	if !longRunning {
+		return
+	}
+	if show == nil || cancel == nil || len(show.Routes) != 1 || !strings.HasSuffix(show.Routes[0].Path, design.OperationPath) {
+		for _, a := range others {
+			a.LongRunning = false
+		}
+		i.warn("paths", "operation actions of resource %s not found, long-running actions are not imported", r.Name)
+		return
+	}
+	r.Actions = others
+	if sec := show.Operation.Security; sec != nil && (len(*sec) == 0 || !sameSecurity(*sec, i.doc.Security)) {
+		r.Security, r.SecurityContext = sec, show.Context+".security"
+	}
+	r.BasePath = strings.TrimSuffix(show.Routes[0].Path, design.OperationPath)
+	if r.BasePath == "" {
+		return
+	}
+	for _, a := range r.Actions {
+		for j, rt := range a.Routes {
+			switch {
+			case rt.Path == r.BasePath:
+				a.Routes[j].Path = ""
+			case strings.HasPrefix(rt.Path, r.BasePath+"/"):
+				a.Routes[j].Path = strings.TrimPrefix(rt.Path, r.BasePath)
+			default:
+				// absolute routes ignore both the resource and the API base paths
+				a.Routes[j].Path = "/" + path.Join("/", i.doc.BasePath, rt.Path)
+			}
+		}
+	}
+}
+
+// findPagination flags a as paginated if its operation has a valid x-pagination extension.
+// Paginated defines the limit and the offset or cursor parameters of the action so the imported
+// ones are removed.
+func (i *importer) findPagination(a *action) {
+	p := a.Operation.Pagination
+	if p == nil {
+		return
+	}
+	ctx := a.Context + ".x-pagination"
+	var param string
+	switch design.PaginationStyle(p.Style) {
+	case design.CursorPagination:
+		param = design.CursorParam
+	case design.OffsetPagination:
+		param = design.OffsetParam
+	default:
+		i.warn(ctx, "unknown pagination style %q, pagination is not imported", p.Style)
+		return
+	}
+	if p.DefaultLimit < 1 || p.MaxLimit < p.DefaultLimit {
+		i.warn(ctx, "invalid default limit %d or maximum limit %d, pagination is not imported", p.DefaultLimit, p.MaxLimit)
+		return
+	}
+	a.Pagination = p
+	var params []*parameter
+	for _, prm := range a.Params {
+		if prm.In == "query" && (prm.Name == design.LimitParam || prm.Name == param) {
+			continue
+		}
+		params = append(params, prm)
+	}
+	a.Params = params
+}
+
+// resolveParameter returns the parameter referred to by p if p is a reference, p otherwise.
+func (i *importer) resolveParameter(ctx string, p *parameter) *parameter {
+	if p.Ref == "" {
+		return p
+	}
+	if rp, ok := i.doc.Parameters[strings.TrimPrefix(p.Ref, "#/parameters/")]; ok && strings.HasPrefix(p.Ref, "#/parameters/") {
+		return rp
+	}
+	i.warn(ctx, "unknown parameter %s", p.Ref)
+	return nil
+}
+
+// resolveResponse returns the response referred to by r if r is a reference, r otherwise.
+func (i *importer) resolveResponse(ctx string, r *response) *response {
+	if r.Ref == "" {
+		return r
+	}
+	if rr, ok := i.doc.Responses[strings.TrimPrefix(r.Ref, "#/responses/")]; ok && strings.HasPrefix(r.Ref, "#/responses/") {
+		return rr
+	}
+	i.warn(ctx, "unknown response %s", r.Ref)
+	return nil
+}
+
+// addParameter adds p to params replacing the parameter with the same name if any.
+func addParameter(params []*parameter, p *parameter) []*parameter {
+	for j, e := range params {
+		if e.Name == p.Name {
+			params[j] = p
+			return params
+		}
+	}
+	return append(params, p)
+}
+
+// operationNames returns the names of the resource and action the operation maps onto. extra is
+// true if the operation describes an additional route of the action.
+func operationNames(op *operation, verb, path string) (res, act string, extra bool) {
+	if parts := strings.Split(op.OperationID, "#"); len(parts) >= 2 && parts[0] != "" && parts[1] != "" {
+		return parts[0], parts[1], len(parts) > 2
+	}
+	if len(op.Tags) > 0 {
+		res = op.Tags[0]
+	} else {
+		for _, s := range strings.Split(path, "/") {
+			if s != "" && !strings.HasPrefix(s, "{") {
+				res = s
+				break
+			}
+		}
+	}
+	if res == "" {
+		res = "root"
+	}
+	if op.OperationID != "" {
+		return res, op.OperationID, false
+	}
+	switch verb {
+	case "GET":
+		act = "list"
+		if strings.HasSuffix(path, "}") {
+			act = "show"
+		}
+	case "POST":
+		act = "create"
+	case "PUT", "PATCH":
+		act = "update"
+	default:
+		act = strings.ToLower(verb)
+	}
+	return res, act, false
+}
+
+// operationNameBase returns name without its numeric suffix.
+func operationNameBase(name string) string {
+	return strings.TrimRight(name, "0123456789")
+}
+
+// goaPath converts the Swagger path p into a goa route path.
+func goaPath(p string) string {
+	return strings.NewReplacer("{", ":", "}", "").Replace(p)
+}
+
+// varName returns a unique Go identifier for the named definition. The identifier ends with
+// suffix so that it does not clash with the identifiers exported by the DSL packages.
+func (i *importer) varName(name, suffix string) string {
+	base := codegen.Goify(name, true)
+	if !strings.HasSuffix(base, suffix) && !(suffix == "Type" && strings.HasSuffix(base, "Payload")) {
+		base += suffix
+	}
+	v := base
+	for n := 2; i.names[v] || reserved[v]; n++ {
+		v = fmt.Sprintf("%s%d", base, n)
+	}
+	i.names[v] = true
+	return v
+}
+
+// synthesize creates a user type for the inline object s and returns the name of the variable
+// holding it.
+func (i *importer) synthesize(name string, s *schema) string {
+	typeName := codegen.Goify(name, true)
+	for n := 2; i.defs[typeName] != nil; n++ {
+		typeName = fmt.Sprintf("%s%d", codegen.Goify(name, true), n)
+	}
+	d := &definition{Name: typeName, Kind: userTypeKind, Schema: s}
+	d.VarName = i.varName(typeName, "Type")
+	i.defs[typeName] = d
+	i.synthesized = append(i.synthesized, d)
+	return d.VarName
+}
+
+// parseMediaTypeTitle extracts the media type identifier, view and collection flag from the
+// title of definitions generated by goa, e.g. "Mediatype identifier: application/vnd.bottle;
+// view=default".
+func parseMediaTypeTitle(title string) (id, view string, collection bool) {
+	const prefix = "Mediatype identifier: "
+	if !strings.HasPrefix(title, prefix) {
+		return "", "", false
+	}
+	base, params, err := mime.ParseMediaType(strings.TrimPrefix(title, prefix))
+	if err != nil {
+		return "", "", false
+	}
+	view = params["view"]
+	if view == "" {
+		view = design.DefaultView
+	}
+	delete(params, "view")
+	collection = params["type"] == "collection"
+	return mime.FormatMediaType(base, params), view, collection
+}
+
+// isObject returns true if s describes an object with known properties.
+func isObject(s *schema) bool {
+	if _, ok := s.additionalProperties(); ok {
+		return false
+	}
+	return s.Ref == "" && (s.Type == "object" || s.Type == "" && len(s.Properties) > 0)
+}
+
+// responseName returns the name of the goa response for the given status code. Standard is true
+// if the name is one of the responses defined by goa.
+func responseName(status int) (name string, standard bool) {
+	for n, r := range design.NewAPIDefinition().DefaultResponses {
+		if r.Status == status {
+			return n, true
+		}
+	}
+	name = strings.NewReplacer(" ", "", "-", "").Replace(http.StatusText(status))
+	if name == "" {
+		name = "Status" + strconv.Itoa(status)
+	}
+	return name, false
+}
+
+// sortedNames returns the keys of m in alphabetical order.
+func sortedNames[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
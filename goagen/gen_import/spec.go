@@ -0,0 +1,747 @@
+package genimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+type (
+	// document is the subset of a Swagger 2.0 document read by the importer. OpenAPI 3
+	// documents are converted to this shape before being decoded.
+	document struct {
+		Swagger             string                     `json:"swagger"`
+		Info                *info                      `json:"info"`
+		Host                string                     `json:"host"`
+		BasePath            string                     `json:"basePath"`
+		Schemes             []string                   `json:"schemes"`
+		Consumes            []string                   `json:"consumes"`
+		Produces            []string                   `json:"produces"`
+		Paths               map[string]*pathItem       `json:"paths"`
+		Definitions         map[string]*schema         `json:"definitions"`
+		Parameters          map[string]*parameter      `json:"parameters"`
+		Responses           map[string]*response       `json:"responses"`
+		SecurityDefinitions map[string]*securityScheme `json:"securityDefinitions"`
+		Security            []map[string][]string      `json:"security"`
+		Tags                []*tag                     `json:"tags"`
+		Webhooks            map[string]*pathItem       `json:"x-webhooks"`
+	}
+
+	info struct {
+		Title          string   `json:"title"`
+		Description    string   `json:"description"`
+		TermsOfService string   `json:"termsOfService"`
+		Version        string   `json:"version"`
+		Contact        *contact `json:"contact"`
+		License        *license `json:"license"`
+	}
+
+	contact struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+		URL   string `json:"url"`
+	}
+
+	license struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	}
+
+	tag struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+
+	pathItem struct {
+		Get        *operation   `json:"get"`
+		Put        *operation   `json:"put"`
+		Post       *operation   `json:"post"`
+		Delete     *operation   `json:"delete"`
+		Options    *operation   `json:"options"`
+		Head       *operation   `json:"head"`
+		Patch      *operation   `json:"patch"`
+		Parameters []*parameter `json:"parameters"`
+	}
+
+	operation struct {
+		Tags        []string               `json:"tags"`
+		Summary     string                 `json:"summary"`
+		Description string                 `json:"description"`
+		OperationID string                 `json:"operationId"`
+		Consumes    []string               `json:"consumes"`
+		Produces    []string               `json:"produces"`
+		Schemes     []string               `json:"schemes"`
+		Parameters  []*parameter           `json:"parameters"`
+		Responses   map[string]*response   `json:"responses"`
+		Deprecated  bool                   `json:"deprecated"`
+		Security    *[]map[string][]string `json:"security"`
+		Pagination  *pagination            `json:"x-pagination"`
+		Callbacks   map[string]*pathItem   `json:"x-callbacks"`
+		// Extensions lists the names of the vendor extensions of the operation that are not
+		// imported.
+		Extensions []string `json:"-"`
+	}
+
+	// pagination is the x-pagination extension written by the genswagger generator for the
+	// paginated actions.
+	pagination struct {
+		Style        string `json:"style"`
+		DefaultLimit int    `json:"defaultLimit"`
+		MaxLimit     int    `json:"maxLimit"`
+	}
+
+	// parameter embeds schema to read the type and validations of the parameters that are not
+	// located in the body.
+	parameter struct {
+		schema
+		Ref         string  `json:"$ref"`
+		Name        string  `json:"name"`
+		In          string  `json:"in"`
+		Description string  `json:"description"`
+		Required    bool    `json:"required"`
+		Schema      *schema `json:"schema"`
+		XDeprecated bool    `json:"x-deprecated"`
+	}
+
+	response struct {
+		Ref         string             `json:"$ref"`
+		Description string             `json:"description"`
+		Schema      *schema            `json:"schema"`
+		Headers     map[string]*schema `json:"headers"`
+	}
+
+	securityScheme struct {
+		Type             string            `json:"type"`
+		Description      string            `json:"description"`
+		Name             string            `json:"name"`
+		In               string            `json:"in"`
+		Flow             string            `json:"flow"`
+		AuthorizationURL string            `json:"authorizationUrl"`
+		TokenURL         string            `json:"tokenUrl"`
+		Scopes           map[string]string `json:"scopes"`
+	}
+
+	// schema is a JSON schema as found in Swagger documents.
+	schema struct {
+		Ref                  string             `json:"$ref"`
+		Title                string             `json:"title"`
+		Description          string             `json:"description"`
+		Type                 string             `json:"type"`
+		Format               string             `json:"format"`
+		Items                *schema            `json:"items"`
+		Properties           map[string]*schema `json:"properties"`
+		AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+		Required             []string           `json:"required"`
+		Enum                 []interface{}      `json:"enum"`
+		Default              interface{}        `json:"default"`
+		Example              interface{}        `json:"example"`
+		Pattern              string             `json:"pattern"`
+		Minimum              *json.Number       `json:"minimum"`
+		Maximum              *json.Number       `json:"maximum"`
+		ExclusiveMinimum     interface{}        `json:"exclusiveMinimum"`
+		ExclusiveMaximum     interface{}        `json:"exclusiveMaximum"`
+		MinLength            *int               `json:"minLength"`
+		MaxLength            *int               `json:"maxLength"`
+		MinItems             *int               `json:"minItems"`
+		MaxItems             *int               `json:"maxItems"`
+		MultipleOf           *json.Number       `json:"multipleOf"`
+		UniqueItems          bool               `json:"uniqueItems"`
+		ReadOnly             bool               `json:"readOnly"`
+		Deprecated           bool               `json:"deprecated"`
+		Nullable             bool               `json:"nullable"`
+		CollectionFormat     string             `json:"collectionFormat"`
+		AllOf                []*schema          `json:"allOf"`
+		OneOf                []*schema          `json:"oneOf"`
+		AnyOf                []*schema          `json:"anyOf"`
+	}
+)
+
+// additionalProperties returns the schema of the values of the hash described by s if any.
+// The returned schema is nil if the values are of any type.
+func (s *schema) additionalProperties() (*schema, bool) {
+	raw := bytes.TrimSpace(s.AdditionalProperties)
+	if len(raw) == 0 || string(raw) == "false" {
+		return nil, false
+	}
+	if string(raw) == "true" {
+		return nil, true
+	}
+	var as schema
+	if err := unmarshal(raw, &as); err != nil {
+		return nil, true
+	}
+	return &as, true
+}
+
+// operations returns the operations of the path item indexed by HTTP verb.
+func (p *pathItem) operations() map[string]*operation {
+	ops := make(map[string]*operation)
+	for verb, op := range map[string]*operation{
+		"GET": p.Get, "PUT": p.Put, "POST": p.Post, "DELETE": p.Delete,
+		"OPTIONS": p.Options, "HEAD": p.Head, "PATCH": p.Patch,
+	} {
+		if op != nil {
+			ops[verb] = op
+		}
+	}
+	return ops
+}
+
+// loadDocument reads the Swagger 2.0 or OpenAPI 3 document in the JSON or YAML file at path.
+func loadDocument(path string) (*document, []string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var raw interface{}
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %s", path, err)
+	}
+	m, ok := toStringMap(raw).(map[string]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("failed to parse %s: not an object", path)
+	}
+	var warnings []string
+	switch {
+	case strings.HasPrefix(fmt.Sprint(m["openapi"]), "3."):
+		m, warnings = convertOpenAPI(m)
+	case fmt.Sprint(m["swagger"]) == "2.0", fmt.Sprint(m["swagger"]) == "2":
+		m["swagger"] = "2.0"
+	default:
+		return nil, nil, fmt.Errorf("%s is not a Swagger 2.0 or OpenAPI 3 document", path)
+	}
+	// YAML documents may use numbers for the API version.
+	if i, ok := m["info"].(map[string]interface{}); ok && i["version"] != nil {
+		i["version"] = fmt.Sprint(i["version"])
+	}
+	b, err = json.Marshal(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	var doc document
+	if err := unmarshal(b, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode %s: %s", path, err)
+	}
+	// Record the vendor extensions of the operations that are not imported so they can be
+	// reported.
+	if paths, ok := m["paths"].(map[string]interface{}); ok {
+		for p, item := range doc.Paths {
+			raw, _ := paths[p].(map[string]interface{})
+			for verb, op := range item.operations() {
+				rop, _ := raw[strings.ToLower(verb)].(map[string]interface{})
+				for k := range rop {
+					if strings.HasPrefix(k, "x-") && k != "x-pagination" && k != "x-callbacks" {
+						op.Extensions = append(op.Extensions, k)
+					}
+				}
+				sort.Strings(op.Extensions)
+			}
+		}
+	}
+	return &doc, warnings, nil
+}
+
+// unmarshal decodes the JSON in b into v keeping the numbers as json.Number so that integers and
+// floating point values can be told apart.
+func unmarshal(b []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// toStringMap converts the map[interface{}]interface{} values produced by the YAML decoder into
+// map[string]interface{} values.
+func toStringMap(val interface{}) interface{} {
+	switch actual := val.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(actual))
+		for k, v := range actual {
+			m[fmt.Sprint(k)] = toStringMap(v)
+		}
+		return m
+	case map[string]interface{}:
+		for k, v := range actual {
+			actual[k] = toStringMap(v)
+		}
+		return actual
+	case []interface{}:
+		for i, e := range actual {
+			actual[i] = toStringMap(e)
+		}
+		return actual
+	default:
+		return val
+	}
+}
+
+// convertOpenAPI converts the OpenAPI 3 document m into a Swagger 2.0 document. It returns the
+// converted document and the list of the OpenAPI features that have no Swagger 2.0 equivalent.
+func convertOpenAPI(m map[string]interface{}) (map[string]interface{}, []string) {
+	c := &openAPIConverter{doc: m}
+	return c.convert(), c.warnings
+}
+
+// openAPIConverter converts OpenAPI 3 documents into Swagger 2.0 documents.
+type openAPIConverter struct {
+	doc      map[string]interface{}
+	warnings []string
+}
+
+func (c *openAPIConverter) warn(format string, args ...interface{}) {
+	c.warnings = append(c.warnings, fmt.Sprintf(format, args...))
+}
+
+func (c *openAPIConverter) convert() map[string]interface{} {
+	res := map[string]interface{}{"swagger": "2.0"}
+	for _, k := range []string{"info", "tags", "security"} {
+		if v, ok := c.doc[k]; ok {
+			res[k] = v
+		}
+	}
+	c.convertServers(res)
+	comps, _ := c.doc["components"].(map[string]interface{})
+	if schemas, ok := comps["schemas"].(map[string]interface{}); ok {
+		res["definitions"] = schemas
+	}
+	if params, ok := comps["parameters"].(map[string]interface{}); ok {
+		ps := make(map[string]interface{}, len(params))
+		for n, p := range params {
+			ps[n] = c.convertParameter(p)
+		}
+		res["parameters"] = ps
+	}
+	if resps, ok := comps["responses"].(map[string]interface{}); ok {
+		rs := make(map[string]interface{}, len(resps))
+		for n, r := range resps {
+			rs[n] = c.convertResponse("components.responses."+n, r)
+		}
+		res["responses"] = rs
+	}
+	if schemes, ok := comps["securitySchemes"].(map[string]interface{}); ok {
+		defs := make(map[string]interface{}, len(schemes))
+		for n, s := range schemes {
+			if def := c.convertSecurityScheme(n, s); def != nil {
+				defs[n] = def
+			}
+		}
+		res["securityDefinitions"] = defs
+	}
+	if paths, ok := c.doc["paths"].(map[string]interface{}); ok {
+		ps := make(map[string]interface{}, len(paths))
+		for p, item := range paths {
+			ps[p] = c.convertPathItem("paths."+p, item)
+		}
+		res["paths"] = ps
+	}
+	if hooks, ok := c.doc["webhooks"].(map[string]interface{}); ok {
+		hs := make(map[string]interface{}, len(hooks))
+		for n, item := range hooks {
+			hs[n] = c.convertPathItem("webhooks."+n, item)
+		}
+		res["x-webhooks"] = hs
+	}
+	return normalize(res).(map[string]interface{})
+}
+
+// convertServers sets the host, base path and schemes of res from the OpenAPI servers.
+func (c *openAPIConverter) convertServers(res map[string]interface{}) {
+	servers, _ := c.doc["servers"].([]interface{})
+	var schemes []interface{}
+	for i, s := range servers {
+		sm, _ := s.(map[string]interface{})
+		raw, _ := sm["url"].(string)
+		u, err := url.Parse(raw)
+		if err != nil {
+			c.warn("servers[%d]: invalid URL %q", i, raw)
+			continue
+		}
+		if i == 0 {
+			if u.Host != "" {
+				res["host"] = u.Host
+			}
+			if u.Path != "" && u.Path != "/" {
+				res["basePath"] = u.Path
+			}
+		} else if u.Host != fmt.Sprint(res["host"]) || u.Path != fmt.Sprint(res["basePath"]) {
+			c.warn("servers[%d]: only the host and base path of the first server are imported", i)
+		}
+		if u.Scheme != "" {
+			schemes = append(schemes, u.Scheme)
+		}
+	}
+	if len(schemes) > 0 {
+		res["schemes"] = schemes
+	}
+}
+
+func (c *openAPIConverter) convertPathItem(ctx string, item interface{}) interface{} {
+	im, ok := item.(map[string]interface{})
+	if !ok {
+		return item
+	}
+	res := make(map[string]interface{}, len(im))
+	for k, v := range im {
+		switch k {
+		case "get", "put", "post", "delete", "options", "head", "patch":
+			res[k] = c.convertOperation(ctx+"."+k, v)
+		case "parameters":
+			res[k] = c.convertParameters(v)
+		case "summary", "description", "servers":
+		default:
+			if strings.HasPrefix(k, "x-") {
+				res[k] = v
+			} else {
+				c.warn("%s: %s operations are not supported", ctx, k)
+			}
+		}
+	}
+	return res
+}
+
+func (c *openAPIConverter) convertOperation(ctx string, op interface{}) interface{} {
+	om, ok := op.(map[string]interface{})
+	if !ok {
+		return op
+	}
+	res := make(map[string]interface{}, len(om))
+	for k, v := range om {
+		switch k {
+		case "parameters":
+			res[k] = c.convertParameters(v)
+		case "requestBody":
+			c.convertRequestBody(ctx, v, res)
+		case "responses":
+			rm, _ := v.(map[string]interface{})
+			rs := make(map[string]interface{}, len(rm))
+			var produces []string
+			for code, r := range rm {
+				rs[code] = c.convertResponse(ctx+".responses."+code, r)
+				produces = append(produces, contentTypes(r)...)
+			}
+			res[k] = rs
+			if len(produces) > 0 {
+				res["produces"] = uniqueSorted(produces)
+			}
+		case "callbacks":
+			c.warn("%s: callbacks are not supported", ctx)
+		case "servers":
+			c.warn("%s: operation servers are not supported", ctx)
+		default:
+			res[k] = v
+		}
+	}
+	return res
+}
+
+func (c *openAPIConverter) convertParameters(v interface{}) interface{} {
+	ps, ok := v.([]interface{})
+	if !ok {
+		return v
+	}
+	res := make([]interface{}, len(ps))
+	for i, p := range ps {
+		res[i] = c.convertParameter(p)
+	}
+	return res
+}
+
+// convertParameter moves the schema of the parameter into the parameter itself as done by
+// Swagger 2.0 for the parameters that are not located in the body.
+func (c *openAPIConverter) convertParameter(p interface{}) interface{} {
+	pm, ok := p.(map[string]interface{})
+	if !ok {
+		return p
+	}
+	res := make(map[string]interface{}, len(pm))
+	for k, v := range pm {
+		if k == "schema" || k == "style" || k == "explode" {
+			continue
+		}
+		res[k] = v
+	}
+	if s, ok := pm["schema"].(map[string]interface{}); ok {
+		s = c.resolveSchema(s)
+		for k, v := range s {
+			if _, ok := res[k]; !ok {
+				res[k] = v
+			}
+		}
+		if s["type"] == "array" {
+			res["collectionFormat"] = "multi"
+			if pm["explode"] == false {
+				res["collectionFormat"] = "csv"
+			}
+		}
+	}
+	return res
+}
+
+// resolveSchema returns the schema referred to by s if s is a reference to a component schema.
+func (c *openAPIConverter) resolveSchema(s map[string]interface{}) map[string]interface{} {
+	ref, ok := s["$ref"].(string)
+	if !ok || !strings.HasPrefix(ref, "#/components/schemas/") {
+		return s
+	}
+	comps, _ := c.doc["components"].(map[string]interface{})
+	schemas, _ := comps["schemas"].(map[string]interface{})
+	if rs, ok := schemas[strings.TrimPrefix(ref, "#/components/schemas/")].(map[string]interface{}); ok {
+		return rs
+	}
+	return s
+}
+
+// resolveComponent returns the component referred to by v if v is a reference to a component of
+// the given kind.
+func (c *openAPIConverter) resolveComponent(kind string, v interface{}) interface{} {
+	vm, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	ref, ok := vm["$ref"].(string)
+	prefix := "#/components/" + kind + "/"
+	if !ok || !strings.HasPrefix(ref, prefix) {
+		return v
+	}
+	comps, _ := c.doc["components"].(map[string]interface{})
+	items, _ := comps[kind].(map[string]interface{})
+	if res, ok := items[strings.TrimPrefix(ref, prefix)]; ok {
+		return res
+	}
+	return v
+}
+
+// convertRequestBody sets the body or form parameters and the consumed MIME types of the
+// operation res from the OpenAPI request body.
+func (c *openAPIConverter) convertRequestBody(ctx string, body interface{}, res map[string]interface{}) {
+	bm, ok := c.resolveComponent("requestBodies", body).(map[string]interface{})
+	if !ok {
+		return
+	}
+	content, _ := bm["content"].(map[string]interface{})
+	if len(content) == 0 {
+		return
+	}
+	ct, media := pickContent(content)
+	res["consumes"] = uniqueSorted(contentTypes(bm))
+	if len(content) > 1 {
+		c.warn("%s.requestBody: only the %s content is imported", ctx, ct)
+	}
+	s, _ := media["schema"].(map[string]interface{})
+	params, _ := res["parameters"].([]interface{})
+	if ct == "multipart/form-data" || ct == "application/x-www-form-urlencoded" {
+		rs := c.resolveSchema(s)
+		props, _ := rs["properties"].(map[string]interface{})
+		required := make(map[string]bool)
+		if req, ok := rs["required"].([]interface{}); ok {
+			for _, r := range req {
+				required[fmt.Sprint(r)] = true
+			}
+		}
+		for _, n := range sortedNames(props) {
+			p := map[string]interface{}{"name": n, "in": "formData", "required": required[n]}
+			if ps, ok := props[n].(map[string]interface{}); ok {
+				ps = c.resolveSchema(ps)
+				for k, v := range ps {
+					p[k] = v
+				}
+				if ps["type"] == "string" && ps["format"] == "binary" {
+					p["type"] = "file"
+					delete(p, "format")
+				}
+			}
+			params = append(params, p)
+		}
+		res["parameters"] = params
+		return
+	}
+	p := map[string]interface{}{"name": "payload", "in": "body", "schema": s}
+	if req, ok := bm["required"]; ok {
+		p["required"] = req
+	}
+	if desc, ok := bm["description"]; ok {
+		p["description"] = desc
+	}
+	res["parameters"] = append(params, p)
+}
+
+// convertResponse converts the OpenAPI response r into a Swagger 2.0 response.
+func (c *openAPIConverter) convertResponse(ctx string, r interface{}) interface{} {
+	rm, ok := r.(map[string]interface{})
+	if !ok {
+		return r
+	}
+	if _, ok := rm["$ref"]; ok {
+		return rm
+	}
+	res := make(map[string]interface{}, len(rm))
+	if desc, ok := rm["description"]; ok {
+		res["description"] = desc
+	}
+	if content, ok := rm["content"].(map[string]interface{}); ok && len(content) > 0 {
+		ct, media := pickContent(content)
+		if s, ok := media["schema"]; ok {
+			res["schema"] = s
+		}
+		if len(content) > 1 {
+			c.warn("%s: only the %s content is imported", ctx, ct)
+		}
+	}
+	if headers, ok := rm["headers"].(map[string]interface{}); ok {
+		hs := make(map[string]interface{}, len(headers))
+		for n, h := range headers {
+			hp, _ := c.convertParameter(c.resolveComponent("headers", h)).(map[string]interface{})
+			delete(hp, "required")
+			hs[n] = hp
+		}
+		res["headers"] = hs
+	}
+	if _, ok := rm["links"]; ok {
+		c.warn("%s: links are not supported", ctx)
+	}
+	return res
+}
+
+// convertSecurityScheme converts the OpenAPI security scheme s into a Swagger 2.0 security
+// definition.
+func (c *openAPIConverter) convertSecurityScheme(name string, s interface{}) interface{} {
+	sm, ok := s.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	res := make(map[string]interface{})
+	if desc, ok := sm["description"]; ok {
+		res["description"] = desc
+	}
+	switch sm["type"] {
+	case "http":
+		switch strings.ToLower(fmt.Sprint(sm["scheme"])) {
+		case "basic":
+			res["type"] = "basic"
+		case "bearer":
+			res["type"] = "apiKey"
+			res["in"] = "header"
+			res["name"] = "Authorization"
+		default:
+			c.warn("components.securitySchemes.%s: HTTP %v authentication is not supported", name, sm["scheme"])
+			return nil
+		}
+	case "apiKey":
+		res["type"] = "apiKey"
+		res["in"] = sm["in"]
+		res["name"] = sm["name"]
+		if sm["in"] == "cookie" {
+			c.warn("components.securitySchemes.%s: API keys located in cookies are not supported", name)
+			return nil
+		}
+	case "oauth2":
+		res["type"] = "oauth2"
+		flows, _ := sm["flows"].(map[string]interface{})
+		for _, f := range []struct{ oa3, swagger string }{
+			{"authorizationCode", "accessCode"},
+			{"implicit", "implicit"},
+			{"password", "password"},
+			{"clientCredentials", "application"},
+		} {
+			flow, ok := flows[f.oa3].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if _, ok := res["flow"]; ok {
+				c.warn("components.securitySchemes.%s: only the first OAuth2 flow is imported", name)
+				break
+			}
+			res["flow"] = f.swagger
+			for _, k := range []string{"authorizationUrl", "tokenUrl", "scopes"} {
+				if v, ok := flow[k]; ok {
+					res[k] = v
+				}
+			}
+		}
+	default:
+		c.warn("components.securitySchemes.%s: %v security schemes are not supported", name, sm["type"])
+		return nil
+	}
+	return res
+}
+
+// pickContent returns the JSON content of the OpenAPI content map if any, the first content in
+// alphabetical order otherwise.
+func pickContent(content map[string]interface{}) (string, map[string]interface{}) {
+	keys := sortedNames(content)
+	ct := keys[0]
+	for _, k := range keys {
+		if k == "application/json" || strings.HasSuffix(k, "+json") {
+			ct = k
+			break
+		}
+	}
+	media, _ := content[ct].(map[string]interface{})
+	return ct, media
+}
+
+// contentTypes returns the MIME types of the content of the OpenAPI request body or response v.
+func contentTypes(v interface{}) []string {
+	vm, _ := v.(map[string]interface{})
+	content, _ := vm["content"].(map[string]interface{})
+	return sortedNames(content)
+}
+
+// normalize replaces the references to OpenAPI components with references to the corresponding
+// Swagger 2.0 definitions, parameters and responses. It also replaces the lists of types of
+// nullable schemas with the non null type.
+func normalize(v interface{}) interface{} {
+	switch actual := v.(type) {
+	case map[string]interface{}:
+		for k, e := range actual {
+			switch val := e.(type) {
+			case string:
+				if k == "$ref" {
+					val = strings.Replace(val, "#/components/schemas/", "#/definitions/", 1)
+					val = strings.Replace(val, "#/components/parameters/", "#/parameters/", 1)
+					val = strings.Replace(val, "#/components/responses/", "#/responses/", 1)
+					actual[k] = val
+				}
+			case []interface{}:
+				if k == "type" {
+					delete(actual, k)
+					for _, t := range val {
+						if t == "null" {
+							actual["nullable"] = true
+						} else {
+							actual[k] = t
+						}
+					}
+					continue
+				}
+				actual[k] = normalize(e)
+			default:
+				actual[k] = normalize(e)
+			}
+		}
+	case []interface{}:
+		for i, e := range actual {
+			actual[i] = normalize(e)
+		}
+	}
+	return v
+}
+
+// uniqueSorted returns the sorted list of the distinct elements of vals.
+func uniqueSorted(vals []string) []string {
+	seen := make(map[string]bool)
+	var res []string
+	for _, v := range vals {
+		if !seen[v] {
+			seen[v] = true
+			res = append(res, v)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,999 @@
+package genimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shogo82148/goa-v1/design"
+	"github.com/shogo82148/goa-v1/design/apidsl"
+	"github.com/shogo82148/goa-v1/goagen/codegen"
+)
+
+// webhookSignatureHeader is the name of the header that carries the signature of the webhooks
+// sent by goa.
+const webhookSignatureHeader = "X-Webhook-Signature"
+
+// dslWriter accumulates DSL source code. The code does not need to be indented, it is formatted
+// once complete.
+type dslWriter struct {
+	bytes.Buffer
+}
+
+// line writes a line of code.
+func (w *dslWriter) line(format string, args ...interface{}) {
+	fmt.Fprintf(w, format, args...)
+	w.WriteByte('\n')
+}
+
+// write renders the design package source code: the API, the security schemes, the resources,
+// the media types and the user types.
+func (i *importer) write() []byte {
+	var api, schemes, resources, mediaTypes, types dslWriter
+	i.writeAPI(&api)
+	i.writeSecuritySchemes(&schemes)
+	for _, r := range i.resources {
+		i.writeResource(&resources, r)
+	}
+	for _, n := range sortedNames(i.defs) {
+		if d := i.defs[n]; d.Kind == mediaTypeKind {
+			i.writeMediaType(&mediaTypes, d)
+		}
+	}
+	for _, n := range sortedNames(i.defs) {
+		if d := i.defs[n]; d.Kind == userTypeKind && !i.isSynthesized(d) {
+			i.writeType(&types, d)
+		}
+	}
+	// Writing the synthesized types may synthesize more types.
+	for j := 0; j < len(i.synthesized); j++ {
+		i.writeType(&types, i.synthesized[j])
+	}
+	var res bytes.Buffer
+	for _, w := range []*dslWriter{&api, &schemes, &resources, &mediaTypes, &types} {
+		if w.Len() > 0 {
+			res.WriteByte('\n')
+			res.Write(w.Bytes())
+		}
+	}
+	return res.Bytes()
+}
+
+func (i *importer) isSynthesized(d *definition) bool {
+	for _, s := range i.synthesized {
+		if s == d {
+			return true
+		}
+	}
+	return false
+}
+
+// writeAPI writes the API definition.
+func (i *importer) writeAPI(w *dslWriter) {
+	inf := i.doc.Info
+	if inf == nil {
+		inf = &info{}
+	}
+	name := apiName(inf.Title)
+	w.line("var _ = API(%s, func() {", quote(name))
+	if inf.Title != "" && inf.Title != name {
+		w.line("Title(%s)", quote(inf.Title))
+	}
+	if inf.Description != "" {
+		w.line("Description(%s)", quote(inf.Description))
+	}
+	if inf.Version != "" {
+		w.line("Version(%s)", quote(inf.Version))
+	}
+	if inf.TermsOfService != "" {
+		w.line("TermsOfService(%s)", quote(inf.TermsOfService))
+	}
+	if c := inf.Contact; c != nil {
+		w.line("Contact(func() {")
+		writeStrings(w, "Name", c.Name, "Email", c.Email, "URL", c.URL)
+		w.line("})")
+	}
+	if l := inf.License; l != nil {
+		w.line("License(func() {")
+		writeStrings(w, "Name", l.Name, "URL", l.URL)
+		w.line("})")
+	}
+	if i.doc.Host != "" {
+		w.line("Host(%s)", quote(i.doc.Host))
+	}
+	if len(i.doc.Schemes) > 0 {
+		w.line("Scheme(%s)", quoteAll(i.doc.Schemes))
+	}
+	if i.doc.BasePath != "" && i.doc.BasePath != "/" {
+		w.line("BasePath(%s)", quote(i.doc.BasePath))
+	}
+	i.writeEncodings(w, "Consumes", i.doc.Consumes)
+	i.writeEncodings(w, "Produces", i.doc.Produces)
+	if len(i.doc.Security) > 0 {
+		i.writeSecurity(w, "security", i.doc.Security)
+	}
+	i.writeWebhooks(w, "x-webhooks", i.doc.Webhooks, "")
+	w.line("})")
+}
+
+// writeEncodings writes the Consumes or Produces DSL for the given MIME types grouping the MIME
+// types that share the same encoder. Only the encoders known to goa are imported.
+func (i *importer) writeEncodings(w *dslWriter, dsl string, mimeTypes []string) {
+	var keys []string
+	groups := make(map[string][]string)
+	for _, m := range mimeTypes {
+		pkg, ok := design.KnownEncoders[m]
+		if !ok {
+			i.warn(strings.ToLower(dsl), "no known encoder for %s", m)
+			continue
+		}
+		key := pkg + "#" + design.KnownEncoderFunctions[m][0]
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], m)
+	}
+	for _, k := range keys {
+		w.line("%s(%s)", dsl, quoteAll(groups[k]))
+	}
+}
+
+// writeSecuritySchemes writes the security scheme definitions.
+func (i *importer) writeSecuritySchemes(w *dslWriter) {
+	for _, n := range sortedNames(i.doc.SecurityDefinitions) {
+		s := i.doc.SecurityDefinitions[n]
+		ctx := "securityDefinitions." + n
+		var body dslWriter
+		var fn string
+		switch s.Type {
+		case "basic":
+			fn = "BasicAuthSecurity"
+		case "apiKey":
+			fn = "APIKeySecurity"
+			switch s.In {
+			case "header":
+				body.line("Header(%s)", quote(s.Name))
+			case "query":
+				body.line("Query(%s)", quote(s.Name))
+			default:
+				i.warn(ctx, "API keys located in %q are not supported", s.In)
+			}
+		case "oauth2":
+			fn = "OAuth2Security"
+			switch s.Flow {
+			case "accessCode":
+				body.line("AccessCodeFlow(%s, %s)", quote(s.AuthorizationURL), quote(s.TokenURL))
+			case "implicit":
+				body.line("ImplicitFlow(%s)", quote(s.AuthorizationURL))
+			case "password":
+				body.line("PasswordFlow(%s)", quote(s.TokenURL))
+			case "application":
+				body.line("ApplicationFlow(%s)", quote(s.TokenURL))
+			default:
+				i.warn(ctx, "unknown OAuth2 flow %q", s.Flow)
+			}
+			for _, sc := range sortedNames(s.Scopes) {
+				body.line("Scope(%s, %s)", quote(sc), quote(s.Scopes[sc]))
+			}
+		default:
+			i.warn(ctx, "security scheme type %q is not supported", s.Type)
+			delete(i.schemes, n)
+			continue
+		}
+		if s.Description != "" {
+			body.line("Description(%s)", quote(s.Description))
+		}
+		if body.Len() == 0 {
+			w.line("var %s = %s(%s)", i.schemes[n], fn, quote(n))
+			w.line("")
+			continue
+		}
+		w.line("var %s = %s(%s, func() {", i.schemes[n], fn, quote(n))
+		w.Write(body.Bytes())
+		w.line("})")
+		w.line("")
+	}
+}
+
+// writeSecurity writes the Security DSL for the given security requirements. goa supports a
+// single security scheme per action so only the first scheme is imported.
+func (i *importer) writeSecurity(w *dslWriter, ctx string, reqs []map[string][]string) {
+	if len(reqs) > 1 {
+		i.warn(ctx, "only the first security requirement is imported")
+	}
+	names := sortedNames(reqs[0])
+	if len(names) == 0 {
+		return
+	}
+	if len(names) > 1 {
+		i.warn(ctx, "only the %s security scheme is imported", names[0])
+	}
+	v, ok := i.schemes[names[0]]
+	if !ok {
+		i.warn(ctx, "unknown security scheme %s", names[0])
+		return
+	}
+	scopes := reqs[0][names[0]]
+	if len(scopes) == 0 {
+		w.line("Security(%s)", v)
+		return
+	}
+	w.line("Security(%s, func() {", v)
+	for _, s := range scopes {
+		w.line("Scope(%s)", quote(s))
+	}
+	w.line("})")
+}
+
+// writeResource writes the resource definition and its actions.
+func (i *importer) writeResource(w *dslWriter, r *resource) {
+	w.line("var _ = Resource(%s, func() {", quote(r.Name))
+	if r.Description != "" {
+		w.line("Description(%s)", quote(r.Description))
+	}
+	if r.BasePath != "" {
+		w.line("BasePath(%s)", quote(r.BasePath))
+	}
+	if r.Security != nil {
+		if len(*r.Security) == 0 {
+			w.line("NoSecurity()")
+		} else {
+			i.writeSecurity(w, r.SecurityContext, *r.Security)
+		}
+	}
+	header := r.Description != "" || r.BasePath != "" || r.Security != nil
+	for j, a := range r.Actions {
+		if j > 0 || header {
+			w.line("")
+		}
+		i.writeAction(w, r, a)
+	}
+	w.line("})")
+	w.line("")
+}
+
+// writeAction writes the action definition.
+func (i *importer) writeAction(w *dslWriter, r *resource, a *action) {
+	op := a.Operation
+	ctx := a.Context
+	w.line("Action(%s, func() {", quote(a.Name))
+	if op.Description != "" {
+		w.line("Description(%s)", quote(op.Description))
+	}
+	if op.Summary != "" && op.Summary != a.Name+" "+a.Resource {
+		w.line("Metadata(\"swagger:summary\", %s)", quote(op.Summary))
+	}
+	if op.Deprecated {
+		w.line("Deprecated(\"\", \"\", \"\")")
+	}
+	routes := make([]string, len(a.Routes))
+	for j, r := range a.Routes {
+		routes[j] = fmt.Sprintf("%s(%s)", r.Verb, quote(r.Path))
+	}
+	w.line("Routing(%s)", strings.Join(routes, ", "))
+	if len(op.Schemes) > 0 && strings.Join(op.Schemes, ",") != strings.Join(i.doc.Schemes, ",") {
+		w.line("Scheme(%s)", quoteAll(op.Schemes))
+	}
+	hint := codegen.Goify(a.Name, true) + codegen.Goify(a.Resource, true)
+	if len(a.Params) > 0 {
+		w.line("Params(func() {")
+		var required []string
+		for _, p := range a.Params {
+			i.writeParameter(w, ctx, "Param", p, hint)
+			if p.Required && p.In != "path" {
+				required = append(required, p.Name)
+			}
+		}
+		if len(required) > 0 {
+			w.line("Required(%s)", quoteAll(required))
+		}
+		w.line("})")
+	}
+	i.writeHeaders(w, ctx, a.Headers, hint)
+	switch {
+	case a.Body != nil:
+		if len(a.Form) > 0 {
+			i.warn(ctx, "form parameters of operations with a body are not imported")
+		}
+		fn := "Payload"
+		if !a.Body.Required {
+			fn = "OptionalPayload"
+		}
+		s := a.Body.Schema
+		if s != nil && s.Description == "" && isObject(s) {
+			s.Description = a.Body.Description
+		}
+		w.line("%s(%s)", fn, i.typeExpr(ctx+".parameters.body", s, hint+"Payload"))
+	case len(a.Form) > 0:
+		s := &schema{Type: "object", Properties: make(map[string]*schema)}
+		for _, p := range a.Form {
+			ps := p.schema
+			ps.Description = p.Description
+			s.Properties[p.Name] = &ps
+			if p.Required {
+				s.Required = append(s.Required, p.Name)
+			}
+		}
+		for _, c := range op.Consumes {
+			if c == "application/x-www-form-urlencoded" {
+				i.warn(ctx, "URL encoded forms are imported as multipart forms")
+			}
+		}
+		w.line("Payload(%s)", i.synthesize(hint+"Payload", s))
+		w.line("MultipartForm()")
+	}
+	if op.Security != nil || r.Security != nil {
+		inherited, sec := i.doc.Security, i.doc.Security
+		if r.Security != nil {
+			inherited = *r.Security
+		}
+		if op.Security != nil {
+			sec = *op.Security
+		}
+		if len(sec) == 0 {
+			if op.Security != nil || len(inherited) > 0 {
+				w.line("NoSecurity()")
+			}
+		} else if !sameSecurity(sec, inherited) {
+			i.writeSecurity(w, ctx+".security", sec)
+		}
+	}
+	if a.LongRunning {
+		w.line("LongRunning(%d)", a.RetryAfter)
+	}
+	if p := a.Pagination; p != nil {
+		style := "CursorPagination"
+		if design.PaginationStyle(p.Style) == design.OffsetPagination {
+			style = "OffsetPagination"
+		}
+		w.line("Paginated(%s, %d, %d)", style, p.DefaultLimit, p.MaxLimit)
+	}
+	i.writeWebhooks(w, ctx+".x-callbacks", op.Callbacks, hint)
+	for _, code := range sortedNames(op.Responses) {
+		if a.LongRunning && code == strconv.Itoa(http.StatusAccepted) {
+			continue
+		}
+		rctx := fmt.Sprintf("%s.responses.%s", ctx, code)
+		resp := op.Responses[code]
+		if a.Pagination != nil && strings.HasPrefix(code, "2") {
+			// Paginated defines the Link header of the successful responses.
+			if resp = i.resolveResponse(rctx, resp); resp == nil {
+				continue
+			}
+			if _, ok := resp.Headers["Link"]; ok {
+				r := *resp
+				r.Headers = make(map[string]*schema, len(resp.Headers))
+				for n, h := range resp.Headers {
+					if n != "Link" {
+						r.Headers[n] = h
+					}
+				}
+				resp = &r
+			}
+		}
+		i.writeResponse(w, rctx, code, resp, hint)
+	}
+	w.line("})")
+}
+
+// writeHeaders writes the Headers definition of the given header parameters if any.
+func (i *importer) writeHeaders(w *dslWriter, ctx string, headers []*parameter, hint string) {
+	if len(headers) == 0 {
+		return
+	}
+	w.line("Headers(func() {")
+	var required []string
+	for _, p := range headers {
+		i.writeParameter(w, ctx, "Header", p, hint)
+		if p.Required {
+			required = append(required, p.Name)
+		}
+	}
+	if len(required) > 0 {
+		w.line("Required(%s)", quoteAll(required))
+	}
+	w.line("})")
+}
+
+// writeWebhooks writes the Webhook definitions of the webhooks documented as POST operations
+// whose body is the payload as done by the genswagger generator. hint prefixes the names of the
+// types synthesized for the payloads.
+func (i *importer) writeWebhooks(w *dslWriter, ctx string, hooks map[string]*pathItem, hint string) {
+	for _, n := range sortedNames(hooks) {
+		hctx := ctx + "." + n
+		var op *operation
+		if item := hooks[n]; item != nil {
+			op = item.Post
+		}
+		if op == nil {
+			i.warn(hctx, "webhooks must be POST operations, webhook is not imported")
+			continue
+		}
+		var (
+			body    *parameter
+			headers []*parameter
+		)
+		for _, p := range op.Parameters {
+			if p = i.resolveParameter(hctx, p); p == nil {
+				continue
+			}
+			switch p.In {
+			case "body":
+				body = p
+			case "header":
+				// The signature header is set by goa.
+				if p.Name != webhookSignatureHeader {
+					headers = addParameter(headers, p)
+				}
+			default:
+				i.warn(hctx, "parameter %s located in %q is not supported", p.Name, p.In)
+			}
+		}
+		if body == nil {
+			i.warn(hctx, "missing payload, webhook is not imported")
+			continue
+		}
+		whint := hint + codegen.Goify(n, true) + "Webhook"
+		w.line("Webhook(%s, func() {", quote(n))
+		if op.Description != "" {
+			w.line("Description(%s)", quote(op.Description))
+		}
+		s := body.Schema
+		if s != nil && s.Description == "" && isObject(s) {
+			s.Description = body.Description
+		}
+		w.line("Payload(%s)", i.typeExpr(hctx+".parameters.body", s, whint+"Payload"))
+		i.writeHeaders(w, hctx, headers, whint)
+		w.line("})")
+	}
+}
+
+// writeParameter writes the Param or Header definition of p.
+func (i *importer) writeParameter(w *dslWriter, ctx, dsl string, p *parameter, hint string) {
+	s := p.schema
+	s.Description = p.Description
+	s.Deprecated = s.Deprecated || p.XDeprecated
+	if s.Type == "array" && s.CollectionFormat != "" && s.CollectionFormat != "multi" {
+		i.warn(ctx+".parameters."+p.Name, "collection format %q is not supported", s.CollectionFormat)
+	}
+	i.writeAttribute(w, ctx+".parameters."+p.Name, dsl, p.Name, &s, hint+codegen.Goify(p.Name, true), true)
+}
+
+// writeResponse writes the Response definition of the response with the given status code.
+func (i *importer) writeResponse(w *dslWriter, ctx, code string, r *response, hint string) {
+	if r = i.resolveResponse(ctx, r); r == nil {
+		return
+	}
+	status, err := strconv.Atoi(code)
+	if err != nil {
+		i.warn(ctx, "%s responses are not supported", code)
+		return
+	}
+	name, standard := responseName(status)
+	var media, view string
+	if r.Schema != nil {
+		media, view = i.responseMedia(ctx, r.Schema, hint+name)
+	}
+	if !standard {
+		name = quote(name)
+	}
+	var body dslWriter
+	if !standard {
+		body.line("Status(%d)", status)
+	}
+	if r.Description != "" && r.Description != http.StatusText(status) {
+		body.line("Description(%s)", quote(r.Description))
+	}
+	if len(r.Headers) > 0 {
+		body.line("Headers(func() {")
+		for _, n := range sortedNames(r.Headers) {
+			i.writeAttribute(&body, ctx+".headers."+n, "Header", n, r.Headers[n], hint+codegen.Goify(n, true), true)
+		}
+		body.line("})")
+	}
+	switch {
+	case body.Len() == 0 && (view == "" || view == design.DefaultView) && media != "":
+		w.line("Response(%s, %s)", name, media)
+	case body.Len() == 0 && media == "":
+		w.line("Response(%s)", name)
+	default:
+		w.line("Response(%s, func() {", name)
+		if media != "" {
+			if view != "" && view != design.DefaultView {
+				w.line("Media(%s, %s)", media, quote(view))
+			} else {
+				w.line("Media(%s)", media)
+			}
+		}
+		w.Write(body.Bytes())
+		w.line("})")
+	}
+}
+
+// responseMedia returns the media type expression and view of the response schema s.
+func (i *importer) responseMedia(ctx string, s *schema, hint string) (string, string) {
+	if s.Ref != "" {
+		d := i.definitionFor(s.Ref)
+		if d == nil {
+			i.warn(ctx, "unknown definition %s", s.Ref)
+			return "", ""
+		}
+		switch d.Kind {
+		case mediaTypeKind, builtinKind:
+			return d.VarName, design.DefaultView
+		case projectionKind:
+			return d.Base.VarName, d.View
+		case collectionKind:
+			return "CollectionOf(" + d.Base.VarName + ")", d.View
+		}
+		i.warn(ctx, "response body %s is not a media type", d.Name)
+		return "", ""
+	}
+	if s.Type == "array" && s.Items != nil {
+		if d := i.definitionFor(s.Items.Ref); d != nil {
+			switch d.Kind {
+			case mediaTypeKind:
+				return "CollectionOf(" + d.VarName + ")", design.DefaultView
+			case projectionKind:
+				return "CollectionOf(" + d.Base.VarName + ")", d.View
+			}
+		}
+	}
+	if isObject(s) {
+		d := &definition{Name: hint, Schema: s}
+		i.makeMediaType(d)
+		d.VarName = i.varName(hint, "Media")
+		i.defs[d.Name] = d
+		return d.VarName, design.DefaultView
+	}
+	i.warn(ctx, "response body of type %q is not a media type", s.Type)
+	return "", ""
+}
+
+// writeMediaType writes the media type definition d, its attributes and views.
+func (i *importer) writeMediaType(w *dslWriter, d *definition) {
+	ctx := "definitions." + d.Name
+	w.line("var %s = MediaType(%s, func() {", d.VarName, quote(d.Identifier))
+	if mediaTypeName(d.Identifier) != d.TypeName {
+		w.line("TypeName(%s)", quote(d.TypeName))
+	}
+	if desc := strings.TrimSuffix(d.Schema.Description, " ("+d.View+" view)"); desc != "" {
+		w.line("Description(%s)", quote(desc))
+	}
+	if !isObject(d.Schema) {
+		i.warn(ctx, "media type is not an object")
+	}
+	// The attributes of the media type are the union of the attributes of its views.
+	views := sortedNames(d.Views)
+	sort.SliceStable(views, func(a, b int) bool { return views[a] == design.DefaultView && views[b] != design.DefaultView })
+	attrs := &schema{Type: "object", Properties: make(map[string]*schema)}
+	required := make(map[string]bool)
+	for _, v := range views {
+		vs := d.Views[v].Schema
+		for n, ps := range vs.Properties {
+			if _, ok := attrs.Properties[n]; !ok {
+				attrs.Properties[n] = ps
+			}
+		}
+		for _, r := range vs.Required {
+			if !required[r] {
+				required[r] = true
+				attrs.Required = append(attrs.Required, r)
+			}
+		}
+	}
+	w.line("Attributes(func() {")
+	i.writeAttributes(w, ctx, attrs, d.TypeName, false)
+	w.line("})")
+	if _, ok := d.Views[design.DefaultView]; !ok {
+		views = append([]string{design.DefaultView}, views...)
+	}
+	for _, v := range views {
+		vs := attrs
+		if vd, ok := d.Views[v]; ok {
+			vs = vd.Schema
+		}
+		w.line("View(%s, func() {", quote(v))
+		for _, n := range sortedNames(vs.Properties) {
+			if pv := i.refView(vs.Properties[n]); pv != "" && pv != design.DefaultView {
+				w.line("Attribute(%s, func() {", quote(n))
+				w.line("View(%s)", quote(pv))
+				w.line("})")
+				continue
+			}
+			w.line("Attribute(%s)", quote(n))
+		}
+		w.line("})")
+	}
+	w.line("})")
+	w.line("")
+}
+
+// writeType writes the user type definition d.
+func (i *importer) writeType(w *dslWriter, d *definition) {
+	ctx := "definitions." + d.Name
+	w.line("var %s = Type(%s, func() {", d.VarName, quote(d.Name))
+	if d.Schema.Description != "" {
+		w.line("Description(%s)", quote(d.Schema.Description))
+	}
+	i.writeAttributes(w, ctx, d.Schema, d.Name, true)
+	if d.Schema.Example != nil && !derivedExample(d.Schema) {
+		w.line("Example(%s)", literal(d.Schema.Example))
+	}
+	w.line("})")
+	w.line("")
+}
+
+// writeAttributes writes the attributes of the object s followed by the list of required
+// attributes.
+func (i *importer) writeAttributes(w *dslWriter, ctx string, s *schema, hint string, views bool) {
+	for _, n := range sortedNames(s.Properties) {
+		i.writeAttribute(w, ctx+".properties."+n, "Attribute", n, s.Properties[n], hint+codegen.Goify(n, true), views)
+	}
+	if len(s.Required) > 0 {
+		w.line("Required(%s)", quoteAll(s.Required))
+	}
+	if len(s.AllOf)+len(s.OneOf)+len(s.AnyOf) > 0 {
+		i.warn(ctx, "schema compositions are not supported")
+	}
+}
+
+// writeAttribute writes the Attribute, Param or Header definition of the attribute with the given
+// name and schema. views indicates whether the view used to render media types may be set.
+func (i *importer) writeAttribute(w *dslWriter, ctx, dsl, name string, s *schema, hint string, views bool) {
+	if dsl == "Attribute" && isObject(s) && len(s.Properties) > 0 {
+		// Inline object
+		w.line("Attribute(%s, func() {", quote(name))
+		if s.Description != "" {
+			w.line("Description(%s)", quote(s.Description))
+		}
+		i.writeAttributes(w, ctx, s, hint, views)
+		w.line("})")
+		return
+	}
+	args := []string{quote(name), i.typeExpr(ctx, s, hint)}
+	if s.Description != "" {
+		args = append(args, quote(s.Description))
+	}
+	var body dslWriter
+	if v := i.refView(s); views && v != "" && v != design.DefaultView {
+		body.line("View(%s)", quote(v))
+	}
+	i.writeValidations(&body, ctx, s)
+	if body.Len() == 0 {
+		w.line("%s(%s)", dsl, strings.Join(args, ", "))
+		return
+	}
+	w.line("%s(%s, func() {", dsl, strings.Join(args, ", "))
+	w.Write(body.Bytes())
+	w.line("})")
+}
+
+// writeValidations writes the validations, default value and example of the attribute described
+// by s.
+func (i *importer) writeValidations(w *dslWriter, ctx string, s *schema) {
+	if s == nil || s.Ref != "" {
+		return
+	}
+	if s.Deprecated {
+		w.line("Deprecated(\"\", \"\", \"\")")
+	}
+	if len(s.Enum) > 0 {
+		vals := make([]string, len(s.Enum))
+		for j, v := range s.Enum {
+			vals[j] = literal(v)
+		}
+		w.line("Enum(%s)", strings.Join(vals, ", "))
+	}
+	if s.Format != "" && !typeFormat(s) {
+		if isValidationFormat(s.Format) {
+			w.line("Format(%s)", quote(s.Format))
+		} else {
+			i.warn(ctx, "format %q is not supported", s.Format)
+		}
+	}
+	if s.Pattern != "" {
+		w.line("Pattern(%s)", quote(s.Pattern))
+	}
+	if s.Minimum != nil {
+		w.line("Minimum(%s)", s.Minimum.String())
+	}
+	if s.Maximum != nil {
+		w.line("Maximum(%s)", s.Maximum.String())
+	}
+	if isTrue(s.ExclusiveMinimum) || isTrue(s.ExclusiveMaximum) {
+		i.warn(ctx, "exclusive bounds are not supported")
+	}
+	minLength, maxLength := s.MinLength, s.MaxLength
+	if s.Type == "array" {
+		minLength, maxLength = s.MinItems, s.MaxItems
+	}
+	if minLength != nil {
+		w.line("MinLength(%d)", *minLength)
+	}
+	if maxLength != nil {
+		w.line("MaxLength(%d)", *maxLength)
+	}
+	if s.MultipleOf != nil {
+		i.warn(ctx, "multipleOf is not supported")
+	}
+	if s.UniqueItems {
+		i.warn(ctx, "uniqueItems is not supported")
+	}
+	if s.Nullable {
+		i.warn(ctx, "nullable is not supported")
+	}
+	if s.Default != nil {
+		w.line("Default(%s)", literal(s.Default))
+	}
+	if s.Example != nil && !derivedExample(s) {
+		w.line("Example(%s)", literal(s.Example))
+	}
+	if s.ReadOnly {
+		w.line("ReadOnly()")
+	}
+}
+
+// typeExpr returns the Go expression of the data type described by s. hint is used to name the
+// user types synthesized for inline objects.
+func (i *importer) typeExpr(ctx string, s *schema, hint string) string {
+	if s == nil {
+		return "Any"
+	}
+	if s.Ref != "" {
+		return i.refExpr(ctx, s.Ref)
+	}
+	if len(s.AllOf) == 1 && len(s.OneOf)+len(s.AnyOf) == 0 {
+		return i.typeExpr(ctx, s.AllOf[0], hint)
+	}
+	if len(s.AllOf)+len(s.OneOf)+len(s.AnyOf) > 0 {
+		i.warn(ctx, "schema compositions are not supported")
+		return "Any"
+	}
+	switch s.Type {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			return "DateTime"
+		case "uuid":
+			return "UUID"
+		case "date":
+			return "Date"
+		case "time":
+			return "TimeOfDay"
+		case "duration":
+			return "Duration"
+		case "decimal":
+			return "Decimal"
+		case "binary":
+			return "File"
+		}
+		return "String"
+	case "integer":
+		return "Integer"
+	case "number":
+		if s.Format == "decimal" {
+			return "Decimal"
+		}
+		return "Number"
+	case "boolean":
+		return "Boolean"
+	case "file":
+		return "File"
+	case "array":
+		elem := i.typeExpr(ctx+".items", s.Items, hint+"Item")
+		var body dslWriter
+		if s.Items != nil && s.Items.Description != "" && s.Items.Ref == "" && !isObject(s.Items) {
+			body.line("Description(%s)", quote(s.Items.Description))
+		}
+		i.writeValidations(&body, ctx+".items", s.Items)
+		if body.Len() == 0 {
+			return "ArrayOf(" + elem + ")"
+		}
+		return "ArrayOf(" + elem + ", func() {\n" + body.String() + "})"
+	case "object", "":
+		if vs, ok := s.additionalProperties(); ok {
+			return "HashOf(String, " + i.typeExpr(ctx+".additionalProperties", vs, hint+"Value") + ")"
+		}
+		if len(s.Properties) > 0 {
+			return i.synthesize(hint, s)
+		}
+		if s.Type == "object" {
+			return "HashOf(String, Any)"
+		}
+		return "Any"
+	}
+	i.warn(ctx, "type %q is not supported", s.Type)
+	return "Any"
+}
+
+// refExpr returns the Go expression of the data type referred to by ref.
+func (i *importer) refExpr(ctx, ref string) string {
+	d := i.definitionFor(ref)
+	if d == nil {
+		i.warn(ctx, "unknown definition %s", ref)
+		return "Any"
+	}
+	switch d.Kind {
+	case inlineKind:
+		return i.typeExpr("definitions."+d.Name, d.Schema, d.Name)
+	case projectionKind:
+		return d.Base.VarName
+	case collectionKind:
+		return "CollectionOf(" + d.Base.VarName + ")"
+	default:
+		return d.VarName
+	}
+}
+
+// refView returns the view of the media type referred to by s if any.
+func (i *importer) refView(s *schema) string {
+	if s == nil {
+		return ""
+	}
+	if s.Type == "array" && s.Items != nil {
+		s = s.Items
+	}
+	if d := i.definitionFor(s.Ref); d != nil && (d.Kind == projectionKind || d.Kind == collectionKind) {
+		return d.View
+	}
+	return ""
+}
+
+// derivedExample returns true if the example of s can be derived from the examples of its
+// properties or items, in which case goa generates it.
+func derivedExample(s *schema) bool {
+	switch {
+	case s.Type == "array" && s.Items != nil:
+		return s.Items.Ref != "" || s.Items.Example != nil || isObject(s.Items)
+	case len(s.Properties) > 0:
+		for _, ps := range s.Properties {
+			if ps.Ref == "" && ps.Example == nil && !isObject(ps) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// typeFormat returns true if the format of s is implied by the data type of the attribute.
+func typeFormat(s *schema) bool {
+	switch s.Type {
+	case "string":
+		switch s.Format {
+		case "date-time", "uuid", "date", "time", "duration", "decimal", "binary", "byte", "password":
+			return true
+		}
+	case "integer":
+		return s.Format == "int32" || s.Format == "int64"
+	case "number":
+		return s.Format == "float" || s.Format == "double" || s.Format == "decimal"
+	}
+	return false
+}
+
+// isValidationFormat returns true if f is a format supported by the Format DSL.
+func isValidationFormat(f string) bool {
+	for _, s := range apidsl.SupportedValidationFormats {
+		if s == f {
+			return true
+		}
+	}
+	return false
+}
+
+// sameSecurity returns true if a and b list the same security requirements.
+func sameSecurity(a, b []map[string][]string) bool {
+	ja, _ := json.Marshal(a)
+	jb, _ := json.Marshal(b)
+	return bytes.Equal(ja, jb)
+}
+
+// mediaTypeName returns the type name the MediaType DSL computes for the given identifier.
+func mediaTypeName(identifier string) string {
+	base, _, err := mime.ParseMediaType(identifier)
+	if err != nil {
+		return ""
+	}
+	if idx := strings.LastIndex(base, "/"); idx > -1 {
+		base = base[idx+1:]
+	}
+	if idx := strings.Index(base, "+"); idx > 0 {
+		base = base[:idx]
+	}
+	elems := strings.Split(strings.TrimPrefix(base, "vnd."), ".")
+	for j, e := range elems {
+		if e != "" {
+			elems[j] = strings.ToUpper(e[:1]) + e[1:]
+		}
+	}
+	return strings.Join(elems, "")
+}
+
+// apiName returns the name of the API given its title.
+func apiName(title string) string {
+	name := strings.Trim(strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(title)), "-")
+	if name == "" {
+		return "api"
+	}
+	return name
+}
+
+// writeStrings writes the DSL calls with a single string argument given as pairs of function
+// name and argument, skipping the empty arguments.
+func writeStrings(w *dslWriter, pairs ...string) {
+	for j := 0; j < len(pairs); j += 2 {
+		if pairs[j+1] != "" {
+			w.line("%s(%s)", pairs[j], quote(pairs[j+1]))
+		}
+	}
+}
+
+// quote returns the Go string literal for s, a raw string literal if s spans multiple lines.
+func quote(s string) string {
+	if strings.Contains(s, "\n") && !strings.Contains(s, "`") && !strings.Contains(s, "\r") {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+// quoteAll returns the comma separated list of the Go string literals for vals.
+func quoteAll(vals []string) string {
+	res := make([]string, len(vals))
+	for j, v := range vals {
+		res[j] = quote(v)
+	}
+	return strings.Join(res, ", ")
+}
+
+// literal returns the Go literal for the JSON value v.
+func literal(v interface{}) string {
+	switch actual := v.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return quote(actual)
+	case bool:
+		return strconv.FormatBool(actual)
+	case json.Number:
+		return actual.String()
+	case []interface{}:
+		elems := make([]string, len(actual))
+		for j, e := range actual {
+			elems[j] = literal(e)
+		}
+		return "[]interface{}{" + strings.Join(elems, ", ") + "}"
+	case map[string]interface{}:
+		keys := sortedNames(actual)
+		elems := make([]string, len(keys))
+		for j, k := range keys {
+			elems[j] = quote(k) + ": " + literal(actual[k])
+		}
+		return "map[string]interface{}{" + strings.Join(elems, ", ") + "}"
+	default:
+		return fmt.Sprintf("%#v", v)
+	}
+}
+
+// isTrue returns true if v is the boolean true or a number as used by OpenAPI 3.1 exclusive
+// bounds.
+func isTrue(v interface{}) bool {
+	switch actual := v.(type) {
+	case bool:
+		return actual
+	case json.Number:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+/*
+Package genir provides a generator that exports an API design to a machine-readable intermediate
+representation (IR).
+
+The generator serializes the evaluated API definition to JSON so that tools not written in Go,
+documentation sites or API governance bots for example, can consume the design without running a
+generator of their own. The IR covers the resources, actions, routes, parameters, headers,
+payloads and responses, the media types with their views, links and hypermedia format, the user
+types, the security schemes and requirements, the traits and the metadata. It also covers the API
+versions, the webhooks, the Go type mappings, the pagination, response selection and collection
+criteria of the actions, the long-running actions, the Server-Sent Events, websocket messages and
+streaming responses. Definitions initialized with DSL functions include the location of the
+function in the design package sources.
+
+The format is versioned: the IR document records the version of the format in its "ir_version"
+field and the generator writes the JSON Schema describing that version of the format next to the
+document. Objects are serialized with their keys sorted and lists are sorted by name so that the
+IR of an unchanged design is identical from one run to the next.
+*/
+package genir
@@ -0,0 +1,13 @@
+package genir_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenIR(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenIR Suite")
+}
@@ -0,0 +1,964 @@
+package genir
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shogo82148/goa-v1/design"
+	"github.com/shogo82148/goa-v1/dslengine"
+)
+
+// Version is the version of the IR format. The minor version is incremented when fields are
+// added to the format, the major version when fields are removed or their meaning changes.
+const Version = "1.1"
+
+type (
+	// Document is the IR of an evaluated API design.
+	Document struct {
+		// IRVersion is the version of the IR format, see Version.
+		IRVersion string `json:"ir_version"`
+		// API is the API definition.
+		API *APIDesign `json:"api"`
+	}
+
+	// APIDesign describes the API definition.
+	APIDesign struct {
+		// Name of the API.
+		Name string `json:"name"`
+		// Title of the API.
+		Title string `json:"title,omitempty"`
+		// Description of the API.
+		Description string `json:"description,omitempty"`
+		// Version of the API.
+		Version string `json:"version,omitempty"`
+		// Host is the default API hostname.
+		Host string `json:"host,omitempty"`
+		// Schemes lists the supported URL schemes.
+		Schemes []string `json:"schemes,omitempty"`
+		// BasePath is the common base path to all API endpoints.
+		BasePath string `json:"base_path,omitempty"`
+		// Params describes the path parameters common to all API endpoints.
+		Params *Attribute `json:"params,omitempty"`
+		// Consumes lists the decoders of the request bodies.
+		Consumes []*Encoding `json:"consumes,omitempty"`
+		// Produces lists the encoders of the response bodies.
+		Produces []*Encoding `json:"produces,omitempty"`
+		// TermsOfService describes or links to the API terms of service.
+		TermsOfService string `json:"terms_of_service,omitempty"`
+		// Contact provides the API users with contact information.
+		Contact *Contact `json:"contact,omitempty"`
+		// License describes the API license.
+		License *License `json:"license,omitempty"`
+		// Docs points to the API external documentation.
+		Docs *Docs `json:"docs,omitempty"`
+		// SecuritySchemes lists the security schemes sorted by name.
+		SecuritySchemes []*SecurityScheme `json:"security_schemes,omitempty"`
+		// Security describes the default security requirement of the API endpoints.
+		Security *Security `json:"security,omitempty"`
+		// Traits lists the traits sorted by name.
+		Traits []*Trait `json:"traits,omitempty"`
+		// APIVersions lists the versions of the API in the order of the design.
+		APIVersions []*APIVersion `json:"api_versions,omitempty"`
+		// Webhooks lists the webhooks defined on the API sorted by name.
+		Webhooks []*Webhook `json:"webhooks,omitempty"`
+		// Hypermedia is the format used to render the media types that do not set one.
+		Hypermedia string `json:"hypermedia,omitempty"`
+		// GoTypeMappings lists the types mapped to existing Go types in the order of the
+		// design.
+		GoTypeMappings []*GoTypeMapping `json:"go_type_mappings,omitempty"`
+		// Resources lists the resources sorted by name.
+		Resources []*Resource `json:"resources"`
+		// MediaTypes lists the media types sorted by identifier.
+		MediaTypes []*MediaType `json:"media_types"`
+		// Types lists the user types sorted by name.
+		Types []*UserType `json:"types"`
+		// Metadata lists the metadata values indexed by key.
+		Metadata map[string][]string `json:"metadata,omitempty"`
+		// Location is the location of the API DSL.
+		Location *Location `json:"location,omitempty"`
+	}
+
+	// Resource describes a resource.
+	Resource struct {
+		// Name of the resource.
+		Name string `json:"name"`
+		// Description of the resource.
+		Description string `json:"description,omitempty"`
+		// BasePath is the common path prefix to all the resource actions.
+		BasePath string `json:"base_path,omitempty"`
+		// FullPath is the base path prefixed with the API and parent resource paths.
+		FullPath string `json:"full_path"`
+		// Parent is the name of the parent resource if any.
+		Parent string `json:"parent,omitempty"`
+		// CanonicalAction is the name of the action with the canonical resource path if any.
+		CanonicalAction string `json:"canonical_action,omitempty"`
+		// MediaType is the identifier or type name of the default media type if any.
+		MediaType string `json:"media_type,omitempty"`
+		// DefaultView is the name of the default view of the default media type if any.
+		DefaultView string `json:"default_view,omitempty"`
+		// Params describes the path and query string parameters common to all actions.
+		Params *Attribute `json:"params,omitempty"`
+		// Headers describes the request headers common to all actions.
+		Headers *Attribute `json:"headers,omitempty"`
+		// Security describes the default security requirement of the resource actions.
+		Security *Security `json:"security,omitempty"`
+		// Actions lists the actions sorted by name.
+		Actions []*Action `json:"actions"`
+		// FileServers lists the file servers sorted by file path.
+		FileServers []*FileServer `json:"file_servers,omitempty"`
+		// Versions lists the API versions exposing the resource, all if empty.
+		Versions []string `json:"versions,omitempty"`
+		// Metadata lists the metadata values indexed by key.
+		Metadata map[string][]string `json:"metadata,omitempty"`
+		// Location is the location of the resource DSL.
+		Location *Location `json:"location,omitempty"`
+	}
+
+	// Action describes a resource action.
+	Action struct {
+		// Name of the action.
+		Name string `json:"name"`
+		// Description of the action.
+		Description string `json:"description,omitempty"`
+		// Docs points to the action external documentation.
+		Docs *Docs `json:"docs,omitempty"`
+		// Schemes lists the URL schemes specific to the action.
+		Schemes []string `json:"schemes,omitempty"`
+		// Routes lists the action routes.
+		Routes []*Route `json:"routes"`
+		// Params describes the path and query string parameters.
+		Params *Attribute `json:"params,omitempty"`
+		// Headers describes the request headers.
+		Headers *Attribute `json:"headers,omitempty"`
+		// Payload describes the request body if any.
+		Payload *Payload `json:"payload,omitempty"`
+		// Responses lists the responses sorted by name.
+		Responses []*Response `json:"responses,omitempty"`
+		// Security describes the security requirement of the action, the action is not
+		// secured if nil.
+		Security *Security `json:"security,omitempty"`
+		// Deprecation describes the deprecation of the action if any.
+		Deprecation *Deprecation `json:"deprecation,omitempty"`
+		// Versions lists the API versions exposing the action, the same as the resource if
+		// empty.
+		Versions []string `json:"versions,omitempty"`
+		// Pagination describes how the action paginates the collection it returns if any.
+		Pagination *Pagination `json:"pagination,omitempty"`
+		// Selection describes how requests select the parts of the OK response if any.
+		Selection *Selection `json:"selection,omitempty"`
+		// Criteria describes how requests filter and sort the returned collection if any.
+		Criteria *Criteria `json:"criteria,omitempty"`
+		// LongRunning describes the operations started by the action if it is long-running.
+		LongRunning *LongRunning `json:"long_running,omitempty"`
+		// Events describes the Server-Sent Events streamed by the action if any.
+		Events *EventStream `json:"events,omitempty"`
+		// Messages describes the messages exchanged over the websocket connections if any.
+		Messages *Messages `json:"messages,omitempty"`
+		// Webhooks lists the webhooks triggered by the action sorted by name.
+		Webhooks []*Webhook `json:"webhooks,omitempty"`
+		// Metadata lists the metadata values indexed by key.
+		Metadata map[string][]string `json:"metadata,omitempty"`
+		// Location is the location of the action DSL.
+		Location *Location `json:"location,omitempty"`
+	}
+
+	// Route describes an action route.
+	Route struct {
+		// Verb is the HTTP method.
+		Verb string `json:"verb"`
+		// Path is the route path as defined in the design.
+		Path string `json:"path"`
+		// FullPath is the path prefixed with the API and resource paths.
+		FullPath string `json:"full_path"`
+		// Metadata lists the metadata values indexed by key.
+		Metadata map[string][]string `json:"metadata,omitempty"`
+	}
+
+	// Payload describes an action or webhook request body.
+	Payload struct {
+		// TypeName is the name of the payload type.
+		TypeName string `json:"type_name"`
+		// Optional is true if the request body may be omitted.
+		Optional bool `json:"optional,omitempty"`
+		// Multipart is true if the request body is a multipart form.
+		Multipart bool `json:"multipart,omitempty"`
+		// Attribute describes the payload type.
+		Attribute *Attribute `json:"attribute"`
+	}
+
+	// Response describes an action response.
+	Response struct {
+		// Name of the response.
+		Name string `json:"name"`
+		// Status is the HTTP status code.
+		Status int `json:"status"`
+		// Description of the response.
+		Description string `json:"description,omitempty"`
+		// MediaType is the identifier or type name of the response media type if any.
+		MediaType string `json:"media_type,omitempty"`
+		// View is the name of the view used to render the response media type if any.
+		View string `json:"view,omitempty"`
+		// Headers describes the response headers.
+		Headers *Attribute `json:"headers,omitempty"`
+		// Stream is true if the elements of the response collection may be streamed.
+		Stream bool `json:"stream,omitempty"`
+		// Metadata lists the metadata values indexed by key.
+		Metadata map[string][]string `json:"metadata,omitempty"`
+	}
+
+	// FileServer describes an endpoint serving static assets.
+	FileServer struct {
+		// FilePath is the file path to the static assets.
+		FilePath string `json:"file_path"`
+		// RequestPath is the HTTP path serving the assets.
+		RequestPath string `json:"request_path"`
+		// Description of the file server.
+		Description string `json:"description,omitempty"`
+		// Security describes the security requirement of the file server if any.
+		Security *Security `json:"security,omitempty"`
+		// Metadata lists the metadata values indexed by key.
+		Metadata map[string][]string `json:"metadata,omitempty"`
+	}
+
+	// MediaType describes a media type.
+	MediaType struct {
+		// Identifier is the media type identifier.
+		Identifier string `json:"identifier"`
+		// TypeName is the name of the media type Go type.
+		TypeName string `json:"type_name"`
+		// ContentType is the value of the response Content-Type header.
+		ContentType string `json:"content_type,omitempty"`
+		// Attribute describes the media type attributes.
+		Attribute *Attribute `json:"attribute"`
+		// Views lists the views sorted by name.
+		Views []*View `json:"views,omitempty"`
+		// Links lists the links sorted by name.
+		Links []*Link `json:"links,omitempty"`
+		// Hypermedia is the format used to render the media type: "none", "hal" or "jsonapi".
+		Hypermedia string `json:"hypermedia"`
+		// ResourceType is the JSON:API type of the resources if the format is "jsonapi".
+		ResourceType string `json:"resource_type,omitempty"`
+		// Location is the location of the media type DSL.
+		Location *Location `json:"location,omitempty"`
+	}
+
+	// View describes a media type view.
+	View struct {
+		// Name of the view.
+		Name string `json:"name"`
+		// Attributes lists the names of the rendered attributes sorted alphabetically.
+		Attributes []string `json:"attributes"`
+	}
+
+	// Link describes a media type link.
+	Link struct {
+		// Name of the link.
+		Name string `json:"name"`
+		// View is the name of the view used to render the link.
+		View string `json:"view"`
+		// MediaType is the identifier of the linked media type.
+		MediaType string `json:"media_type,omitempty"`
+		// URITemplate is the RFC 6570 URI template of the link href if any.
+		URITemplate string `json:"uri_template,omitempty"`
+	}
+
+	// UserType describes a user type.
+	UserType struct {
+		// Name of the type.
+		Name string `json:"name"`
+		// Attribute describes the type.
+		Attribute *Attribute `json:"attribute"`
+		// Location is the location of the type DSL.
+		Location *Location `json:"location,omitempty"`
+	}
+
+	// Attribute describes a data structure, its validations and documentation. User types and
+	// media types are referred to by name and identifier respectively.
+	Attribute struct {
+		// Type is the name of the primitive type, "array", "hash", "object", "user_type" or
+		// "media_type".
+		Type string `json:"type"`
+		// Ref is the name of the user type or the identifier of the media type.
+		Ref string `json:"ref,omitempty"`
+		// Description of the attribute.
+		Description string `json:"description,omitempty"`
+		// Key describes the hash keys.
+		Key *Attribute `json:"key,omitempty"`
+		// Elem describes the array elements or hash values.
+		Elem *Attribute `json:"elem,omitempty"`
+		// Attributes lists the object attributes indexed by name.
+		Attributes map[string]*Attribute `json:"attributes,omitempty"`
+		// Required lists the names of the required object attributes sorted alphabetically.
+		Required []string `json:"required,omitempty"`
+		// Enum lists the allowed values.
+		Enum []interface{} `json:"enum,omitempty"`
+		// Format is the format validation.
+		Format string `json:"format,omitempty"`
+		// Pattern is the regular expression validation.
+		Pattern string `json:"pattern,omitempty"`
+		// Minimum is the minimum value validation.
+		Minimum *float64 `json:"minimum,omitempty"`
+		// Maximum is the maximum value validation.
+		Maximum *float64 `json:"maximum,omitempty"`
+		// MinLength is the minimum length validation.
+		MinLength *int `json:"min_length,omitempty"`
+		// MaxLength is the maximum length validation.
+		MaxLength *int `json:"max_length,omitempty"`
+		// Default is the default value.
+		Default interface{} `json:"default,omitempty"`
+		// Example is the example value given in the design, generated examples are omitted.
+		Example interface{} `json:"example,omitempty"`
+		// View is the name of the view used to render media type attributes.
+		View string `json:"view,omitempty"`
+		// Deprecation describes the deprecation of the attribute if any.
+		Deprecation *Deprecation `json:"deprecation,omitempty"`
+		// Metadata lists the metadata values indexed by key.
+		Metadata map[string][]string `json:"metadata,omitempty"`
+		// Location is the location of the attribute DSL.
+		Location *Location `json:"location,omitempty"`
+	}
+
+	// SecurityScheme describes a security scheme.
+	SecurityScheme struct {
+		// Name of the scheme.
+		Name string `json:"name"`
+		// Kind is one of "basic", "api_key", "jwt" or "oauth2".
+		Kind string `json:"kind"`
+		// Description of the scheme.
+		Description string `json:"description,omitempty"`
+		// In is "header" or "query" for API key and JWT schemes.
+		In string `json:"in,omitempty"`
+		// ParamName is the name of the header or query string parameter for API key and JWT
+		// schemes.
+		ParamName string `json:"param_name,omitempty"`
+		// Scopes lists the descriptions of the available scopes indexed by name.
+		Scopes map[string]string `json:"scopes,omitempty"`
+		// Flow is the OAuth2 flow.
+		Flow string `json:"flow,omitempty"`
+		// TokenURL is the URL used to retrieve tokens.
+		TokenURL string `json:"token_url,omitempty"`
+		// AuthorizationURL is the URL used to retrieve OAuth2 authorization codes.
+		AuthorizationURL string `json:"authorization_url,omitempty"`
+		// Metadata lists the metadata values indexed by key.
+		Metadata map[string][]string `json:"metadata,omitempty"`
+		// Location is the location of the scheme DSL.
+		Location *Location `json:"location,omitempty"`
+	}
+
+	// Security describes a security requirement.
+	Security struct {
+		// Scheme is the name of the security scheme.
+		Scheme string `json:"scheme"`
+		// Scopes lists the required scopes.
+		Scopes []string `json:"scopes,omitempty"`
+	}
+
+	// Trait describes a trait. Traits are DSL functions, the IR only records where they are
+	// defined.
+	Trait struct {
+		// Name of the trait.
+		Name string `json:"name"`
+		// Location is the location of the trait DSL.
+		Location *Location `json:"location,omitempty"`
+	}
+
+	// Encoding describes an encoder or decoder.
+	Encoding struct {
+		// MIMETypes lists the encoded or decoded MIME types.
+		MIMETypes []string `json:"mime_types"`
+		// PackagePath is the import path of the package implementing the encoder or decoder.
+		PackagePath string `json:"package_path,omitempty"`
+		// Function is the name of the function that creates the encoder or decoder.
+		Function string `json:"function,omitempty"`
+	}
+
+	// Contact describes the API contact information.
+	Contact struct {
+		// Name of the contact person or organization.
+		Name string `json:"name,omitempty"`
+		// Email address of the contact.
+		Email string `json:"email,omitempty"`
+		// URL pointing to the contact information.
+		URL string `json:"url,omitempty"`
+	}
+
+	// License describes the API license.
+	License struct {
+		// Name of the license.
+		Name string `json:"name,omitempty"`
+		// URL of the license.
+		URL string `json:"url,omitempty"`
+	}
+
+	// Docs points to external documentation.
+	Docs struct {
+		// Description of the documentation.
+		Description string `json:"description,omitempty"`
+		// URL of the documentation.
+		URL string `json:"url,omitempty"`
+	}
+
+	// Deprecation describes the deprecation of an action or an attribute.
+	Deprecation struct {
+		// Since is the date of the deprecation.
+		Since string `json:"since,omitempty"`
+		// Sunset is the date of the removal.
+		Sunset string `json:"sunset,omitempty"`
+		// Replacement describes what replaces the deprecated definition.
+		Replacement string `json:"replacement,omitempty"`
+	}
+
+	// APIVersion describes a version of the API.
+	APIVersion struct {
+		// Name of the version.
+		Name string `json:"name"`
+		// Description of the version.
+		Description string `json:"description,omitempty"`
+		// BasePath is the path prefix of the version endpoints if selected by path.
+		BasePath string `json:"base_path,omitempty"`
+		// Header is the name of the request header that selects the version if any.
+		Header string `json:"header,omitempty"`
+		// MediaTypeParam is the name of the media type parameter that selects the version if
+		// any.
+		MediaTypeParam string `json:"media_type_param,omitempty"`
+	}
+
+	// Webhook describes a request sent by the API to a URL provided by a third party.
+	Webhook struct {
+		// Name of the webhook.
+		Name string `json:"name"`
+		// Description of the webhook.
+		Description string `json:"description,omitempty"`
+		// Payload describes the request body.
+		Payload *Payload `json:"payload"`
+		// Headers describes the request headers if any.
+		Headers *Attribute `json:"headers,omitempty"`
+	}
+
+	// GoTypeMapping describes a primitive or user type mapped to an existing Go type.
+	GoTypeMapping struct {
+		// Type is the name of the mapped primitive type or "user_type".
+		Type string `json:"type"`
+		// Ref is the name of the mapped user type.
+		Ref string `json:"ref,omitempty"`
+		// GoType is the qualified name of the Go type.
+		GoType string `json:"go_type"`
+		// PackagePath is the import path of the package defining the Go type if any.
+		PackagePath string `json:"package_path,omitempty"`
+		// Function is the name of the function that parses string values if any.
+		Function string `json:"function,omitempty"`
+	}
+
+	// Pagination describes how an action paginates the collection it returns.
+	Pagination struct {
+		// Style is "cursor" or "offset".
+		Style string `json:"style"`
+		// DefaultLimit is the number of items returned when requests do not set the limit.
+		DefaultLimit int `json:"default_limit"`
+		// MaxLimit is the maximum number of items returned by a single request.
+		MaxLimit int `json:"max_limit"`
+	}
+
+	// Selection describes how requests select the parts of the response they get.
+	Selection struct {
+		// View is true if requests may select the response view.
+		View bool `json:"view,omitempty"`
+		// Fields is true if requests may select the response attributes.
+		Fields bool `json:"fields,omitempty"`
+	}
+
+	// Criteria describes how requests filter and sort the returned collection.
+	Criteria struct {
+		// Filters lists the names of the attributes requests may filter on.
+		Filters []string `json:"filters,omitempty"`
+		// Sorts lists the names of the attributes requests may sort by.
+		Sorts []string `json:"sorts,omitempty"`
+	}
+
+	// LongRunning describes the operations started by a long-running action.
+	LongRunning struct {
+		// RetryAfter is the number of seconds clients should wait before polling.
+		RetryAfter int `json:"retry_after"`
+		// StatusAction is the name of the action that retrieves the operation status.
+		StatusAction string `json:"status_action"`
+		// CancelAction is the name of the action that cancels the operation.
+		CancelAction string `json:"cancel_action"`
+	}
+
+	// EventStream describes the Server-Sent Events streamed by an action.
+	EventStream struct {
+		// MediaType is the identifier of the media type of the event data.
+		MediaType string `json:"media_type"`
+		// KeepAlive is the interval in seconds between keepalive comments, 0 if disabled.
+		KeepAlive int `json:"keep_alive"`
+	}
+
+	// Messages describes the messages exchanged over the connections of a websocket action.
+	Messages struct {
+		// Inbound describes the messages sent by the client if any.
+		Inbound *Attribute `json:"inbound,omitempty"`
+		// Outbound describes the messages sent by the server if any.
+		Outbound *Attribute `json:"outbound,omitempty"`
+		// PingInterval is the interval in seconds between pings, 0 if disabled.
+		PingInterval int `json:"ping_interval"`
+	}
+
+	// Location is the location of a DSL function in the design package sources.
+	Location struct {
+		// File is the path to the source file relative to the working directory of goagen.
+		File string `json:"file"`
+		// Line is the line number of the DSL function.
+		Line int `json:"line"`
+	}
+)
+
+// NewDocument builds the IR of the given API definition.
+func NewDocument(api *design.APIDefinition) *Document {
+	a := &APIDesign{
+		Name:            api.Name,
+		Title:           api.Title,
+		Description:     api.Description,
+		Version:         api.Version,
+		Host:            api.Host,
+		Schemes:         api.Schemes,
+		BasePath:        api.BasePath,
+		Params:          newAttribute(api.Params),
+		Consumes:        newEncodings(api.Consumes),
+		Produces:        newEncodings(api.Produces),
+		TermsOfService:  api.TermsOfService,
+		Docs:            newDocs(api.Docs),
+		Security:        newSecurity(api.Security),
+		Resources:       []*Resource{},
+		MediaTypes:      []*MediaType{},
+		Types:           []*UserType{},
+		Metadata:        newMetadata(api.Metadata),
+		Location:        newLocation(dslengine.Location(api)),
+		SecuritySchemes: newSecuritySchemes(api.SecuritySchemes),
+		Traits:          newTraits(api.Traits),
+		Webhooks:        newWebhooks(api.Webhooks),
+		Hypermedia:      string(api.Hypermedia),
+	}
+	if c := api.Contact; c != nil {
+		a.Contact = &Contact{Name: c.Name, Email: c.Email, URL: c.URL}
+	}
+	if l := api.License; l != nil {
+		a.License = &License{Name: l.Name, URL: l.URL}
+	}
+	for _, v := range api.APIVersions {
+		a.APIVersions = append(a.APIVersions, &APIVersion{
+			Name:           v.Name,
+			Description:    v.Description,
+			BasePath:       v.BasePath,
+			Header:         v.Header,
+			MediaTypeParam: v.MediaTypeParam,
+		})
+	}
+	for _, m := range api.GoTypeMappings {
+		t := newDataType(m.Type)
+		a.GoTypeMappings = append(a.GoTypeMappings, &GoTypeMapping{
+			Type:        t.Type,
+			Ref:         t.Ref,
+			GoType:      m.GoType,
+			PackagePath: m.PackagePath,
+			Function:    m.Function,
+		})
+	}
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		a.Resources = append(a.Resources, newResource(r))
+		return nil
+	})
+	sort.Slice(a.Resources, func(i, j int) bool { return a.Resources[i].Name < a.Resources[j].Name })
+	api.IterateMediaTypes(func(m *design.MediaTypeDefinition) error {
+		a.MediaTypes = append(a.MediaTypes, newMediaType(m))
+		return nil
+	})
+	api.IterateUserTypes(func(u *design.UserTypeDefinition) error {
+		a.Types = append(a.Types, &UserType{
+			Name:      u.TypeName,
+			Attribute: newAttribute(u.AttributeDefinition),
+			Location:  newLocation(dslengine.Location(u)),
+		})
+		return nil
+	})
+	return &Document{IRVersion: Version, API: a}
+}
+
+func newResource(r *design.ResourceDefinition) *Resource {
+	res := &Resource{
+		Name:            r.Name,
+		Description:     r.Description,
+		BasePath:        r.BasePath,
+		FullPath:        r.FullPath(),
+		Parent:          r.ParentName,
+		CanonicalAction: r.CanonicalActionName,
+		MediaType:       r.MediaType,
+		DefaultView:     r.DefaultViewName,
+		Params:          newAttribute(r.Params),
+		Headers:         newAttribute(r.Headers),
+		Security:        newSecurity(r.Security),
+		Actions:         []*Action{},
+		Versions:        r.Versions,
+		Metadata:        newMetadata(r.Metadata),
+		Location:        newLocation(dslengine.Location(r)),
+	}
+	r.IterateActions(func(a *design.ActionDefinition) error {
+		res.Actions = append(res.Actions, newAction(a))
+		return nil
+	})
+	r.IterateFileServers(func(f *design.FileServerDefinition) error {
+		res.FileServers = append(res.FileServers, &FileServer{
+			FilePath:    f.FilePath,
+			RequestPath: f.RequestPath,
+			Description: f.Description,
+			Security:    newSecurity(f.Security),
+			Metadata:    newMetadata(f.Metadata),
+		})
+		return nil
+	})
+	return res
+}
+
+func newAction(a *design.ActionDefinition) *Action {
+	act := &Action{
+		Name:        a.Name,
+		Description: a.Description,
+		Docs:        newDocs(a.Docs),
+		Schemes:     a.Schemes,
+		Routes:      make([]*Route, len(a.Routes)),
+		Params:      newAttribute(a.Params),
+		Headers:     newAttribute(a.Headers),
+		Security:    newSecurity(a.Security),
+		Deprecation: newDeprecation(a.Deprecation),
+		Versions:    a.Versions,
+		Webhooks:    newWebhooks(a.Webhooks),
+		Metadata:    newMetadata(a.Metadata),
+		Location:    newLocation(dslengine.Location(a)),
+	}
+	if p := a.Pagination; p != nil {
+		act.Pagination = &Pagination{Style: string(p.Style), DefaultLimit: p.DefaultLimit, MaxLimit: p.MaxLimit}
+	}
+	if s := a.Selection; s != nil {
+		act.Selection = &Selection{View: s.View, Fields: s.Fields}
+	}
+	if c := a.Criteria; c != nil {
+		act.Criteria = &Criteria{Filters: c.Filters, Sorts: c.Sorts}
+	}
+	if l := a.LongRunning; l != nil {
+		act.LongRunning = &LongRunning{
+			RetryAfter:   l.RetryAfter,
+			StatusAction: design.ShowOperationAction,
+			CancelAction: design.CancelOperationAction,
+		}
+	}
+	if e := a.Events; e != nil {
+		act.Events = &EventStream{MediaType: e.MediaType, KeepAlive: e.KeepAlive}
+	}
+	if m := a.Messages; m != nil {
+		act.Messages = &Messages{
+			Inbound:      newDataType(m.Inbound),
+			Outbound:     newDataType(m.Outbound),
+			PingInterval: m.PingInterval,
+		}
+	}
+	for i, r := range a.Routes {
+		act.Routes[i] = &Route{
+			Verb:     r.Verb,
+			Path:     r.Path,
+			FullPath: r.FullPath(),
+			Metadata: newMetadata(r.Metadata),
+		}
+	}
+	if p := a.Payload; p != nil {
+		act.Payload = &Payload{
+			TypeName:  p.TypeName,
+			Optional:  a.PayloadOptional,
+			Multipart: a.PayloadMultipart,
+			Attribute: newAttribute(p.AttributeDefinition),
+		}
+	}
+	a.IterateResponses(func(r *design.ResponseDefinition) error {
+		act.Responses = append(act.Responses, &Response{
+			Name:        r.Name,
+			Status:      r.Status,
+			Description: r.Description,
+			MediaType:   r.MediaType,
+			View:        r.ViewName,
+			Headers:     newAttribute(r.Headers),
+			Stream:      r.Stream,
+			Metadata:    newMetadata(r.Metadata),
+		})
+		return nil
+	})
+	return act
+}
+
+func newMediaType(m *design.MediaTypeDefinition) *MediaType {
+	mt := &MediaType{
+		Identifier:  m.Identifier,
+		TypeName:    m.TypeName,
+		ContentType: m.ContentType,
+		Attribute:   newAttribute(m.AttributeDefinition),
+		Hypermedia:  string(m.HypermediaFormat()),
+		Location:    newLocation(dslengine.Location(m)),
+	}
+	if m.HypermediaFormat() == design.JSONAPIHypermedia {
+		mt.ResourceType = m.JSONAPIType()
+	}
+	m.IterateViews(func(v *design.ViewDefinition) error {
+		view := &View{Name: v.Name, Attributes: []string{}}
+		if v.AttributeDefinition != nil && v.Type != nil {
+			for n := range v.Type.ToObject() {
+				view.Attributes = append(view.Attributes, n)
+			}
+			sort.Strings(view.Attributes)
+		}
+		mt.Views = append(mt.Views, view)
+		return nil
+	})
+	names := make([]string, 0, len(m.Links))
+	for n := range m.Links {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		l := m.Links[n]
+		link := &Link{Name: l.Name, View: l.View, URITemplate: l.URITemplate}
+		if link.View == "" {
+			link.View = "link"
+		}
+		if lmt := l.MediaType(); lmt != nil {
+			link.MediaType = lmt.Identifier
+		}
+		mt.Links = append(mt.Links, link)
+	}
+	return mt
+}
+
+func newAttribute(att *design.AttributeDefinition) *Attribute {
+	if att == nil || att.Type == nil {
+		return nil
+	}
+	a := &Attribute{
+		Description: att.Description,
+		Default:     jsonValue(att.DefaultValue),
+		View:        att.View,
+		Deprecation: newDeprecation(att.Deprecation),
+		Metadata:    newMetadata(att.Metadata),
+		Location:    newLocation(dslengine.Location(att)),
+	}
+	if att.HasExplicitExample() {
+		a.Example = jsonValue(att.Example)
+	}
+	switch t := att.Type.(type) {
+	case design.Primitive:
+		a.Type = primitiveName(t)
+	case *design.Array:
+		a.Type = "array"
+		a.Elem = newAttribute(t.ElemType)
+	case *design.Hash:
+		a.Type = "hash"
+		a.Key = newAttribute(t.KeyType)
+		a.Elem = newAttribute(t.ElemType)
+	case design.Object:
+		a.Type = "object"
+		a.Attributes = make(map[string]*Attribute, len(t))
+		for n, f := range t {
+			a.Attributes[n] = newAttribute(f)
+		}
+	case *design.MediaTypeDefinition:
+		a.Type = "media_type"
+		a.Ref = t.Identifier
+	case *design.UserTypeDefinition:
+		a.Type = "user_type"
+		a.Ref = t.TypeName
+	}
+	if v := att.Validation; v != nil {
+		if len(v.Required) > 0 {
+			a.Required = append([]string(nil), v.Required...)
+			sort.Strings(a.Required)
+		}
+		for _, e := range v.Values {
+			a.Enum = append(a.Enum, jsonValue(e))
+		}
+		a.Format = v.Format
+		a.Pattern = v.Pattern
+		a.Minimum = v.Minimum
+		a.Maximum = v.Maximum
+		a.MinLength = v.MinLength
+		a.MaxLength = v.MaxLength
+	}
+	return a
+}
+
+// newDataType returns the attribute that refers to the given type, nil if t is nil.
+func newDataType(t design.DataType) *Attribute {
+	if t == nil {
+		return nil
+	}
+	return newAttribute(&design.AttributeDefinition{Type: t})
+}
+
+func newWebhooks(webhooks map[string]*design.WebhookDefinition) []*Webhook {
+	if len(webhooks) == 0 {
+		return nil
+	}
+	res := make([]*Webhook, 0, len(webhooks))
+	for _, w := range webhooks {
+		wh := &Webhook{
+			Name:        w.Name,
+			Description: w.Description,
+			Headers:     newAttribute(w.Headers),
+		}
+		if p := w.Payload; p != nil {
+			wh.Payload = &Payload{TypeName: p.TypeName, Attribute: newAttribute(p.AttributeDefinition)}
+		}
+		res = append(res, wh)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+func newSecuritySchemes(schemes []*design.SecuritySchemeDefinition) []*SecurityScheme {
+	if len(schemes) == 0 {
+		return nil
+	}
+	res := make([]*SecurityScheme, len(schemes))
+	for i, s := range schemes {
+		res[i] = &SecurityScheme{
+			Name:             s.SchemeName,
+			Kind:             securityKind(s.Kind),
+			Description:      s.Description,
+			In:               s.In,
+			ParamName:        s.Name,
+			Scopes:           s.Scopes,
+			Flow:             s.Flow,
+			TokenURL:         s.TokenURL,
+			AuthorizationURL: s.AuthorizationURL,
+			Metadata:         newMetadata(s.Metadata),
+			Location:         newLocation(dslengine.Location(s)),
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+func newSecurity(s *design.SecurityDefinition) *Security {
+	if s == nil || s.Scheme == nil || s.Scheme.Kind == design.NoSecurityKind {
+		return nil
+	}
+	return &Security{Scheme: s.Scheme.SchemeName, Scopes: s.Scopes}
+}
+
+func newTraits(traits map[string]*dslengine.TraitDefinition) []*Trait {
+	if len(traits) == 0 {
+		return nil
+	}
+	res := make([]*Trait, 0, len(traits))
+	for _, t := range traits {
+		res = append(res, &Trait{Name: t.Name, Location: newLocation(dslengine.FuncLocation(t.DSLFunc))})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+func newEncodings(encs []*design.EncodingDefinition) []*Encoding {
+	if len(encs) == 0 {
+		return nil
+	}
+	res := make([]*Encoding, len(encs))
+	for i, e := range encs {
+		res[i] = &Encoding{MIMETypes: e.MIMETypes, PackagePath: e.PackagePath, Function: e.Function}
+	}
+	return res
+}
+
+func newDocs(d *design.DocsDefinition) *Docs {
+	if d == nil {
+		return nil
+	}
+	return &Docs{Description: d.Description, URL: d.URL}
+}
+
+func newDeprecation(d *design.DeprecationDefinition) *Deprecation {
+	if d == nil {
+		return nil
+	}
+	return &Deprecation{Since: d.Since, Sunset: d.Sunset, Replacement: d.Replacement}
+}
+
+func newMetadata(md dslengine.MetadataDefinition) map[string][]string {
+	if len(md) == 0 {
+		return nil
+	}
+	return md
+}
+
+func newLocation(file string, line int) *Location {
+	if file == "" {
+		return nil
+	}
+	return &Location{File: file, Line: line}
+}
+
+// securityKind returns the IR name of the given security scheme kind.
+func securityKind(k design.SecuritySchemeKind) string {
+	switch k {
+	case design.OAuth2SecurityKind:
+		return "oauth2"
+	case design.BasicAuthSecurityKind:
+		return "basic"
+	case design.APIKeySecurityKind:
+		return "api_key"
+	case design.JWTSecurityKind:
+		return "jwt"
+	default:
+		return "none"
+	}
+}
+
+// primitiveName returns the name of the given primitive type as used in the design DSL.
+func primitiveName(p design.Primitive) string {
+	switch p {
+	case design.DateTime:
+		return "datetime"
+	case design.UUID:
+		return "uuid"
+	case design.Date:
+		return "date"
+	case design.TimeOfDay:
+		return "timeofday"
+	case design.Duration:
+		return "duration"
+	case design.Decimal:
+		return "decimal"
+	default:
+		return p.Name()
+	}
+}
+
+// jsonValue converts the given default, example or enum value so that it can be serialized to
+// JSON: the DSL represents hash values with maps whose keys are interface{} values.
+func jsonValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(val))
+		for k, e := range val {
+			res[fmt.Sprint(k)] = jsonValue(e)
+		}
+		return res
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(val))
+		for k, e := range val {
+			res[k] = jsonValue(e)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(val))
+		for i, e := range val {
+			res[i] = jsonValue(e)
+		}
+		return res
+	default:
+		return v
+	}
+}
@@ -0,0 +1,433 @@
+package genir_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1/design"
+	"github.com/shogo82148/goa-v1/design/apidsl"
+	"github.com/shogo82148/goa-v1/dslengine"
+	genir "github.com/shogo82148/goa-v1/goagen/gen_ir"
+)
+
+var _ = Describe("NewDocument", func() {
+	var doc *genir.Document
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		apidsl.API("cellar", func() {
+			apidsl.Title("The wine cellar")
+			apidsl.BasePath("/cellar")
+			apidsl.Metadata("owner", "wine-team")
+			apidsl.Trait("paginated", func() {
+				apidsl.Params(func() {
+					apidsl.Param("page", design.Integer)
+				})
+			})
+			apidsl.OAuth2Security("oauth", func() {
+				apidsl.AccessCodeFlow("/authorization", "/token")
+				apidsl.Scope("bottle:read", "Read bottles")
+			})
+		})
+		account := apidsl.MediaType("application/vnd.account", func() {
+			apidsl.Attributes(func() {
+				apidsl.Attribute("id", design.Integer)
+			})
+			apidsl.View("default", func() {
+				apidsl.Attribute("id")
+			})
+			apidsl.View("link", func() {
+				apidsl.Attribute("id")
+			})
+		})
+		bottle := apidsl.MediaType("application/vnd.bottle", func() {
+			apidsl.Attributes(func() {
+				apidsl.Attribute("id", design.Integer, func() {
+					apidsl.Minimum(1)
+					apidsl.Example(42)
+				})
+				apidsl.Attribute("name", design.String, func() {
+					apidsl.MaxLength(64)
+				})
+				apidsl.Attribute("tags", apidsl.HashOf(design.String, design.Integer), func() {
+					apidsl.Default(map[interface{}]interface{}{"red": 1})
+				})
+				apidsl.Attribute("account", account)
+				apidsl.Links(func() {
+					apidsl.Link("account")
+				})
+				apidsl.Required("id", "name")
+			})
+			apidsl.View("default", func() {
+				apidsl.Attribute("name")
+				apidsl.Attribute("id")
+				apidsl.Attribute("links")
+			})
+		})
+		apidsl.Resource("bottle", func() {
+			apidsl.BasePath("/bottles")
+			apidsl.DefaultMedia(bottle)
+			apidsl.Action("list", func() {
+				apidsl.Routing(apidsl.GET(""))
+				apidsl.UseTrait("paginated")
+				apidsl.Security("oauth", func() {
+					apidsl.Scope("bottle:read")
+				})
+				apidsl.Response(design.OK, apidsl.CollectionOf(bottle))
+			})
+			apidsl.Action("create", func() {
+				apidsl.Routing(apidsl.POST(""))
+				apidsl.Payload(func() {
+					apidsl.Attribute("name", design.String)
+					apidsl.Required("name")
+				})
+				apidsl.Response(design.Created, func() {
+					apidsl.Headers(func() {
+						apidsl.Header("Location")
+					})
+				})
+			})
+		})
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		doc = genir.NewDocument(design.Design)
+	})
+
+	It("records the format version", func() {
+		Ω(doc.IRVersion).Should(Equal(genir.Version))
+	})
+
+	It("exports the API definition", func() {
+		api := doc.API
+		Ω(api.Name).Should(Equal("cellar"))
+		Ω(api.Title).Should(Equal("The wine cellar"))
+		Ω(api.Metadata).Should(HaveKeyWithValue("owner", []string{"wine-team"}))
+		Ω(api.Location).ShouldNot(BeNil())
+		Ω(api.Location.File).Should(Equal("ir_test.go"))
+		Ω(api.Traits).Should(HaveLen(1))
+		Ω(api.Traits[0].Name).Should(Equal("paginated"))
+		Ω(api.Traits[0].Location).ShouldNot(BeNil())
+		Ω(api.SecuritySchemes).Should(HaveLen(1))
+		Ω(api.SecuritySchemes[0].Name).Should(Equal("oauth"))
+		Ω(api.SecuritySchemes[0].Kind).Should(Equal("oauth2"))
+		Ω(api.SecuritySchemes[0].Scopes).Should(HaveKeyWithValue("bottle:read", "Read bottles"))
+	})
+
+	It("exports the resources and actions", func() {
+		Ω(doc.API.Resources).Should(HaveLen(1))
+		res := doc.API.Resources[0]
+		Ω(res.Name).Should(Equal("bottle"))
+		Ω(res.FullPath).Should(Equal("/cellar/bottles"))
+		Ω(res.MediaType).Should(Equal("application/vnd.bottle"))
+		Ω(res.Location).ShouldNot(BeNil())
+		Ω(res.Actions).Should(HaveLen(2))
+
+		create, list := res.Actions[0], res.Actions[1]
+		Ω(create.Name).Should(Equal("create"))
+		Ω(create.Routes).Should(HaveLen(1))
+		Ω(*create.Routes[0]).Should(Equal(genir.Route{Verb: "POST", Path: "", FullPath: "/cellar/bottles"}))
+		Ω(create.Payload).ShouldNot(BeNil())
+		Ω(create.Payload.TypeName).Should(Equal("CreateBottlePayload"))
+		Ω(create.Payload.Attribute.Type).Should(Equal("object"))
+		Ω(create.Payload.Attribute.Required).Should(Equal([]string{"name"}))
+		Ω(create.Responses).Should(HaveLen(1))
+		Ω(create.Responses[0].Status).Should(Equal(201))
+		Ω(create.Responses[0].Headers.Attributes).Should(HaveKey("Location"))
+		Ω(create.Security).Should(BeNil())
+
+		Ω(list.Name).Should(Equal("list"))
+		Ω(list.Params.Attributes).Should(HaveKey("page"))
+		Ω(list.Security).Should(Equal(&genir.Security{Scheme: "oauth", Scopes: []string{"bottle:read"}}))
+		Ω(list.Responses[0].MediaType).Should(Equal("application/vnd.bottle; type=collection"))
+		Ω(list.Location).ShouldNot(BeNil())
+	})
+
+	It("exports the media types with their views and links", func() {
+		var mt *genir.MediaType
+		for _, m := range doc.API.MediaTypes {
+			if m.Identifier == "application/vnd.bottle" {
+				mt = m
+			}
+		}
+		Ω(mt).ShouldNot(BeNil())
+		Ω(mt.TypeName).Should(Equal("Bottle"))
+		Ω(mt.Location).ShouldNot(BeNil())
+		Ω(mt.Links).Should(Equal([]*genir.Link{{Name: "account", View: "link", MediaType: "application/vnd.account"}}))
+		Ω(mt.Views).Should(HaveLen(1))
+		Ω(mt.Views[0].Name).Should(Equal("default"))
+		Ω(mt.Views[0].Attributes).Should(Equal([]string{"id", "links", "name"}))
+
+		att := mt.Attribute
+		Ω(att.Required).Should(Equal([]string{"id", "name"}))
+		id := att.Attributes["id"]
+		Ω(id.Type).Should(Equal("integer"))
+		Ω(*id.Minimum).Should(Equal(1.0))
+		Ω(id.Example).Should(Equal(42))
+		Ω(att.Attributes["name"].Example).Should(BeNil())
+		Ω(*att.Attributes["name"].MaxLength).Should(Equal(64))
+		tags := att.Attributes["tags"]
+		Ω(tags.Type).Should(Equal("hash"))
+		Ω(tags.Key.Type).Should(Equal("string"))
+		Ω(tags.Default).Should(Equal(map[string]interface{}{"red": 1}))
+		account := att.Attributes["account"]
+		Ω(account.Type).Should(Equal("media_type"))
+		Ω(account.Ref).Should(Equal("application/vnd.account"))
+	})
+
+	It("serializes deterministically", func() {
+		b1, err := json.Marshal(doc)
+		Ω(err).ShouldNot(HaveOccurred())
+		b2, err := json.Marshal(genir.NewDocument(design.Design))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b1)).Should(Equal(string(b2)))
+	})
+})
+
+var _ = Describe("NewDocument with the design extensions", func() {
+	var doc genir.Document
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		apidsl.API("cellar", func() {
+			apidsl.Hypermedia(design.HALHypermedia)
+			apidsl.APIVersion("v1", func() {
+				apidsl.Description("Initial version")
+				apidsl.BasePath("/v1")
+			})
+			apidsl.APIVersion("v2", func() {
+				apidsl.VersionHeader("X-Api-Version")
+			})
+			apidsl.GoType(design.UUID, "guuid.UUID", func() {
+				apidsl.Package("github.com/google/uuid")
+			})
+			apidsl.Webhook("bottle.created", func() {
+				apidsl.Payload(func() {
+					apidsl.Member("id", design.Integer)
+					apidsl.Required("id")
+				})
+			})
+		})
+		bottle := apidsl.MediaType("application/vnd.bottle", func() {
+			apidsl.Attributes(func() {
+				apidsl.Attribute("id", design.Integer)
+				apidsl.Attribute("name", design.String)
+			})
+			apidsl.View("default", func() {
+				apidsl.Attribute("id")
+				apidsl.Attribute("name")
+			})
+		})
+		event := apidsl.MediaType("application/vnd.bottle.event", func() {
+			apidsl.Hypermedia(design.NoHypermedia)
+			apidsl.Attributes(func() {
+				apidsl.Attribute("id", design.Integer)
+			})
+			apidsl.View("default", func() {
+				apidsl.Attribute("id")
+			})
+		})
+		chat := apidsl.Type("Chat", func() {
+			apidsl.Attribute("text", design.String)
+		})
+		apidsl.Resource("bottle", func() {
+			apidsl.BasePath("/bottles")
+			apidsl.Action("list", func() {
+				apidsl.Routing(apidsl.GET(""))
+				apidsl.Paginated(design.OffsetPagination, 20, 100)
+				apidsl.Selectable(design.ViewSelector)
+				apidsl.Filterable("name")
+				apidsl.Sortable("id")
+				apidsl.Response(design.OK, apidsl.CollectionOf(bottle))
+			})
+			apidsl.Action("dump", func() {
+				apidsl.Routing(apidsl.GET("/dump"))
+				apidsl.Response(design.OK, apidsl.CollectionOf(bottle), func() {
+					apidsl.Streaming()
+				})
+			})
+			apidsl.Action("export", func() {
+				apidsl.Routing(apidsl.POST("/export"))
+				apidsl.LongRunning(5)
+				apidsl.Webhook("bottle.exported", func() {
+					apidsl.Payload(func() {
+						apidsl.Member("href", design.String)
+					})
+					apidsl.Headers(func() {
+						apidsl.Header("X-Tenant")
+					})
+				})
+			})
+			apidsl.Action("watch", func() {
+				apidsl.Routing(apidsl.GET("/events"))
+				apidsl.ServerSentEvents(event, func() {
+					apidsl.KeepAlive(30)
+				})
+			})
+			apidsl.Action("chat", func() {
+				apidsl.Scheme("ws")
+				apidsl.Routing(apidsl.GET("/chat"))
+				apidsl.Messages(chat, chat, func() {
+					apidsl.PingInterval(10)
+				})
+				apidsl.Response(design.SwitchingProtocols)
+			})
+		})
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+
+		// Round trip through JSON as the tools consuming the IR do.
+		b, err := json.Marshal(genir.NewDocument(design.Design))
+		Ω(err).ShouldNot(HaveOccurred())
+		doc = genir.Document{}
+		Ω(json.Unmarshal(b, &doc)).ShouldNot(HaveOccurred())
+	})
+
+	// action returns the IR of the bottle action with the given name.
+	action := func(name string) *genir.Action {
+		for _, a := range doc.API.Resources[0].Actions {
+			if a.Name == name {
+				return a
+			}
+		}
+		return nil
+	}
+
+	It("exports the API versions, webhooks, hypermedia format and Go type mappings", func() {
+		api := doc.API
+		Ω(api.APIVersions).Should(Equal([]*genir.APIVersion{
+			{Name: "v1", Description: "Initial version", BasePath: "/v1"},
+			{Name: "v2", Header: "X-Api-Version"},
+		}))
+		Ω(api.Webhooks).Should(HaveLen(1))
+		Ω(api.Webhooks[0].Name).Should(Equal("bottle.created"))
+		Ω(api.Webhooks[0].Payload.Attribute.Attributes).Should(HaveKey("id"))
+		Ω(api.Webhooks[0].Payload.Attribute.Required).Should(Equal([]string{"id"}))
+		Ω(api.Hypermedia).Should(Equal("hal"))
+		Ω(api.GoTypeMappings).Should(Equal([]*genir.GoTypeMapping{
+			{Type: "uuid", GoType: "guuid.UUID", PackagePath: "github.com/google/uuid"},
+		}))
+	})
+
+	It("exports the pagination, selection and criteria of the actions", func() {
+		list := action("list")
+		Ω(list.Pagination).Should(Equal(&genir.Pagination{Style: "offset", DefaultLimit: 20, MaxLimit: 100}))
+		Ω(list.Selection).Should(Equal(&genir.Selection{View: true}))
+		Ω(list.Criteria).Should(Equal(&genir.Criteria{Filters: []string{"name"}, Sorts: []string{"id"}}))
+	})
+
+	It("exports the streaming responses, event streams and websocket messages", func() {
+		Ω(action("dump").Responses[0].Stream).Should(BeTrue())
+		Ω(action("list").Responses[0].Stream).Should(BeFalse())
+		Ω(action("watch").Events).Should(Equal(&genir.EventStream{MediaType: "application/vnd.bottle.event", KeepAlive: 30}))
+		msgs := action("chat").Messages
+		Ω(msgs).ShouldNot(BeNil())
+		Ω(msgs.PingInterval).Should(Equal(10))
+		Ω(msgs.Inbound).Should(Equal(&genir.Attribute{Type: "user_type", Ref: "Chat"}))
+		Ω(msgs.Outbound).Should(Equal(&genir.Attribute{Type: "user_type", Ref: "Chat"}))
+	})
+
+	It("exports the long-running operations and the action webhooks", func() {
+		export := action("export")
+		Ω(export.LongRunning).Should(Equal(&genir.LongRunning{
+			RetryAfter:   5,
+			StatusAction: design.ShowOperationAction,
+			CancelAction: design.CancelOperationAction,
+		}))
+		Ω(export.Webhooks).Should(HaveLen(1))
+		Ω(export.Webhooks[0].Name).Should(Equal("bottle.exported"))
+		Ω(export.Webhooks[0].Headers.Attributes).Should(HaveKey("X-Tenant"))
+		Ω(action(design.ShowOperationAction)).ShouldNot(BeNil())
+	})
+
+	It("exports the hypermedia format of the media types", func() {
+		formats := make(map[string]string)
+		for _, mt := range doc.API.MediaTypes {
+			formats[mt.Identifier] = mt.Hypermedia
+		}
+		Ω(formats).Should(HaveKeyWithValue("application/vnd.bottle", "hal"))
+		Ω(formats).Should(HaveKeyWithValue("application/vnd.bottle.event", "none"))
+	})
+})
+
+var _ = Describe("Schema", func() {
+	var schema struct {
+		Definitions map[string]struct {
+			Properties map[string]interface{} `json:"properties"`
+			Required   []string               `json:"required"`
+		} `json:"definitions"`
+	}
+
+	BeforeEach(func() {
+		Ω(json.Unmarshal([]byte(genir.Schema), &schema)).ShouldNot(HaveOccurred())
+	})
+
+	It("describes all the fields of the IR types", func() {
+		types := []interface{}{
+			genir.APIDesign{}, genir.Resource{}, genir.Action{}, genir.Route{}, genir.Payload{},
+			genir.Response{}, genir.FileServer{}, genir.MediaType{}, genir.View{}, genir.Link{},
+			genir.UserType{}, genir.Attribute{}, genir.SecurityScheme{}, genir.Security{},
+			genir.Trait{}, genir.Encoding{}, genir.Contact{}, genir.License{}, genir.Docs{},
+			genir.Deprecation{}, genir.Location{}, genir.APIVersion{}, genir.Webhook{},
+			genir.GoTypeMapping{}, genir.Pagination{}, genir.Selection{}, genir.Criteria{},
+			genir.LongRunning{}, genir.EventStream{}, genir.Messages{},
+		}
+		for _, v := range types {
+			t := reflect.TypeOf(v)
+			def, ok := schema.Definitions[t.Name()]
+			Ω(ok).Should(BeTrue(), t.Name())
+			var fields, required []string
+			for i := 0; i < t.NumField(); i++ {
+				tag := strings.Split(t.Field(i).Tag.Get("json"), ",")
+				fields = append(fields, tag[0])
+				if len(tag) == 1 {
+					required = append(required, tag[0])
+				}
+			}
+			props := make([]string, 0, len(def.Properties))
+			for p := range def.Properties {
+				props = append(props, p)
+			}
+			Ω(props).Should(ConsistOf(fields), t.Name())
+			Ω(def.Required).Should(ConsistOf(required), t.Name())
+		}
+	})
+})
+
+var _ = Describe("Generate", func() {
+	var outDir string
+
+	BeforeEach(func() {
+		var err error
+		outDir, err = os.MkdirTemp("", "genir")
+		Ω(err).ShouldNot(HaveOccurred())
+		dslengine.Reset()
+		apidsl.API("cellar", nil)
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outDir)
+	})
+
+	It("writes the IR and its JSON Schema", func() {
+		g := genir.NewGenerator(genir.API(design.Design), genir.OutDir(outDir))
+		files, err := g.Generate()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(files).Should(Equal([]string{
+			filepath.Join(outDir, "ir", "design.json"),
+			filepath.Join(outDir, "ir", "design.schema.json"),
+		}))
+		b, err := os.ReadFile(files[0])
+		Ω(err).ShouldNot(HaveOccurred())
+		var doc genir.Document
+		Ω(json.Unmarshal(b, &doc)).ShouldNot(HaveOccurred())
+		Ω(doc.IRVersion).Should(Equal(genir.Version))
+		Ω(doc.API.Name).Should(Equal("cellar"))
+		b, err = os.ReadFile(files[1])
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(genir.Schema))
+	})
+})
@@ -0,0 +1,412 @@
+package genir
+
+// Schema is the JSON Schema of the version of the IR format produced by the generator, see
+// Version. The schema must be kept in sync with the IR types.
+const Schema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "goa design IR",
+  "description": "Intermediate representation of an evaluated goa API design, format version 1.1.",
+  "type": "object",
+  "required": ["ir_version", "api"],
+  "additionalProperties": false,
+  "properties": {
+    "ir_version": {"type": "string", "pattern": "^1\\.[0-9]+$"},
+    "api": {"$ref": "#/definitions/APIDesign"}
+  },
+  "definitions": {
+    "APIDesign": {
+      "type": "object",
+      "required": ["name", "resources", "media_types", "types"],
+      "additionalProperties": false,
+      "properties": {
+        "name": {"type": "string"},
+        "title": {"type": "string"},
+        "description": {"type": "string"},
+        "version": {"type": "string"},
+        "host": {"type": "string"},
+        "schemes": {"type": "array", "items": {"type": "string"}},
+        "base_path": {"type": "string"},
+        "params": {"$ref": "#/definitions/Attribute"},
+        "consumes": {"type": "array", "items": {"$ref": "#/definitions/Encoding"}},
+        "produces": {"type": "array", "items": {"$ref": "#/definitions/Encoding"}},
+        "terms_of_service": {"type": "string"},
+        "contact": {"$ref": "#/definitions/Contact"},
+        "license": {"$ref": "#/definitions/License"},
+        "docs": {"$ref": "#/definitions/Docs"},
+        "security_schemes": {"type": "array", "items": {"$ref": "#/definitions/SecurityScheme"}},
+        "security": {"$ref": "#/definitions/Security"},
+        "traits": {"type": "array", "items": {"$ref": "#/definitions/Trait"}},
+        "api_versions": {"type": "array", "items": {"$ref": "#/definitions/APIVersion"}},
+        "webhooks": {"type": "array", "items": {"$ref": "#/definitions/Webhook"}},
+        "hypermedia": {"type": "string", "enum": ["none", "hal", "jsonapi"]},
+        "go_type_mappings": {"type": "array", "items": {"$ref": "#/definitions/GoTypeMapping"}},
+        "resources": {"type": "array", "items": {"$ref": "#/definitions/Resource"}},
+        "media_types": {"type": "array", "items": {"$ref": "#/definitions/MediaType"}},
+        "types": {"type": "array", "items": {"$ref": "#/definitions/UserType"}},
+        "metadata": {"$ref": "#/definitions/Metadata"},
+        "location": {"$ref": "#/definitions/Location"}
+      }
+    },
+    "Resource": {
+      "type": "object",
+      "required": ["name", "full_path", "actions"],
+      "additionalProperties": false,
+      "properties": {
+        "name": {"type": "string"},
+        "description": {"type": "string"},
+        "base_path": {"type": "string"},
+        "full_path": {"type": "string"},
+        "parent": {"type": "string"},
+        "canonical_action": {"type": "string"},
+        "media_type": {"type": "string"},
+        "default_view": {"type": "string"},
+        "params": {"$ref": "#/definitions/Attribute"},
+        "headers": {"$ref": "#/definitions/Attribute"},
+        "security": {"$ref": "#/definitions/Security"},
+        "actions": {"type": "array", "items": {"$ref": "#/definitions/Action"}},
+        "file_servers": {"type": "array", "items": {"$ref": "#/definitions/FileServer"}},
+        "versions": {"type": "array", "items": {"type": "string"}},
+        "metadata": {"$ref": "#/definitions/Metadata"},
+        "location": {"$ref": "#/definitions/Location"}
+      }
+    },
+    "Action": {
+      "type": "object",
+      "required": ["name", "routes"],
+      "additionalProperties": false,
+      "properties": {
+        "name": {"type": "string"},
+        "description": {"type": "string"},
+        "docs": {"$ref": "#/definitions/Docs"},
+        "schemes": {"type": "array", "items": {"type": "string"}},
+        "routes": {"type": "array", "items": {"$ref": "#/definitions/Route"}},
+        "params": {"$ref": "#/definitions/Attribute"},
+        "headers": {"$ref": "#/definitions/Attribute"},
+        "payload": {"$ref": "#/definitions/Payload"},
+        "responses": {"type": "array", "items": {"$ref": "#/definitions/Response"}},
+        "security": {"$ref": "#/definitions/Security"},
+        "deprecation": {"$ref": "#/definitions/Deprecation"},
+        "versions": {"type": "array", "items": {"type": "string"}},
+        "pagination": {"$ref": "#/definitions/Pagination"},
+        "selection": {"$ref": "#/definitions/Selection"},
+        "criteria": {"$ref": "#/definitions/Criteria"},
+        "long_running": {"$ref": "#/definitions/LongRunning"},
+        "events": {"$ref": "#/definitions/EventStream"},
+        "messages": {"$ref": "#/definitions/Messages"},
+        "webhooks": {"type": "array", "items": {"$ref": "#/definitions/Webhook"}},
+        "metadata": {"$ref": "#/definitions/Metadata"},
+        "location": {"$ref": "#/definitions/Location"}
+      }
+    },
+    "Route": {
+      "type": "object",
+      "required": ["verb", "path", "full_path"],
+      "additionalProperties": false,
+      "properties": {
+        "verb": {"type": "string"},
+        "path": {"type": "string"},
+        "full_path": {"type": "string"},
+        "metadata": {"$ref": "#/definitions/Metadata"}
+      }
+    },
+    "Payload": {
+      "type": "object",
+      "required": ["type_name", "attribute"],
+      "additionalProperties": false,
+      "properties": {
+        "type_name": {"type": "string"},
+        "optional": {"type": "boolean"},
+        "multipart": {"type": "boolean"},
+        "attribute": {"$ref": "#/definitions/Attribute"}
+      }
+    },
+    "Response": {
+      "type": "object",
+      "required": ["name", "status"],
+      "additionalProperties": false,
+      "properties": {
+        "name": {"type": "string"},
+        "status": {"type": "integer"},
+        "description": {"type": "string"},
+        "media_type": {"type": "string"},
+        "view": {"type": "string"},
+        "headers": {"$ref": "#/definitions/Attribute"},
+        "stream": {"type": "boolean"},
+        "metadata": {"$ref": "#/definitions/Metadata"}
+      }
+    },
+    "FileServer": {
+      "type": "object",
+      "required": ["file_path", "request_path"],
+      "additionalProperties": false,
+      "properties": {
+        "file_path": {"type": "string"},
+        "request_path": {"type": "string"},
+        "description": {"type": "string"},
+        "security": {"$ref": "#/definitions/Security"},
+        "metadata": {"$ref": "#/definitions/Metadata"}
+      }
+    },
+    "MediaType": {
+      "type": "object",
+      "required": ["identifier", "type_name", "attribute", "hypermedia"],
+      "additionalProperties": false,
+      "properties": {
+        "identifier": {"type": "string"},
+        "type_name": {"type": "string"},
+        "content_type": {"type": "string"},
+        "attribute": {"$ref": "#/definitions/Attribute"},
+        "views": {"type": "array", "items": {"$ref": "#/definitions/View"}},
+        "links": {"type": "array", "items": {"$ref": "#/definitions/Link"}},
+        "hypermedia": {"type": "string", "enum": ["none", "hal", "jsonapi"]},
+        "resource_type": {"type": "string"},
+        "location": {"$ref": "#/definitions/Location"}
+      }
+    },
+    "View": {
+      "type": "object",
+      "required": ["name", "attributes"],
+      "additionalProperties": false,
+      "properties": {
+        "name": {"type": "string"},
+        "attributes": {"type": "array", "items": {"type": "string"}}
+      }
+    },
+    "Link": {
+      "type": "object",
+      "required": ["name", "view"],
+      "additionalProperties": false,
+      "properties": {
+        "name": {"type": "string"},
+        "view": {"type": "string"},
+        "media_type": {"type": "string"},
+        "uri_template": {"type": "string"}
+      }
+    },
+    "UserType": {
+      "type": "object",
+      "required": ["name", "attribute"],
+      "additionalProperties": false,
+      "properties": {
+        "name": {"type": "string"},
+        "attribute": {"$ref": "#/definitions/Attribute"},
+        "location": {"$ref": "#/definitions/Location"}
+      }
+    },
+    "Attribute": {
+      "type": "object",
+      "required": ["type"],
+      "additionalProperties": false,
+      "properties": {
+        "type": {
+          "type": "string",
+          "enum": ["boolean", "integer", "number", "string", "datetime", "uuid", "date", "timeofday",
+            "duration", "decimal", "any", "file", "array", "hash", "object", "user_type", "media_type"]
+        },
+        "ref": {"type": "string"},
+        "description": {"type": "string"},
+        "key": {"$ref": "#/definitions/Attribute"},
+        "elem": {"$ref": "#/definitions/Attribute"},
+        "attributes": {"type": "object", "additionalProperties": {"$ref": "#/definitions/Attribute"}},
+        "required": {"type": "array", "items": {"type": "string"}},
+        "enum": {"type": "array"},
+        "format": {"type": "string"},
+        "pattern": {"type": "string"},
+        "minimum": {"type": "number"},
+        "maximum": {"type": "number"},
+        "min_length": {"type": "integer"},
+        "max_length": {"type": "integer"},
+        "default": {},
+        "example": {},
+        "view": {"type": "string"},
+        "deprecation": {"$ref": "#/definitions/Deprecation"},
+        "metadata": {"$ref": "#/definitions/Metadata"},
+        "location": {"$ref": "#/definitions/Location"}
+      }
+    },
+    "SecurityScheme": {
+      "type": "object",
+      "required": ["name", "kind"],
+      "additionalProperties": false,
+      "properties": {
+        "name": {"type": "string"},
+        "kind": {"type": "string", "enum": ["basic", "api_key", "jwt", "oauth2"]},
+        "description": {"type": "string"},
+        "in": {"type": "string", "enum": ["header", "query"]},
+        "param_name": {"type": "string"},
+        "scopes": {"type": "object", "additionalProperties": {"type": "string"}},
+        "flow": {"type": "string"},
+        "token_url": {"type": "string"},
+        "authorization_url": {"type": "string"},
+        "metadata": {"$ref": "#/definitions/Metadata"},
+        "location": {"$ref": "#/definitions/Location"}
+      }
+    },
+    "Security": {
+      "type": "object",
+      "required": ["scheme"],
+      "additionalProperties": false,
+      "properties": {
+        "scheme": {"type": "string"},
+        "scopes": {"type": "array", "items": {"type": "string"}}
+      }
+    },
+    "Trait": {
+      "type": "object",
+      "required": ["name"],
+      "additionalProperties": false,
+      "properties": {
+        "name": {"type": "string"},
+        "location": {"$ref": "#/definitions/Location"}
+      }
+    },
+    "Encoding": {
+      "type": "object",
+      "required": ["mime_types"],
+      "additionalProperties": false,
+      "properties": {
+        "mime_types": {"type": "array", "items": {"type": "string"}},
+        "package_path": {"type": "string"},
+        "function": {"type": "string"}
+      }
+    },
+    "Contact": {
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "name": {"type": "string"},
+        "email": {"type": "string"},
+        "url": {"type": "string"}
+      }
+    },
+    "License": {
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "name": {"type": "string"},
+        "url": {"type": "string"}
+      }
+    },
+    "Docs": {
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "description": {"type": "string"},
+        "url": {"type": "string"}
+      }
+    },
+    "Deprecation": {
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "since": {"type": "string"},
+        "sunset": {"type": "string"},
+        "replacement": {"type": "string"}
+      }
+    },
+    "APIVersion": {
+      "type": "object",
+      "required": ["name"],
+      "additionalProperties": false,
+      "properties": {
+        "name": {"type": "string"},
+        "description": {"type": "string"},
+        "base_path": {"type": "string"},
+        "header": {"type": "string"},
+        "media_type_param": {"type": "string"}
+      }
+    },
+    "Webhook": {
+      "type": "object",
+      "required": ["name", "payload"],
+      "additionalProperties": false,
+      "properties": {
+        "name": {"type": "string"},
+        "description": {"type": "string"},
+        "payload": {"$ref": "#/definitions/Payload"},
+        "headers": {"$ref": "#/definitions/Attribute"}
+      }
+    },
+    "GoTypeMapping": {
+      "type": "object",
+      "required": ["type", "go_type"],
+      "additionalProperties": false,
+      "properties": {
+        "type": {"type": "string"},
+        "ref": {"type": "string"},
+        "go_type": {"type": "string"},
+        "package_path": {"type": "string"},
+        "function": {"type": "string"}
+      }
+    },
+    "Pagination": {
+      "type": "object",
+      "required": ["style", "default_limit", "max_limit"],
+      "additionalProperties": false,
+      "properties": {
+        "style": {"type": "string", "enum": ["cursor", "offset"]},
+        "default_limit": {"type": "integer"},
+        "max_limit": {"type": "integer"}
+      }
+    },
+    "Selection": {
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "view": {"type": "boolean"},
+        "fields": {"type": "boolean"}
+      }
+    },
+    "Criteria": {
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "filters": {"type": "array", "items": {"type": "string"}},
+        "sorts": {"type": "array", "items": {"type": "string"}}
+      }
+    },
+    "LongRunning": {
+      "type": "object",
+      "required": ["retry_after", "status_action", "cancel_action"],
+      "additionalProperties": false,
+      "properties": {
+        "retry_after": {"type": "integer"},
+        "status_action": {"type": "string"},
+        "cancel_action": {"type": "string"}
+      }
+    },
+    "EventStream": {
+      "type": "object",
+      "required": ["media_type", "keep_alive"],
+      "additionalProperties": false,
+      "properties": {
+        "media_type": {"type": "string"},
+        "keep_alive": {"type": "integer"}
+      }
+    },
+    "Messages": {
+      "type": "object",
+      "required": ["ping_interval"],
+      "additionalProperties": false,
+      "properties": {
+        "inbound": {"$ref": "#/definitions/Attribute"},
+        "outbound": {"$ref": "#/definitions/Attribute"},
+        "ping_interval": {"type": "integer"}
+      }
+    },
+    "Location": {
+      "type": "object",
+      "required": ["file", "line"],
+      "additionalProperties": false,
+      "properties": {
+        "file": {"type": "string"},
+        "line": {"type": "integer"}
+      }
+    },
+    "Metadata": {
+      "type": "object",
+      "additionalProperties": {"type": "array", "items": {"type": "string"}}
+    }
+  }
+}
+`
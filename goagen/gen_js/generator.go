@@ -40,10 +40,10 @@ type Generator struct {
 // Generate is the generator entry point called by the meta generator.
 func Generate() (files []string, err error) {
 	var (
-		outDir, ver  string
-		timeout      time.Duration
-		scheme, host string
-		noexample    bool
+		outDir, ver, templates string
+		timeout                time.Duration
+		scheme, host           string
+		noexample              bool
 	)
 
 	set := flag.NewFlagSet("client", flag.PanicOnError)
@@ -54,12 +54,16 @@ func Generate() (files []string, err error) {
 	set.StringVar(&host, "host", "", "")
 	set.StringVar(&ver, "version", "", "")
 	set.BoolVar(&noexample, "noexample", false, "")
+	set.StringVar(&templates, "templates", "", "")
 	set.Parse(os.Args[1:])
 
 	// First check compatibility
 	if err := codegen.CheckVersion(ver); err != nil {
 		return nil, err
 	}
+	if err := codegen.LoadTemplateOverrides(templates, "js"); err != nil {
+		return nil, err
+	}
 
 	// Now proceed
 	g := &Generator{OutDir: outDir, Timeout: timeout, Scheme: scheme, Host: host, NoExample: noexample, API: design.Design}
@@ -106,6 +110,35 @@ func (g *Generator) Generate() (_ []string, err error) {
 	}
 	g.genfiles = append(g.genfiles, g.OutDir)
 
+	if len(g.API.APIVersions) > 0 {
+		// Generate one client per API version
+		err = codegen.ForEachVersion(g.API, func(api *design.APIDefinition) error {
+			vg := *g
+			vg.API = api
+			vg.OutDir = filepath.Join(g.OutDir, api.SelectedVersion.Name)
+			vg.genfiles = nil
+			vg.NoExample = true // the example serves the client from /js
+			if err := os.MkdirAll(vg.OutDir, 0755); err != nil {
+				return err
+			}
+			err := vg.generate()
+			g.genfiles = append(g.genfiles, vg.genfiles...)
+			return err
+		})
+		if err != nil {
+			return
+		}
+		return g.genfiles, nil
+	}
+	if err = g.generate(); err != nil {
+		return
+	}
+
+	return g.genfiles, nil
+}
+
+// generate generates the client files in the output directory.
+func (g *Generator) generate() (err error) {
 	// Generate client.js
 	exampleAction, err := g.generateJS(filepath.Join(g.OutDir, "client.js"))
 	if err != nil {
@@ -129,7 +162,7 @@ func (g *Generator) Generate() (_ []string, err error) {
 		}
 	}
 
-	return g.genfiles, nil
+	return nil
 }
 
 func (g *Generator) generateJS(jsFile string) (_ *design.ActionDefinition, err error) {
@@ -146,7 +179,7 @@ func (g *Generator) generateJS(jsFile string) (_ *design.ActionDefinition, err e
 		"Scheme":  g.Scheme,
 		"Timeout": int64(g.Timeout / time.Millisecond),
 	}
-	if err = file.ExecuteTemplate("module", moduleT, nil, data); err != nil {
+	if err = file.ExecuteTemplate("js/moduleT", moduleT, nil, data); err != nil {
 		return
 	}
 
@@ -173,9 +206,9 @@ func (g *Generator) generateJS(jsFile string) (_ *design.ActionDefinition, err e
 			if exampleAction == nil && a.Routes[0].Verb == "GET" {
 				exampleAction = a
 			}
-			data := map[string]interface{}{"Action": a}
+			data := map[string]interface{}{"Action": a, "Version": g.API.SelectedVersion}
 			funcs := template.FuncMap{"params": params}
-			if err = file.ExecuteTemplate("jsFuncs", jsFuncsT, funcs, data); err != nil {
+			if err = file.ExecuteTemplate("js/jsFuncsT", jsFuncsT, funcs, data); err != nil {
 				return
 			}
 		}
@@ -231,7 +264,7 @@ func (g *Generator) generateIndexHTML(htmlFile string, exampleAction *design.Act
 		"ExampleFunc": exampleFunc,
 	}
 
-	return file.ExecuteTemplate("exampleHTML", exampleT, nil, data)
+	return file.ExecuteTemplate("js/exampleT", exampleT, nil, data)
 }
 
 func (g *Generator) generateAxiosJS() error {
@@ -267,7 +300,7 @@ func (g *Generator) generateExample() error {
 	g.genfiles = append(g.genfiles, controllerFile)
 
 	data := map[string]interface{}{"ServeDir": g.OutDir}
-	return file.ExecuteTemplate("examples", exampleCtrlT, nil, data)
+	return file.ExecuteTemplate("js/exampleCtrlT", exampleCtrlT, nil, data)
 }
 
 // Cleanup removes all the files generated by this generator during the last invokation of Generate.
@@ -292,6 +325,16 @@ func params(action *design.ActionDefinition) []string {
 	return params
 }
 
+// Register the JavaScript templates so that they may be overridden.
+func init() {
+	codegen.RegisterTemplates("js", map[string]string{
+		"moduleT":      moduleT,
+		"jsFuncsT":     jsFuncsT,
+		"exampleT":     exampleT,
+		"exampleCtrlT": exampleCtrlT,
+	})
+}
+
 const moduleT = `// This module exports functions that give access to the {{.API.Name}} API hosted at {{.API.Host}}.
 // It uses the axios javascript library for making the actual HTTP requests.
 define(['axios'] , function (axios) {
@@ -337,7 +380,9 @@ const jsFuncsT = `{{$params := params .Action}}
 {{end}}        {{$param}}: {{$param}}{{end}}
       },
 {{end}}{{if .Action.Payload}}    data: data,
-{{end}}      responseType: 'json'
+{{end}}{{with .Version}}{{if .Header}}      headers: {'{{.Header}}': '{{.Name}}'},
+{{else if .MediaTypeParam}}      headers: {'Accept': 'application/json; {{.MediaTypeParam}}={{.Name}}'},
+{{end}}{{end}}      responseType: 'json'
     };
     if (config) {
       cfg = merge(cfg, config);
@@ -0,0 +1,20 @@
+/*
+Package genlint provides a generator that checks an API design against style rules.
+
+The dslengine package only validates that a design is correct, the linter reports the parts of a
+correct design that do not follow common API design practices. The builtin rules are:
+
+	description     resources, actions, media types and types have a description
+	snake-case      attribute and parameter names are snake_case
+	error-response  actions define at least one error response
+	pluralization   resource names are consistently singular or plural
+	example         attributes of media types and types define an example
+	string-length   string attributes of requests have a maximum length, an enum or a format
+	security        actions that modify resources are secured
+
+Custom rules implement the Rule interface as well as one or more of the ResourceChecker,
+ActionChecker, MediaTypeChecker and UserTypeChecker interfaces and are registered with
+RegisterRule, usually by the init function of a package given to the "goagen lint" command with
+the --rules flag. The issues point at the DSL that defines the offending definitions.
+*/
+package genlint
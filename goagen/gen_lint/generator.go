@@ -0,0 +1,79 @@
+package genlint
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/shogo82148/goa-v1/design"
+	"github.com/shogo82148/goa-v1/goagen/codegen"
+)
+
+//NewGenerator returns an initialized instance of a design linter
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the design linter. It does not generate files, Generate returns the issues found
+// in the design instead.
+type Generator struct {
+	API     *design.APIDefinition // The API definition
+	Enable  []string              // Names of the only rules to check, all rules if empty
+	Disable []string              // Names of the rules not to check
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() ([]string, error) {
+	var ver, enable, disable string
+	set := flag.NewFlagSet("lint", flag.PanicOnError)
+	set.String("out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.StringVar(&enable, "enable", "", "")
+	set.StringVar(&disable, "disable", "", "")
+	set.String("rules", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{API: design.Design, Enable: splitList(enable), Disable: splitList(disable)}
+
+	return g.Generate()
+}
+
+// Generate checks the API definition and returns the issues found formatted as strings.
+func (g *Generator) Generate() ([]string, error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+	rules, err := SelectRules(g.Enable, g.Disable)
+	if err != nil {
+		return nil, err
+	}
+	issues := Lint(g.API, rules)
+	res := make([]string, len(issues))
+	for i, issue := range issues {
+		res[i] = issue.String()
+	}
+	return res, nil
+}
+
+// splitList splits the given comma separated list.
+func splitList(list string) []string {
+	var res []string
+	for _, e := range strings.Split(list, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			res = append(res, e)
+		}
+	}
+	return res
+}
@@ -0,0 +1,269 @@
+package genlint
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/shogo82148/goa-v1/design"
+	"github.com/shogo82148/goa-v1/dslengine"
+)
+
+type (
+	// Rule is a style rule. Rules implement one or more of the ResourceChecker,
+	// ActionChecker, MediaTypeChecker and UserTypeChecker interfaces to check the
+	// corresponding definitions.
+	Rule interface {
+		// Name is the name used to enable or disable the rule, e.g. "snake-case".
+		Name() string
+		// Description describes what the rule checks.
+		Description() string
+	}
+
+	// ResourceChecker is implemented by the rules that check resources.
+	ResourceChecker interface {
+		CheckResource(r *design.ResourceDefinition, rep *Reporter)
+	}
+
+	// ActionChecker is implemented by the rules that check actions.
+	ActionChecker interface {
+		CheckAction(a *design.ActionDefinition, rep *Reporter)
+	}
+
+	// MediaTypeChecker is implemented by the rules that check media types.
+	MediaTypeChecker interface {
+		CheckMediaType(mt *design.MediaTypeDefinition, rep *Reporter)
+	}
+
+	// UserTypeChecker is implemented by the rules that check user types.
+	UserTypeChecker interface {
+		CheckUserType(ut *design.UserTypeDefinition, rep *Reporter)
+	}
+
+	// Reporter records the issues found by a rule in a definition.
+	Reporter struct {
+		rule   Rule
+		def    dslengine.Definition
+		issues []*Issue
+	}
+
+	// Issue describes a style issue.
+	Issue struct {
+		// Rule is the name of the rule that reported the issue.
+		Rule string `json:"rule"`
+		// File is the name of the file containing the DSL of the definition if known.
+		File string `json:"file,omitempty"`
+		// Line is the line of the DSL of the definition in File.
+		Line int `json:"line,omitempty"`
+		// Context describes the definition, e.g. `resource "bottle" action "show"`.
+		Context string `json:"context"`
+		// Message describes the issue.
+		Message string `json:"message"`
+	}
+)
+
+// registered rules indexed by name
+var rules = make(map[string]Rule)
+
+// RegisterRule registers the given rule so that it gets checked by the linter. Custom rules are
+// usually registered by the init function of their package.
+func RegisterRule(r Rule) {
+	if _, ok := rules[r.Name()]; ok {
+		fmt.Fprintf(os.Stderr, "goagen: duplicate lint rule %s", r.Name())
+		os.Exit(1)
+	}
+	rules[r.Name()] = r
+}
+
+// Rules returns the registered rules sorted by name.
+func Rules() []Rule {
+	res := make([]Rule, 0, len(rules))
+	for _, r := range rules {
+		res = append(res, r)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name() < res[j].Name() })
+	return res
+}
+
+// SelectRules returns the registered rules whose name is listed in enable, or all the registered
+// rules if enable is empty, and whose name is not listed in disable.
+func SelectRules(enable, disable []string) ([]Rule, error) {
+	for _, n := range append(append([]string{}, enable...), disable...) {
+		if _, ok := rules[n]; !ok {
+			return nil, fmt.Errorf("unknown lint rule %q", n)
+		}
+	}
+	var res []Rule
+	for _, r := range Rules() {
+		if len(enable) > 0 && !contains(enable, r.Name()) {
+			continue
+		}
+		if contains(disable, r.Name()) {
+			continue
+		}
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+// Lint checks the given API definition against the given rules and returns the issues sorted by
+// location. The media types and user types that are not defined in user code such as the
+// builtin error media type are not checked, neither are the actions and media types generated by
+// the DSL such as the operation actions added by LongRunning or the media types created by
+// CollectionOf.
+func Lint(api *design.APIDefinition, rules []Rule) []*Issue {
+	var issues []*Issue
+	check := func(def dslengine.Definition, fn func(Rule, *Reporter)) {
+		for _, r := range rules {
+			rep := &Reporter{rule: r, def: def}
+			fn(r, rep)
+			issues = append(issues, rep.issues...)
+		}
+	}
+	api.IterateResources(func(res *design.ResourceDefinition) error {
+		check(res, func(r Rule, rep *Reporter) {
+			if c, ok := r.(ResourceChecker); ok {
+				c.CheckResource(res, rep)
+			}
+		})
+		return res.IterateActions(func(a *design.ActionDefinition) error {
+			if design.IsGenerated(a.Metadata) {
+				return nil
+			}
+			check(a, func(r Rule, rep *Reporter) {
+				if c, ok := r.(ActionChecker); ok {
+					c.CheckAction(a, rep)
+				}
+			})
+			return nil
+		})
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		if file, _ := dslengine.Location(mt); file == "" || design.IsGenerated(mt.Metadata) {
+			return nil
+		}
+		check(mt, func(r Rule, rep *Reporter) {
+			if c, ok := r.(MediaTypeChecker); ok {
+				c.CheckMediaType(mt, rep)
+			}
+		})
+		return nil
+	})
+	api.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		if file, _ := dslengine.Location(ut); file == "" {
+			return nil
+		}
+		check(ut, func(r Rule, rep *Reporter) {
+			if c, ok := r.(UserTypeChecker); ok {
+				c.CheckUserType(ut, rep)
+			}
+		})
+		return nil
+	})
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].File != issues[j].File {
+			return issues[i].File < issues[j].File
+		}
+		return issues[i].Line < issues[j].Line
+	})
+	return issues
+}
+
+// Report records an issue with the definition being checked.
+func (r *Reporter) Report(format string, args ...interface{}) {
+	r.ReportAt(r.def, format, args...)
+}
+
+// ReportAt records an issue with the definition being checked located at the DSL of the given
+// definition, typically one of its attributes. The issue is located at the DSL of the definition
+// being checked if the location of def is unknown.
+func (r *Reporter) ReportAt(def dslengine.Definition, format string, args ...interface{}) {
+	file, line := dslengine.Location(def)
+	if file == "" {
+		file, line = dslengine.Location(r.def)
+	}
+	r.issues = append(r.issues, &Issue{
+		Rule:    r.rule.Name(),
+		File:    file,
+		Line:    line,
+		Context: r.def.Context(),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// String returns the issue formatted like the DSL errors.
+func (i *Issue) String() string {
+	msg := fmt.Sprintf("%s: %s (%s)", i.Context, i.Message, i.Rule)
+	if i.File == "" {
+		return msg
+	}
+	return fmt.Sprintf("[%s:%d] %s", i.File, i.Line, msg)
+}
+
+// WalkAttributes calls fn with each attribute of the object, array or hash described by att and
+// recursively with the attributes of their inline objects, arrays and hashes. The path of the
+// attributes is relative to att, e.g. "owner.name" or "tags[]". name is the name of the attribute
+// if it is an object attribute, the empty string if it is an array element or hash key or value.
+// WalkAttributes does not walk through user types and media types nor through the attributes
+// generated by the DSL such as the query string parameters added by Paginated.
+func WalkAttributes(att *design.AttributeDefinition, fn func(path, name string, att *design.AttributeDefinition)) {
+	walkAttributes("", att, fn)
+}
+
+func walkAttributes(path string, att *design.AttributeDefinition, fn func(path, name string, att *design.AttributeDefinition)) {
+	if att == nil {
+		return
+	}
+	switch t := att.Type.(type) {
+	case design.Object:
+		names := make([]string, 0, len(t))
+		for n := range t {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			if design.IsGenerated(t[n].Metadata) {
+				continue
+			}
+			p := n
+			if path != "" {
+				p = path + "." + n
+			}
+			fn(p, n, t[n])
+			walkAttributes(p, t[n], fn)
+		}
+	case *design.Array:
+		p := path + "[]"
+		fn(p, "", t.ElemType)
+		walkAttributes(p, t.ElemType, fn)
+	case *design.Hash:
+		for _, e := range []struct {
+			p   string
+			att *design.AttributeDefinition
+		}{{path + "[key]", t.KeyType}, {path + "[value]", t.ElemType}} {
+			fn(e.p, "", e.att)
+			walkAttributes(e.p, e.att, fn)
+		}
+	}
+}
+
+func contains(vals []string, val string) bool {
+	for _, v := range vals {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
+
+// joinPath prefixes the given attribute path with the given prefix.
+func joinPath(prefix, path string) string {
+	if prefix == "" {
+		return path
+	}
+	if strings.HasPrefix(path, "[") {
+		return prefix + path
+	}
+	return prefix + "." + path
+}
@@ -0,0 +1,218 @@
+package genlint_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1/design"
+	"github.com/shogo82148/goa-v1/design/apidsl"
+	"github.com/shogo82148/goa-v1/dslengine"
+	genlint "github.com/shogo82148/goa-v1/goagen/gen_lint"
+)
+
+var _ = Describe("Lint", func() {
+	var dsl func()
+	var enable []string
+	var issues []*genlint.Issue
+
+	BeforeEach(func() {
+		enable = nil
+	})
+
+	JustBeforeEach(func() {
+		dslengine.Reset()
+		dsl()
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		rules, err := genlint.SelectRules(enable, nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		issues = genlint.Lint(design.Design, rules)
+	})
+
+	// messages returns the context and message of the issues found by the given rule.
+	messages := func(rule string) []string {
+		var res []string
+		for _, i := range issues {
+			if i.Rule == rule {
+				res = append(res, i.Context+": "+i.Message)
+			}
+		}
+		return res
+	}
+
+	Context("with a design following the rules", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				apidsl.API("cellar", func() {
+					apidsl.Security(apidsl.BasicAuthSecurity("basic"))
+				})
+				bottle := apidsl.MediaType("application/vnd.bottle", func() {
+					apidsl.Description("A bottle of wine")
+					apidsl.Attributes(func() {
+						apidsl.Attribute("bottle_id", design.Integer, func() {
+							apidsl.Example(1)
+						})
+					})
+					apidsl.View("default", func() {
+						apidsl.Attribute("bottle_id")
+					})
+				})
+				apidsl.Resource("bottle", func() {
+					apidsl.Description("Bottles")
+					apidsl.Action("create", func() {
+						apidsl.Description("Create a bottle")
+						apidsl.Routing(apidsl.POST("/bottles"))
+						apidsl.Payload(func() {
+							apidsl.Attribute("name", design.String, func() {
+								apidsl.MaxLength(100)
+							})
+						})
+						apidsl.Response(design.Created, bottle)
+						apidsl.Response(design.BadRequest, design.ErrorMedia)
+					})
+				})
+			}
+		})
+
+		It("reports no issue", func() {
+			Ω(issues).Should(BeEmpty())
+		})
+	})
+
+	Context("with a design breaking the rules", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				apidsl.API("cellar", nil)
+				bottle := apidsl.MediaType("application/vnd.bottle", func() {
+					apidsl.Attributes(func() {
+						apidsl.Attribute("bottleID", design.Integer)
+					})
+					apidsl.View("default", func() {
+						apidsl.Attribute("bottleID")
+					})
+				})
+				apidsl.Resource("bottles", func() {
+					apidsl.Action("create", func() {
+						apidsl.Routing(apidsl.POST("/bottles"))
+						apidsl.Payload(func() {
+							apidsl.Attribute("name", design.String)
+						})
+						apidsl.Response(design.Created, bottle)
+					})
+				})
+				apidsl.Resource("account", func() {})
+				apidsl.Resource("user", func() {})
+			}
+		})
+
+		It("reports missing descriptions", func() {
+			Ω(messages("description")).Should(ConsistOf(
+				`resource "bottles": missing description`,
+				`resource "bottles" action "create": missing description`,
+				`resource "account": missing description`,
+				`resource "user": missing description`,
+				`type "Bottle": missing description`,
+			))
+		})
+
+		It("reports attribute names that are not snake case", func() {
+			Ω(messages("snake-case")).Should(ConsistOf(`type "Bottle": attribute "bottleID" is not snake_case`))
+		})
+
+		It("reports actions without error responses", func() {
+			Ω(messages("error-response")).Should(ConsistOf(`resource "bottles" action "create": no error response`))
+		})
+
+		It("reports inconsistent pluralization", func() {
+			Ω(messages("pluralization")).Should(ConsistOf(`resource "bottles": resource name is plural while most resource names are singular`))
+		})
+
+		It("reports missing examples", func() {
+			Ω(messages("example")).Should(ConsistOf(`type "Bottle": attribute "bottleID" has no example`))
+		})
+
+		It("reports unbounded strings", func() {
+			Ω(messages("string-length")).Should(ConsistOf(`resource "bottles" action "create": string attribute "payload.name" has no maximum length`))
+		})
+
+		It("reports unsecured mutating actions", func() {
+			Ω(messages("security")).Should(ConsistOf(`resource "bottles" action "create": POST route is not secured`))
+		})
+
+		It("locates the issues in the DSL", func() {
+			for _, i := range issues {
+				Ω(i.File).Should(Equal("lint_test.go"))
+				Ω(i.Line).Should(BeNumerically(">", 0))
+			}
+		})
+
+		Context("with enabled rules", func() {
+			BeforeEach(func() {
+				enable = []string{"security"}
+			})
+
+			It("only checks the enabled rules", func() {
+				Ω(issues).Should(HaveLen(1))
+				Ω(issues[0].Rule).Should(Equal("security"))
+			})
+		})
+	})
+
+	Context("with definitions generated by the DSL", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				apidsl.API("cellar", func() {
+					apidsl.Security(apidsl.BasicAuthSecurity("basic"))
+				})
+				bottle := apidsl.MediaType("application/vnd.bottle", func() {
+					apidsl.Description("A bottle of wine")
+					apidsl.Attributes(func() {
+						apidsl.Attribute("name", design.String, func() {
+							apidsl.MaxLength(100)
+							apidsl.Example("Chateau")
+						})
+					})
+					apidsl.View("default", func() {
+						apidsl.Attribute("name")
+					})
+				})
+				apidsl.Resource("bottle", func() {
+					apidsl.Description("Bottles")
+					apidsl.Action("list", func() {
+						apidsl.Description("List bottles")
+						apidsl.Routing(apidsl.GET("/bottles"))
+						apidsl.Paginated(design.CursorPagination, 20, 100)
+						apidsl.Selectable(design.FieldsSelector)
+						apidsl.Filterable("name")
+						apidsl.Response(design.OK, apidsl.CollectionOf(bottle))
+						apidsl.Response(design.BadRequest, design.ErrorMedia)
+					})
+					apidsl.Action("export", func() {
+						apidsl.Description("Export bottles")
+						apidsl.Routing(apidsl.POST("/bottles/export"))
+						apidsl.LongRunning(5)
+						apidsl.Response(design.BadRequest, design.ErrorMedia)
+					})
+				})
+			}
+		})
+
+		It("does not check them", func() {
+			Ω(issues).Should(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("SelectRules", func() {
+	It("excludes disabled rules", func() {
+		rules, err := genlint.SelectRules(nil, []string{"description"})
+		Ω(err).ShouldNot(HaveOccurred())
+		for _, r := range rules {
+			Ω(r.Name()).ShouldNot(Equal("description"))
+		}
+		Ω(rules).Should(HaveLen(len(genlint.Rules()) - 1))
+	})
+
+	It("rejects unknown rules", func() {
+		_, err := genlint.SelectRules([]string{"unknown"}, nil)
+		Ω(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,27 @@
+package genlint
+
+import "github.com/shogo82148/goa-v1/design"
+
+//Option a generator option definition
+type Option func(*Generator)
+
+//API The API definition
+func API(API *design.APIDefinition) Option {
+	return func(g *Generator) {
+		g.API = API
+	}
+}
+
+//Enable Names of the only rules to check, all rules are checked if empty
+func Enable(rules ...string) Option {
+	return func(g *Generator) {
+		g.Enable = rules
+	}
+}
+
+//Disable Names of the rules not to check
+func Disable(rules ...string) Option {
+	return func(g *Generator) {
+		g.Disable = rules
+	}
+}
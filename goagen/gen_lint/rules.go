@@ -0,0 +1,242 @@
+package genlint
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/shogo82148/goa-v1/design"
+)
+
+func init() {
+	RegisterRule(descriptionRule{})
+	RegisterRule(snakeCaseRule{})
+	RegisterRule(errorResponseRule{})
+	RegisterRule(pluralizationRule{})
+	RegisterRule(exampleRule{})
+	RegisterRule(stringLengthRule{})
+	RegisterRule(securityRule{})
+}
+
+type (
+	// descriptionRule checks that definitions have a description.
+	descriptionRule struct{}
+
+	// snakeCaseRule checks that attribute names are snake_case.
+	snakeCaseRule struct{}
+
+	// errorResponseRule checks that actions define error responses.
+	errorResponseRule struct{}
+
+	// pluralizationRule checks that resource names are consistently singular or plural.
+	pluralizationRule struct{}
+
+	// exampleRule checks that the attributes of media types and user types have examples.
+	exampleRule struct{}
+
+	// stringLengthRule checks that the string attributes of requests are bounded.
+	stringLengthRule struct{}
+
+	// securityRule checks that the actions modifying resources are secured.
+	securityRule struct{}
+)
+
+var snakeCaseRegex = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+func (descriptionRule) Name() string { return "description" }
+
+func (descriptionRule) Description() string {
+	return "resources, actions, media types and types have a description"
+}
+
+func (descriptionRule) CheckResource(r *design.ResourceDefinition, rep *Reporter) {
+	if r.Description == "" {
+		rep.Report("missing description")
+	}
+}
+
+func (descriptionRule) CheckAction(a *design.ActionDefinition, rep *Reporter) {
+	if a.Description == "" {
+		rep.Report("missing description")
+	}
+}
+
+func (descriptionRule) CheckMediaType(mt *design.MediaTypeDefinition, rep *Reporter) {
+	if mt.Description == "" {
+		rep.Report("missing description")
+	}
+}
+
+func (descriptionRule) CheckUserType(ut *design.UserTypeDefinition, rep *Reporter) {
+	if ut.Description == "" {
+		rep.Report("missing description")
+	}
+}
+
+func (snakeCaseRule) Name() string { return "snake-case" }
+
+func (snakeCaseRule) Description() string {
+	return "attribute and parameter names are snake_case"
+}
+
+func (r snakeCaseRule) CheckAction(a *design.ActionDefinition, rep *Reporter) {
+	r.check("params", a.Params, rep)
+	r.check("payload", inlinePayload(a), rep)
+}
+
+func (r snakeCaseRule) CheckMediaType(mt *design.MediaTypeDefinition, rep *Reporter) {
+	r.check("", mt.AttributeDefinition, rep)
+}
+
+func (r snakeCaseRule) CheckUserType(ut *design.UserTypeDefinition, rep *Reporter) {
+	r.check("", ut.AttributeDefinition, rep)
+}
+
+func (snakeCaseRule) check(prefix string, att *design.AttributeDefinition, rep *Reporter) {
+	WalkAttributes(att, func(path, name string, att *design.AttributeDefinition) {
+		if name != "" && !snakeCaseRegex.MatchString(name) {
+			rep.ReportAt(att, "attribute %q is not snake_case", joinPath(prefix, path))
+		}
+	})
+}
+
+func (errorResponseRule) Name() string { return "error-response" }
+
+func (errorResponseRule) Description() string {
+	return "actions define at least one error response"
+}
+
+func (errorResponseRule) CheckAction(a *design.ActionDefinition, rep *Reporter) {
+	for _, r := range a.Responses {
+		if r.Status >= 400 {
+			return
+		}
+	}
+	rep.Report("no error response")
+}
+
+func (pluralizationRule) Name() string { return "pluralization" }
+
+func (pluralizationRule) Description() string {
+	return "resource names are consistently singular or plural"
+}
+
+func (pluralizationRule) CheckResource(r *design.ResourceDefinition, rep *Reporter) {
+	var plurals, singulars int
+	for _, res := range design.Design.Resources {
+		if isPlural(res.Name) {
+			plurals++
+		} else {
+			singulars++
+		}
+	}
+	// Singular names are the goa convention, they win ties.
+	if plural := plurals > singulars; isPlural(r.Name) != plural {
+		if plural {
+			rep.Report("resource name is singular while most resource names are plural")
+		} else {
+			rep.Report("resource name is plural while most resource names are singular")
+		}
+	}
+}
+
+func (exampleRule) Name() string { return "example" }
+
+func (exampleRule) Description() string {
+	return "attributes of media types and types define an example"
+}
+
+func (r exampleRule) CheckMediaType(mt *design.MediaTypeDefinition, rep *Reporter) {
+	r.check(mt.AttributeDefinition, rep)
+}
+
+func (r exampleRule) CheckUserType(ut *design.UserTypeDefinition, rep *Reporter) {
+	r.check(ut.AttributeDefinition, rep)
+}
+
+func (exampleRule) check(att *design.AttributeDefinition, rep *Reporter) {
+	if design.Design.NoExamples {
+		return
+	}
+	WalkAttributes(att, func(path, name string, att *design.AttributeDefinition) {
+		if name != "" && att.Type.IsPrimitive() && !att.HasExplicitExample() {
+			rep.ReportAt(att, "attribute %q has no example", path)
+		}
+	})
+}
+
+func (stringLengthRule) Name() string { return "string-length" }
+
+func (stringLengthRule) Description() string {
+	return "string attributes of requests have a maximum length, an enum or a format"
+}
+
+func (r stringLengthRule) CheckAction(a *design.ActionDefinition, rep *Reporter) {
+	r.check("params", a.Params, rep)
+	r.check("headers", a.Headers, rep)
+	r.check("payload", inlinePayload(a), rep)
+}
+
+func (r stringLengthRule) CheckUserType(ut *design.UserTypeDefinition, rep *Reporter) {
+	r.check("", ut.AttributeDefinition, rep)
+}
+
+func (stringLengthRule) check(prefix string, att *design.AttributeDefinition, rep *Reporter) {
+	WalkAttributes(att, func(path, _ string, att *design.AttributeDefinition) {
+		if att.Type != design.String {
+			return
+		}
+		if v := att.Validation; v != nil && (v.MaxLength != nil || len(v.Values) > 0 || v.Format != "") {
+			return
+		}
+		rep.ReportAt(att, "string attribute %q has no maximum length", joinPath(prefix, path))
+	})
+}
+
+func (securityRule) Name() string { return "security" }
+
+func (securityRule) Description() string {
+	return "actions that modify resources are secured"
+}
+
+func (securityRule) CheckAction(a *design.ActionDefinition, rep *Reporter) {
+	if a.Security != nil {
+		return
+	}
+	for _, r := range a.Routes {
+		switch r.Verb {
+		case "POST", "PUT", "PATCH", "DELETE":
+			rep.Report("%s route is not secured", r.Verb)
+			return
+		}
+	}
+}
+
+// inlinePayload returns the payload of the given action if it is defined inline, nil if the
+// action has no payload or if it uses a user type checked on its own.
+func inlinePayload(a *design.ActionDefinition) *design.AttributeDefinition {
+	if a.Payload == nil {
+		return nil
+	}
+	if ut, ok := design.Design.Types[a.Payload.TypeName]; ok && ut == a.Payload {
+		return nil
+	}
+	return a.Payload.AttributeDefinition
+}
+
+// isPlural returns true if the last word of the given name looks plural.
+func isPlural(name string) bool {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool { return r == '_' || r == '-' || r == ' ' })
+	if len(words) == 0 {
+		return false
+	}
+	w := words[len(words)-1]
+	if !strings.HasSuffix(w, "s") {
+		return false
+	}
+	for _, suffix := range []string{"ss", "us", "is"} {
+		if strings.HasSuffix(w, suffix) {
+			return false
+		}
+	}
+	return true
+}
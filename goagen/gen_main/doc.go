@@ -4,6 +4,9 @@ This generator generates the code for a basic "main" package and is mainly inten
 bootstrap new applications.
 The generator creates a main.go file and one file per resource listed in the API metadata.
 If a file already exists it skips its creation unless the flag --force is provided on the command
-line in which case it overrides the content of existing files.
+line in which case it overrides the content of existing files. With the flag --regen the
+generator adds the methods of the new actions to the existing controller files instead and
+flags the methods of the actions that were removed from the design with a comment, leaving the
+rest of the code untouched.
 */
 package genmain
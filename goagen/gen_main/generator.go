@@ -1,17 +1,12 @@
 package genmain
 
 import (
-	"bufio"
 	"flag"
 	"fmt"
-	"go/ast"
-	"go/parser"
-	"go/token"
 	"net"
 	"os"
 	"path"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"text/template"
 
@@ -45,8 +40,8 @@ type Generator struct {
 // Generate is the generator entry point called by the meta generator.
 func Generate() (files []string, err error) {
 	var (
-		outDir, toolDir, designPkg, target, ver string
-		force, notool, regen                    bool
+		outDir, toolDir, designPkg, target, ver, templates string
+		force, notool, regen                               bool
 	)
 
 	set := flag.NewFlagSet("main", flag.PanicOnError)
@@ -59,11 +54,16 @@ func Generate() (files []string, err error) {
 	set.BoolVar(&force, "force", false, "")
 	set.BoolVar(&regen, "regen", false, "")
 	set.Bool("notest", false, "")
+	set.StringVar(&templates, "templates", "", "")
+	set.String("app-pkg", "", "")
 	set.Parse(os.Args[1:])
 
 	if err := codegen.CheckVersion(ver); err != nil {
 		return nil, err
 	}
+	if err := codegen.LoadTemplateOverrides(templates, "main"); err != nil {
+		return nil, err
+	}
 
 	target = codegen.Goify(target, false)
 	g := &Generator{OutDir: outDir, DesignPkg: designPkg, Target: target, Force: force, Regen: regen, API: design.Design}
@@ -71,80 +71,46 @@ func Generate() (files []string, err error) {
 	return g.Generate()
 }
 
-func extractControllerBody(filename string) (map[string]string, []*ast.ImportSpec, error) {
-	// First check if a file is there. If not, return empty results to let generation proceed.
-	if _, e := os.Stat(filename); e != nil {
-		return map[string]string{}, []*ast.ImportSpec{}, nil
-	}
-	fset := token.NewFileSet()
-	pfile, err := parser.ParseFile(fset, filename, nil, parser.ImportsOnly)
-	if err != nil {
-		return nil, nil, err
-	}
-	f, err := os.Open(filename)
-	if err != nil {
-		return nil, nil, err
-	}
-	defer f.Close()
-	var (
-		inBlock bool
-		block   []string
-	)
-	actionImpls := map[string]string{}
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		line := scanner.Text()
-		match := linePattern.FindStringSubmatch(line)
-		if len(match) == 3 {
-			switch match[2] {
-			case "start":
-				inBlock = true
-			case "end":
-				inBlock = false
-				actionImpls[match[1]] = strings.Join(block, "\n")
-				block = []string{}
-			}
-			continue
-		}
-		if inBlock {
-			block = append(block, line)
-		}
-	}
-	if err := scanner.Err(); err != nil {
-		return nil, nil, err
-	}
-	return actionImpls, pfile.Imports, nil
-}
-
 // GenerateController generates the controller corresponding to the given
-// resource and returns the generated filename.
+// resource and returns the generated filename. If regen is true and the file
+// already exists, the methods of the new actions are added to it instead, see
+// mergeController.
 func GenerateController(force, regen bool, appPkg, outDir, pkg, name string, r *design.ResourceDefinition) (filename string, err error) {
 	filename = filepath.Join(outDir, codegen.SnakeCase(name)+".go")
-	var (
-		actionImpls      map[string]string
-		extractedImports []*ast.ImportSpec
-	)
-	if regen {
-		actionImpls, extractedImports, err = extractControllerBody(filename)
-		if err != nil {
-			return "", err
-		}
-		os.Remove(filename)
-	}
 	if force {
 		os.Remove(filename)
 	}
 	if _, e := os.Stat(filename); e == nil {
-		return "", nil
+		if !regen {
+			return "", nil
+		}
+		// Generate the controller next to the existing file, the go tool ignores files
+		// starting with a dot.
+		generated := filepath.Join(outDir, "."+codegen.SnakeCase(name)+".go")
+		defer os.Remove(generated)
+		if err = generateController(generated, appPkg, outDir, pkg, r); err != nil {
+			return "", err
+		}
+		if err = mergeController(filename, generated); err != nil {
+			return "", err
+		}
+		return filename, nil
 	}
 	if err = os.MkdirAll(outDir, 0755); err != nil {
 		return "", err
 	}
+	if err = generateController(filename, appPkg, outDir, pkg, r); err != nil {
+		return "", err
+	}
+	return
+}
 
+// generateController writes the controller corresponding to the given resource to filename.
+func generateController(filename, appPkg, outDir, pkg string, r *design.ResourceDefinition) (err error) {
 	var file *codegen.SourceFile
 	file, err = codegen.SourceFileFor(filename)
 	if err != nil {
-		return "", err
+		return err
 	}
 	defer func() {
 		file.Close()
@@ -161,7 +127,7 @@ func GenerateController(force, regen bool, appPkg, outDir, pkg, name string, r *
 	} else {
 		imp, err = codegen.PackagePath(outDir)
 		if err != nil {
-			return "", err
+			return err
 		}
 		imp = path.Join(filepath.ToSlash(imp), appPkg)
 	}
@@ -172,36 +138,23 @@ func GenerateController(force, regen bool, appPkg, outDir, pkg, name string, r *
 		codegen.SimpleImport(imp),
 		codegen.SimpleImport("golang.org/x/net/websocket"),
 	}
-	for _, imp := range extractedImports {
-		// This may introduce duplicate imports of the defaults, but
-		// that'll get worked out by Format later.
-		var cgimp *codegen.ImportSpec
-		path := strings.Trim(imp.Path.Value, `"`)
-		if imp.Name != nil {
-			cgimp = codegen.NewImport(imp.Name.Name, path)
-		} else {
-			cgimp = codegen.SimpleImport(path)
-		}
-		imports = append(imports, cgimp)
-	}
 
-	funcs := funcMap(pkgName, actionImpls)
+	funcs := funcMap(pkgName)
 	if err = file.WriteHeader("", pkg, imports); err != nil {
-		return "", err
+		return err
 	}
-	if err = file.ExecuteTemplate("controller", ctrlT, funcs, r); err != nil {
-		return "", err
+	if err = file.ExecuteTemplate("main/ctrlT", ctrlT, funcs, r); err != nil {
+		return err
 	}
-	err = r.IterateActions(func(a *design.ActionDefinition) error {
+	return r.IterateActions(func(a *design.ActionDefinition) error {
 		if a.WebSocket() {
-			return file.ExecuteTemplate("actionWS", actionWST, funcs, a)
+			return file.ExecuteTemplate("main/actionWST", actionWST, funcs, a)
+		}
+		if a.Events != nil {
+			return file.ExecuteTemplate("main/actionEventsT", actionEventsT, funcs, a)
 		}
-		return file.ExecuteTemplate("action", actionT, funcs, a)
+		return file.ExecuteTemplate("main/actionT", actionT, funcs, a)
 	})
-	if err != nil {
-		return "", err
-	}
-	return
 }
 
 // Generate produces the skeleton main.
@@ -234,7 +187,7 @@ func (g *Generator) Generate() (_ []string, err error) {
 		if err = os.MkdirAll(g.OutDir, 0755); err != nil {
 			return nil, err
 		}
-		if err = g.createMainFile(mainFile, funcMap(g.Target, nil)); err != nil {
+		if err = g.createMainFile(mainFile, funcMap(g.Target)); err != nil {
 			return nil, err
 		}
 	}
@@ -302,7 +255,7 @@ func (g *Generator) createMainFile(mainFile string, funcs template.FuncMap) (err
 		"Name": g.API.Name,
 		"API":  g.API,
 	}
-	err = file.ExecuteTemplate("main", mainT, funcs, data)
+	err = file.ExecuteTemplate("main/mainT", mainT, funcs, data)
 	return
 }
 
@@ -369,29 +322,67 @@ func okResp(a *design.ActionDefinition, appPkg string) map[string]interface{} {
 	}
 }
 
+// eventRef returns the code that initializes an event streamed by the given action, the empty
+// string if the event media type cannot be found.
+func eventRef(a *design.ActionDefinition, appPkg string) string {
+	mt := a.Events.EventType()
+	if mt == nil {
+		return ""
+	}
+	pmt, _, err := mt.Project(design.DefaultView)
+	if err != nil {
+		return ""
+	}
+	return typeInit(pmt, appPkg)
+}
+
+// messageRef returns the code that initializes a websocket message of the given type, the empty
+// string if t is nil.
+func messageRef(t design.DataType, appPkg string) string {
+	if t == nil {
+		return ""
+	}
+	if mt, ok := t.(*design.MediaTypeDefinition); ok {
+		pmt, _, err := mt.Project(design.DefaultView)
+		if err != nil {
+			return ""
+		}
+		t = pmt
+	}
+	return typeInit(t, appPkg)
+}
+
+// typeInit returns the code that initializes a value of the given user type or media type.
+func typeInit(t design.DataType, appPkg string) string {
+	name := codegen.GoTypeRef(t, nil, 1, false)
+	if strings.HasPrefix(name, "*") {
+		return fmt.Sprintf("&%s.%s{}", appPkg, name[1:])
+	}
+	return fmt.Sprintf("%s.%s{}", appPkg, name)
+}
+
 // funcMap creates the funcMap used to render the controller code.
-func funcMap(appPkg string, actionImpls map[string]string) template.FuncMap {
+func funcMap(appPkg string) template.FuncMap {
 	return template.FuncMap{
-		"tempvar":   tempvar,
-		"okResp":    okResp,
-		"targetPkg": func() string { return appPkg },
-		"actionBody": func(name string) string {
-			body, ok := actionImpls[name]
-			if !ok {
-				return defaultActionBody
-			}
-			return body
-		},
-		"printResp": func(name string) bool {
-			_, ok := actionImpls[name]
-			return !ok
-		},
+		"tempvar":    tempvar,
+		"okResp":     okResp,
+		"eventRef":   eventRef,
+		"messageRef": messageRef,
+		"targetPkg":  func() string { return appPkg },
 	}
 }
 
-var linePattern = regexp.MustCompile(`^\s*// ([^:]+): (\w+)_implement\s*$`)
-
-const defaultActionBody = `// Put your logic here`
+// The controller generator executes the same templates as the main generator, both use the
+// "main" template overrides.
+func init() {
+	codegen.RegisterTemplates("main", map[string]string{
+		"mainT":         mainT,
+		"ctrlT":         ctrlT,
+		"actionT":       actionT,
+		"actionEventsT": actionEventsT,
+		"actionWST":     actionWST,
+	})
+}
 
 const ctrlT = `// {{ $ctrlName := printf "%s%s" (goify .Name true) "Controller" }}{{ $ctrlName }} implements the {{ .Name }} resource.
 type {{ $ctrlName }} struct {
@@ -411,11 +402,27 @@ const actionT = `
 func (c *{{ $ctrlName }}) {{ goify .Name true }}(ctx *{{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}Context) error {
 	// {{ $actionDescr }}: start_implement
 
-	{{ actionBody $actionDescr }}
+	// Put your logic here
 
-{{ if printResp $actionDescr }}
 {{ $ok := okResp . targetPkg }}{{ if $ok }} res := {{ $ok.TypeRef }}
 {{ end }} return {{ if $ok }}ctx.{{ $ok.Name }}(res){{ else }}nil{{ end }}
+	// {{ $actionDescr }}: end_implement
+}
+`
+
+const actionEventsT = `
+{{- $ctrlName := printf "%s%s" (goify .Parent.Name true) "Controller" -}}
+{{- $actionDescr := printf "%s_%s" $ctrlName (goify .Name true) -}}
+// {{ goify .Name true }} runs the {{ .Name }} action.
+func (c *{{ $ctrlName }}) {{ goify .Name true }}(ctx *{{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}Context) error {
+	// {{ $actionDescr }}: start_implement
+
+	// Put your logic here
+
+	defer ctx.CloseStream()
+{{ $event := eventRef . targetPkg }}{{ if $event }}	res := {{ $event }}
+	return ctx.Send("", res)
+{{ else }}	return ctx.OpenStream()
 {{ end }}	// {{ $actionDescr }}: end_implement
 }
 `
@@ -431,17 +438,37 @@ func (c *{{ $ctrlName }}) {{ goify .Name true }}(ctx *{{ targetPkg }}.{{ goify .
 
 // {{ goify .Name true }}WSHandler establishes a websocket connection to run the {{ .Name }} action.
 func (c *{{ $ctrlName }}) {{ goify .Name true }}WSHandler(ctx *{{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}Context) websocket.Handler {
+{{- if .Messages }}
+	return ctx.Handler(func(conn *{{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}Conn) error {
+		// {{ $actionDescr }}: start_implement
+
+		// Put your logic here
+{{ $out := messageRef .Messages.Outbound targetPkg }}
+{{ if .Messages.Inbound }}		for {
+			if _, err := conn.Recv(); err != nil {
+				return err
+			}
+{{ if $out }}			if err := conn.Send({{ $out }}); err != nil {
+				return err
+			}
+{{ end }}		}
+{{ else }}		return conn.Send({{ $out }})
+{{ end }}		// {{ $actionDescr }}: end_implement
+	})
+}
+{{ else }}
 	return func(ws *websocket.Conn) {
 		// {{ $actionDescr }}: start_implement
 
-		{{ actionBody $actionDescr }}
-{{ if printResp $actionDescr }}
+		// Put your logic here
+
 		ws.Write([]byte("{{ .Name }} {{ .Parent.Name }}"))
 		// Dummy echo websocket server
 		io.Copy(ws, ws)
-{{ end }}		// {{ $actionDescr }}: end_implement
+		// {{ $actionDescr }}: end_implement
 	}
-}`
+}
+{{ end }}`
 
 const mainT = `
 func main() {
@@ -101,7 +101,7 @@ var _ = Describe("Generate", func() {
 				Ω(err).ShouldNot(HaveOccurred())
 
 				// First add an import for fmt, to make sure it remains
-				existing = bytes.Replace(existing, []byte("import ("), []byte("import (\n\t\"fmt\")"), 1)
+				existing = bytes.Replace(existing, []byte("import ("), []byte("import (\n\t\"fmt\""), 1)
 
 				// Next add some body that uses fmt
 				existing = bytes.Replace(existing, []byte("// Put your logic here"), []byte("fmt.Println(\"I did it first\")"), 1)
@@ -164,6 +164,84 @@ var _ = Describe("Generate", func() {
 			})
 		})
 
+		Context("regenerated after an action was replaced", func() {
+			var existing []byte
+
+			BeforeEach(func() {
+				// Perform a first generation
+				files, genErr = genmain.Generate()
+				Ω(genErr).ShouldNot(HaveOccurred())
+
+				// Add a helper function and some impl to the existing controller
+				content, err := os.ReadFile(filepath.Join(outDir, "first.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				content = bytes.Replace(content, []byte("// Put your logic here"), []byte("// Keep me\n\tlog()"), 1)
+				content = append(content, []byte("\n// log is a helper.\nfunc log() {}\n")...)
+				Ω(os.WriteFile(filepath.Join(outDir, "first.go"), content, 0644)).ShouldNot(HaveOccurred())
+
+				// Replace the action
+				delete(resource.Actions, "alpha")
+				beta := &design.ActionDefinition{
+					Parent:      resource,
+					Name:        "beta",
+					Schemes:     []string{"http"},
+					Description: "Beta-like things",
+				}
+				resource.Actions[beta.Name] = beta
+
+				os.Args = append(os.Args, "--regen")
+			})
+
+			JustBeforeEach(func() {
+				var err error
+				existing, err = os.ReadFile(filepath.Join(outDir, "first.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("adds the new action and flags the removed action", func() {
+				Ω(genErr).ShouldNot(HaveOccurred())
+				Ω(string(existing)).Should(ContainSubstring("// Alpha runs the alpha action.\n// Alpha does not implement an action of the design anymore.\nfunc (c *FirstController) Alpha("))
+				Ω(string(existing)).Should(MatchRegexp(`// FirstController_Alpha: start_implement\s*// Keep me\s*log\(\)\s*return nil`))
+				Ω(string(existing)).Should(ContainSubstring("// log is a helper.\nfunc log() {}\n"))
+				Ω(string(existing)).Should(ContainSubstring("FirstController_Beta: start_implement"))
+			})
+
+			It("leaves the controller untouched when regenerated again", func() {
+				_, err := genmain.Generate()
+				Ω(err).ShouldNot(HaveOccurred())
+				content, err := os.ReadFile(filepath.Join(outDir, "first.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(Equal(string(existing)))
+			})
+		})
+
+		Context("regenerated with an action following a websocket action", func() {
+			BeforeEach(func() {
+				resource.Actions["alpha"].Schemes = []string{"ws"}
+
+				// Perform a first generation
+				files, genErr = genmain.Generate()
+				Ω(genErr).ShouldNot(HaveOccurred())
+
+				// Add an action after the websocket action
+				beta := &design.ActionDefinition{
+					Parent:      resource,
+					Name:        "beta",
+					Schemes:     []string{"http"},
+					Description: "Beta-like things",
+				}
+				resource.Actions[beta.Name] = beta
+
+				os.Args = append(os.Args, "--regen")
+			})
+
+			It("keeps the doc comment of the new action", func() {
+				Ω(genErr).ShouldNot(HaveOccurred())
+				content, err := os.ReadFile(filepath.Join(outDir, "first.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("// Beta runs the beta action.\nfunc (c *FirstController) Beta("))
+			})
+		})
 	})
 })
 
@@ -0,0 +1,234 @@
+package genmain
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// removedActionComment is the comment added to the methods of the actions that are no longer
+// defined in the design.
+const removedActionComment = "// %s does not implement an action of the design anymore."
+
+// mergeController merges the controller generated in the file generated into the existing
+// controller file filename. The declarations of the generated file that are missing from the
+// existing file, typically the methods of new actions, are appended to it together with the
+// imports they use. The action methods of the existing file that are not generated anymore are
+// flagged with a comment. Everything else including the method bodies, comments and helper
+// functions is left untouched.
+func mergeController(filename, generated string) error {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	gsrc, err := os.ReadFile(generated)
+	if err != nil {
+		return err
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("failed to parse existing controller: %s", err)
+	}
+	gfile, err := parser.ParseFile(fset, generated, gsrc, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	var (
+		existing    = make(map[string]bool)
+		gen         = make(map[string]bool)
+		controllers = make(map[string]bool)
+		edits       []edit
+	)
+	for _, decl := range file.Decls {
+		for _, k := range declKeys(decl) {
+			existing[k] = true
+		}
+	}
+	for _, decl := range gfile.Decls {
+		if d, ok := decl.(*ast.GenDecl); ok && d.Tok == token.TYPE {
+			for _, spec := range d.Specs {
+				controllers[spec.(*ast.TypeSpec).Name.Name] = true
+			}
+		}
+		for _, k := range declKeys(decl) {
+			gen[k] = true
+		}
+	}
+
+	// Flag the action methods of the existing controllers that are not generated anymore.
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || !controllers[recvName(fn)] || !isAction(fn) {
+			continue
+		}
+		if gen[recvName(fn)+"."+fn.Name.Name] {
+			continue
+		}
+		comment := fmt.Sprintf(removedActionComment, fn.Name.Name)
+		if fn.Doc != nil && strings.Contains(fn.Doc.Text(), comment[3:]) {
+			continue
+		}
+		edits = append(edits, edit{offset: fset.Position(fn.Pos()).Offset, text: comment + "\n"})
+	}
+
+	// Append the generated declarations missing from the existing file.
+	var added bytes.Buffer
+	for _, decl := range gfile.Decls {
+		keys := declKeys(decl)
+		if len(keys) == 0 {
+			continue
+		}
+		missing := false
+		for _, k := range keys {
+			if !existing[k] {
+				missing = true
+			}
+		}
+		if !missing {
+			continue
+		}
+		start := decl.Pos()
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Doc != nil {
+				start = d.Doc.Pos()
+			}
+		case *ast.GenDecl:
+			if d.Doc != nil {
+				start = d.Doc.Pos()
+			}
+		}
+		added.WriteString("\n")
+		added.Write(gsrc[fset.Position(start).Offset:fset.Position(decl.End()).Offset])
+		added.WriteString("\n")
+	}
+	if len(edits) == 0 && added.Len() == 0 {
+		return nil
+	}
+	edits = append(edits, edit{offset: len(src), text: added.String()})
+
+	merged := applyEdits(src, edits)
+	file, err = parser.ParseFile(fset, filename, merged, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	// Import the packages used by the added declarations.
+	for _, imp := range gfile.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			return err
+		}
+		var name string
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if hasImport(file, path) {
+			continue
+		}
+		astutil.AddNamedImport(fset, file, name, path)
+		if !astutil.UsesImport(file, path) {
+			astutil.DeleteNamedImport(fset, file, name, path)
+		}
+	}
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, buf.Bytes(), 0644)
+}
+
+// edit inserts text at the given offset.
+type edit struct {
+	offset int
+	text   string
+}
+
+// applyEdits returns a copy of src with the given edits applied.
+func applyEdits(src []byte, edits []edit) []byte {
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].offset < edits[j].offset })
+	var (
+		buf  bytes.Buffer
+		prev int
+	)
+	for _, e := range edits {
+		buf.Write(src[prev:e.offset])
+		buf.WriteString(e.text)
+		prev = e.offset
+	}
+	buf.Write(src[prev:])
+	return buf.Bytes()
+}
+
+// declKeys returns the keys identifying the given top-level declaration: the type names for type
+// declarations, the function name or the receiver type and method name for functions.
+func declKeys(decl ast.Decl) []string {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv == nil {
+			return []string{d.Name.Name}
+		}
+		return []string{recvName(d) + "." + d.Name.Name}
+	case *ast.GenDecl:
+		if d.Tok != token.TYPE {
+			return nil
+		}
+		keys := make([]string, len(d.Specs))
+		for i, spec := range d.Specs {
+			keys[i] = "type " + spec.(*ast.TypeSpec).Name.Name
+		}
+		return keys
+	}
+	return nil
+}
+
+// recvName returns the name of the receiver type of the given method, the empty string if fn is
+// not a method.
+func recvName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if id, ok := typ.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+// isAction returns true if the given method has the signature of the generated action methods and
+// websocket handlers: it accepts a single action context.
+func isAction(fn *ast.FuncDecl) bool {
+	params := fn.Type.Params.List
+	if len(params) != 1 || len(params[0].Names) > 1 {
+		return false
+	}
+	star, ok := params[0].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := star.X.(*ast.SelectorExpr)
+	return ok && strings.HasSuffix(sel.Sel.Name, "Context")
+}
+
+// hasImport returns true if the given file imports the package with the given path.
+func hasImport(file *ast.File, path string) bool {
+	for _, imp := range file.Imports {
+		if p, err := strconv.Unquote(imp.Path.Value); err == nil && p == path {
+			return true
+		}
+	}
+	return false
+}
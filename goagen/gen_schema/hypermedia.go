@@ -0,0 +1,192 @@
+package genschema
+
+import (
+	"sort"
+
+	"github.com/shogo82148/goa-v1/design"
+)
+
+// buildHypermediaSchema turns s, the JSON schema of the projected media type p, into the JSON
+// schema of the HAL or JSON:API document rendering p.
+func buildHypermediaSchema(api *design.APIDefinition, p *design.MediaTypeDefinition, s *JSONSchema) {
+	format := p.HypermediaFormat()
+	if format == design.NoHypermedia {
+		return
+	}
+	// Examples describe the plain rendering of the media type.
+	s.Example = nil
+	if p.IsArray() {
+		e := p.ToArray().ElemType.Type.(*design.MediaTypeDefinition)
+		items := NewJSONSchema()
+		if format == design.HALHypermedia {
+			items.Ref = MediaTypeRef(api, e, design.DefaultView)
+			s.Type = JSONObject
+			s.Items = nil
+			s.Properties = map[string]*JSONSchema{
+				"_embedded": {
+					Type:       JSONObject,
+					Properties: map[string]*JSONSchema{"items": {Type: JSONArray, Items: items}},
+				},
+			}
+			return
+		}
+		buildAttributeSchema(api, items, e.AttributeDefinition)
+		s.Type = JSONObject
+		s.Items = nil
+		s.Properties = map[string]*JSONSchema{
+			"data":     {Type: JSONArray, Items: jsonapiResourceSchema(e, items)},
+			"included": jsonapiIncludedSchema(),
+		}
+		s.Required = []string{"data"}
+		return
+	}
+	if format == design.HALHypermedia {
+		buildHALSchema(p, s)
+		return
+	}
+	resource := jsonapiResourceSchema(p, s)
+	s.Properties = map[string]*JSONSchema{
+		"data":     resource,
+		"included": jsonapiIncludedSchema(),
+	}
+	s.Required = []string{"data"}
+}
+
+// buildHALSchema moves the href, the links and the related resources described by s, the JSON
+// schema of the projected media type p, to the "_links" and "_embedded" properties.
+func buildHALSchema(p *design.MediaTypeDefinition, s *JSONSchema) {
+	links := make(map[string]*JSONSchema)
+	if _, ok := s.Properties["href"]; ok {
+		links["self"] = halLinkSchema()
+		delete(s.Properties, "href")
+	}
+	if ls := p.HypermediaLinks(); len(ls) > 0 {
+		for n := range ls {
+			links[n] = halLinkSchema()
+		}
+		delete(s.Properties, "links")
+	}
+	embedded := make(map[string]*JSONSchema)
+	for n := range p.EmbeddedResources() {
+		if ps, ok := s.Properties[n]; ok {
+			embedded[n] = ps
+			delete(s.Properties, n)
+		}
+	}
+	if len(links) > 0 {
+		s.Properties["_links"] = &JSONSchema{Type: JSONObject, Properties: links}
+	}
+	if len(embedded) > 0 {
+		s.Properties["_embedded"] = &JSONSchema{Type: JSONObject, Properties: embedded}
+	}
+	s.Required = remainingRequired(s.Required, s.Properties)
+}
+
+// jsonapiResourceSchema returns the JSON schema of the JSON:API resource rendering the projected
+// media type p given s, the JSON schema of p.
+func jsonapiResourceSchema(p *design.MediaTypeDefinition, s *JSONSchema) *JSONSchema {
+	props := make(map[string]*JSONSchema, len(s.Properties))
+	for n, ps := range s.Properties {
+		props[n] = ps
+	}
+	res := &JSONSchema{
+		Type:        JSONObject,
+		Description: s.Description,
+		Properties: map[string]*JSONSchema{
+			"type": {Type: JSONString, Enum: []interface{}{p.JSONAPIType()}},
+			"id":   {Type: JSONString},
+		},
+		Required: []string{"type", "id"},
+	}
+	delete(props, "id")
+	if _, ok := props["href"]; ok {
+		res.Properties["links"] = &JSONSchema{
+			Type:       JSONObject,
+			Properties: map[string]*JSONSchema{"self": {Type: JSONString}},
+		}
+		delete(props, "href")
+	}
+	rels := make(map[string]*JSONSchema)
+	for n, e := range p.EmbeddedResources() {
+		if _, ok := props[n]; !ok {
+			continue
+		}
+		data := jsonapiIdentifierSchema(e)
+		if att := p.Type.ToObject()[n]; att != nil && att.Type.IsArray() {
+			data = &JSONSchema{Type: JSONArray, Items: data}
+		}
+		rels[n] = &JSONSchema{Type: JSONObject, Properties: map[string]*JSONSchema{"data": data}}
+		delete(props, n)
+	}
+	if ls := p.HypermediaLinks(); len(ls) > 0 {
+		for n, l := range ls {
+			rel, ok := rels[n]
+			if !ok {
+				rel = &JSONSchema{Type: JSONObject, Properties: map[string]*JSONSchema{
+					"data": jsonapiIdentifierSchema(l),
+				}}
+				rels[n] = rel
+			}
+			rel.Properties["links"] = &JSONSchema{
+				Type:       JSONObject,
+				Properties: map[string]*JSONSchema{"related": {Type: JSONString}},
+			}
+		}
+		delete(props, "links")
+	}
+	if len(props) > 0 {
+		res.Properties["attributes"] = &JSONSchema{
+			Type:       JSONObject,
+			Properties: props,
+			Required:   remainingRequired(s.Required, props),
+		}
+	}
+	if len(rels) > 0 {
+		res.Properties["relationships"] = &JSONSchema{Type: JSONObject, Properties: rels}
+	}
+	return res
+}
+
+// jsonapiIdentifierSchema returns the JSON schema of the identifiers of the JSON:API resources
+// rendering mt.
+func jsonapiIdentifierSchema(mt *design.MediaTypeDefinition) *JSONSchema {
+	return &JSONSchema{
+		Type: JSONObject,
+		Properties: map[string]*JSONSchema{
+			"type": {Type: JSONString, Enum: []interface{}{mt.JSONAPIType()}},
+			"id":   {Type: JSONString},
+		},
+		Required: []string{"type", "id"},
+	}
+}
+
+// jsonapiIncludedSchema returns the JSON schema of the "included" member of JSON:API documents.
+func jsonapiIncludedSchema() *JSONSchema {
+	return &JSONSchema{
+		Type:        JSONArray,
+		Description: "Related resources",
+		Items:       &JSONSchema{Type: JSONObject},
+	}
+}
+
+// halLinkSchema returns the JSON schema of HAL links.
+func halLinkSchema() *JSONSchema {
+	return &JSONSchema{
+		Type:       JSONObject,
+		Properties: map[string]*JSONSchema{"href": {Type: JSONString}},
+		Required:   []string{"href"},
+	}
+}
+
+// remainingRequired returns the sorted names of the required properties that are still defined
+// in props.
+func remainingRequired(required []string, props map[string]*JSONSchema) []string {
+	var res []string
+	for _, n := range required {
+		if _, ok := props[n]; ok {
+			res = append(res, n)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
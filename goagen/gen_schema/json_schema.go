@@ -34,6 +34,9 @@ type (
 		Links     []*JSONLink `json:"links,omitempty"`
 		Ref       string      `json:"$ref,omitempty"`
 
+		// Meta-data
+		Deprecated bool `json:"deprecated,omitempty"`
+
 		// Validation
 		Enum                 []interface{} `json:"enum,omitempty"`
 		Format               string        `json:"format,omitempty"`
@@ -71,6 +74,7 @@ type (
 		TargetSchema *JSONSchema `json:"targetSchema,omitempty"`
 		MediaType    string      `json:"mediaType,omitempty"`
 		EncType      string      `json:"encType,omitempty"`
+		Deprecated   bool        `json:"deprecated,omitempty"`
 	}
 )
 
@@ -221,6 +225,7 @@ func GenerateResourceDefinition(api *design.APIDefinition, r *design.ResourceDef
 				Schema:       requestSchema,
 				TargetSchema: targetSchema,
 				MediaType:    identifier,
+				Deprecated:   a.Deprecation != nil,
 			}
 			if i == 0 {
 				if ca := a.Parent.CanonicalAction(); ca != nil {
@@ -296,6 +301,14 @@ func TypeSchema(api *design.APIDefinition, t design.DataType) *JSONSchema {
 			s.Format = "double"
 		case design.IntegerKind:
 			s.Format = "int64"
+		case design.DateKind:
+			s.Format = "date"
+		case design.TimeOfDayKind:
+			s.Format = "time"
+		case design.DurationKind:
+			s.Format = "duration"
+		case design.DecimalKind:
+			s.Format = "decimal"
 		}
 	case *design.Array:
 		s.Type = JSONArray
@@ -303,9 +316,15 @@ func TypeSchema(api *design.APIDefinition, t design.DataType) *JSONSchema {
 		buildAttributeSchema(api, s.Items, actual.ElemType)
 	case design.Object:
 		s.Type = JSONObject
-		for n, at := range actual {
+		// iterate in a fixed order so generated examples are the same on every run
+		names := make([]string, 0, len(actual))
+		for n := range actual {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
 			prop := NewJSONSchema()
-			buildAttributeSchema(api, prop, at)
+			buildAttributeSchema(api, prop, actual[n])
 			s.Properties[n] = prop
 		}
 	case *design.Hash:
@@ -339,6 +358,7 @@ func (s *JSONSchema) createMergeItems(other *JSONSchema) mergeItems {
 		{&s.Title, other.Title, s.Title == ""},
 		{&s.Media, other.Media, s.Media == nil},
 		{&s.ReadOnly, other.ReadOnly, !s.ReadOnly},
+		{&s.Deprecated, other.Deprecated, !s.Deprecated},
 		{&s.PathStart, other.PathStart, s.PathStart == ""},
 		{&s.Enum, other.Enum, s.Enum == nil},
 		{&s.Format, other.Format, s.Format == ""},
@@ -413,6 +433,7 @@ func (s *JSONSchema) Dup() *JSONSchema {
 		PathStart:            s.PathStart,
 		Links:                s.Links,
 		Ref:                  s.Ref,
+		Deprecated:           s.Deprecated,
 		Enum:                 s.Enum,
 		Format:               s.Format,
 		Pattern:              s.Pattern,
@@ -454,6 +475,7 @@ func buildAttributeSchema(api *design.APIDefinition, s *JSONSchema, at *design.A
 	s.Description = at.Description
 	s.Example = at.GenerateExample(api.RandomGenerator(), nil)
 	s.ReadOnly = at.IsReadOnly()
+	s.Deprecated = at.Deprecation != nil
 	val := at.Validation
 	if val == nil {
 		return s
@@ -590,4 +612,5 @@ func buildMediaTypeSchema(api *design.APIDefinition, mt *design.MediaTypeDefinit
 		}
 	}
 	buildAttributeSchema(api, s, projected.AttributeDefinition)
+	buildHypermediaSchema(api, projected, s)
 }
@@ -49,6 +49,8 @@ func Generate() (files []string, err error) {
 	set.BoolVar(&regen, "regen", false, "")
 	set.Bool("force", false, "")
 	set.Bool("notest", false, "")
+	set.String("templates", "", "")
+	set.String("app-pkg", "", "")
 	set.Parse(os.Args[1:])
 
 	if err := codegen.CheckVersion(ver); err != nil {
@@ -74,11 +76,6 @@ func (g *Generator) Generate() (_ []string, err error) {
 		}
 	}()
 
-	s, err := New(g.API)
-	if err != nil {
-		return nil, err
-	}
-
 	swaggerDir := filepath.Join(g.OutDir, "swagger")
 	os.RemoveAll(swaggerDir)
 	if err = os.MkdirAll(swaggerDir, 0755); err != nil {
@@ -86,29 +83,54 @@ func (g *Generator) Generate() (_ []string, err error) {
 	}
 	g.genfiles = append(g.genfiles, swaggerDir)
 
+	if len(g.API.APIVersions) > 0 {
+		// Generate one specification per API version
+		err = codegen.ForEachVersion(g.API, func(api *design.APIDefinition) error {
+			return g.generateSpec(api, filepath.Join(swaggerDir, api.SelectedVersion.Name))
+		})
+	} else {
+		err = g.generateSpec(g.API, swaggerDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// generateSpec writes the JSON and YAML Swagger specifications of api to dir.
+func (g *Generator) generateSpec(api *design.APIDefinition, dir string) error {
+	s, err := New(api)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
 	// JSON
 	rawJSON, err := json.Marshal(s)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	swaggerFile := filepath.Join(swaggerDir, "swagger.json")
+	swaggerFile := filepath.Join(dir, "swagger.json")
 	if err := os.WriteFile(swaggerFile, rawJSON, 0644); err != nil {
-		return nil, err
+		return err
 	}
 	g.genfiles = append(g.genfiles, swaggerFile)
 
 	// YAML
 	rawYAML, err := jsonToYAML(rawJSON)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	swaggerFile = filepath.Join(swaggerDir, "swagger.yaml")
+	swaggerFile = filepath.Join(dir, "swagger.yaml")
 	if err := os.WriteFile(swaggerFile, rawYAML, 0644); err != nil {
-		return nil, err
+		return err
 	}
 	g.genfiles = append(g.genfiles, swaggerFile)
 
-	return g.genfiles, nil
+	return nil
 }
 
 // Cleanup removes all the files generated by this generator during the last invocation of Generate.
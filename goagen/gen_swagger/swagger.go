@@ -30,6 +30,7 @@ type (
 		SecurityDefinitions map[string]*SecurityDefinition   `json:"securityDefinitions,omitempty"`
 		Tags                []*Tag                           `json:"tags,omitempty"`
 		ExternalDocs        *ExternalDocs                    `json:"externalDocs,omitempty"`
+		Webhooks            map[string]*Path                 `json:"x-webhooks,omitempty"`
 	}
 
 	// Info provides metadata about the API. The metadata can be used by the clients if needed,
@@ -424,6 +425,19 @@ func New(api *design.APIDefinition) (*Swagger, error) {
 	if err != nil {
 		return nil, err
 	}
+	// generate the webhook payload examples in a fixed order so they are the same on every run
+	api.IterateWebhooks(func(w *design.WebhookDefinition) error {
+		if w.Payload != nil {
+			w.Payload.GenerateExample(api.RandomGenerator(), nil)
+		}
+		return nil
+	})
+	for n, w := range api.Webhooks {
+		if s.Webhooks == nil {
+			s.Webhooks = make(map[string]*Path)
+		}
+		s.Webhooks[n] = webhookPath(api, w)
+	}
 	err = api.IterateResources(func(res *design.ResourceDefinition) error {
 		for k, v := range extensionsFromDefinition(res.Metadata) {
 			s.Paths[k] = v
@@ -719,12 +733,20 @@ func paramFor(at *design.AttributeDefinition, name, in string, required bool) *P
 		Description: at.Description,
 		Required:    required,
 		Type:        at.Type.Name(),
+		Format:      primitiveFormat(at.Type),
 	}
 	if at.Type.IsArray() {
 		p.Items = itemsFromDefinition(at.Type.ToArray().ElemType)
 		p.CollectionFormat = "multi"
 	}
 	p.Extensions = extensionsFromDefinition(at.Metadata)
+	if at.Deprecation != nil {
+		// Swagger 2.0 parameters cannot be flagged as deprecated, use an extension instead.
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]interface{})
+		}
+		p.Extensions["x-deprecated"] = true
+	}
 	initValidations(at, p)
 	return p
 }
@@ -766,7 +788,7 @@ func toString(val interface{}) string {
 }
 
 func itemsFromDefinition(at *design.AttributeDefinition) *Items {
-	items := &Items{Type: at.Type.Name()}
+	items := &Items{Type: at.Type.Name(), Format: primitiveFormat(at.Type)}
 	initValidations(at, items)
 	if at.Type.IsArray() {
 		items.Items = itemsFromDefinition(at.Type.ToArray().ElemType)
@@ -774,6 +796,16 @@ func itemsFromDefinition(at *design.AttributeDefinition) *Items {
 	return items
 }
 
+// primitiveFormat returns the Swagger format of the date, time of day, duration and decimal
+// primitives, the empty string for any other type.
+func primitiveFormat(t design.DataType) string {
+	switch t.Kind() {
+	case design.DateKind, design.TimeOfDayKind, design.DurationKind, design.DecimalKind:
+		return genschema.TypeSchema(nil, t).Format
+	}
+	return ""
+}
+
 func responseSpecFromDefinition(s *Swagger, api *design.APIDefinition, r *design.ResponseDefinition) (*Response, error) {
 	var schema *genschema.JSONSchema
 	if r.MediaType != "" {
@@ -919,6 +951,16 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 	}
 
 	params = append(params, paramsFromHeaders(action)...)
+	if v := api.SelectedVersion; v != nil && v.Header != "" {
+		params = append(params, &Parameter{
+			Name:        v.Header,
+			In:          "header",
+			Description: fmt.Sprintf("Selects the %s API version", v.Name),
+			Required:    true,
+			Type:        "string",
+			Enum:        []interface{}{v.Name},
+		})
+	}
 
 	responses := make(map[string]*Response, len(action.Responses))
 	for _, r := range action.Responses {
@@ -926,6 +968,15 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 		if err != nil {
 			return err
 		}
+		if action.Pagination != nil && r.Status >= 200 && r.Status < 300 {
+			if resp.Headers == nil {
+				resp.Headers = make(map[string]*Header)
+			}
+			resp.Headers["Link"] = &Header{
+				Description: "RFC 8288 links to the other pages of the collection",
+				Type:        "string",
+			}
+		}
 		responses[strconv.Itoa(r.Status)] = resp
 	}
 
@@ -977,15 +1028,41 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 		Parameters:   params,
 		Responses:    responses,
 		Schemes:      schemes,
-		Deprecated:   false,
+		Deprecated:   action.Deprecation != nil,
 		Extensions:   extensionsFromDefinition(route.Metadata),
 	}
 
+	if p := action.Pagination; p != nil {
+		if operation.Extensions == nil {
+			operation.Extensions = make(map[string]interface{})
+		}
+		operation.Extensions["x-pagination"] = map[string]interface{}{
+			"style":        string(p.Style),
+			"defaultLimit": p.DefaultLimit,
+			"maxLimit":     p.MaxLimit,
+		}
+	}
+
+	if len(action.Webhooks) > 0 {
+		if operation.Extensions == nil {
+			operation.Extensions = make(map[string]interface{})
+		}
+		callbacks := make(map[string]*Path, len(action.Webhooks))
+		for n, w := range action.Webhooks {
+			callbacks[n] = webhookPath(api, w)
+		}
+		operation.Extensions["x-callbacks"] = callbacks
+	}
+
 	if consumesMultipart {
 		operation.Consumes = append(operation.Consumes, "multipart/form-data")
 	}
 
 	computeProduces(operation, s, action)
+	if action.Events != nil {
+		addEventStream(operation, s, api, action)
+	}
+	addStreamedResponses(operation, s, action)
 	applySecurity(operation, action.Security)
 
 	computePaths(operation, s, route, basePath)
@@ -1025,6 +1102,74 @@ func computeProduces(operation *Operation, s *Swagger, action *design.ActionDefi
 	}
 }
 
+// addEventStream documents the Server-Sent Events streamed by the action as the 200 response.
+func addEventStream(operation *Operation, s *Swagger, api *design.APIDefinition, action *design.ActionDefinition) {
+	if len(operation.Produces) == 0 {
+		operation.Produces = append(operation.Produces, s.Produces...)
+	}
+	operation.Produces = append(operation.Produces, "text/event-stream")
+	if _, ok := operation.Responses["200"]; ok {
+		return
+	}
+	resp := &Response{Description: "Stream of Server-Sent Events"}
+	if mt := action.Events.EventType(); mt != nil {
+		resp.Schema = genschema.NewJSONSchema()
+		resp.Schema.Ref = genschema.MediaTypeRef(api, mt, design.DefaultView)
+	}
+	operation.Responses["200"] = resp
+}
+
+// webhookPath documents the POST request sent by the given webhook.
+func webhookPath(api *design.APIDefinition, w *design.WebhookDefinition) *Path {
+	var params []*Parameter
+	w.IterateHeaders(func(name string, required bool, h *design.AttributeDefinition) error {
+		params = append(params, paramFor(h, name, "header", required))
+		return nil
+	})
+	params = append(params, &Parameter{
+		Name:        "X-Webhook-Signature",
+		In:          "header",
+		Description: "HMAC-SHA256 signature of the request, \"t=<unix time>,v1=<hex signature>\"",
+		Required:    true,
+		Type:        "string",
+	})
+	if w.Payload != nil {
+		params = append(params, &Parameter{
+			Name:        "payload",
+			In:          "body",
+			Description: w.Payload.Description,
+			Required:    true,
+			Schema:      genschema.TypeSchema(api, w.Payload),
+		})
+	}
+	return &Path{
+		Post: &Operation{
+			Summary:     w.Name,
+			Description: w.Description,
+			Consumes:    []string{"application/json"},
+			Parameters:  params,
+			Responses: map[string]*Response{
+				"200": {Description: "Webhook received"},
+			},
+		},
+	}
+}
+
+// addStreamedResponses documents that the collections of streamed responses may also be
+// encoded as newline delimited JSON.
+func addStreamedResponses(operation *Operation, s *Swagger, action *design.ActionDefinition) {
+	for _, resp := range action.Responses {
+		if !resp.Stream {
+			continue
+		}
+		if len(operation.Produces) == 0 {
+			operation.Produces = append(operation.Produces, s.Produces...)
+		}
+		operation.Produces = append(operation.Produces, "application/x-ndjson")
+		return
+	}
+}
+
 func computePaths(operation *Operation, s *Swagger, route *design.RouteDefinition, basePath string) {
 	key := design.WildcardRegex.ReplaceAllStringFunc(
 		route.FullPath(),
@@ -825,3 +825,85 @@ var _ = Describe("New", func() {
 		})
 	})
 })
+
+var _ = Describe("New output", func() {
+	var dsl func()
+
+	var apiDSL func()
+
+	BeforeEach(func() {
+		apiDSL = func() {}
+	})
+
+	generate := func() []byte {
+		dslengine.Reset()
+		genschema.Definitions = make(map[string]*genschema.JSONSchema)
+		apidsl.API("test", func() {
+			apidsl.BasePath("/api")
+			apiDSL()
+		})
+		apidsl.Resource("res", dsl)
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		swagger, err := genswagger.New(design.Design)
+		Ω(err).ShouldNot(HaveOccurred())
+		b, err := json.Marshal(swagger)
+		Ω(err).ShouldNot(HaveOccurred())
+		return b
+	}
+
+	Context("with long-running actions", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				apidsl.Action("export", func() {
+					apidsl.Routing(apidsl.POST("/export"))
+					apidsl.LongRunning(5)
+				})
+			}
+		})
+
+		It("is deterministic", func() {
+			first := generate()
+			for i := 0; i < 5; i++ {
+				Ω(string(generate())).Should(Equal(string(first)))
+			}
+		})
+
+		It("uses the examples of the operation media type", func() {
+			generate()
+			s := genschema.Definitions["GoaOperation"]
+			Ω(s).ShouldNot(BeNil())
+			Ω(s.Properties["id"].Example).Should(Equal("d9d8b6e41c2a4f0f"))
+			Ω(s.Properties["status"].Example).Should(Equal("running"))
+		})
+	})
+
+	Context("with webhooks", func() {
+		payload := func() {
+			apidsl.Attribute("id", design.Integer)
+			apidsl.Attribute("name", design.String)
+			apidsl.Attribute("total", design.Number)
+		}
+
+		BeforeEach(func() {
+			apiDSL = func() {
+				apidsl.Webhook("order.created", func() { apidsl.Payload(payload) })
+				apidsl.Webhook("order.updated", func() { apidsl.Payload(payload) })
+				apidsl.Webhook("order.deleted", func() { apidsl.Payload(payload) })
+			}
+			dsl = func() {
+				apidsl.Action("export", func() {
+					apidsl.Routing(apidsl.POST("/export"))
+					apidsl.Webhook("export.done", func() { apidsl.Payload(payload) })
+					apidsl.Webhook("export.failed", func() { apidsl.Payload(payload) })
+				})
+			}
+		})
+
+		It("is deterministic", func() {
+			first := generate()
+			for i := 0; i < 5; i++ {
+				Ω(string(generate())).Should(Equal(string(first)))
+			}
+		})
+	})
+})
@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/shogo82148/goa-v1/goagen/codegen"
+	gendiff "github.com/shogo82148/goa-v1/goagen/gen_diff"
+	genimport "github.com/shogo82148/goa-v1/goagen/gen_import"
 	"github.com/shogo82148/goa-v1/goagen/meta"
 	"github.com/shogo82148/goa-v1/goagen/utils"
 	"github.com/shogo82148/goa-v1/version"
@@ -39,15 +43,35 @@ the "app" command generates the code that supports the service controllers.
 The "bootstrap" command runs the "app", "main", "client" and "swagger" commands generating the
 controllers supporting code and main skeleton code (if not already present) as well as a client
 package and tool and the Swagger specification for the API.
+
+Running goagen with no command executes the generator runs declared in the goagen.yaml project
+configuration file. The design package is compiled once into a generator tool shared by all the
+runs and runs that do not come after one another execute in parallel.
+
+The --templates flag of the "app", "main", "client", "js" and "controller" commands points to a
+directory of template overrides. Each override is a file named after the built-in template it
+replaces in a subdirectory named after the generator, for example "app/ctxT.tmpl" or
+"client/clientsTmpl.tmpl". The controller command uses the "main" templates. Overrides are
+executed with the same data and functions as the built-in templates, which they may include
+with {{ template "builtin" . }}.
 `}
 	var (
-		designPkg string
-		debug     bool
+		designPkg             string
+		debug, noCache, check bool
 	)
 
 	rootCmd.PersistentFlags().StringP("out", "o", ".", "output directory")
 	rootCmd.PersistentFlags().StringVarP(&designPkg, "design", "d", "", "design package import path")
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "enable debug mode, does not cleanup temporary files.")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "always compile and run generators instead of restoring their outputs from the cache")
+	rootCmd.PersistentFlags().BoolVar(&check, "check", false, "generate into a scratch directory and print the diff of the generated files that are out of date instead of writing them, exit with an error if any")
+
+	var (
+		config string
+		jobs   = runtime.NumCPU()
+	)
+	rootCmd.Flags().StringVar(&config, "config", meta.ProjectFile, "path to the project configuration `file` declaring the generator runs")
+	rootCmd.Flags().IntVar(&jobs, "jobs", jobs, "maximum `number` of generator runs executing in parallel")
 
 	// versionCmd implements the "version" command
 	versionCmd := &cobra.Command{
@@ -61,9 +85,10 @@ package and tool and the Swagger specification for the API.
 
 	// appCmd implements the "app" command.
 	var (
-		pkg    string
-		notest bool
+		pkg, templates string
+		notest         bool
 	)
+	const templatesUsage = "`directory` containing template overrides, for example \"app/ctxT.tmpl\" replaces the ctxT template of the app command"
 	appCmd := &cobra.Command{
 		Use:   "app",
 		Short: "Generate application code",
@@ -71,6 +96,7 @@ package and tool and the Swagger specification for the API.
 	}
 	appCmd.Flags().StringVar(&pkg, "pkg", "app", "Name of generated Go package containing controllers supporting code (contexts, media types, user types etc.)")
 	appCmd.Flags().BoolVar(&notest, "notest", false, "Prevent generation of test helpers")
+	appCmd.Flags().StringVar(&templates, "templates", "", templatesUsage)
 	rootCmd.AddCommand(appCmd)
 
 	// mainCmd implements the "main" command.
@@ -83,13 +109,14 @@ package and tool and the Swagger specification for the API.
 		Run:   func(c *cobra.Command, _ []string) { files, err = run("genmain", c) },
 	}
 	mainCmd.Flags().BoolVar(&force, "force", false, "overwrite existing files")
-	mainCmd.Flags().BoolVar(&regen, "regen", false, "regenerate scaffolding, maintaining controller implementations")
+	mainCmd.Flags().BoolVar(&regen, "regen", false, "add the methods of new actions to existing controllers and flag the methods of removed actions, maintaining controller implementations")
+	mainCmd.Flags().StringVar(&templates, "templates", "", templatesUsage)
 	rootCmd.AddCommand(mainCmd)
 
 	// clientCmd implements the "client" command.
 	var (
-		toolDir, tool string
-		notool        bool
+		toolDir, tool, appPkg string
+		notool                bool
 	)
 	clientCmd := &cobra.Command{
 		Use:   "client",
@@ -100,6 +127,8 @@ package and tool and the Swagger specification for the API.
 	clientCmd.Flags().StringVar(&toolDir, "tooldir", "tool", "Name of generated tool directory")
 	clientCmd.Flags().StringVar(&tool, "tool", "[API-name]-cli", "Name of generated tool")
 	clientCmd.Flags().BoolVar(&notool, "notool", false, "Prevent generation of cli tool")
+	clientCmd.Flags().StringVar(&appPkg, "app-pkg", "", "`import path` of Go package generated with 'goagen app' defining the enum types, may be relative to output, the enum types are generated in the client package if empty")
+	clientCmd.Flags().StringVar(&templates, "templates", "", templatesUsage)
 	rootCmd.AddCommand(clientCmd)
 
 	// swaggerCmd implements the "swagger" command.
@@ -125,6 +154,7 @@ package and tool and the Swagger specification for the API.
 	jsCmd.Flags().StringVar(&scheme, "scheme", "", `the URL scheme used to make requests to the API, defaults to the scheme defined in the API design if any.`)
 	jsCmd.Flags().StringVar(&host, "host", "", `the API hostname, defaults to the hostname defined in the API design if any`)
 	jsCmd.Flags().BoolVar(&noexample, "noexample", false, `Skip generation of example HTML and controller`)
+	jsCmd.Flags().StringVar(&templates, "templates", "", templatesUsage)
 	rootCmd.AddCommand(jsCmd)
 
 	// schemaCmd implements the "schema" command.
@@ -135,6 +165,56 @@ package and tool and the Swagger specification for the API.
 	}
 	rootCmd.AddCommand(schemaCmd)
 
+	// importCmd implements the "import" command.
+	var (
+		spec, target string
+		overwrite    bool
+	)
+	importCmd := &cobra.Command{
+		Use:   "import",
+		Short: "Generate design package from Swagger or OpenAPI specification",
+		Run:   func(c *cobra.Command, _ []string) { files, err = runImport(c) },
+	}
+	importCmd.Flags().StringVar(&spec, "spec", "", "path to the Swagger 2.0 or OpenAPI 3 specification file (JSON or YAML)")
+	importCmd.Flags().StringVar(&target, "pkg", "design", "name of the generated design `package`")
+	importCmd.Flags().BoolVar(&overwrite, "force", false, "overwrite existing files")
+	rootCmd.AddCommand(importCmd)
+
+	// diffCmd implements the "diff" command.
+	var (
+		base, format string
+	)
+	diffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Detect breaking changes between two versions of a design",
+		Run:   func(c *cobra.Command, _ []string) { files, err = runDiff(c) },
+	}
+	diffCmd.Flags().StringVar(&base, "base", "", "`import path or git reference` of the base version of the design package")
+	diffCmd.Flags().StringVar(&format, "format", "text", "output `format`, one of \"text\" or \"json\"")
+	rootCmd.AddCommand(diffCmd)
+
+	// lintCmd implements the "lint" command.
+	var (
+		enable, disable, rules string
+	)
+	lintCmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Check design against style rules",
+		Run:   func(c *cobra.Command, _ []string) { files, err = runLint(c) },
+	}
+	lintCmd.Flags().StringVar(&enable, "enable", "", "comma separated `list` of the only rules to check, all rules are checked by default")
+	lintCmd.Flags().StringVar(&disable, "disable", "", "comma separated `list` of the rules not to check")
+	lintCmd.Flags().StringVar(&rules, "rules", "", "comma separated `list` of import paths of packages registering custom rules")
+	rootCmd.AddCommand(lintCmd)
+
+	// irCmd implements the "ir" command.
+	irCmd := &cobra.Command{
+		Use:   "ir",
+		Short: "Export design to machine-readable JSON",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genir", c) },
+	}
+	rootCmd.AddCommand(irCmd)
+
 	// genCmd implements the "gen" command.
 	var (
 		pkgPath string
@@ -155,26 +235,34 @@ package and tool and the Swagger specification for the API.
 		Use:   "bootstrap",
 		Short: `Equivalent to running the "app", "main", "client" and "swagger" commands.`,
 		Run: func(c *cobra.Command, a []string) {
-			appCmd.Run(c, a)
-			if err != nil {
-				return
+			var (
+				prev  []string
+				stale staleError
+			)
+			if !c.Flag("app-pkg").Changed {
+				// The client enum types alias the types of the app package generated first.
+				app := "app"
+				if f := c.Flag("pkg"); f.Changed {
+					app = f.Value.String()
+				}
+				c.Flags().Set("app-pkg", app)
 			}
-			prev := files
-
-			mainCmd.Run(c, a)
-			if err != nil {
-				return
+			for _, cmd := range []*cobra.Command{appCmd, mainCmd, clientCmd, swaggerCmd} {
+				cmd.Run(c, a)
+				if s, ok := err.(staleError); ok {
+					// Report the out of date files of all the commands in check mode.
+					stale += s
+					err = nil
+				}
+				if err != nil {
+					return
+				}
+				prev = append(prev, files...)
 			}
-			prev = append(prev, files...)
-
-			clientCmd.Run(c, a)
-			if err != nil {
-				return
+			files = prev
+			if stale > 0 {
+				err = stale
 			}
-			prev = append(prev, files...)
-
-			swaggerCmd.Run(c, a)
-			files = append(prev, files...)
 		},
 	}
 	bootCmd.Flags().AddFlagSet(appCmd.Flags())
@@ -185,7 +273,7 @@ package and tool and the Swagger specification for the API.
 
 	// controllerCmd implements the "controller" command.
 	var (
-		res, appPkg string
+		res string
 	)
 	controllerCmd := &cobra.Command{
 		Use:   "controller",
@@ -193,14 +281,64 @@ package and tool and the Swagger specification for the API.
 		Run:   func(c *cobra.Command, _ []string) { files, err = run("gencontroller", c) },
 	}
 	controllerCmd.Flags().BoolVar(&force, "force", false, "overwrite existing files")
-	controllerCmd.Flags().BoolVar(&regen, "regen", false, "regenerate scaffolding, maintaining controller implementations")
+	controllerCmd.Flags().BoolVar(&regen, "regen", false, "add the methods of new actions to existing controllers and flag the methods of removed actions, maintaining controller implementations")
 	controllerCmd.Flags().StringVar(&res, "res", "", "name of the `resource` to generate the controller for, generate all if not specified")
 	controllerCmd.Flags().StringVar(&pkg, "pkg", "main", "name of the generated controller `package`")
 	controllerCmd.Flags().StringVar(&appPkg, "app-pkg", "app", "`import path` of Go package generated with 'goagen app', may be relative to output")
+	controllerCmd.Flags().StringVar(&templates, "templates", "", templatesUsage)
 	rootCmd.AddCommand(controllerCmd)
 
+	// watchCmd implements the "watch" command.
+	var (
+		gens     string
+		interval = 500 * time.Millisecond
+	)
+	generators := []*cobra.Command{appCmd, mainCmd, clientCmd, swaggerCmd, jsCmd, schemaCmd, controllerCmd, irCmd}
+	watchCmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Run commands each time the design package changes",
+		Long: `The flags of the commands apply to all the commands that define them. A flag prefixed with
+the name of a command and a dot only applies to that command, for example --client.pkg=cli sets the
+name of the client package without changing the name of the app package.`,
+		Run: func(c *cobra.Command, _ []string) { files, err = runWatch(c, generators) },
+	}
+	watchCmd.Flags().StringVar(&gens, "gen", "app,swagger", "comma separated `list` of the commands run on change, any of \"app\", \"main\", \"client\", \"swagger\", \"js\", \"schema\", \"controller\" and \"ir\"")
+	watchCmd.Flags().DurationVar(&interval, "interval", interval, "delay between two checks of the design package sources")
+	for _, cmd := range generators {
+		watchCmd.Flags().AddFlagSet(cmd.Flags())
+	}
+	for _, cmd := range generators {
+		prefix := cmd.Name() + "."
+		cmd.Flags().VisitAll(func(f *pflag.Flag) {
+			scoped := watchCmd.Flags().VarPF(newScopedValue(f), prefix+f.Name, "", f.Usage)
+			scoped.NoOptDefVal = f.NoOptDefVal
+			scoped.Hidden = true
+		})
+	}
+	rootCmd.AddCommand(watchCmd)
+
+	// cacheCmd implements the "cache" command.
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the cache of generator outputs",
+		Long: `Generators whose outputs only depend on the design are not compiled and run if their
+outputs are present in the cache. The cache directory is set with the GOAGEN_CACHE environment
+variable and defaults to the "goagen" subdirectory of the user cache directory.`,
+	}
+	var maxAge = 30 * 24 * time.Hour
+	pruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete the cache entries not used recently",
+		Run:   func(c *cobra.Command, _ []string) { files, err = runPrune(c) },
+	}
+	pruneCmd.Flags().DurationVar(&maxAge, "max-age", maxAge, "delete the entries not used for longer than this `duration`, 0 deletes all the entries")
+	cacheCmd.AddCommand(pruneCmd)
+	rootCmd.AddCommand(cacheCmd)
+
 	// cmdsCmd implements the commands command
 	// It lists all the commands and flags in JSON to enable shell integrations.
+	rootCmd.Run = func(c *cobra.Command, _ []string) { files, err = runProject(c, generators) }
+
 	cmdsCmd := &cobra.Command{
 		Use:   "commands",
 		Short: "Lists all commands and flags in JSON",
@@ -246,16 +384,444 @@ package and tool and the Swagger specification for the API.
 }
 
 func run(pkg string, c *cobra.Command) ([]string, error) {
-	pkgPath := fmt.Sprintf("github.com/shogo82148/goa-v1/goagen/gen_%s", pkg[3:])
+	pkgName, pkgPath, err := builtinGenerator(pkg)
+	if err != nil {
+		return nil, err
+	}
+	gen, err := newGenerator(pkgName, pkgPath, c, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.Flag("check").Value.String() == "true" {
+		return checkDrifts(gen.Check(scaffolded(pkg, gen.Flags)))
+	}
+	if cacheable(pkg, c) && c.Flag("no-cache").Value.String() != "true" {
+		dir, err := meta.DefaultCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		gen.Cache = &meta.Cache{Dir: dir}
+	}
+	return gen.Generate()
+}
+
+// cacheable returns true if the outputs of the given built-in generator only depend on the design
+// and the flags: the "main" and "controller" commands and the client tool leave existing files
+// untouched.
+func cacheable(pkg string, c *cobra.Command) bool {
+	switch pkg {
+	case "genapp", "genswagger", "genschema", "genjs", "genir":
+		return true
+	case "genclient":
+		return c.Flag("notool").Value.String() == "true"
+	default:
+		return false
+	}
+}
+
+// scaffolded returns the glob patterns matching the files that the given built-in generator only
+// creates if they do not exist yet, relative to the output directory.
+func scaffolded(pkg string, flags map[string]string) []string {
+	switch pkg {
+	case "genmain", "gencontroller":
+		return []string{"*.go"}
+	case "genclient":
+		toolDir, ok := flags["tooldir"]
+		if !ok {
+			toolDir = "tool"
+		}
+		return []string{
+			filepath.Join(toolDir, "*", "main.go"),
+			filepath.Join(toolDir, "*", "*", "main.go"),
+		}
+	default:
+		return nil
+	}
+}
+
+// staleError is the error returned in check mode when generated files are out of date.
+type staleError int
+
+// Error returns the error message.
+func (e staleError) Error() string {
+	return fmt.Sprintf("%d generated file(s) out of date", int(e))
+}
+
+// scopedValue is the value of a watch command flag that only applies to one command. The value is
+// given as is to the generator of the command which parses it.
+type scopedValue struct {
+	value string
+	typ   string
+}
+
+// newScopedValue returns the value of the scoped flag corresponding to the command flag f.
+func newScopedValue(f *pflag.Flag) *scopedValue {
+	return &scopedValue{value: f.DefValue, typ: f.Value.Type()}
+}
+
+// String returns the flag value.
+func (v *scopedValue) String() string { return v.value }
+
+// Set sets the flag value.
+func (v *scopedValue) Set(s string) error {
+	if v.typ == "bool" {
+		if _, err := strconv.ParseBool(s); err != nil {
+			return err
+		}
+	}
+	v.value = s
+	return nil
+}
+
+// Type returns the type of the command flag.
+func (v *scopedValue) Type() string { return v.typ }
+
+// checkDrifts prints the diffs of the generated files that are out of date and returns a
+// staleError if there are any.
+func checkDrifts(drifts []*meta.Drift, err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range drifts {
+		fmt.Print(d.Diff)
+	}
+	if len(drifts) > 0 {
+		return nil, staleError(len(drifts))
+	}
+	return nil, nil
+}
+
+// builtinGenerator returns the package name and import path of the given built-in generator.
+func builtinGenerator(pkg string) (pkgName, pkgPath string, err error) {
+	pkgPath = fmt.Sprintf("github.com/shogo82148/goa-v1/goagen/gen_%s", pkg[3:])
 	pkgSrcPath, err := codegen.PackageSourcePath(pkgPath)
 	if err != nil {
-		return nil, fmt.Errorf("invalid plugin package import path: %s", err)
+		return "", "", fmt.Errorf("invalid plugin package import path: %s", err)
 	}
-	pkgName, err := codegen.PackageName(pkgSrcPath)
+	pkgName, err = codegen.PackageName(pkgSrcPath)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid package import path: %s", err)
+	}
+	return pkgName, pkgPath, nil
+}
+
+// runImport runs the import generator directly as there is no design package to compile.
+func runImport(c *cobra.Command) ([]string, error) {
+	out, err := filepath.Abs(c.Flag("out").Value.String())
+	if err != nil {
+		return nil, err
+	}
+	genDir := out
+	check := c.Flag("check").Value.String() == "true"
+	if check {
+		scratch, err := codegen.ScratchPackageFor(out)
+		if err != nil {
+			return nil, err
+		}
+		defer scratch.Workspace.Delete()
+		genDir = scratch.Abs()
+	}
+	g := genimport.NewGenerator(
+		genimport.Spec(c.Flag("spec").Value.String()),
+		genimport.OutDir(genDir),
+		genimport.Target(c.Flag("pkg").Value.String()),
+		genimport.Force(c.Flag("force").Value.String() == "true"),
+	)
+	files, err := g.Generate()
+	for _, w := range g.Warnings {
+		fmt.Fprintln(os.Stderr, "warning: "+w)
+	}
+	if check {
+		if err != nil {
+			return nil, err
+		}
+		return checkDrifts(meta.CompareOutputs(genDir, out, files))
+	}
+	return files, err
+}
+
+// runDiff compares the design package with its base version and prints the changes. It returns
+// an error if there are breaking changes.
+func runDiff(c *cobra.Command) ([]string, error) {
+	format := c.Flag("format").Value.String()
+	if format != "text" && format != "json" {
+		return nil, fmt.Errorf("invalid format %q, must be one of \"text\" or \"json\"", format)
+	}
+	report, err := gendiff.Diff(c.Flag("design").Value.String(), c.Flag("base").Value.String(), c.Flag("debug").Value.String() == "true")
+	if err != nil {
+		return nil, err
+	}
+	if format == "json" {
+		err = report.WriteJSON(os.Stdout)
+	} else {
+		err = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if report.Breaking > 0 {
+		return nil, fmt.Errorf("%d breaking change(s) found", report.Breaking)
+	}
+	return nil, nil
+}
+
+// runLint runs the linter and prints the issues. It returns an error if there are issues.
+func runLint(c *cobra.Command) ([]string, error) {
+	imports := []*codegen.ImportSpec{codegen.SimpleImport("github.com/shogo82148/goa-v1/goagen/gen_lint")}
+	for _, r := range strings.Split(c.Flag("rules").Value.String(), ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			imports = append(imports, codegen.NewImport("_", r))
+		}
+	}
+	m := make(map[string]string)
+	c.Flags().Visit(func(f *pflag.Flag) {
+		m[f.Name] = f.Value.String()
+	})
+	m["out"] = "."
+	gen, err := meta.NewGenerator("genlint.Generate", imports, m, nil)
+	if err != nil {
+		return nil, err
+	}
+	issues, err := gen.Generate()
+	if err != nil {
+		return nil, err
+	}
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+	if len(issues) > 0 {
+		return nil, fmt.Errorf("%d issue(s) found", len(issues))
+	}
+	return nil, nil
+}
+
+// runWatch runs the commands selected with --gen each time the design package changes until
+// goagen is interrupted. The commands share a generator tool that is rebuilt on change. DSL and
+// generation errors are printed and do not stop the watch.
+func runWatch(c *cobra.Command, cmds []*cobra.Command) ([]string, error) {
+	if c.Flag("check").Value.String() == "true" {
+		return nil, fmt.Errorf("the watch command does not support --check")
+	}
+	out, err := filepath.Abs(c.Flag("out").Value.String())
+	if err != nil {
+		return nil, err
+	}
+	interval, err := c.Flags().GetDuration("interval")
+	if err != nil {
+		return nil, err
+	}
+	var (
+		names []string
+		gens  []*meta.Generator
+	)
+	for _, name := range strings.Split(c.Flag("gen").Value.String(), ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		var cmd *cobra.Command
+		for _, cm := range cmds {
+			if cm.Name() == name {
+				cmd = cm
+				break
+			}
+		}
+		if cmd == nil {
+			return nil, fmt.Errorf("invalid command %q in --gen", name)
+		}
+		pkgName, pkgPath, err := builtinGenerator("gen" + name)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[string]string)
+		c.Flags().Visit(func(f *pflag.Flag) {
+			if cmd.Flags().Lookup(f.Name) != nil || c.Root().PersistentFlags().Lookup(f.Name) != nil {
+				m[f.Name] = f.Value.String()
+			}
+		})
+		// Flags scoped to the command override the flags shared by all the commands.
+		c.Flags().Visit(func(f *pflag.Flag) {
+			if n := strings.TrimPrefix(f.Name, name+"."); n != f.Name {
+				m[n] = f.Value.String()
+			}
+		})
+		m["out"] = out
+		if t := m["templates"]; t != "" {
+			if m["templates"], err = filepath.Abs(t); err != nil {
+				return nil, err
+			}
+		}
+		gen, err := meta.NewGenerator(pkgName+".Generate", []*codegen.ImportSpec{codegen.SimpleImport(pkgPath)}, m, nil)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		gens = append(gens, gen)
+	}
+	tool, err := meta.NewTool(gens)
+	if err != nil {
+		return nil, err
+	}
+	defer tool.Close()
+	srcDir, err := codegen.PackageSourcePath(tool.DesignPkgPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid design package import path: %s", err)
+	}
+
+	regenerate := func() {
+		start := time.Now()
+		fmt.Printf("[%s] generating %s\n", start.Format("15:04:05"), strings.Join(names, ", "))
+		if err := tool.Build(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		for i, gen := range gens {
+			files, err := tool.Run(gen)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", names[i], err)
+				return
+			}
+			fmt.Printf("%s: %d file(s)\n", names[i], len(files))
+		}
+		fmt.Printf("done in %s\n", time.Since(start).Round(time.Millisecond))
+	}
+	regenerate()
+
+	watcher, err := meta.NewWatcher(interval, srcDir)
+	if err != nil {
+		return nil, err
+	}
+	stop := make(chan struct{})
+	go utils.Catch(nil, func() { close(stop) })
+	fmt.Printf("watching %s\n", srcDir)
+	watcher.Watch(stop, regenerate, func(err error) { fmt.Fprintln(os.Stderr, err) })
+	return nil, nil
+}
+
+// runProject executes the generator runs declared in the project configuration file. It prints
+// the help if there is no configuration file and none was explicitly given.
+func runProject(c *cobra.Command, cmds []*cobra.Command) ([]string, error) {
+	path := c.Flag("config").Value.String()
+	if _, err := os.Stat(path); os.IsNotExist(err) && !c.Flags().Changed("config") {
+		return nil, c.Help()
+	}
+	project, err := meta.LoadProject(path)
+	if err != nil {
+		return nil, err
+	}
+	if c.Flags().Changed("design") {
+		project.Design = c.Flag("design").Value.String()
+	}
+	check := c.Flag("check").Value.String() == "true"
+
+	var (
+		gens  = make(map[*meta.Run]*meta.Generator, len(project.Runs))
+		seeds = make(map[*meta.Run][]string, len(project.Runs))
+		all   []*meta.Generator
+	)
+	for _, r := range project.Runs {
+		var pkgName, pkgPath string
+		m := r.Flags()
+		if r.Gen != "" {
+			var cmd *cobra.Command
+			for _, cm := range cmds {
+				if cm.Name() == r.Gen {
+					cmd = cm
+					break
+				}
+			}
+			if cmd == nil {
+				return nil, fmt.Errorf("run %q: unknown generator %q", r.Name, r.Gen)
+			}
+			for k := range m {
+				if cmd.Flags().Lookup(k) == nil {
+					return nil, fmt.Errorf("run %q: unknown option %q for the %s command", r.Name, k, r.Gen)
+				}
+			}
+			if pkgName, pkgPath, err = builtinGenerator("gen" + r.Gen); err != nil {
+				return nil, err
+			}
+			seeds[r] = scaffolded("gen"+r.Gen, m)
+		} else {
+			pkgPath = r.PkgPath
+			pkgSrcPath, err := codegen.PackageSourcePath(pkgPath)
+			if err != nil {
+				return nil, fmt.Errorf("run %q: invalid plugin package import path: %s", r.Name, err)
+			}
+			if pkgName, err = codegen.PackageName(pkgSrcPath); err != nil {
+				return nil, fmt.Errorf("run %q: invalid plugin package import path: %s", r.Name, err)
+			}
+		}
+		m["design"] = project.Design
+		m["out"] = project.OutDir(r)
+		if t := m["templates"]; t != "" && !filepath.IsAbs(t) {
+			m["templates"] = filepath.Join(project.Dir, t)
+		}
+		if c.Flag("debug").Value.String() == "true" {
+			m["debug"] = "true"
+		}
+		gen, err := meta.NewGenerator(pkgName+".Generate", []*codegen.ImportSpec{codegen.SimpleImport(pkgPath)}, m, r.Args)
+		if err != nil {
+			return nil, err
+		}
+		gens[r] = gen
+		all = append(all, gen)
+	}
+
+	tool, err := meta.NewTool(all)
+	if err != nil {
+		return nil, err
+	}
+	defer tool.Close()
+	if err := tool.Build(); err != nil {
+		return nil, err
+	}
+
+	jobs, err := c.Flags().GetInt("jobs")
 	if err != nil {
-		return nil, fmt.Errorf("invalid package import path: %s", err)
+		return nil, err
+	}
+	if !check {
+		return project.Execute(jobs, func(r *meta.Run) ([]string, error) {
+			return tool.Run(gens[r])
+		})
 	}
-	return generate(pkgName, pkgPath, c, nil)
+
+	// Scratch workspaces may update GOPATH, check the runs one at a time.
+	var stale staleError
+	_, err = project.Execute(1, func(r *meta.Run) ([]string, error) {
+		_, err := checkDrifts(tool.Check(gens[r], seeds[r]))
+		if s, ok := err.(staleError); ok {
+			stale += s
+			return nil, nil
+		}
+		return nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if stale > 0 {
+		return nil, stale
+	}
+	return nil, nil
+}
+
+// runPrune deletes the cache entries not used recently.
+func runPrune(c *cobra.Command) ([]string, error) {
+	maxAge, err := c.Flags().GetDuration("max-age")
+	if err != nil {
+		return nil, err
+	}
+	dir, err := meta.DefaultCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	cache := &meta.Cache{Dir: dir}
+	count, err := cache.Prune(maxAge)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("deleted %d cache entries\n", count)
+	return nil, nil
 }
 
 func runGen(c *cobra.Command, args []string) ([]string, error) {
@@ -272,6 +838,19 @@ func runGen(c *cobra.Command, args []string) ([]string, error) {
 }
 
 func generate(pkgName, pkgPath string, c *cobra.Command, args []string) ([]string, error) {
+	gen, err := newGenerator(pkgName, pkgPath, c, args)
+	if err != nil {
+		return nil, err
+	}
+	if c.Flag("check").Value.String() == "true" {
+		return checkDrifts(gen.Check(nil))
+	}
+	return gen.Generate()
+}
+
+// newGenerator returns the meta generator running the generator implemented by the given
+// package with the flags set on the command line.
+func newGenerator(pkgName, pkgPath string, c *cobra.Command, args []string) (*meta.Generator, error) {
 	m := make(map[string]string)
 	c.Flags().Visit(func(f *pflag.Flag) {
 		if f.Name != "pkg-path" {
@@ -287,17 +866,19 @@ func generate(pkgName, pkgPath string, c *cobra.Command, args []string) ([]strin
 	if err != nil {
 		return nil, err
 	}
+	// generators may run from another directory in check mode
+	if t := m["templates"]; t != "" {
+		if m["templates"], err = filepath.Abs(t); err != nil {
+			return nil, err
+		}
+	}
 
-	gen, err := meta.NewGenerator(
+	return meta.NewGenerator(
 		pkgName+".Generate",
 		[]*codegen.ImportSpec{codegen.SimpleImport(pkgPath)},
 		m,
 		args,
 	)
-	if err != nil {
-		return nil, err
-	}
-	return gen.Generate()
 }
 
 type (
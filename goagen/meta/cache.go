@@ -0,0 +1,362 @@
+package meta
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/shogo82148/goa-v1/goagen/codegen"
+	"github.com/shogo82148/goa-v1/version"
+)
+
+// Cache stores the outputs of generators indexed by a hash of their inputs: the goagen version,
+// the generator entry point, imports and flags, the template overrides and the sources of the
+// design package and of all the packages it depends on. Packages of required modules are
+// identified by module version rather than hashed.
+//
+// The cache only applies to generators whose outputs do not depend on the files already present
+// in the output directory. Generators that return directories must remove and recreate them.
+type Cache struct {
+	// Dir is the cache directory.
+	Dir string
+}
+
+type (
+	// cacheManifest describes a cache entry.
+	cacheManifest struct {
+		// Outputs lists the lines printed by the generator, usually the generated paths.
+		Outputs []string `json:"outputs"`
+		// Files lists the generated directories and files.
+		Files []*cacheFile `json:"files"`
+	}
+
+	// cacheFile describes a generated directory or file.
+	cacheFile struct {
+		// Path is the absolute path of the file.
+		Path string `json:"path"`
+		// Dir is true if the path is a directory, directories are recreated on restore.
+		Dir bool `json:"dir,omitempty"`
+		// Mode is the file permissions.
+		Mode fs.FileMode `json:"mode"`
+		// Blob is the name of the file holding the content relative to the entry directory.
+		Blob string `json:"blob,omitempty"`
+	}
+
+	// listedPackage is the subset of the "go list -json" output used to compute cache keys.
+	listedPackage struct {
+		ImportPath string
+		Dir        string
+		Standard   bool
+		GoFiles    []string
+		CgoFiles   []string
+		EmbedFiles []string
+		Module     *struct {
+			Path    string
+			Version string
+			Main    bool
+			Replace *struct {
+				Path    string
+				Version string
+			}
+		}
+	}
+)
+
+// manifestFile is the name of the file describing a cache entry.
+const manifestFile = "manifest.json"
+
+// DefaultCacheDir returns the directory used to cache generator outputs. It is the value of the
+// GOAGEN_CACHE environment variable if set, the "goagen" subdirectory of the user cache directory
+// otherwise.
+func DefaultCacheDir() (string, error) {
+	if dir := os.Getenv("GOAGEN_CACHE"); dir != "" {
+		return dir, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate cache directory, set GOAGEN_CACHE: %s", err)
+	}
+	return filepath.Join(dir, "goagen"), nil
+}
+
+// Key computes the cache key of the given generator.
+func (c *Cache) Key(m *Generator) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "version %s\n", version.String())
+	fmt.Fprintf(h, "genfunc %s\n", m.Genfunc)
+	pkgs := []string{m.DesignPkgPath}
+	for _, imp := range m.Imports {
+		fmt.Fprintf(h, "import %s %s\n", imp.Name, imp.Path)
+		pkgs = append(pkgs, imp.Path)
+	}
+	keys := make([]string, 0, len(m.Flags))
+	for k := range m.Flags {
+		if k != "debug" && k != "no-cache" && k != "check" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "flag %s=%s\n", k, m.Flags[k])
+	}
+	// Outputs are stored with absolute paths, relative output directories must be resolved.
+	out, err := filepath.Abs(m.OutDir)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(h, "outdir %s\n", out)
+	for _, f := range m.CustomFlags {
+		fmt.Fprintf(h, "arg %s\n", f)
+	}
+	if dir := m.Flags["templates"]; dir != "" {
+		if err := hashTemplates(h, dir); err != nil {
+			return "", err
+		}
+	}
+	if err := hashSources(h, pkgs); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Get restores the outputs stored with the given key. It returns false if there is no entry for
+// the key.
+func (c *Cache) Get(key string) ([]string, bool, error) {
+	dir := c.entryDir(key)
+	b, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	var man cacheManifest
+	if err := json.Unmarshal(b, &man); err != nil {
+		return nil, false, fmt.Errorf("corrupted cache entry %s: %s", dir, err)
+	}
+	for _, f := range man.Files {
+		if f.Dir {
+			if err := os.RemoveAll(f.Path); err != nil {
+				return nil, false, err
+			}
+			if err := os.MkdirAll(f.Path, f.Mode.Perm()); err != nil {
+				return nil, false, err
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+			return nil, false, err
+		}
+		if err := copyFile(filepath.Join(dir, f.Blob), f.Path, f.Mode.Perm()); err != nil {
+			return nil, false, err
+		}
+	}
+	// Record the use of the entry for Prune.
+	now := time.Now()
+	os.Chtimes(filepath.Join(dir, manifestFile), now, now)
+	return man.Outputs, true, nil
+}
+
+// Put stores the given generator outputs with the given key. Outputs that are absolute paths to
+// existing files or directories are stored with their content.
+func (c *Cache) Put(key string, outputs []string) (err error) {
+	if err = os.MkdirAll(c.Dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp(c.Dir, "tmp")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	man := &cacheManifest{Outputs: outputs, Files: []*cacheFile{}}
+	seen := make(map[string]bool)
+	add := func(path string, info fs.FileInfo) error {
+		if seen[path] {
+			return nil
+		}
+		seen[path] = true
+		f := &cacheFile{Path: path, Mode: info.Mode()}
+		if info.IsDir() {
+			f.Dir = true
+		} else {
+			f.Blob = strconv.Itoa(len(man.Files))
+			if err := copyFile(path, filepath.Join(tmp, f.Blob), 0644); err != nil {
+				return err
+			}
+		}
+		man.Files = append(man.Files, f)
+		return nil
+	}
+	for _, out := range outputs {
+		if !filepath.IsAbs(out) {
+			continue
+		}
+		info, err := os.Stat(out)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			if err := add(out, info); err != nil {
+				return err
+			}
+			continue
+		}
+		err = filepath.Walk(out, func(path string, info fs.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && !info.Mode().IsRegular() {
+				return nil
+			}
+			return add(path, info)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	b, err := json.Marshal(man)
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(filepath.Join(tmp, manifestFile), b, 0644); err != nil {
+		return err
+	}
+	dir := c.entryDir(key)
+	if err = os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return err
+	}
+	os.RemoveAll(dir)
+	return os.Rename(tmp, dir)
+}
+
+// Prune deletes the cache entries that were not used for longer than the given duration and
+// returns the number of deleted entries. A zero duration deletes all the entries.
+func (c *Cache) Prune(maxAge time.Duration) (int, error) {
+	manifests, err := filepath.Glob(filepath.Join(c.Dir, "*", "*", manifestFile))
+	if err != nil {
+		return 0, err
+	}
+	deadline := time.Now().Add(-maxAge)
+	count := 0
+	for _, man := range manifests {
+		info, err := os.Stat(man)
+		if err != nil {
+			continue
+		}
+		if maxAge > 0 && info.ModTime().After(deadline) {
+			continue
+		}
+		dir := filepath.Dir(man)
+		if err := os.RemoveAll(dir); err != nil {
+			return count, err
+		}
+		os.Remove(filepath.Dir(dir)) // fails if other entries share the key prefix
+		count++
+	}
+	// Remove the leftovers of interrupted Put calls.
+	tmps, _ := filepath.Glob(filepath.Join(c.Dir, "tmp*"))
+	for _, tmp := range tmps {
+		os.RemoveAll(tmp)
+	}
+	return count, nil
+}
+
+// entryDir returns the directory of the entry with the given key.
+func (c *Cache) entryDir(key string) string {
+	return filepath.Join(c.Dir, key[:2], key)
+}
+
+// hashSources writes the sources of the given packages and of their dependencies to h. Standard
+// library packages are skipped, packages of required modules are identified by module version.
+func hashSources(h io.Writer, pkgs []string) error {
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		return fmt.Errorf(`failed to find a go compiler, looked in "%s"`, os.Getenv("PATH"))
+	}
+	args := append([]string{"list", "-deps", "-json", "--"}, pkgs...)
+	var stderr bytes.Buffer
+	cmd := exec.Command(gobin, args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to list design package dependencies: %s\n%s", err, stderr.String())
+	}
+	dec := json.NewDecoder(bytes.NewReader(out))
+	modules := make(map[string]bool)
+	for dec.More() {
+		var p listedPackage
+		if err := dec.Decode(&p); err != nil {
+			return err
+		}
+		if p.Standard {
+			continue
+		}
+		if mod := p.Module; mod != nil && !mod.Main && mod.Version != "" && (mod.Replace == nil || mod.Replace.Version != "") {
+			id := mod.Path + "@" + mod.Version
+			if mod.Replace != nil {
+				id += " => " + mod.Replace.Path + "@" + mod.Replace.Version
+			}
+			if !modules[id] {
+				modules[id] = true
+				fmt.Fprintf(h, "module %s\n", id)
+			}
+			continue
+		}
+		fmt.Fprintf(h, "package %s\n", p.ImportPath)
+		files := append(append(append([]string(nil), p.GoFiles...), p.CgoFiles...), p.EmbedFiles...)
+		for _, name := range files {
+			b, err := os.ReadFile(filepath.Join(p.Dir, name))
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "file %s %x\n", name, sha256.Sum256(b))
+		}
+	}
+	return nil
+}
+
+// hashTemplates writes the template override files located in dir to h.
+func hashTemplates(h io.Writer, dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*", "*"+codegen.TemplateExt))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		fmt.Fprintf(h, "template %s %x\n", filepath.ToSlash(rel), sha256.Sum256(b))
+	}
+	return nil
+}
+
+// copyFile copies the content of the file src to dst.
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,145 @@
+package meta_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1/goagen/codegen"
+	"github.com/shogo82148/goa-v1/goagen/meta"
+)
+
+var _ = Describe("Cache", func() {
+	var (
+		cacheDir, outDir string
+		cache            *meta.Cache
+	)
+
+	BeforeEach(func() {
+		var err error
+		cacheDir, err = os.MkdirTemp("", "cache")
+		Ω(err).ShouldNot(HaveOccurred())
+		outDir, err = os.MkdirTemp("", "out")
+		Ω(err).ShouldNot(HaveOccurred())
+		cache = &meta.Cache{Dir: cacheDir}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(cacheDir)
+		os.RemoveAll(outDir)
+	})
+
+	Describe("Key", func() {
+		var gen *meta.Generator
+
+		BeforeEach(func() {
+			gen = &meta.Generator{
+				Genfunc:       "genapp.Generate",
+				Imports:       []*codegen.ImportSpec{codegen.SimpleImport("github.com/shogo82148/goa-v1/goagen/gen_app")},
+				Flags:         map[string]string{"out": "out", "design": "github.com/shogo82148/goa-v1/goagen/meta"},
+				DesignPkgPath: "github.com/shogo82148/goa-v1/goagen/meta",
+			}
+		})
+
+		It("depends on the generator flags only", func() {
+			key, err := cache.Key(gen)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(key).Should(HaveLen(64))
+
+			gen.Flags["debug"] = "true"
+			gen.Flags["no-cache"] = "false"
+			other, err := cache.Key(gen)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(other).Should(Equal(key))
+
+			gen.Flags["pkg"] = "other"
+			other, err = cache.Key(gen)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(other).ShouldNot(Equal(key))
+		})
+
+		It("depends on the absolute output directory", func() {
+			gen.OutDir = "out"
+			key, err := cache.Key(gen)
+			Ω(err).ShouldNot(HaveOccurred())
+			gen.OutDir = outDir
+			other, err := cache.Key(gen)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(other).ShouldNot(Equal(key))
+		})
+
+		It("fails with an invalid design package", func() {
+			gen.DesignPkgPath = "github.com/shogo82148/goa-v1/goagen/missing"
+			_, err := cache.Key(gen)
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("Put and Get", func() {
+		const key = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+		var outputs []string
+
+		BeforeEach(func() {
+			dir := filepath.Join(outDir, "swagger")
+			Ω(os.MkdirAll(filepath.Join(dir, "sub"), 0755)).ShouldNot(HaveOccurred())
+			Ω(os.WriteFile(filepath.Join(dir, "swagger.json"), []byte("{}"), 0644)).ShouldNot(HaveOccurred())
+			Ω(os.WriteFile(filepath.Join(dir, "sub", "file.txt"), []byte("sub"), 0644)).ShouldNot(HaveOccurred())
+			Ω(os.WriteFile(filepath.Join(outDir, "main.go"), []byte("package main"), 0600)).ShouldNot(HaveOccurred())
+			outputs = []string{dir, filepath.Join(dir, "swagger.json"), filepath.Join(outDir, "main.go"), "not a file"}
+		})
+
+		It("misses unknown keys", func() {
+			_, ok, err := cache.Get(key)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(ok).Should(BeFalse())
+		})
+
+		It("restores the stored outputs", func() {
+			Ω(cache.Put(key, outputs)).ShouldNot(HaveOccurred())
+			Ω(os.RemoveAll(outDir)).ShouldNot(HaveOccurred())
+			Ω(os.MkdirAll(filepath.Join(outDir, "swagger"), 0755)).ShouldNot(HaveOccurred())
+			Ω(os.WriteFile(filepath.Join(outDir, "swagger", "stale.json"), nil, 0644)).ShouldNot(HaveOccurred())
+
+			restored, ok, err := cache.Get(key)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(ok).Should(BeTrue())
+			Ω(restored).Should(Equal(outputs))
+
+			b, err := os.ReadFile(filepath.Join(outDir, "swagger", "sub", "file.txt"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(Equal("sub"))
+			info, err := os.Stat(filepath.Join(outDir, "main.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(info.Mode().Perm()).Should(Equal(os.FileMode(0600)))
+			Ω(filepath.Join(outDir, "swagger", "stale.json")).ShouldNot(BeAnExistingFile())
+		})
+	})
+
+	Describe("Prune", func() {
+		const used, unused = "aa00000000000000000000000000000000000000000000000000000000000000",
+			"bb00000000000000000000000000000000000000000000000000000000000000"
+
+		BeforeEach(func() {
+			Ω(cache.Put(used, nil)).ShouldNot(HaveOccurred())
+			Ω(cache.Put(unused, nil)).ShouldNot(HaveOccurred())
+			old := time.Now().Add(-48 * time.Hour)
+			Ω(os.Chtimes(filepath.Join(cacheDir, "bb", unused, "manifest.json"), old, old)).ShouldNot(HaveOccurred())
+		})
+
+		It("deletes the entries not used recently", func() {
+			count, err := cache.Prune(24 * time.Hour)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(count).Should(Equal(1))
+			Ω(filepath.Join(cacheDir, "aa", used)).Should(BeADirectory())
+			Ω(filepath.Join(cacheDir, "bb")).ShouldNot(BeADirectory())
+		})
+
+		It("deletes all the entries given no duration", func() {
+			count, err := cache.Prune(0)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(count).Should(Equal(2))
+		})
+	})
+})
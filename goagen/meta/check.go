@@ -0,0 +1,205 @@
+package meta
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/shogo82148/goa-v1/goagen/codegen"
+)
+
+// Drift describes a generated file whose content differs from the file in the output directory.
+type Drift struct {
+	// Path is the absolute path of the file in the output directory.
+	Path string
+	// Diff is the unified diff from the file in the output directory to the generated file.
+	Diff string
+}
+
+// Check runs the generator in a scratch copy of the output directory and compares the generated
+// files with the files present in the output directory. It never writes to the output
+// directory.
+//
+// Scaffolding generators only create the files that do not exist yet: seed lists the glob
+// patterns, relative to the output directory, of the existing files copied to the scratch
+// directory before the generator runs so that it skips them as it would in the output directory.
+func (m *Generator) Check(seed []string) ([]*Drift, error) {
+	return m.check(seed, (*Generator).Generate)
+}
+
+// check runs the generator in a scratch copy of the output directory using the given function
+// and compares the generated files with the files present in the output directory.
+func (m *Generator) check(seed []string, run func(*Generator) ([]string, error)) ([]*Drift, error) {
+	if m.OutDir == "" {
+		return nil, fmt.Errorf("missing output directory flag")
+	}
+	outDir, err := filepath.Abs(m.OutDir)
+	if err != nil {
+		return nil, err
+	}
+	scratch, err := codegen.ScratchPackageFor(outDir)
+	if err != nil {
+		return nil, err
+	}
+	defer scratch.Workspace.Delete()
+	scratchDir := scratch.Abs()
+
+	for _, pattern := range seed {
+		matches, err := filepath.Glob(filepath.Join(outDir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			rel, err := filepath.Rel(outDir, match)
+			if err != nil {
+				return nil, err
+			}
+			if err := copyTree(match, filepath.Join(scratchDir, rel)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	flags := make(map[string]string, len(m.Flags))
+	for k, v := range m.Flags {
+		flags[k] = v
+	}
+	flags["out"] = scratchDir
+	gen := *m
+	gen.Flags = flags
+	gen.OutDir = scratchDir
+	gen.Cache = nil
+	// Generators may behave differently when run from the output directory, run the generator
+	// from the corresponding scratch directory.
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(outDir, wd); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			gen.dir = filepath.Join(scratchDir, rel)
+			if err := os.MkdirAll(gen.dir, 0755); err != nil {
+				return nil, err
+			}
+		}
+	}
+	files, err := run(&gen)
+	if err != nil {
+		return nil, err
+	}
+	return CompareOutputs(scratchDir, outDir, files)
+}
+
+// CompareOutputs compares the generator outputs located in the scratch directory with the
+// corresponding files in the output directory. Outputs that are directories are compared
+// recursively and the files they contain only in the output directory are reported as deleted.
+// Occurrences of the scratch directory path in the generated files are replaced with the output
+// directory path before comparison.
+func CompareOutputs(scratchDir, outDir string, outputs []string) ([]*Drift, error) {
+	var (
+		paths []string
+		seen  = make(map[string]bool)
+	)
+	add := func(rel string) {
+		if !seen[rel] {
+			seen[rel] = true
+			paths = append(paths, rel)
+		}
+	}
+	for _, out := range outputs {
+		if !filepath.IsAbs(out) {
+			continue
+		}
+		rel, err := filepath.Rel(scratchDir, out)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		info, err := os.Stat(out)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			add(rel)
+			continue
+		}
+		for _, root := range []string{scratchDir, outDir} {
+			dir := filepath.Join(root, rel)
+			if _, err := os.Stat(dir); os.IsNotExist(err) {
+				continue
+			}
+			err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if d.Type().IsRegular() {
+					r, err := filepath.Rel(root, path)
+					if err != nil {
+						return err
+					}
+					add(r)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	sort.Strings(paths)
+
+	var drifts []*Drift
+	for _, rel := range paths {
+		generated, err := readOptional(filepath.Join(scratchDir, rel))
+		if err != nil {
+			return nil, err
+		}
+		generated = bytes.ReplaceAll(generated, []byte(scratchDir), []byte(outDir))
+		path := filepath.Join(outDir, rel)
+		existing, err := readOptional(path)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(generated, existing) {
+			continue
+		}
+		label := filepath.ToSlash(rel)
+		drifts = append(drifts, &Drift{
+			Path: path,
+			Diff: unifiedDiff("a/"+label, "b/"+label, string(existing), string(generated)),
+		})
+	}
+	return drifts, nil
+}
+
+// readOptional returns the content of the given file or nil if it does not exist.
+func readOptional(path string) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return b, err
+}
+
+// copyTree copies the file or directory src to dst.
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return copyFile(path, target, info.Mode().Perm())
+	})
+}
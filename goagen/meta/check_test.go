@@ -0,0 +1,176 @@
+package meta_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1/goagen/codegen"
+	"github.com/shogo82148/goa-v1/goagen/meta"
+)
+
+var _ = Describe("Check", func() {
+	var (
+		projectDir     string
+		wd             string
+		m              *meta.Generator
+		drifts         []*meta.Drift
+		err            error
+		oldGO111MODULE = os.Getenv("GO111MODULE")
+	)
+
+	BeforeEach(func() {
+		os.Setenv("GO111MODULE", "on")
+		// Create the project inside the goa module so that the design and the generated
+		// packages share the module path of the packages used by the generated code.
+		projectDir, err = os.MkdirTemp(".", "check")
+		Ω(err).ShouldNot(HaveOccurred())
+		projectDir, err = filepath.Abs(projectDir)
+		Ω(err).ShouldNot(HaveOccurred())
+		designDir := filepath.Join(projectDir, "design")
+		Ω(os.MkdirAll(designDir, 0755)).ShouldNot(HaveOccurred())
+		Ω(os.WriteFile(filepath.Join(designDir, "design.go"), []byte(checkDesign), 0644)).ShouldNot(HaveOccurred())
+		designPkgPath, err := codegen.PackagePath(designDir)
+		Ω(err).ShouldNot(HaveOccurred())
+		m = &meta.Generator{
+			Genfunc:       "genapp.Generate",
+			Imports:       []*codegen.ImportSpec{codegen.SimpleImport("github.com/shogo82148/goa-v1/goagen/gen_app")},
+			OutDir:        projectDir,
+			DesignPkgPath: designPkgPath,
+			Flags:         map[string]string{"out": projectDir, "design": designPkgPath},
+		}
+		_, err = m.Generate()
+		Ω(err).ShouldNot(HaveOccurred())
+		// goagen runs from the project directory.
+		wd, err = os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(os.Chdir(projectDir)).ShouldNot(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		drifts, err = m.Check(nil)
+	})
+
+	AfterEach(func() {
+		Ω(os.Chdir(wd)).ShouldNot(HaveOccurred())
+		os.RemoveAll(projectDir)
+		os.Setenv("GO111MODULE", oldGO111MODULE)
+	})
+
+	Context("in Module mode with up to date files", func() {
+		It("reports no drift", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(drifts).Should(BeEmpty())
+		})
+	})
+
+	Context("in Module mode with a modified file", func() {
+		BeforeEach(func() {
+			Ω(os.WriteFile(filepath.Join(projectDir, "app", "contexts.go"), []byte("package app\n"), 0644)).ShouldNot(HaveOccurred())
+		})
+
+		It("reports the drift", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(drifts).Should(HaveLen(1))
+			Ω(drifts[0].Path).Should(Equal(filepath.Join(projectDir, "app", "contexts.go")))
+		})
+	})
+})
+
+const checkDesign = `package design
+
+import (
+	. "github.com/shogo82148/goa-v1/design"
+	. "github.com/shogo82148/goa-v1/design/apidsl"
+)
+
+var _ = API("check", nil)
+
+var _ = Resource("bottle", func() {
+	Action("show", func() {
+		Routing(GET("/bottles/:id"))
+		Params(func() {
+			Param("id", Integer)
+		})
+		Response(OK, "text/plain")
+	})
+})
+`
+
+var _ = Describe("CompareOutputs", func() {
+	var (
+		scratchDir, outDir string
+		outputs            []string
+		drifts             []*meta.Drift
+		err                error
+	)
+
+	write := func(dir, name, content string) {
+		path := filepath.Join(dir, name)
+		Ω(os.MkdirAll(filepath.Dir(path), 0755)).ShouldNot(HaveOccurred())
+		Ω(os.WriteFile(path, []byte(content), 0644)).ShouldNot(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		scratchDir, err = os.MkdirTemp("", "scratch")
+		Ω(err).ShouldNot(HaveOccurred())
+		outDir, err = os.MkdirTemp("", "out")
+		Ω(err).ShouldNot(HaveOccurred())
+		outputs = nil
+	})
+
+	JustBeforeEach(func() {
+		drifts, err = meta.CompareOutputs(scratchDir, outDir, outputs)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(scratchDir)
+		os.RemoveAll(outDir)
+	})
+
+	Context("with up to date files", func() {
+		BeforeEach(func() {
+			write(scratchDir, "main.go", "package main\n// out: "+scratchDir+"\n")
+			write(outDir, "main.go", "package main\n// out: "+outDir+"\n")
+			outputs = []string{filepath.Join(scratchDir, "main.go"), "not a file"}
+		})
+
+		It("reports no drift", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(drifts).Should(BeEmpty())
+		})
+	})
+
+	Context("with a modified file", func() {
+		BeforeEach(func() {
+			write(scratchDir, "main.go", "package main\n\nfunc a() {}\n\nfunc b() {}\n")
+			write(outDir, "main.go", "package main\n\nfunc a() {}\n\nfunc c() {}\n")
+			outputs = []string{filepath.Join(scratchDir, "main.go")}
+		})
+
+		It("reports the unified diff", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(drifts).Should(HaveLen(1))
+			Ω(drifts[0].Path).Should(Equal(filepath.Join(outDir, "main.go")))
+			Ω(drifts[0].Diff).Should(Equal("--- a/main.go\n+++ b/main.go\n@@ -2,4 +2,4 @@\n \n func a() {}\n \n-func c() {}\n+func b() {}\n"))
+		})
+	})
+
+	Context("with a generated directory", func() {
+		BeforeEach(func() {
+			write(scratchDir, "app/contexts.go", "package app\n")
+			write(scratchDir, "app/test/test.go", "package test\n")
+			write(outDir, "app/contexts.go", "package app\n")
+			write(outDir, "app/stale.go", "package app\n")
+			outputs = []string{filepath.Join(scratchDir, "app")}
+		})
+
+		It("reports the missing and deleted files", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(drifts).Should(HaveLen(2))
+			Ω(drifts[0].Diff).Should(Equal("--- a/app/stale.go\n+++ /dev/null\n@@ -1 +0,0 @@\n-package app\n"))
+			Ω(drifts[1].Diff).Should(Equal("--- /dev/null\n+++ b/app/test/test.go\n@@ -0,0 +1 @@\n+package test\n"))
+		})
+	})
+})
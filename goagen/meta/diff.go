@@ -0,0 +1,218 @@
+package meta
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines surrounding the changes in unified diff hunks.
+const diffContext = 3
+
+type (
+	// edit is a line of an edit script.
+	edit struct {
+		// op is ' ' for an unchanged line, '-' for a deleted line and '+' for an inserted line.
+		op byte
+		// line is the line content including its end of line if any.
+		line string
+	}
+)
+
+// unifiedDiff returns the unified diff from a to b using the given file labels. It returns an
+// empty string if a and b are identical.
+func unifiedDiff(fromLabel, toLabel, a, b string) string {
+	if a == b {
+		return ""
+	}
+	if a == "" {
+		fromLabel = "/dev/null"
+	}
+	if b == "" {
+		toLabel = "/dev/null"
+	}
+	edits := diffLines(splitLines(a), splitLines(b))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromLabel, toLabel)
+	for start := 0; start < len(edits); {
+		// Find the next change.
+		for start < len(edits) && edits[start].op == ' ' {
+			start++
+		}
+		if start == len(edits) {
+			break
+		}
+		// Extend the hunk while changes are separated by less than twice the context.
+		end := start
+		for i := start; i < len(edits); i++ {
+			if edits[i].op != ' ' {
+				end = i + 1
+			} else if i-end >= 2*diffContext {
+				break
+			}
+		}
+		first := start - diffContext
+		if first < 0 {
+			first = 0
+		}
+		last := end + diffContext
+		if last > len(edits) {
+			last = len(edits)
+		}
+
+		// Compute the hunk line ranges.
+		fromLine, toLine := 1, 1
+		for _, e := range edits[:first] {
+			if e.op != '+' {
+				fromLine++
+			}
+			if e.op != '-' {
+				toLine++
+			}
+		}
+		fromCount, toCount := 0, 0
+		for _, e := range edits[first:last] {
+			if e.op != '+' {
+				fromCount++
+			}
+			if e.op != '-' {
+				toCount++
+			}
+		}
+		if fromCount == 0 {
+			fromLine--
+		}
+		if toCount == 0 {
+			toLine--
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(fromLine, fromCount), hunkRange(toLine, toCount))
+		for _, e := range edits[first:last] {
+			sb.WriteByte(e.op)
+			sb.WriteString(e.line)
+			if !strings.HasSuffix(e.line, "\n") {
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		start = last
+	}
+	return sb.String()
+}
+
+// hunkRange formats a unified diff hunk range.
+func hunkRange(line, count int) string {
+	if count == 1 {
+		return fmt.Sprint(line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
+
+// splitLines splits s after each end of line.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes the shortest edit script from a to b using the Myers algorithm.
+func diffLines(a, b []string) []edit {
+	// Strip the common prefix and suffix to reduce the search space.
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+	edits := make([]edit, 0, len(a)+len(b))
+	for _, l := range a[:pre] {
+		edits = append(edits, edit{' ', l})
+	}
+	edits = append(edits, myers(a[pre:len(a)-suf], b[pre:len(b)-suf])...)
+	for _, l := range a[len(a)-suf:] {
+		edits = append(edits, edit{' ', l})
+	}
+	return edits
+}
+
+// myers computes the shortest edit script from a to b.
+func myers(a, b []string) []edit {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		edits := make([]edit, 0, n+m)
+		for _, l := range a {
+			edits = append(edits, edit{'-', l})
+		}
+		for _, l := range b {
+			edits = append(edits, edit{'+', l})
+		}
+		return edits
+	}
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	var trace [][]int
+	var d int
+search:
+	for d = 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Backtrack through the saved states to build the edit script.
+	edits := make([]edit, 0, n+m)
+	x, y := n, m
+	for ; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, edit{' ', a[x]})
+		}
+		if x == prevX {
+			y--
+			edits = append(edits, edit{'+', b[y]})
+		} else {
+			x--
+			edits = append(edits, edit{'-', a[x]})
+		}
+	}
+	for x > 0 {
+		x--
+		edits = append(edits, edit{' ', a[x]})
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
@@ -42,6 +42,12 @@ type Generator struct {
 	// DesignPkgPath is the Go import path to the design package.
 	DesignPkgPath string
 
+	// Cache stores the generator outputs if not nil. Generate restores the outputs from the
+	// cache instead of compiling and running the generator when its inputs did not change.
+	Cache *Cache
+
+	// dir is the working directory of the generator process, the current directory if empty.
+	dir   string
 	debug bool
 }
 
@@ -93,6 +99,24 @@ func (m *Generator) Generate() ([]string, error) {
 		return nil, err
 	}
 
+	// Restore outputs from cache if possible
+	var key string
+	if m.Cache != nil {
+		var err error
+		if key, err = m.Cache.Key(m); err != nil {
+			if m.debug {
+				fmt.Printf("** Cache disabled: %s\n", err)
+			}
+		} else if files, ok, err := m.Cache.Get(key); err != nil {
+			return nil, err
+		} else if ok {
+			if m.debug {
+				fmt.Printf("** Outputs restored from cache entry %s\n", key)
+			}
+			return files, nil
+		}
+	}
+
 	// Create temporary workspace used for generation
 	wd, err := os.Getwd()
 	if err != nil {
@@ -139,7 +163,16 @@ func (m *Generator) Generate() ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
-	return m.spawn(genbin)
+	files, err := m.spawn(genbin)
+	if err != nil {
+		return nil, err
+	}
+	if key != "" {
+		if err := m.Cache.Put(key, files); err != nil && m.debug {
+			fmt.Printf("** Failed to cache outputs: %s\n", err)
+		}
+	}
+	return files, nil
 }
 
 func (m *Generator) generateToolSourceCode(pkg *codegen.Package) {
@@ -174,19 +207,21 @@ func (m *Generator) generateToolSourceCode(pkg *codegen.Package) {
 }
 
 // spawn runs the compiled generator using the arguments initialized by Kingpin
-// when parsing the command line.
-func (m *Generator) spawn(genbin string) ([]string, error) {
-	var args []string
+// when parsing the command line. The given arguments are inserted before the generator flags.
+func (m *Generator) spawn(genbin string, args ...string) ([]string, error) {
+	var flags []string
 	for k, v := range m.Flags {
-		if k == "debug" {
+		if k == "debug" || k == "no-cache" || k == "check" {
 			continue
 		}
-		args = append(args, fmt.Sprintf("--%s=%s", k, v))
+		flags = append(flags, fmt.Sprintf("--%s=%s", k, v))
 	}
-	sort.Strings(args)
+	sort.Strings(flags)
+	args = append(args, flags...)
 	args = append(args, "--version="+version.String())
 	args = append(args, m.CustomFlags...)
 	cmd := exec.Command(genbin, args...)
+	cmd.Dir = m.dir
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		return nil, fmt.Errorf("%s\n%s", err, string(out))
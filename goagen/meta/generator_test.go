@@ -82,9 +82,10 @@ var _ = Describe("Run", func() {
 	})
 
 	AfterEach(func() {
+		// Delete the workspaces in reverse order of creation to restore GOPATH.
+		genWorkspace.Delete()
 		designWorkspace.Delete()
 		outputWorkspace.Delete()
-		genWorkspace.Delete()
 	})
 
 	// FIXME: @shogo82148
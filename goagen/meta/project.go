@@ -0,0 +1,245 @@
+package meta
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ProjectFile is the name of the goagen project configuration file.
+const ProjectFile = "goagen.yaml"
+
+type (
+	// Project is a goagen project configuration. It declares the design package and the
+	// generator runs that produce the project artifacts, for example:
+	//
+	//	design: github.com/acme/cellar/design
+	//	runs:
+	//	- gen: app
+	//	- gen: main
+	//	  after: [app]
+	//	- gen: client
+	//	  options:
+	//	    notool: true
+	//	- gen: swagger
+	//	  out: public
+	//	- name: types
+	//	  pkg-path: github.com/acme/gen/types
+	//	  args: [--strict]
+	Project struct {
+		// Design is the import path of the design package.
+		Design string `yaml:"design"`
+		// Out is the default output directory of the runs, relative to the configuration
+		// file directory. It defaults to the configuration file directory.
+		Out string `yaml:"out"`
+		// Runs lists the generator runs.
+		Runs []*Run `yaml:"runs"`
+		// Dir is the absolute path to the directory containing the configuration file.
+		Dir string `yaml:"-"`
+	}
+
+	// Run is a generator run of a project.
+	Run struct {
+		// Name identifies the run in After and in error messages. It defaults to the
+		// built-in generator command name or to the last element of the plugin import path.
+		Name string `yaml:"name"`
+		// Gen is the name of the goagen command implementing a built-in generator such as
+		// "app" or "swagger".
+		Gen string `yaml:"gen"`
+		// PkgPath is the import path of a third-party generator package as given to
+		// "goagen gen --pkg-path".
+		PkgPath string `yaml:"pkg-path"`
+		// Out is the output directory of the run, relative to the configuration file
+		// directory. It defaults to the project output directory.
+		Out string `yaml:"out"`
+		// Options maps the generator command line flag names to their values. A relative
+		// "templates" directory is relative to the configuration file directory.
+		Options map[string]interface{} `yaml:"options"`
+		// Args lists the custom arguments of third-party generators.
+		Args []string `yaml:"args"`
+		// After lists the names of the runs that must complete before this one starts.
+		// Other runs are independent and may run in parallel.
+		After []string `yaml:"after"`
+	}
+)
+
+// LoadProject reads and validates the project configuration file at the given path.
+func LoadProject(path string) (*Project, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Project
+	if err := yaml.UnmarshalStrict(b, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %s", path, err)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	p.Dir = filepath.Dir(abs)
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %s", path, err)
+	}
+	return &p, nil
+}
+
+// OutDir returns the absolute path to the output directory of the given run.
+func (p *Project) OutDir(r *Run) string {
+	out := r.Out
+	if out == "" {
+		out = p.Out
+	}
+	if filepath.IsAbs(out) {
+		return out
+	}
+	return filepath.Join(p.Dir, out)
+}
+
+// Flags returns the generator flags set by the options of the given run.
+func (r *Run) Flags() map[string]string {
+	flags := make(map[string]string, len(r.Options))
+	for k, v := range r.Options {
+		flags[k] = fmt.Sprint(v)
+	}
+	return flags
+}
+
+// Execute calls fn for each run and returns the concatenation of the results in the order of
+// the runs. A run starts once all the runs it comes after have completed, at most parallel runs
+// execute at the same time. Runs that come after a failed run are skipped.
+func (p *Project) Execute(parallel int, fn func(*Run) ([]string, error)) ([]string, error) {
+	if parallel < 1 {
+		parallel = 1
+	}
+	var (
+		results = make([][]string, len(p.Runs))
+		errs    = make([]error, len(p.Runs))
+		done    = make(map[string]chan struct{}, len(p.Runs))
+		failed  = make(map[string]bool)
+		mu      sync.Mutex
+		sem     = make(chan struct{}, parallel)
+		wg      sync.WaitGroup
+	)
+	for _, r := range p.Runs {
+		done[r.Name] = make(chan struct{})
+	}
+	for i, r := range p.Runs {
+		wg.Add(1)
+		go func(i int, r *Run) {
+			defer wg.Done()
+			defer close(done[r.Name])
+			for _, dep := range r.After {
+				<-done[dep]
+				mu.Lock()
+				skip := failed[dep]
+				mu.Unlock()
+				if skip {
+					mu.Lock()
+					failed[r.Name] = true
+					mu.Unlock()
+					return
+				}
+			}
+			sem <- struct{}{}
+			results[i], errs[i] = fn(r)
+			<-sem
+			if errs[i] != nil {
+				mu.Lock()
+				failed[r.Name] = true
+				mu.Unlock()
+			}
+		}(i, r)
+	}
+	wg.Wait()
+
+	var (
+		files []string
+		msgs  []string
+	)
+	for i, r := range p.Runs {
+		if errs[i] != nil {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", r.Name, errs[i]))
+		}
+		files = append(files, results[i]...)
+	}
+	if len(msgs) > 0 {
+		return files, fmt.Errorf("%s", strings.Join(msgs, "\n"))
+	}
+	return files, nil
+}
+
+// validate checks the project configuration and sets the default run names.
+func (p *Project) validate() error {
+	if p.Design == "" {
+		return fmt.Errorf("missing design package")
+	}
+	if len(p.Runs) == 0 {
+		return fmt.Errorf("missing runs")
+	}
+	names := make(map[string]*Run, len(p.Runs))
+	for i, r := range p.Runs {
+		switch {
+		case r.Gen != "" && r.PkgPath != "":
+			return fmt.Errorf("run #%d: gen and pkg-path are mutually exclusive", i+1)
+		case r.Gen == "" && r.PkgPath == "":
+			return fmt.Errorf("run #%d: missing gen or pkg-path", i+1)
+		case r.Gen != "" && len(r.Args) > 0:
+			return fmt.Errorf("run #%d: args are only supported by third-party generators", i+1)
+		}
+		if r.Name == "" {
+			r.Name = r.Gen
+			if r.Name == "" {
+				r.Name = r.PkgPath[strings.LastIndex(r.PkgPath, "/")+1:]
+			}
+		}
+		if _, ok := names[r.Name]; ok {
+			return fmt.Errorf("duplicate run name %q, set distinct names", r.Name)
+		}
+		names[r.Name] = r
+	}
+	for _, r := range p.Runs {
+		for _, dep := range r.After {
+			if _, ok := names[dep]; !ok {
+				return fmt.Errorf("run %q: unknown run %q in after", r.Name, dep)
+			}
+		}
+	}
+
+	// Detect dependency cycles with a depth-first traversal.
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[string]int, len(p.Runs))
+	var visit func(r *Run, path []string) error
+	visit = func(r *Run, path []string) error {
+		switch state[r.Name] {
+		case visiting:
+			return fmt.Errorf("dependency cycle %s", strings.Join(append(path, r.Name), " -> "))
+		case visited:
+			return nil
+		}
+		state[r.Name] = visiting
+		deps := append([]string(nil), r.After...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if err := visit(names[dep], append(path, r.Name)); err != nil {
+				return err
+			}
+		}
+		state[r.Name] = visited
+		return nil
+	}
+	for _, r := range p.Runs {
+		if err := visit(r, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
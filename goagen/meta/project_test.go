@@ -0,0 +1,157 @@
+package meta_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1/goagen/meta"
+)
+
+var _ = Describe("LoadProject", func() {
+	var (
+		dir, content string
+		project      *meta.Project
+		err          error
+	)
+
+	BeforeEach(func() {
+		dir, err = os.MkdirTemp("", "project")
+		Ω(err).ShouldNot(HaveOccurred())
+		content = `
+design: github.com/acme/cellar/design
+runs:
+- gen: app
+- gen: main
+  after: [app]
+- gen: swagger
+  out: public
+- pkg-path: github.com/acme/gen/types
+  args: [--strict]
+  options:
+    flag: true
+`
+	})
+
+	JustBeforeEach(func() {
+		path := filepath.Join(dir, meta.ProjectFile)
+		Ω(os.WriteFile(path, []byte(content), 0644)).ShouldNot(HaveOccurred())
+		project, err = meta.LoadProject(path)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("loads the runs", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(project.Design).Should(Equal("github.com/acme/cellar/design"))
+		Ω(project.Runs).Should(HaveLen(4))
+		Ω(project.Runs[1].Name).Should(Equal("main"))
+		Ω(project.Runs[1].After).Should(Equal([]string{"app"}))
+		Ω(project.Runs[3].Name).Should(Equal("types"))
+		Ω(project.Runs[3].Args).Should(Equal([]string{"--strict"}))
+		Ω(project.Runs[3].Flags()).Should(Equal(map[string]string{"flag": "true"}))
+		Ω(project.OutDir(project.Runs[0])).Should(Equal(project.Dir))
+		Ω(project.OutDir(project.Runs[2])).Should(Equal(filepath.Join(project.Dir, "public")))
+	})
+
+	Context("with an unknown field", func() {
+		BeforeEach(func() {
+			content = "design: design\nrun:\n- gen: app\n"
+		})
+
+		It("fails with a useful error message", func() {
+			Ω(err).Should(MatchError(ContainSubstring("field run not found")))
+		})
+	})
+
+	Context("with duplicate run names", func() {
+		BeforeEach(func() {
+			content = "design: design\nruns:\n- gen: swagger\n- gen: swagger\n  out: public\n"
+		})
+
+		It("fails with a useful error message", func() {
+			Ω(err).Should(MatchError(HaveSuffix(`duplicate run name "swagger", set distinct names`)))
+		})
+	})
+
+	Context("with a run that is both built-in and third-party", func() {
+		BeforeEach(func() {
+			content = "design: design\nruns:\n- gen: app\n  pkg-path: github.com/acme/gen\n"
+		})
+
+		It("fails with a useful error message", func() {
+			Ω(err).Should(MatchError(HaveSuffix("run #1: gen and pkg-path are mutually exclusive")))
+		})
+	})
+
+	Context("with an unknown run in after", func() {
+		BeforeEach(func() {
+			content = "design: design\nruns:\n- gen: app\n  after: [main]\n"
+		})
+
+		It("fails with a useful error message", func() {
+			Ω(err).Should(MatchError(HaveSuffix(`run "app": unknown run "main" in after`)))
+		})
+	})
+
+	Context("with a dependency cycle", func() {
+		BeforeEach(func() {
+			content = "design: design\nruns:\n- gen: app\n  after: [main]\n- gen: main\n  after: [app]\n"
+		})
+
+		It("fails with a useful error message", func() {
+			Ω(err).Should(MatchError(HaveSuffix("dependency cycle app -> main -> app")))
+		})
+	})
+})
+
+var _ = Describe("Project.Execute", func() {
+	var project *meta.Project
+
+	BeforeEach(func() {
+		project = &meta.Project{Runs: []*meta.Run{
+			{Name: "app"},
+			{Name: "main", After: []string{"app"}},
+			{Name: "swagger"},
+		}}
+	})
+
+	It("runs the runs after their dependencies", func() {
+		var (
+			mu    sync.Mutex
+			order []string
+		)
+		files, err := project.Execute(4, func(r *meta.Run) ([]string, error) {
+			mu.Lock()
+			order = append(order, r.Name)
+			mu.Unlock()
+			return []string{r.Name + ".go"}, nil
+		})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(files).Should(Equal([]string{"app.go", "main.go", "swagger.go"}))
+		Ω(order).Should(ConsistOf("app", "main", "swagger"))
+		for i, name := range order {
+			if name == "main" {
+				Ω(order[:i]).Should(ContainElement("app"))
+			}
+		}
+	})
+
+	It("skips the runs that come after a failed run", func() {
+		var ran []string
+		_, err := project.Execute(1, func(r *meta.Run) ([]string, error) {
+			ran = append(ran, r.Name)
+			if r.Name == "app" {
+				return nil, fmt.Errorf("boom")
+			}
+			return nil, nil
+		})
+		Ω(err).Should(MatchError("app: boom"))
+		Ω(ran).ShouldNot(ContainElement("main"))
+	})
+})
@@ -0,0 +1,191 @@
+package meta
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/shogo82148/goa-v1/goagen/codegen"
+)
+
+// Tool is a generator tool that runs any number of generators. Unlike Generator which compiles
+// a tool for each run, Tool keeps the tool sources and binary across builds: rebuilding the tool
+// after a change to the design package only recompiles the design package and links the binary,
+// the compiled generator packages are reused. Each generator runs in its own process so that the
+// generators do not share state.
+type Tool struct {
+	// Generators lists the generators run by the tool.
+	Generators []*Generator
+
+	// DesignPkgPath is the Go import path to the design package.
+	DesignPkgPath string
+
+	// Dir is the directory containing the tool sources and binary, it is created by the
+	// first call to Build.
+	Dir string
+
+	pkg   *codegen.Package
+	bin   string
+	debug bool
+}
+
+// NewTool returns a tool that runs the given generators. The generators must all use the same
+// design package.
+func NewTool(generators []*Generator) (*Tool, error) {
+	if len(generators) == 0 {
+		return nil, fmt.Errorf("missing generators")
+	}
+	t := &Tool{Generators: generators, DesignPkgPath: generators[0].DesignPkgPath}
+	if t.DesignPkgPath == "" {
+		return nil, fmt.Errorf("missing design package flag")
+	}
+	for _, g := range generators {
+		if g.DesignPkgPath != t.DesignPkgPath {
+			return nil, fmt.Errorf("generators use different design packages %s and %s", t.DesignPkgPath, g.DesignPkgPath)
+		}
+		if g.OutDir == "" {
+			return nil, fmt.Errorf("missing output directory flag")
+		}
+		t.debug = t.debug || g.debug
+	}
+	return t, nil
+}
+
+// Build compiles the tool. The first call generates the tool sources, subsequent calls reuse
+// them.
+func (t *Tool) Build() error {
+	if t.pkg == nil {
+		pkgSourcePath, err := codegen.PackageSourcePath(t.DesignPkgPath)
+		if err != nil {
+			return fmt.Errorf("invalid design package import path: %s", err)
+		}
+		pkgName, err := codegen.PackageName(pkgSourcePath)
+		if err != nil {
+			return err
+		}
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		dir, err := os.MkdirTemp(wd, "goagen")
+		if err != nil {
+			return err
+		}
+		if t.debug {
+			fmt.Printf("** Code generator source dir: %s\n", dir)
+		}
+		p, err := codegen.PackageFor(filepath.Join(dir, pkgName))
+		if err != nil {
+			os.RemoveAll(dir)
+			return err
+		}
+		t.Dir, t.pkg = dir, p
+		t.generateToolSourceCode(p)
+	}
+	bin, err := t.pkg.Compile("goagen")
+	if err != nil {
+		return err
+	}
+	t.bin = bin
+	return nil
+}
+
+// Run runs the given generator with the tool compiled by the last call to Build and returns the
+// generated filenames.
+func (t *Tool) Run(g *Generator) ([]string, error) {
+	if t.bin == "" {
+		return nil, fmt.Errorf("tool is not built")
+	}
+	if err := os.MkdirAll(g.OutDir, 0755); err != nil {
+		return nil, err
+	}
+	return g.spawn(t.bin, g.Genfunc)
+}
+
+// Check runs the given generator with the tool compiled by the last call to Build in a scratch
+// copy of its output directory and compares the generated files with the files present in the
+// output directory, see Generator.Check.
+func (t *Tool) Check(g *Generator, seed []string) ([]*Drift, error) {
+	return g.check(seed, t.Run)
+}
+
+// Close deletes the tool sources and binary unless debug is enabled.
+func (t *Tool) Close() error {
+	if t.Dir == "" || t.debug {
+		return nil
+	}
+	dir := t.Dir
+	t.Dir, t.pkg, t.bin = "", nil, ""
+	return os.RemoveAll(dir)
+}
+
+func (t *Tool) generateToolSourceCode(pkg *codegen.Package) {
+	file, err := pkg.CreateSourceFile("main.go")
+	if err != nil {
+		panic(err) // bug
+	}
+	defer file.Close()
+	var (
+		imports  []*codegen.ImportSpec
+		seen     = make(map[string]bool)
+		genfuncs []string
+	)
+	for _, g := range t.Generators {
+		for _, imp := range g.Imports {
+			if !seen[imp.Path] {
+				seen[imp.Path] = true
+				imports = append(imports, imp)
+			}
+		}
+		if !seen[g.Genfunc] {
+			seen[g.Genfunc] = true
+			genfuncs = append(genfuncs, g.Genfunc)
+		}
+	}
+	imports = append(imports,
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("os"),
+		codegen.SimpleImport("strings"),
+		codegen.SimpleImport("github.com/shogo82148/goa-v1/dslengine"),
+		codegen.NewImport("_", filepath.ToSlash(t.DesignPkgPath)),
+	)
+	file.WriteHeader("Code Generator", "main", imports)
+	tmpl, err := template.New("tool").Parse(toolTmpl)
+	if err != nil {
+		panic(err) // bug
+	}
+	if err := tmpl.Execute(file, genfuncs); err != nil {
+		panic(err) // bug
+	}
+}
+
+const toolTmpl = `
+func main() {
+	// Check if there were errors while running the first DSL pass
+	dslengine.FailOnError(dslengine.Errors)
+
+	// Now run the secondary DSLs
+	dslengine.FailOnError(dslengine.Run())
+
+	// Select the generator, the first argument is the name of its entry point
+	var genfunc func() ([]string, error)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+{{- range .}}
+		case {{printf "%q" .}}:
+			genfunc = {{.}}
+{{- end}}
+		}
+	}
+	if genfunc == nil {
+		dslengine.FailOnError(fmt.Errorf("unknown generator"))
+	}
+	os.Args = append(os.Args[:1], os.Args[2:]...)
+
+	files, err := genfunc()
+	dslengine.FailOnError(err)
+
+	// We're done
+	fmt.Println(strings.Join(files, "\n"))
+}`
@@ -0,0 +1,59 @@
+package meta_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1/goagen/meta"
+)
+
+var _ = Describe("NewTool", func() {
+	var (
+		generators []*meta.Generator
+		err        error
+	)
+
+	BeforeEach(func() {
+		generators = []*meta.Generator{
+			{Genfunc: "genapp.Generate", DesignPkgPath: "design", OutDir: "out"},
+			{Genfunc: "genswagger.Generate", DesignPkgPath: "design", OutDir: "out"},
+		}
+	})
+
+	JustBeforeEach(func() {
+		_, err = meta.NewTool(generators)
+	})
+
+	It("accepts generators sharing a design package", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("with no generator", func() {
+		BeforeEach(func() {
+			generators = nil
+		})
+
+		It("fails with a useful error message", func() {
+			Ω(err).Should(MatchError("missing generators"))
+		})
+	})
+
+	Context("with generators using different design packages", func() {
+		BeforeEach(func() {
+			generators[1].DesignPkgPath = "other"
+		})
+
+		It("fails with a useful error message", func() {
+			Ω(err).Should(MatchError("generators use different design packages design and other"))
+		})
+	})
+
+	Context("with no output directory specified", func() {
+		BeforeEach(func() {
+			generators[1].OutDir = ""
+		})
+
+		It("fails with a useful error message", func() {
+			Ω(err).Should(MatchError("missing output directory flag"))
+		})
+	})
+})
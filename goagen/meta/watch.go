@@ -0,0 +1,121 @@
+package meta
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Watcher polls directories for changes to the Go source files they contain, subdirectories
+// included. Test files, hidden directories and directories whose names start with "_" or are
+// "testdata" are ignored.
+type Watcher struct {
+	// Dirs lists the watched directories.
+	Dirs []string
+
+	// Interval is the delay between two polls.
+	Interval time.Duration
+
+	files map[string]fileState
+}
+
+// fileState is the state of a source file used to detect changes.
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// NewWatcher returns a watcher that polls the given directories every interval. It records the
+// current state of the directories so that Changed only reports subsequent changes.
+func NewWatcher(interval time.Duration, dirs ...string) (*Watcher, error) {
+	w := &Watcher{Dirs: dirs, Interval: interval}
+	files, err := w.scan()
+	if err != nil {
+		return nil, err
+	}
+	w.files = files
+	return w, nil
+}
+
+// Changed returns true if a source file was added, removed or modified since the last call to
+// Changed or since the watcher was created.
+func (w *Watcher) Changed() (bool, error) {
+	files, err := w.scan()
+	if err != nil {
+		return false, err
+	}
+	changed := len(files) != len(w.files)
+	if !changed {
+		for path, st := range files {
+			if prev, ok := w.files[path]; !ok || prev.size != st.size || !prev.modTime.Equal(st.modTime) {
+				changed = true
+				break
+			}
+		}
+	}
+	w.files = files
+	return changed, nil
+}
+
+// Watch calls onChange each time Changed reports a change until stop is closed. Changes made
+// while onChange runs, by generators writing to the watched directories for example, are not
+// reported. Errors that occur while polling are given to onError and do not stop the watch.
+func (w *Watcher) Watch(stop <-chan struct{}, onChange func(), onError func(error)) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			changed, err := w.Changed()
+			if err != nil {
+				onError(err)
+				continue
+			}
+			if changed {
+				onChange()
+				if files, err := w.scan(); err == nil {
+					w.files = files
+				}
+			}
+		}
+	}
+}
+
+// scan returns the state of the source files of the watched directories indexed by path.
+func (w *Watcher) scan() (map[string]fileState, error) {
+	files := make(map[string]fileState)
+	for _, dir := range w.Dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			name := d.Name()
+			if d.IsDir() {
+				if path != dir && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil // removed while scanning
+				}
+				return err
+			}
+			files[path] = fileState{size: info.Size(), modTime: info.ModTime()}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
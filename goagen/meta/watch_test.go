@@ -0,0 +1,82 @@
+package meta_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1/goagen/meta"
+)
+
+var _ = Describe("Watcher", func() {
+	var (
+		dir     string
+		watcher *meta.Watcher
+	)
+
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		Ω(os.MkdirAll(filepath.Dir(path), 0755)).ShouldNot(HaveOccurred())
+		Ω(os.WriteFile(path, []byte(content), 0644)).ShouldNot(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "watch")
+		Ω(err).ShouldNot(HaveOccurred())
+		write("design.go", "package design")
+		watcher, err = meta.NewWatcher(time.Millisecond, dir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	changed := func() bool {
+		c, err := watcher.Changed()
+		Ω(err).ShouldNot(HaveOccurred())
+		return c
+	}
+
+	It("does not report unchanged sources", func() {
+		Ω(changed()).Should(BeFalse())
+	})
+
+	It("reports modified sources once", func() {
+		write("design.go", "package design // modified")
+		Ω(changed()).Should(BeTrue())
+		Ω(changed()).Should(BeFalse())
+	})
+
+	It("reports added and removed sources", func() {
+		write("types/types.go", "package types")
+		Ω(changed()).Should(BeTrue())
+		Ω(os.Remove(filepath.Join(dir, "types", "types.go"))).ShouldNot(HaveOccurred())
+		Ω(changed()).Should(BeTrue())
+	})
+
+	It("ignores test files, other files and hidden directories", func() {
+		write("design_test.go", "package design")
+		write("README.md", "design")
+		write(".git/hook.go", "package hook")
+		write("testdata/data.go", "package data")
+		Ω(changed()).Should(BeFalse())
+	})
+
+	It("calls the change handler until stopped", func() {
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		calls := make(chan struct{}, 1)
+		go func() {
+			watcher.Watch(stop, func() { calls <- struct{}{} }, func(err error) { Fail(err.Error()) })
+			close(done)
+		}()
+		write("design.go", "package design // modified")
+		Eventually(calls).Should(Receive())
+		close(stop)
+		Eventually(done).Should(BeClosed())
+	})
+})
@@ -0,0 +1,503 @@
+package goa
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type (
+	// HypermediaSchema describes how the values of a media type are rendered as HAL or JSON:API
+	// documents.
+	HypermediaSchema struct {
+		// Type is the JSON:API type of the resources.
+		Type string
+		// Embedded maps the names of the attributes holding related resources to the names
+		// of the schemas of these resources.
+		Embedded map[string]string
+		// Links maps the names of the links rendered in the "links" attribute to the names
+		// of the schemas of the linked resources.
+		Links map[string]string
+	}
+
+	// HypermediaSchemas indexes hypermedia schemas by name.
+	HypermediaSchemas map[string]*HypermediaSchema
+
+	// HALMarshaler is the interface implemented by the types that render as HAL documents.
+	HALMarshaler interface {
+		// MarshalHAL returns the HAL document rendering the value.
+		MarshalHAL() (interface{}, error)
+	}
+
+	// JSONAPIMarshaler is the interface implemented by the types that render as JSON:API
+	// documents.
+	JSONAPIMarshaler interface {
+		// MarshalJSONAPI returns the JSON:API document rendering the value.
+		MarshalJSONAPI() (interface{}, error)
+	}
+
+	// jsonapiDocument collects the resources included in a JSON:API document.
+	jsonapiDocument struct {
+		schemas  HypermediaSchemas
+		included []interface{}
+		seen     map[string]bool
+	}
+)
+
+// MarshalHAL returns the HAL document rendering v. name is the name of the schema of v or of its
+// elements if v is a collection. The "href" attribute and the links are rendered in the "_links"
+// object, link objects keep the attributes rendered by the link view, the related resources in
+// the "_embedded" object and the elements of collections in the "items" array of the "_embedded"
+// object.
+func (s HypermediaSchemas) MarshalHAL(v interface{}, name string) (interface{}, error) {
+	val, err := toJSONValue(v)
+	if err != nil {
+		return nil, err
+	}
+	if elems, ok := val.([]interface{}); ok {
+		for i, e := range elems {
+			elems[i] = s.halResource(e, name)
+		}
+		return map[string]interface{}{"_embedded": map[string]interface{}{"items": elems}}, nil
+	}
+	return s.halResource(val, name), nil
+}
+
+// MarshalJSONAPI returns the JSON:API document rendering v. name is the name of the schema of v
+// or of its elements if v is a collection. The resources are rendered in the "data" member and the
+// related resources in the "included" member.
+func (s HypermediaSchemas) MarshalJSONAPI(v interface{}, name string) (interface{}, error) {
+	val, err := toJSONValue(v)
+	if err != nil {
+		return nil, err
+	}
+	doc := &jsonapiDocument{schemas: s, seen: make(map[string]bool)}
+	var data interface{}
+	if elems, ok := val.([]interface{}); ok {
+		res := make([]interface{}, len(elems))
+		for i, e := range elems {
+			res[i] = doc.resource(e, name)
+		}
+		data = res
+	} else if val != nil {
+		data = doc.resource(val, name)
+	}
+	res := map[string]interface{}{"data": data}
+	if len(doc.included) > 0 {
+		res["included"] = doc.included
+	}
+	return res, nil
+}
+
+// halResource renders the JSON value v using the schema with the given name.
+func (s HypermediaSchemas) halResource(v interface{}, name string) interface{} {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	schema := s[name]
+	if schema == nil {
+		schema = &HypermediaSchema{}
+	}
+	links := make(map[string]interface{})
+	if href, ok := obj["href"].(string); ok {
+		links["self"] = map[string]interface{}{"href": href}
+		delete(obj, "href")
+	}
+	if ls, ok := obj["links"].(map[string]interface{}); ok && len(schema.Links) > 0 {
+		for n, l := range ls {
+			if lo, ok := l.(map[string]interface{}); ok && lo["href"] != nil {
+				links[n] = lo
+			}
+		}
+		delete(obj, "links")
+	}
+	embedded := make(map[string]interface{})
+	for _, n := range sortedNames(schema.Embedded) {
+		target := schema.Embedded[n]
+		e, ok := obj[n]
+		if !ok || e == nil {
+			continue
+		}
+		if elems, ok := e.([]interface{}); ok {
+			for i, elem := range elems {
+				elems[i] = s.halResource(elem, target)
+			}
+		} else {
+			e = s.halResource(e, target)
+		}
+		embedded[n] = e
+		delete(obj, n)
+	}
+	if len(links) > 0 {
+		obj["_links"] = links
+	}
+	if len(embedded) > 0 {
+		obj["_embedded"] = embedded
+	}
+	return obj
+}
+
+// resource renders the JSON value v as a JSON:API resource using the schema with the given name.
+func (d *jsonapiDocument) resource(v interface{}, name string) interface{} {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	schema := d.schemas[name]
+	if schema == nil {
+		schema = &HypermediaSchema{}
+	}
+	res := map[string]interface{}{"type": schema.Type}
+	if id, ok := obj["id"]; ok && id != nil {
+		res["id"] = jsonapiID(id)
+		d.seen[schema.Type+"/"+jsonapiID(id)] = true
+		delete(obj, "id")
+	}
+	if href, ok := obj["href"].(string); ok {
+		res["links"] = map[string]interface{}{"self": href}
+		delete(obj, "href")
+	}
+	rels := make(map[string]interface{})
+	for _, n := range sortedNames(schema.Embedded) {
+		target := schema.Embedded[n]
+		e, ok := obj[n]
+		if !ok {
+			continue
+		}
+		if e == nil {
+			rels[n] = map[string]interface{}{"data": nil}
+			delete(obj, n)
+			continue
+		}
+		if elems, ok := e.([]interface{}); ok {
+			ids := make([]interface{}, 0, len(elems))
+			for _, elem := range elems {
+				id := d.include(elem, target)
+				if id == nil {
+					ids = nil
+					break
+				}
+				ids = append(ids, id)
+			}
+			if ids == nil {
+				continue
+			}
+			rels[n] = map[string]interface{}{"data": ids}
+		} else {
+			id := d.include(e, target)
+			if id == nil {
+				continue
+			}
+			rels[n] = map[string]interface{}{"data": id}
+		}
+		delete(obj, n)
+	}
+	if ls, ok := obj["links"].(map[string]interface{}); ok && len(schema.Links) > 0 {
+		for n, l := range ls {
+			lo, ok := l.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			rel := make(map[string]interface{})
+			if href, ok := lo["href"].(string); ok {
+				rel["links"] = map[string]interface{}{"related": href}
+			}
+			if id, ok := lo["id"]; ok && id != nil {
+				if target := d.schemas[schema.Links[n]]; target != nil {
+					rel["data"] = map[string]interface{}{"type": target.Type, "id": jsonapiID(id)}
+				}
+			}
+			if existing, ok := rels[n].(map[string]interface{}); ok {
+				if links, ok := rel["links"]; ok {
+					existing["links"] = links
+				}
+				continue
+			}
+			rels[n] = rel
+		}
+		delete(obj, "links")
+	}
+	if len(obj) > 0 {
+		res["attributes"] = obj
+	}
+	if len(rels) > 0 {
+		res["relationships"] = rels
+	}
+	return res
+}
+
+// include adds the JSON:API resource rendering v with the schema with the given name to the
+// included resources and returns its resource identifier. include returns nil if v does not have
+// an id.
+func (d *jsonapiDocument) include(v interface{}, name string) interface{} {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	id, ok := obj["id"]
+	if !ok || id == nil {
+		return nil
+	}
+	typ := ""
+	if schema := d.schemas[name]; schema != nil {
+		typ = schema.Type
+	}
+	ident := map[string]interface{}{"type": typ, "id": jsonapiID(id)}
+	key := typ + "/" + jsonapiID(id)
+	if !d.seen[key] {
+		d.seen[key] = true
+		d.included = append(d.included, d.resource(obj, name))
+	}
+	return ident
+}
+
+// UnmarshalHAL converts the HAL document doc into the JSON value of the rendered media type or
+// collection. It reverts the changes made by MarshalHAL.
+func UnmarshalHAL(doc interface{}, collection bool) interface{} {
+	if collection {
+		if obj, ok := doc.(map[string]interface{}); ok {
+			if emb, ok := obj["_embedded"].(map[string]interface{}); ok {
+				if items, ok := emb["items"].([]interface{}); ok {
+					doc = items
+				}
+			}
+		}
+	}
+	return fromHAL(doc)
+}
+
+// fromHAL converts the HAL resources found in v into plain JSON values.
+func fromHAL(v interface{}) interface{} {
+	switch actual := v.(type) {
+	case []interface{}:
+		for i, e := range actual {
+			actual[i] = fromHAL(e)
+		}
+	case map[string]interface{}:
+		if links, ok := actual["_links"].(map[string]interface{}); ok {
+			delete(actual, "_links")
+			plain := make(map[string]interface{})
+			for n, l := range links {
+				lo, ok := l.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if n == "self" {
+					actual["href"] = lo["href"]
+					continue
+				}
+				plain[n] = lo
+			}
+			if len(plain) > 0 {
+				actual["links"] = plain
+			}
+		}
+		if emb, ok := actual["_embedded"].(map[string]interface{}); ok {
+			delete(actual, "_embedded")
+			for n, e := range emb {
+				actual[n] = fromHAL(e)
+			}
+		}
+	}
+	return v
+}
+
+// UnmarshalJSONAPI converts the JSON:API document doc into the JSON value of the rendered media
+// type or collection. It reverts the changes made by MarshalJSONAPI, the related resources are
+// looked up in the included resources.
+func UnmarshalJSONAPI(doc interface{}) (interface{}, error) {
+	obj, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid JSON:API document, must be an object")
+	}
+	if errs, ok := obj["errors"]; ok {
+		return nil, fmt.Errorf("JSON:API document contains errors: %v", errs)
+	}
+	included := make(map[string]map[string]interface{})
+	if inc, ok := obj["included"].([]interface{}); ok {
+		for _, r := range inc {
+			if ro, ok := r.(map[string]interface{}); ok {
+				included[jsonapiKey(ro)] = ro
+			}
+		}
+	}
+	data := obj["data"]
+	if elems, ok := data.([]interface{}); ok {
+		res := make([]interface{}, len(elems))
+		for i, e := range elems {
+			res[i] = fromJSONAPI(e, included, make(map[string]bool))
+		}
+		return res, nil
+	}
+	return fromJSONAPI(data, included, make(map[string]bool)), nil
+}
+
+// fromJSONAPI converts the JSON:API resource v into a plain JSON value. visiting records the keys
+// of the resources being converted to stop on cycles.
+func fromJSONAPI(v interface{}, included map[string]map[string]interface{}, visiting map[string]bool) interface{} {
+	r, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	key := jsonapiKey(r)
+	visiting[key] = true
+	defer delete(visiting, key)
+	res := make(map[string]interface{})
+	if attrs, ok := r["attributes"].(map[string]interface{}); ok {
+		for n, a := range attrs {
+			res[n] = a
+		}
+	}
+	if id, ok := r["id"]; ok {
+		res["id"] = id
+	}
+	if links, ok := r["links"].(map[string]interface{}); ok {
+		if self, ok := links["self"]; ok {
+			res["href"] = self
+		}
+	}
+	rels, _ := r["relationships"].(map[string]interface{})
+	plain := make(map[string]interface{})
+	for n, rel := range rels {
+		ro, ok := rel.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		data, hasData := ro["data"]
+		var related interface{}
+		if links, ok := ro["links"].(map[string]interface{}); ok {
+			related = links["related"]
+		}
+		resolve := func(ident interface{}) (interface{}, bool) {
+			io, ok := ident.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			k := jsonapiKey(io)
+			inc, ok := included[k]
+			if !ok || visiting[k] {
+				return nil, false
+			}
+			return fromJSONAPI(inc, included, visiting), true
+		}
+		if elems, ok := data.([]interface{}); ok {
+			values := make([]interface{}, 0, len(elems))
+			for _, e := range elems {
+				if rv, ok := resolve(e); ok {
+					values = append(values, rv)
+				}
+			}
+			res[n] = values
+			continue
+		}
+		if hasData && data == nil && related == nil {
+			res[n] = nil
+			continue
+		}
+		if rv, ok := resolve(data); ok {
+			res[n] = rv
+			continue
+		}
+		if related != nil {
+			link := map[string]interface{}{"href": related}
+			if io, ok := data.(map[string]interface{}); ok {
+				link["id"] = io["id"]
+			}
+			plain[n] = link
+		}
+	}
+	if len(plain) > 0 {
+		res["links"] = plain
+	}
+	return res
+}
+
+// RestoreIDs converts the string ids of the JSON value v rendered by UnmarshalJSONAPI back into
+// numbers where the corresponding fields of the Go type t are numeric. JSON:API renders the ids of
+// resources as strings.
+func RestoreIDs(v interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch actual := v.(type) {
+	case []interface{}:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return v
+		}
+		for i, e := range actual {
+			actual[i] = RestoreIDs(e, t.Elem())
+		}
+	case map[string]interface{}:
+		if t.Kind() != reflect.Struct {
+			return v
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name := strings.Split(f.Tag.Get("json"), ",")[0]
+			if name == "" {
+				name = f.Name
+			}
+			fv, ok := actual[name]
+			if !ok {
+				continue
+			}
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if s, ok := fv.(string); ok && name == "id" {
+				switch ft.Kind() {
+				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+					reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+					reflect.Float32, reflect.Float64:
+					actual[name] = json.Number(s)
+				}
+				continue
+			}
+			actual[name] = RestoreIDs(fv, ft)
+		}
+	}
+	return v
+}
+
+// jsonapiID returns the JSON:API representation of the id of a resource.
+func jsonapiID(id interface{}) string {
+	if s, ok := id.(string); ok {
+		return s
+	}
+	return fmt.Sprint(id)
+}
+
+// jsonapiKey returns the key identifying the JSON:API resource r in a document.
+func jsonapiKey(r map[string]interface{}) string {
+	return fmt.Sprintf("%v/%v", r["type"], r["id"])
+}
+
+// sortedNames returns the sorted keys of m.
+func sortedNames(m map[string]string) []string {
+	names := make([]string, 0, len(m))
+	for n := range m {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// toJSONValue returns the JSON value of v, numbers are decoded into json.Number values.
+func toJSONValue(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var res interface{}
+	if err := dec.Decode(&res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,125 @@
+package goa_test
+
+import (
+	"encoding/json"
+	"reflect"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1"
+)
+
+var _ = Describe("HypermediaSchemas", func() {
+	type link struct {
+		ID   int    `json:"id"`
+		Href string `json:"href"`
+	}
+	type links struct {
+		Account *link `json:"account,omitempty"`
+	}
+	type producer struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	type wine struct {
+		ID       int       `json:"id"`
+		Href     string    `json:"href,omitempty"`
+		Name     string    `json:"name"`
+		Producer *producer `json:"producer,omitempty"`
+		Links    *links    `json:"links,omitempty"`
+	}
+
+	schemas := goa.HypermediaSchemas{
+		"Wine": {
+			Type:     "wines",
+			Embedded: map[string]string{"producer": "Producer"},
+			Links:    map[string]string{"account": "AccountLink"},
+		},
+		"Producer":    {Type: "producers"},
+		"AccountLink": {Type: "accounts"},
+	}
+
+	var v interface{}
+	var body []byte
+
+	BeforeEach(func() {
+		v = &wine{
+			ID:       1,
+			Href:     "/wines/1",
+			Name:     "red",
+			Producer: &producer{ID: "p1", Name: "prod"},
+			Links:    &links{Account: &link{ID: 7, Href: "/accounts/7"}},
+		}
+	})
+
+	Describe("MarshalHAL", func() {
+		JustBeforeEach(func() {
+			doc, err := schemas.MarshalHAL(v, "Wine")
+			Ω(err).ShouldNot(HaveOccurred())
+			body, err = json.Marshal(doc)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("renders the links and the related resources", func() {
+			Ω(string(body)).Should(Equal(`{"_embedded":{"producer":{"id":"p1","name":"prod"}},"_links":{"account":{"href":"/accounts/7","id":7},"self":{"href":"/wines/1"}},"id":1,"name":"red"}`))
+		})
+
+		Context("with a collection", func() {
+			BeforeEach(func() {
+				v = []*wine{{ID: 2, Name: "white"}}
+			})
+
+			It("renders the elements in the embedded items", func() {
+				Ω(string(body)).Should(Equal(`{"_embedded":{"items":[{"id":2,"name":"white"}]}}`))
+			})
+		})
+	})
+
+	Describe("MarshalJSONAPI", func() {
+		JustBeforeEach(func() {
+			doc, err := schemas.MarshalJSONAPI(v, "Wine")
+			Ω(err).ShouldNot(HaveOccurred())
+			body, err = json.Marshal(doc)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("renders the resource, its relationships and the included resources", func() {
+			Ω(string(body)).Should(Equal(`{"data":{"attributes":{"name":"red"},"id":"1","links":{"self":"/wines/1"},"relationships":{"account":{"data":{"id":"7","type":"accounts"},"links":{"related":"/accounts/7"}},"producer":{"data":{"id":"p1","type":"producers"}}},"type":"wines"},"included":[{"attributes":{"name":"prod"},"id":"p1","type":"producers"}]}`))
+		})
+
+		Context("with a collection sharing related resources", func() {
+			BeforeEach(func() {
+				p := &producer{ID: "p1", Name: "prod"}
+				v = []*wine{{ID: 1, Name: "red", Producer: p}, {ID: 2, Name: "white", Producer: p}}
+			})
+
+			It("includes the related resources once", func() {
+				Ω(string(body)).Should(Equal(`{"data":[{"attributes":{"name":"red"},"id":"1","relationships":{"producer":{"data":{"id":"p1","type":"producers"}}},"type":"wines"},{"attributes":{"name":"white"},"id":"2","relationships":{"producer":{"data":{"id":"p1","type":"producers"}}},"type":"wines"}],"included":[{"attributes":{"name":"prod"},"id":"p1","type":"producers"}]}`))
+			})
+		})
+
+		It("reverts to the plain rendering", func() {
+			var doc interface{}
+			Ω(json.Unmarshal(body, &doc)).Should(Succeed())
+			val, err := goa.UnmarshalJSONAPI(doc)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := json.Marshal(goa.RestoreIDs(val, reflect.TypeOf(v)))
+			Ω(err).ShouldNot(HaveOccurred())
+			var w wine
+			Ω(json.Unmarshal(b, &w)).Should(Succeed())
+			Ω(&w).Should(Equal(v))
+		})
+	})
+
+	Describe("UnmarshalHAL", func() {
+		It("reverts to the plain rendering", func() {
+			doc, err := schemas.MarshalHAL(v, "Wine")
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := json.Marshal(goa.UnmarshalHAL(doc, false))
+			Ω(err).ShouldNot(HaveOccurred())
+			var w wine
+			Ω(json.Unmarshal(b, &w)).Should(Succeed())
+			Ω(&w).Should(Equal(v))
+		})
+	})
+})
@@ -0,0 +1,250 @@
+package goa
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// OperationStatus is the status of a long-running operation.
+type OperationStatus string
+
+const (
+	// OperationRunning is the status of the operations that have not completed yet.
+	OperationRunning OperationStatus = "running"
+	// OperationSucceeded is the status of the operations that completed successfully.
+	OperationSucceeded OperationStatus = "succeeded"
+	// OperationFailed is the status of the operations that completed with an error.
+	OperationFailed OperationStatus = "failed"
+	// OperationCanceled is the status of the operations canceled before they completed.
+	OperationCanceled OperationStatus = "canceled"
+)
+
+// ErrOperationNotFound is the error returned by operation stores when the requested operation does
+// not exist.
+var ErrOperationNotFound = ErrNotFound("operation not found")
+
+type (
+	// Operation is the state of a long-running operation.
+	Operation struct {
+		// ID is the unique identifier of the operation.
+		ID string `json:"id"`
+		// Status is the status of the operation.
+		Status OperationStatus `json:"status"`
+		// Result is the value returned by the operation once it succeeded.
+		Result interface{} `json:"result,omitempty"`
+		// Error is the error message of the operation once it failed.
+		Error string `json:"error,omitempty"`
+		// CreatedAt is the time the operation started.
+		CreatedAt time.Time `json:"created_at"`
+		// UpdatedAt is the time the status of the operation last changed.
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+
+	// OperationFunc is the function run by a long-running operation. The context is canceled
+	// when the operation is canceled.
+	OperationFunc func(ctx context.Context) (interface{}, error)
+
+	// OperationStore persists the state of long-running operations. Implementations must be
+	// safe for concurrent use.
+	OperationStore interface {
+		// Create records a new operation and sets its ID.
+		Create(ctx context.Context, op *Operation) error
+		// Get returns the operation with the given ID or ErrOperationNotFound.
+		Get(ctx context.Context, id string) (*Operation, error)
+		// Update records the new state of an existing operation.
+		Update(ctx context.Context, op *Operation) error
+		// Delete removes the operation with the given ID or returns ErrOperationNotFound.
+		Delete(ctx context.Context, id string) error
+	}
+
+	// MemoryOperationStore is an OperationStore that keeps the operations in memory.
+	MemoryOperationStore struct {
+		// TTL is the duration the operations are kept once they are done. Done operations
+		// are kept until they are deleted if TTL is zero.
+		TTL time.Duration
+
+		mu  sync.Mutex
+		ops map[string]*Operation
+	}
+
+	// OperationRunner runs long-running operations in the background and records their state in
+	// a store.
+	OperationRunner struct {
+		// Store records the state of the operations.
+		Store OperationStore
+
+		mu      sync.Mutex
+		cancels map[string]context.CancelFunc
+	}
+)
+
+// Done returns true if the operation completed or was canceled.
+func (op *Operation) Done() bool {
+	return op.Status != OperationRunning
+}
+
+// NewMemoryOperationStore returns an empty in-memory operation store.
+func NewMemoryOperationStore() *MemoryOperationStore {
+	return &MemoryOperationStore{ops: make(map[string]*Operation)}
+}
+
+// Create records a copy of op after setting its ID to a random identifier. It also drops the done
+// operations whose TTL expired.
+func (s *MemoryOperationStore) Create(ctx context.Context, op *Operation) error {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	op.ID = hex.EncodeToString(b)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, o := range s.ops {
+		if s.expired(o) {
+			delete(s.ops, id)
+		}
+	}
+	cp := *op
+	s.ops[op.ID] = &cp
+	return nil
+}
+
+// Get returns a copy of the operation with the given ID.
+func (s *MemoryOperationStore) Get(ctx context.Context, id string) (*Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	op, ok := s.ops[id]
+	if !ok || s.expired(op) {
+		return nil, ErrOperationNotFound
+	}
+	cp := *op
+	return &cp, nil
+}
+
+// Update records a copy of op.
+func (s *MemoryOperationStore) Update(ctx context.Context, op *Operation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.ops[op.ID]; !ok {
+		return ErrOperationNotFound
+	}
+	cp := *op
+	s.ops[op.ID] = &cp
+	return nil
+}
+
+// Delete removes the operation with the given ID.
+func (s *MemoryOperationStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.ops[id]; !ok {
+		return ErrOperationNotFound
+	}
+	delete(s.ops, id)
+	return nil
+}
+
+// expired returns true if op is done and its TTL expired.
+func (s *MemoryOperationStore) expired(op *Operation) bool {
+	return s.TTL > 0 && op.Done() && time.Since(op.UpdatedAt) > s.TTL
+}
+
+// NewOperationRunner returns a runner that records the operations in store, an in-memory store if
+// store is nil.
+func NewOperationRunner(store OperationStore) *OperationRunner {
+	if store == nil {
+		store = NewMemoryOperationStore()
+	}
+	return &OperationRunner{Store: store, cancels: make(map[string]context.CancelFunc)}
+}
+
+// Start records a new running operation and runs fn in the background. The operation context
+// carries the values of ctx but is not canceled when ctx is, it is canceled when the operation is
+// canceled with Cancel. The operation status is updated with the result or the error returned by
+// fn once it returns.
+func (r *OperationRunner) Start(ctx context.Context, fn OperationFunc) (*Operation, error) {
+	now := time.Now()
+	op := &Operation{Status: OperationRunning, CreatedAt: now, UpdatedAt: now}
+	if err := r.Store.Create(ctx, op); err != nil {
+		return nil, err
+	}
+	opctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	r.mu.Lock()
+	r.cancels[op.ID] = cancel
+	r.mu.Unlock()
+	go func() {
+		defer cancel()
+		res, err := fn(opctx)
+		r.finish(opctx, op.ID, res, err)
+	}()
+	return op, nil
+}
+
+// Get returns the operation with the given ID.
+func (r *OperationRunner) Get(ctx context.Context, id string) (*Operation, error) {
+	return r.Store.Get(ctx, id)
+}
+
+// Cancel marks the operation with the given ID as canceled and cancels its context if it is run by
+// r. Cancel returns the operation unchanged if it is already done.
+func (r *OperationRunner) Cancel(ctx context.Context, id string) (*Operation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	op, err := r.Store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if op.Done() {
+		return op, nil
+	}
+	op.Status = OperationCanceled
+	op.UpdatedAt = time.Now()
+	if err := r.Store.Update(ctx, op); err != nil {
+		return nil, err
+	}
+	if cancel, ok := r.cancels[id]; ok {
+		cancel()
+		delete(r.cancels, id)
+	}
+	return op, nil
+}
+
+// Delete cancels the operation with the given ID if it is still running and removes it from the
+// store.
+func (r *OperationRunner) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cancel, ok := r.cancels[id]; ok {
+		cancel()
+		delete(r.cancels, id)
+	}
+	return r.Store.Delete(ctx, id)
+}
+
+// finish records the outcome of the operation with the given ID unless it was canceled.
+func (r *OperationRunner) finish(ctx context.Context, id string, res interface{}, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.cancels, id)
+	op, gerr := r.Store.Get(ctx, id)
+	if gerr != nil {
+		LogError(ctx, "failed to load operation", "id", id, "err", gerr)
+		return
+	}
+	if op.Done() {
+		return
+	}
+	if err != nil {
+		op.Status = OperationFailed
+		op.Error = err.Error()
+	} else {
+		op.Status = OperationSucceeded
+		op.Result = res
+	}
+	op.UpdatedAt = time.Now()
+	if uerr := r.Store.Update(ctx, op); uerr != nil {
+		LogError(ctx, "failed to update operation", "id", id, "err", uerr)
+	}
+}
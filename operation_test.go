@@ -0,0 +1,144 @@
+package goa_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1"
+)
+
+var _ = Describe("OperationRunner", func() {
+	var runner *goa.OperationRunner
+	var release chan struct{}
+	var fnErr error
+	var op *goa.Operation
+	var err error
+
+	status := func() goa.OperationStatus {
+		op, err := runner.Get(context.Background(), op.ID)
+		Ω(err).ShouldNot(HaveOccurred())
+		return op.Status
+	}
+
+	BeforeEach(func() {
+		runner = goa.NewOperationRunner(nil)
+		release = make(chan struct{})
+		fnErr = nil
+	})
+
+	JustBeforeEach(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		release, fnErr := release, fnErr
+		op, err = runner.Start(ctx, func(ctx context.Context) (interface{}, error) {
+			select {
+			case <-release:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			if fnErr != nil {
+				return nil, fnErr
+			}
+			return "done", nil
+		})
+		cancel()
+	})
+
+	It("records a running operation", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(op.ID).ShouldNot(BeEmpty())
+		Ω(op.Status).Should(Equal(goa.OperationRunning))
+		Ω(op.Done()).Should(BeFalse())
+		Consistently(status, 50*time.Millisecond).Should(Equal(goa.OperationRunning))
+	})
+
+	It("records the result of the operation", func() {
+		close(release)
+		Eventually(status).Should(Equal(goa.OperationSucceeded))
+		op, err := runner.Get(context.Background(), op.ID)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(op.Result).Should(Equal("done"))
+		Ω(op.Done()).Should(BeTrue())
+	})
+
+	Context("with a failing operation", func() {
+		BeforeEach(func() {
+			fnErr = errors.New("boom")
+		})
+
+		It("records the error", func() {
+			close(release)
+			Eventually(status).Should(Equal(goa.OperationFailed))
+			op, err := runner.Get(context.Background(), op.ID)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(op.Error).Should(Equal("boom"))
+		})
+	})
+
+	Context("canceling the operation", func() {
+		var canceled *goa.Operation
+		var cancelErr error
+
+		JustBeforeEach(func() {
+			canceled, cancelErr = runner.Cancel(context.Background(), op.ID)
+		})
+
+		It("marks it as canceled", func() {
+			Ω(cancelErr).ShouldNot(HaveOccurred())
+			Ω(canceled.Status).Should(Equal(goa.OperationCanceled))
+			Consistently(status, 50*time.Millisecond).Should(Equal(goa.OperationCanceled))
+		})
+	})
+
+	Context("deleting the operation", func() {
+		JustBeforeEach(func() {
+			Ω(runner.Delete(context.Background(), op.ID)).ShouldNot(HaveOccurred())
+		})
+
+		It("removes it", func() {
+			_, err := runner.Get(context.Background(), op.ID)
+			Ω(err).Should(Equal(goa.ErrOperationNotFound))
+		})
+	})
+
+	It("returns ErrOperationNotFound for unknown operations", func() {
+		_, err := runner.Get(context.Background(), "unknown")
+		Ω(err).Should(Equal(goa.ErrOperationNotFound))
+		_, err = runner.Cancel(context.Background(), "unknown")
+		Ω(err).Should(Equal(goa.ErrOperationNotFound))
+		Ω(runner.Delete(context.Background(), "unknown")).Should(Equal(goa.ErrOperationNotFound))
+	})
+})
+
+var _ = Describe("MemoryOperationStore", func() {
+	var store *goa.MemoryOperationStore
+	var op *goa.Operation
+
+	BeforeEach(func() {
+		store = goa.NewMemoryOperationStore()
+		store.TTL = time.Minute
+		op = &goa.Operation{Status: goa.OperationSucceeded, UpdatedAt: time.Now()}
+		Ω(store.Create(context.Background(), op)).ShouldNot(HaveOccurred())
+	})
+
+	It("keeps the done operations until their TTL expires", func() {
+		_, err := store.Get(context.Background(), op.ID)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("with an expired operation", func() {
+		BeforeEach(func() {
+			op.UpdatedAt = time.Now().Add(-2 * time.Minute)
+			Ω(store.Update(context.Background(), op)).ShouldNot(HaveOccurred())
+		})
+
+		It("drops it", func() {
+			_, err := store.Get(context.Background(), op.ID)
+			Ω(err).Should(Equal(goa.ErrOperationNotFound))
+			Ω(store.Create(context.Background(), &goa.Operation{Status: goa.OperationRunning})).ShouldNot(HaveOccurred())
+			Ω(store.Delete(context.Background(), op.ID)).Should(Equal(goa.ErrOperationNotFound))
+		})
+	})
+})
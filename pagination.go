@@ -0,0 +1,74 @@
+package goa
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// OffsetPageLinks returns the value of the RFC 8288 Link header that points to the first, previous,
+// next and last pages of a collection paginated with the "offset" and "limit" query string
+// parameters. u is the URL of the current page, offset and limit the values used to compute it and
+// total the number of items in the collection. The previous and next links are omitted on the
+// first and last pages respectively.
+func OffsetPageLinks(u *url.URL, offset, limit, total int) string {
+	if limit <= 0 {
+		return ""
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	last := 0
+	if total > 0 {
+		last = (total - 1) / limit * limit
+	}
+	page := func(o int) string {
+		return pageURL(u, map[string]string{"offset": strconv.Itoa(o), "limit": strconv.Itoa(limit)})
+	}
+	links := []string{pageLink(page(0), "first")}
+	if offset > 0 {
+		prev := offset - limit
+		if prev < 0 {
+			prev = 0
+		}
+		links = append(links, pageLink(page(prev), "prev"))
+	}
+	if offset+limit < total {
+		links = append(links, pageLink(page(offset+limit), "next"))
+	}
+	links = append(links, pageLink(page(last), "last"))
+	return strings.Join(links, ", ")
+}
+
+// CursorPageLinks returns the value of the RFC 8288 Link header that points to the first and next
+// pages of a collection paginated with the "cursor" query string parameter. u is the URL of the
+// current page and next the cursor of the next page, the empty string if the current page is the
+// last one.
+func CursorPageLinks(u *url.URL, next string) string {
+	links := []string{pageLink(pageURL(u, map[string]string{"cursor": ""}), "first")}
+	if next != "" {
+		links = append(links, pageLink(pageURL(u, map[string]string{"cursor": next}), "next"))
+	}
+	return strings.Join(links, ", ")
+}
+
+// pageURL returns the path and query string of u with the query string parameters overridden by
+// params. Parameters with an empty value are removed.
+func pageURL(u *url.URL, params map[string]string) string {
+	q := u.Query()
+	for k, v := range params {
+		if v == "" {
+			q.Del(k)
+			continue
+		}
+		q.Set(k, v)
+	}
+	p := url.URL{Path: u.Path, RawPath: u.RawPath, RawQuery: q.Encode()}
+	return p.String()
+}
+
+// pageLink formats a single link value.
+func pageLink(target, rel string) string {
+	return fmt.Sprintf("<%s>; rel=%q", target, rel)
+}
@@ -0,0 +1,86 @@
+package goa_test
+
+import (
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1"
+)
+
+var _ = Describe("OffsetPageLinks", func() {
+	var u *url.URL
+	var offset, limit, total int
+	var links string
+
+	BeforeEach(func() {
+		var err error
+		u, err = url.Parse("/bottles?sort=name&offset=10&limit=10")
+		Ω(err).ShouldNot(HaveOccurred())
+		offset, limit, total = 10, 10, 35
+	})
+
+	JustBeforeEach(func() {
+		links = goa.OffsetPageLinks(u, offset, limit, total)
+	})
+
+	It("links to the first, previous, next and last pages", func() {
+		Ω(links).Should(Equal(`</bottles?limit=10&offset=0&sort=name>; rel="first", ` +
+			`</bottles?limit=10&offset=0&sort=name>; rel="prev", ` +
+			`</bottles?limit=10&offset=20&sort=name>; rel="next", ` +
+			`</bottles?limit=10&offset=30&sort=name>; rel="last"`))
+	})
+
+	Context("on the last page", func() {
+		BeforeEach(func() {
+			offset = 30
+		})
+
+		It("omits the next link", func() {
+			Ω(links).ShouldNot(ContainSubstring(`rel="next"`))
+			Ω(links).Should(ContainSubstring(`</bottles?limit=10&offset=20&sort=name>; rel="prev"`))
+		})
+	})
+
+	Context("with an empty collection", func() {
+		BeforeEach(func() {
+			offset, total = 0, 0
+		})
+
+		It("links to the first page only", func() {
+			Ω(links).Should(Equal(`</bottles?limit=10&offset=0&sort=name>; rel="first", ` +
+				`</bottles?limit=10&offset=0&sort=name>; rel="last"`))
+		})
+	})
+})
+
+var _ = Describe("CursorPageLinks", func() {
+	var u *url.URL
+	var next string
+	var links string
+
+	BeforeEach(func() {
+		var err error
+		u, err = url.Parse("/bottles?cursor=abc&limit=10")
+		Ω(err).ShouldNot(HaveOccurred())
+		next = "def"
+	})
+
+	JustBeforeEach(func() {
+		links = goa.CursorPageLinks(u, next)
+	})
+
+	It("links to the first and next pages", func() {
+		Ω(links).Should(Equal(`</bottles?limit=10>; rel="first", </bottles?cursor=def&limit=10>; rel="next"`))
+	})
+
+	Context("on the last page", func() {
+		BeforeEach(func() {
+			next = ""
+		})
+
+		It("omits the next link", func() {
+			Ω(links).Should(Equal(`</bottles?limit=10>; rel="first"`))
+		})
+	})
+})
@@ -0,0 +1,260 @@
+package goa
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// Date is a calendar date without time zone. Date values are formatted as RFC3339
+	// full-date values (e.g. "2006-01-02"). It is the Go type of the design Date primitive.
+	Date struct {
+		Year  int
+		Month time.Month
+		Day   int
+	}
+
+	// TimeOfDay is a wall clock time without date nor time zone. TimeOfDay values are
+	// formatted as RFC3339 partial-time values (e.g. "15:04:05" or "15:04:05.999"). It is the
+	// Go type of the design TimeOfDay primitive.
+	TimeOfDay struct {
+		Hour       int
+		Minute     int
+		Second     int
+		Nanosecond int
+	}
+
+	// Duration is a length of time. Duration values are formatted using the Go duration
+	// syntax (e.g. "1h30m"). It is the Go type of the design Duration primitive.
+	Duration time.Duration
+
+	// Decimal is an arbitrary precision decimal number. The value is kept in its textual
+	// form so that no precision is lost when decoding and encoding it, use Rat to compute
+	// with it. It is the Go type of the design Decimal primitive.
+	Decimal string
+)
+
+// decimalRegex matches valid decimal numbers and captures their sign, integer part, fractional
+// part and exponent.
+var decimalRegex = regexp.MustCompile(`^([-+]?)(\d*)(?:\.(\d*))?([eE][-+]?\d+)?$`)
+
+// ParseDate parses a RFC3339 full-date value.
+func ParseDate(val string) (Date, error) {
+	t, err := time.Parse("2006-01-02", val)
+	if err != nil {
+		return Date{}, err
+	}
+	return DateOf(t), nil
+}
+
+// DateOf returns the date in which t occurs in t's location.
+func DateOf(t time.Time) Date {
+	var d Date
+	d.Year, d.Month, d.Day = t.Date()
+	return d
+}
+
+// String returns the RFC3339 full-date representation of d.
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// In returns the time corresponding to midnight of d in the given location.
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// After reports whether d is after u.
+func (d Date) After(u Date) bool {
+	if d.Year != u.Year {
+		return d.Year > u.Year
+	}
+	if d.Month != u.Month {
+		return d.Month > u.Month
+	}
+	return d.Day > u.Day
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Date) UnmarshalText(text []byte) error {
+	v, err := ParseDate(string(text))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// ParseTimeOfDay parses a RFC3339 partial-time value.
+func ParseTimeOfDay(val string) (TimeOfDay, error) {
+	t, err := time.Parse("15:04:05.999999999", val)
+	if err != nil {
+		return TimeOfDay{}, err
+	}
+	return TimeOfDayOf(t), nil
+}
+
+// TimeOfDayOf returns the wall clock time of t in t's location.
+func TimeOfDayOf(t time.Time) TimeOfDay {
+	var tod TimeOfDay
+	tod.Hour, tod.Minute, tod.Second = t.Clock()
+	tod.Nanosecond = t.Nanosecond()
+	return tod
+}
+
+// String returns the RFC3339 partial-time representation of t.
+func (t TimeOfDay) String() string {
+	s := fmt.Sprintf("%02d:%02d:%02d", t.Hour, t.Minute, t.Second)
+	if t.Nanosecond == 0 {
+		return s
+	}
+	return s + time.Date(0, 1, 1, 0, 0, 0, t.Nanosecond, time.UTC).Format(".999999999")
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t TimeOfDay) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (t *TimeOfDay) UnmarshalText(text []byte) error {
+	v, err := ParseTimeOfDay(string(text))
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// ParseDuration parses a duration expressed with the Go duration syntax.
+func ParseDuration(val string) (Duration, error) {
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return 0, err
+	}
+	return Duration(d), nil
+}
+
+// String returns the Go duration syntax representation of d.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// ParseDecimal validates that val is a decimal number and returns it normalized to the JSON number
+// grammar, for example ".5" becomes "0.5" and "+1." becomes "1".
+func ParseDecimal(val string) (Decimal, error) {
+	m := decimalRegex.FindStringSubmatch(val)
+	if m == nil || m[2] == "" && m[3] == "" {
+		return "", fmt.Errorf("invalid decimal value %q", val)
+	}
+	sign, integer, frac, exp := m[1], strings.TrimLeft(m[2], "0"), m[3], m[4]
+	if sign == "+" {
+		sign = ""
+	}
+	if integer == "" {
+		integer = "0"
+	}
+	if frac != "" {
+		frac = "." + frac
+	}
+	return Decimal(sign + integer + frac + exp), nil
+}
+
+// String returns the textual representation of d.
+func (d Decimal) String() string {
+	if d == "" {
+		return "0"
+	}
+	return string(d)
+}
+
+// Rat returns the value of d as a rational number.
+func (d Decimal) Rat() (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(d.String())
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal value %q", string(d))
+	}
+	return r, nil
+}
+
+// Float64 returns the value of d as a float64, possibly losing precision.
+func (d Decimal) Float64() (float64, error) {
+	return strconv.ParseFloat(d.String(), 64)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	v, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// MarshalJSON renders d as a JSON number.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	v, err := ParseDecimal(d.String())
+	if err != nil {
+		return nil, err
+	}
+	return []byte(v), nil
+}
+
+// UnmarshalJSON accepts both JSON numbers and JSON strings containing a decimal number.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	var n json.Number
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		n = json.Number(s)
+	} else {
+		n = json.Number(data)
+	}
+	return d.UnmarshalText([]byte(n))
+}
+
+// ParseText parses val into a value of type T using its encoding.TextUnmarshaler implementation.
+// It is used by the generated code to coerce string values into Go types that primitives or user
+// types are mapped to.
+func ParseText[T any, PT interface {
+	*T
+	encoding.TextUnmarshaler
+}](val string) (T, error) {
+	var v T
+	err := PT(&v).UnmarshalText([]byte(val))
+	return v, err
+}
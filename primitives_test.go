@@ -0,0 +1,113 @@
+package goa_test
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1"
+)
+
+var _ = Describe("Date", func() {
+	It("parses and formats RFC3339 full-date values", func() {
+		d, err := goa.ParseDate("2016-02-29")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(d).Should(Equal(goa.Date{Year: 2016, Month: time.February, Day: 29}))
+		Ω(d.String()).Should(Equal("2016-02-29"))
+	})
+
+	It("rejects invalid values", func() {
+		_, err := goa.ParseDate("2015-02-29")
+		Ω(err).Should(HaveOccurred())
+	})
+
+	It("marshals to JSON strings", func() {
+		b, err := json.Marshal(goa.Date{Year: 2016, Month: time.March, Day: 1})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(`"2016-03-01"`))
+	})
+
+	It("compares dates", func() {
+		d := goa.Date{Year: 2016, Month: time.March, Day: 1}
+		Ω(d.After(goa.Date{Year: 2016, Month: time.February, Day: 29})).Should(BeTrue())
+		Ω(d.After(goa.Date{Year: 2015, Month: time.December, Day: 31})).Should(BeTrue())
+		Ω(d.After(d)).Should(BeFalse())
+		Ω(d.After(goa.Date{Year: 2016, Month: time.March, Day: 2})).Should(BeFalse())
+	})
+})
+
+var _ = Describe("TimeOfDay", func() {
+	It("parses and formats RFC3339 partial-time values", func() {
+		t, err := goa.ParseTimeOfDay("08:30:00")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(t).Should(Equal(goa.TimeOfDay{Hour: 8, Minute: 30}))
+		Ω(t.String()).Should(Equal("08:30:00"))
+	})
+
+	It("keeps fractional seconds", func() {
+		t, err := goa.ParseTimeOfDay("08:30:00.25")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(t.Nanosecond).Should(Equal(250000000))
+		Ω(t.String()).Should(Equal("08:30:00.25"))
+	})
+})
+
+var _ = Describe("Duration", func() {
+	It("round trips through JSON", func() {
+		var d goa.Duration
+		Ω(json.Unmarshal([]byte(`"1h30m"`), &d)).Should(Succeed())
+		Ω(time.Duration(d)).Should(Equal(90 * time.Minute))
+		b, err := json.Marshal(d)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(`"1h30m0s"`))
+	})
+})
+
+var _ = Describe("Decimal", func() {
+	It("preserves precision", func() {
+		var d goa.Decimal
+		Ω(json.Unmarshal([]byte(`12345678901234567890.123456789`), &d)).Should(Succeed())
+		b, err := json.Marshal(d)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(`12345678901234567890.123456789`))
+	})
+
+	It("accepts JSON strings", func() {
+		var d goa.Decimal
+		Ω(json.Unmarshal([]byte(`"0.10"`), &d)).Should(Succeed())
+		r, err := d.Rat()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(r.FloatString(2)).Should(Equal("0.10"))
+	})
+
+	It("normalizes values to the JSON number grammar", func() {
+		for val, expected := range map[string]string{
+			".5":     "0.5",
+			"1.":     "1",
+			"+1":     "1",
+			"-007.0": "-7.0",
+			"1e+3":   "1e+3",
+		} {
+			d, err := goa.ParseDecimal(val)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(d)).Should(Equal(expected))
+			b, err := json.Marshal(d)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(json.Valid(b)).Should(BeTrue())
+		}
+	})
+
+	It("marshals unnormalized values as valid JSON", func() {
+		b, err := json.Marshal(goa.Decimal(".5"))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal("0.5"))
+	})
+
+	It("rejects invalid values", func() {
+		for _, val := range []string{"1/3", ".", "+", "1e", "e5", ""} {
+			_, err := goa.ParseDecimal(val)
+			Ω(err).Should(HaveOccurred(), val)
+		}
+	})
+})
@@ -0,0 +1,115 @@
+package goa
+
+import (
+	"sort"
+	"strings"
+)
+
+// SelectedFields returns the paths of the attributes of a response selected by a request. views
+// lists the paths of the attributes rendered by each view of the response media type, view is the
+// name of the selected view and fields the value of the fields query string parameter if any.
+// SelectedFields returns nil if the response must be rendered in full with the default view and an
+// invalid request error if the view is unknown or if fields lists attributes the view does not
+// render.
+//
+// Selecting an attribute selects all its nested attributes rendered by the view and the attributes
+// that enclose it.
+func SelectedFields(views map[string][]string, view string, fields *string) ([]string, error) {
+	paths, ok := views[view]
+	if !ok {
+		allowed := make([]interface{}, 0, len(views))
+		for v := range views {
+			allowed = append(allowed, v)
+		}
+		sort.Slice(allowed, func(i, j int) bool { return allowed[i].(string) < allowed[j].(string) })
+		return nil, InvalidEnumValueError("view", view, allowed)
+	}
+	if fields == nil || strings.TrimSpace(*fields) == "" {
+		if view == "default" {
+			return nil, nil
+		}
+		return paths, nil
+	}
+	rendered := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		rendered[p] = true
+	}
+	var err error
+	selected := make(map[string]bool)
+	for _, f := range strings.Split(*fields, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if !rendered[f] {
+			err = MergeErrors(err, UnknownFieldError("fields", f))
+			continue
+		}
+		for _, p := range paths {
+			if p == f || strings.HasPrefix(p, f+".") || strings.HasPrefix(f, p+".") {
+				selected[p] = true
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	res := make([]string, 0, len(selected))
+	for p := range selected {
+		res = append(res, p)
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+// SelectFields returns the JSON representation of v that only contains the attributes whose paths
+// are listed in fields. Nested attributes are not filtered if fields does not list any of them.
+// The attributes of the elements of arrays are filtered using the path of the array.
+func SelectFields(v interface{}, fields []string) (interface{}, error) {
+	res, err := toJSONValue(v)
+	if err != nil {
+		return nil, err
+	}
+	selected := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		selected[f] = true
+	}
+	return selectFields(res, "", selected), nil
+}
+
+// selectFields removes the attributes of the JSON value v whose paths are not selected. prefix is
+// the path of v.
+func selectFields(v interface{}, prefix string, selected map[string]bool) interface{} {
+	switch actual := v.(type) {
+	case []interface{}:
+		for i, e := range actual {
+			actual[i] = selectFields(e, prefix, selected)
+		}
+	case map[string]interface{}:
+		for k, e := range actual {
+			p := k
+			if prefix != "" {
+				p = prefix + "." + k
+			}
+			if !selected[p] {
+				delete(actual, k)
+				continue
+			}
+			if hasSelectedChild(p, selected) {
+				actual[k] = selectFields(e, p, selected)
+			}
+		}
+	}
+	return v
+}
+
+// hasSelectedChild returns true if selected contains a path nested under p.
+func hasSelectedChild(p string, selected map[string]bool) bool {
+	p += "."
+	for s := range selected {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
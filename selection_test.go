@@ -0,0 +1,129 @@
+package goa_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1"
+)
+
+var _ = Describe("SelectedFields", func() {
+	var views map[string][]string
+	var view string
+	var fields *string
+	var selected []string
+	var err error
+
+	BeforeEach(func() {
+		views = map[string][]string{
+			"default": {"id", "name", "owner", "owner.id", "owner.name"},
+			"tiny":    {"id", "owner", "owner.id"},
+		}
+		view = "default"
+		fields = nil
+	})
+
+	JustBeforeEach(func() {
+		selected, err = goa.SelectedFields(views, view, fields)
+	})
+
+	It("selects nothing with the default view", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(selected).Should(BeNil())
+	})
+
+	Context("with another view", func() {
+		BeforeEach(func() {
+			view = "tiny"
+		})
+
+		It("selects the attributes of the view", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(selected).Should(Equal([]string{"id", "owner", "owner.id"}))
+		})
+	})
+
+	Context("with fields", func() {
+		BeforeEach(func() {
+			f := "name, owner"
+			fields = &f
+		})
+
+		It("selects the listed attributes and their nested attributes", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(selected).Should(Equal([]string{"name", "owner", "owner.id", "owner.name"}))
+		})
+	})
+
+	Context("with nested fields", func() {
+		BeforeEach(func() {
+			f := "owner.name"
+			fields = &f
+		})
+
+		It("selects the enclosing attributes", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(selected).Should(Equal([]string{"owner", "owner.name"}))
+		})
+	})
+
+	Context("with fields not rendered by the view", func() {
+		BeforeEach(func() {
+			view = "tiny"
+			f := "id,name"
+			fields = &f
+		})
+
+		It("returns an invalid request error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(400))
+			Ω(err.Error()).Should(ContainSubstring(`"name"`))
+		})
+	})
+
+	Context("with an unknown view", func() {
+		BeforeEach(func() {
+			view = "full"
+		})
+
+		It("returns an invalid request error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(400))
+		})
+	})
+})
+
+var _ = Describe("SelectFields", func() {
+	type owner struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	type shelf struct {
+		ID    int               `json:"id"`
+		Name  string            `json:"name"`
+		Tags  map[string]string `json:"tags"`
+		Owner *owner            `json:"owner"`
+	}
+
+	var v interface{}
+	var fields []string
+	var body []byte
+
+	BeforeEach(func() {
+		s := &shelf{ID: 1, Name: "top", Tags: map[string]string{"a": "b"}, Owner: &owner{ID: 2, Name: "joe"}}
+		v = []*shelf{s, s}
+		fields = []string{"id", "owner", "owner.name", "tags"}
+	})
+
+	JustBeforeEach(func() {
+		res, err := goa.SelectFields(v, fields)
+		Ω(err).ShouldNot(HaveOccurred())
+		body, err = json.Marshal(res)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("only keeps the selected attributes of each element", func() {
+		Ω(string(body)).Should(Equal(`[{"id":1,"owner":{"name":"joe"},"tags":{"a":"b"}},{"id":1,"owner":{"name":"joe"},"tags":{"a":"b"}}]`))
+	})
+})
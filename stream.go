@@ -0,0 +1,189 @@
+package goa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// NDJSONContentType is the content type of streamed responses encoded as newline delimited JSON.
+const NDJSONContentType = "application/x-ndjson"
+
+// StreamFlushInterval is the maximum duration the elements written by SendStream are buffered
+// before being flushed to the client.
+var StreamFlushInterval = 100 * time.Millisecond
+
+// Iterator returns the next element of a streamed collection. It returns io.EOF once all the
+// elements have been returned.
+type Iterator func() (interface{}, error)
+
+// SendStream writes the response header with the given status code and writes the elements
+// returned by next to the response body one at a time so that large collections are never held in
+// memory. The elements are encoded as newline delimited JSON if the request accepts
+// NDJSONContentType and as a JSON array if the negotiated encoder is a JSON encoder. Other encoders
+// cannot encode collections one element at a time so SendStream falls back to collecting the
+// elements and encoding the whole collection.
+//
+// The response status is written before the first element is produced: if next fails the response
+// body is left truncated so that clients fail to decode it and SendStream returns the error.
+func (service *Service) SendStream(ctx context.Context, code int, next Iterator) error {
+	r := ContextResponse(ctx)
+	if r == nil {
+		return fmt.Errorf("no response data in context")
+	}
+	accept := ContextRequest(ctx).Header.Get("Accept")
+	if acceptsNDJSON(accept) {
+		r.Header().Set("Content-Type", NDJSONContentType)
+		r.WriteHeader(code)
+		return streamElements(ctx, r, next, json.NewEncoder(r), "")
+	}
+	_, p, err := service.Encoder.pool(accept)
+	if err != nil {
+		return err
+	}
+	e := p.Get(r)
+	defer p.Put(e)
+	if _, ok := e.(*json.Encoder); !ok {
+		all, err := collect(next)
+		if err != nil {
+			return err
+		}
+		r.WriteHeader(code)
+		return e.Encode(all)
+	}
+	r.WriteHeader(code)
+	if _, err := io.WriteString(r, "["); err != nil {
+		return err
+	}
+	if err := streamElements(ctx, r, next, e, ","); err != nil {
+		return err
+	}
+	_, err = io.WriteString(r, "]")
+	return err
+}
+
+// streamElements encodes the elements returned by next with e and writes sep between elements. The
+// elements are flushed once they have been buffered for StreamFlushInterval, including while next
+// blocks: next is called from another goroutine so that the response can be flushed while waiting
+// for the next element.
+func streamElements(ctx context.Context, r *ResponseData, next Iterator, e Encoder, sep string) error {
+	type result struct {
+		v   interface{}
+		err error
+	}
+	var (
+		rc      = http.NewResponseController(r.ResponseWriter)
+		reqs    = make(chan struct{})
+		results = make(chan result, 1)
+		timer   *time.Timer
+		flushC  <-chan time.Time // nil if there is nothing to flush
+	)
+	defer close(reqs)
+	go func() {
+		for range reqs {
+			v, err := next()
+			results <- result{v, err}
+		}
+	}()
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+	flush := func() error {
+		flushC = nil
+		if err := rc.Flush(); err != nil && err != http.ErrNotSupported {
+			return err
+		}
+		return nil
+	}
+	for i := 0; ; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		reqs <- struct{}{}
+		var res result
+	wait:
+		for {
+			select {
+			case res = <-results:
+				break wait
+			case <-flushC:
+				if err := flush(); err != nil {
+					return err
+				}
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if res.err == io.EOF {
+			break
+		}
+		if res.err != nil {
+			return res.err
+		}
+		if i > 0 && sep != "" {
+			if _, err := io.WriteString(r, sep); err != nil {
+				return err
+			}
+		}
+		if err := e.Encode(res.v); err != nil {
+			return err
+		}
+		if flushC == nil {
+			if timer == nil {
+				timer = time.NewTimer(StreamFlushInterval)
+			} else {
+				timer.Reset(StreamFlushInterval)
+			}
+			flushC = timer.C
+		}
+		select {
+		case <-flushC:
+			if err := flush(); err != nil {
+				return err
+			}
+		default:
+		}
+	}
+	return nil
+}
+
+// collect returns a slice containing all the elements returned by next. The slice element type is
+// the type of the first element so that encoders that need concrete types can encode it.
+func collect(next Iterator) (interface{}, error) {
+	var all reflect.Value
+	for {
+		v, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !all.IsValid() {
+			all = reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(v)), 0, 1)
+		}
+		all = reflect.Append(all, reflect.ValueOf(v))
+	}
+	if !all.IsValid() {
+		return []interface{}{}, nil
+	}
+	return all.Interface(), nil
+}
+
+// acceptsNDJSON returns true if the Accept header value accept lists NDJSONContentType.
+func acceptsNDJSON(accept string) bool {
+	for _, r := range strings.Split(accept, ",") {
+		if mt, _, err := mime.ParseMediaType(strings.TrimSpace(r)); err == nil && mt == NDJSONContentType {
+			return true
+		}
+	}
+	return false
+}
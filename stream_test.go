@@ -0,0 +1,151 @@
+package goa_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1"
+)
+
+var _ = Describe("SendStream", func() {
+	type item struct {
+		ID int `json:"id" xml:"id"`
+	}
+
+	var service *goa.Service
+	var rw *httptest.ResponseRecorder
+	var req *http.Request
+	var items []*item
+	var iterErr error
+	var err error
+
+	BeforeEach(func() {
+		service = goa.New("test")
+		service.Encoder.Register(goa.NewJSONEncoder, "application/json", "*/*")
+		service.Encoder.Register(goa.NewXMLEncoder, "application/xml")
+		rw = httptest.NewRecorder()
+		var e error
+		req, e = http.NewRequest("GET", "/items", nil)
+		Ω(e).ShouldNot(HaveOccurred())
+		items = []*item{{ID: 1}, {ID: 2}}
+		iterErr = nil
+	})
+
+	JustBeforeEach(func() {
+		ctx := goa.NewContext(context.Background(), rw, req, url.Values{})
+		goa.ContextResponse(ctx).Service = service
+		i := 0
+		next := func() (interface{}, error) {
+			if i == len(items) {
+				if iterErr != nil {
+					return nil, iterErr
+				}
+				return nil, io.EOF
+			}
+			i++
+			return items[i-1], nil
+		}
+		err = service.SendStream(ctx, 200, next)
+	})
+
+	It("streams a JSON array", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Code).Should(Equal(200))
+		Ω(rw.Body.String()).Should(MatchJSON(`[{"id":1},{"id":2}]`))
+	})
+
+	Context("with no elements", func() {
+		BeforeEach(func() {
+			items = nil
+		})
+
+		It("streams an empty JSON array", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(rw.Body.String()).Should(Equal("[]"))
+		})
+	})
+
+	Context("with a request accepting newline delimited JSON", func() {
+		BeforeEach(func() {
+			req.Header.Set("Accept", "application/x-ndjson")
+		})
+
+		It("streams newline delimited JSON", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(rw.Header().Get("Content-Type")).Should(Equal(goa.NDJSONContentType))
+			Ω(rw.Body.String()).Should(Equal("{\"id\":1}\n{\"id\":2}\n"))
+		})
+	})
+
+	Context("with an encoder that cannot stream", func() {
+		BeforeEach(func() {
+			req.Header.Set("Accept", "application/xml")
+		})
+
+		It("encodes the whole collection", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(rw.Body.String()).Should(Equal("<item><id>1</id></item><item><id>2</id></item>"))
+		})
+	})
+
+	Context("with an iterator that fails", func() {
+		BeforeEach(func() {
+			iterErr = errors.New("boom")
+		})
+
+		It("returns the error and leaves the body truncated", func() {
+			Ω(err).Should(MatchError("boom"))
+			Ω(rw.Body.String()).Should(Equal("[{\"id\":1}\n,{\"id\":2}\n"))
+		})
+	})
+})
+
+// flushRecorder records the body written when the response is flushed.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushed chan string
+}
+
+func (r *flushRecorder) Flush() {
+	r.flushed <- r.Body.String()
+}
+
+var _ = Describe("SendStream with a slow iterator", func() {
+	It("flushes the elements while waiting for the next one", func() {
+		service := goa.New("test")
+		service.Encoder.Register(goa.NewJSONEncoder, "application/json", "*/*")
+		rw := &flushRecorder{ResponseRecorder: httptest.NewRecorder(), flushed: make(chan string, 10)}
+		req, err := http.NewRequest("GET", "/items", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		ctx := goa.NewContext(context.Background(), rw, req, url.Values{})
+		goa.ContextResponse(ctx).Service = service
+		release := make(chan struct{})
+		i := 0
+		next := func() (interface{}, error) {
+			i++
+			switch i {
+			case 1:
+				return 1, nil
+			case 2:
+				<-release
+				return 2, nil
+			}
+			return nil, io.EOF
+		}
+		done := make(chan error, 1)
+		go func() { done <- service.SendStream(ctx, 200, next) }()
+
+		var body string
+		Eventually(rw.flushed).Should(Receive(&body))
+		Ω(body).Should(Equal("[1\n"))
+		close(release)
+		Eventually(done).Should(Receive(BeNil()))
+		Ω(rw.Body.String()).Should(MatchJSON("[1,2]"))
+	})
+})
@@ -0,0 +1,80 @@
+package goa
+
+import (
+	"context"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// VersionSelector returns the name of the API version targeted by a request, the empty string if
+// the request does not specify one.
+type VersionSelector func(*http.Request) string
+
+// FixedVersion returns a version selector that always selects the given version. The generated
+// code uses it to mount the actions of versions selected by path.
+func FixedVersion(version string) VersionSelector {
+	return func(*http.Request) string { return version }
+}
+
+// HeaderVersion returns a version selector that reads the version from the request header with the
+// given name.
+func HeaderVersion(name string) VersionSelector {
+	return func(req *http.Request) string { return req.Header.Get(name) }
+}
+
+// MediaTypeVersion returns a version selector that reads the version from the media type parameter
+// with the given name. The parameter is looked up in the media ranges of the Accept header first
+// and in the Content-Type header next.
+func MediaTypeVersion(param string) VersionSelector {
+	return func(req *http.Request) string {
+		for _, accept := range strings.Split(req.Header.Get("Accept"), ",") {
+			if v := mediaTypeParam(accept, param); v != "" {
+				return v
+			}
+		}
+		return mediaTypeParam(req.Header.Get("Content-Type"), param)
+	}
+}
+
+// SelectVersion returns a middleware that stores the API version targeted by the request in the
+// context (see ContextAPIVersion). versions lists the versions that expose the endpoint, requests
+// that don't specify a version are handled by the first one. Requests that target a version not
+// listed in versions are rejected with a ErrNotFound error.
+func SelectVersion(selector VersionSelector, versions ...string) Middleware {
+	return func(h Handler) Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			version := selector(req)
+			if version == "" && len(versions) > 0 {
+				version = versions[0]
+			} else if !hasVersion(versions, version) {
+				return ErrNotFound("API version not supported", "version", version)
+			}
+			return h(WithAPIVersion(ctx, version), rw, req)
+		}
+	}
+}
+
+// mediaTypeParam returns the value of the parameter with the given name of the media type mt, the
+// empty string if mt is invalid or does not have such parameter.
+func mediaTypeParam(mt, name string) string {
+	mt = strings.TrimSpace(mt)
+	if mt == "" {
+		return ""
+	}
+	_, params, err := mime.ParseMediaType(mt)
+	if err != nil {
+		return ""
+	}
+	return params[strings.ToLower(name)]
+}
+
+// hasVersion returns true if versions contains version.
+func hasVersion(versions []string, version string) bool {
+	for _, v := range versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package goa_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1"
+)
+
+var _ = Describe("SelectVersion", func() {
+	var selector goa.VersionSelector
+	var versions []string
+	var req *http.Request
+	var version string
+	var err error
+
+	BeforeEach(func() {
+		selector = goa.HeaderVersion("X-Api-Version")
+		versions = []string{"v1", "v2"}
+		var e error
+		req, e = http.NewRequest("GET", "/bottles/1", nil)
+		Ω(e).ShouldNot(HaveOccurred())
+		version = ""
+	})
+
+	JustBeforeEach(func() {
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			version = goa.ContextAPIVersion(ctx)
+			return nil
+		}
+		err = goa.SelectVersion(selector, versions...)(h)(context.Background(), httptest.NewRecorder(), req)
+	})
+
+	It("defaults to the first version", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(version).Should(Equal("v1"))
+	})
+
+	Context("with a request selecting a version", func() {
+		BeforeEach(func() {
+			req.Header.Set("X-Api-Version", "v2")
+		})
+
+		It("stores the version in the context", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(version).Should(Equal("v2"))
+		})
+	})
+
+	Context("with a request selecting an unsupported version", func() {
+		BeforeEach(func() {
+			req.Header.Set("X-Api-Version", "v3")
+		})
+
+		It("returns a not found error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(http.StatusNotFound))
+			Ω(version).Should(BeEmpty())
+		})
+	})
+
+	Context("with a media type parameter selector", func() {
+		BeforeEach(func() {
+			selector = goa.MediaTypeVersion("Version")
+			req.Header.Set("Accept", "text/plain, application/json; version=v2")
+		})
+
+		It("reads the version from the Accept header", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(version).Should(Equal("v2"))
+		})
+	})
+
+	Context("with a fixed version", func() {
+		BeforeEach(func() {
+			selector = goa.FixedVersion("v2")
+			req.Header.Set("X-Api-Version", "v1")
+		})
+
+		It("ignores the request", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(version).Should(Equal("v2"))
+		})
+	})
+})
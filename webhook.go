@@ -0,0 +1,100 @@
+package goa
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebhookSignatureHeader is the name of the header that contains the signature of webhook
+// requests.
+const WebhookSignatureHeader = "X-Webhook-Signature"
+
+// DefaultWebhookTolerance is the maximum age of the webhook requests accepted by the generated
+// verification helpers.
+const DefaultWebhookTolerance = 5 * time.Minute
+
+// WebhookMaxBodySize is the maximum length in bytes of the webhook request bodies read by
+// VerifyWebhook. Set to 0 to remove the limit altogether. Defaults to 1MB.
+var WebhookMaxBodySize int64 = 1 << 20
+
+// SignWebhook returns the value of the WebhookSignatureHeader header of a webhook request with the
+// given body sent at t. The value has the form "t=<unix time>,v1=<signature>" where signature is
+// the hex encoded HMAC-SHA256 of the unix time, a dot and the body computed with secret.
+func SignWebhook(secret []byte, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + webhookSignature(secret, ts, body)
+}
+
+// VerifyWebhook reads the body of the webhook request req and checks its WebhookSignatureHeader
+// header against secret. Requests signed more than tolerance ago are rejected to prevent replays,
+// a tolerance of zero disables the check. VerifyWebhook returns the request body and replaces it so
+// that it can be read again. It returns an ErrUnauthorized error if the signature is missing or
+// invalid and an ErrRequestBodyTooLarge error if the body is longer than WebhookMaxBodySize.
+func VerifyWebhook(req *http.Request, secret []byte, tolerance time.Duration) ([]byte, error) {
+	header := req.Header.Get(WebhookSignatureHeader)
+	if header == "" {
+		return nil, ErrUnauthorized("missing webhook signature")
+	}
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sigs = append(sigs, v)
+		}
+	}
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(sigs) == 0 {
+		return nil, ErrUnauthorized("invalid webhook signature")
+	}
+	if tolerance > 0 {
+		if age := time.Since(time.Unix(sec, 0)); age > tolerance || age < -tolerance {
+			return nil, ErrUnauthorized("webhook signature expired")
+		}
+	}
+	r := req.Body
+	if WebhookMaxBodySize > 0 {
+		r = http.MaxBytesReader(nil, r, WebhookMaxBodySize)
+	}
+	body, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		var merr *http.MaxBytesError
+		if errors.As(err, &merr) {
+			return nil, ErrRequestBodyTooLarge(fmt.Sprintf("webhook body length exceeds %d bytes", merr.Limit))
+		}
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	expected := webhookSignature(secret, ts, body)
+	for _, sig := range sigs {
+		if hmac.Equal([]byte(sig), []byte(expected)) {
+			return body, nil
+		}
+	}
+	return nil, ErrUnauthorized("invalid webhook signature")
+}
+
+// webhookSignature computes the hex encoded HMAC-SHA256 of the timestamp ts and body.
+func webhookSignature(secret []byte, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
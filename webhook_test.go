@@ -0,0 +1,120 @@
+package goa_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1"
+)
+
+var _ = Describe("VerifyWebhook", func() {
+	const body = `{"id":1}`
+	var secret = []byte("secret")
+
+	var signature string
+	var tolerance time.Duration
+	var req *http.Request
+	var payload []byte
+	var err error
+
+	BeforeEach(func() {
+		signature = goa.SignWebhook(secret, time.Now(), []byte(body))
+		tolerance = goa.DefaultWebhookTolerance
+	})
+
+	JustBeforeEach(func() {
+		req, _ = http.NewRequest("POST", "/webhook", strings.NewReader(body))
+		if signature != "" {
+			req.Header.Set(goa.WebhookSignatureHeader, signature)
+		}
+		payload, err = goa.VerifyWebhook(req, secret, tolerance)
+	})
+
+	It("returns the body of signed requests", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(payload)).Should(Equal(body))
+	})
+
+	It("replaces the request body", func() {
+		b, err := io.ReadAll(req.Body)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(body))
+	})
+
+	Context("with no signature", func() {
+		BeforeEach(func() {
+			signature = ""
+		})
+
+		It("returns an unauthorized error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	Context("with a signature computed with another secret", func() {
+		BeforeEach(func() {
+			signature = goa.SignWebhook([]byte("other"), time.Now(), []byte(body))
+		})
+
+		It("returns an unauthorized error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	Context("with a malformed signature", func() {
+		BeforeEach(func() {
+			signature = "v1=deadbeef"
+		})
+
+		It("returns an unauthorized error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring("invalid webhook signature"))
+		})
+	})
+
+	Context("with an old signature", func() {
+		BeforeEach(func() {
+			signature = goa.SignWebhook(secret, time.Now().Add(-time.Hour), []byte(body))
+		})
+
+		It("returns an unauthorized error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring("expired"))
+		})
+
+		Context("and no tolerance", func() {
+			BeforeEach(func() {
+				tolerance = 0
+			})
+
+			It("accepts the request", func() {
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(payload)).Should(Equal(body))
+			})
+		})
+	})
+
+	Context("with a body longer than WebhookMaxBodySize", func() {
+		var max int64
+
+		BeforeEach(func() {
+			max = goa.WebhookMaxBodySize
+			goa.WebhookMaxBodySize = int64(len(body) - 1)
+		})
+
+		AfterEach(func() {
+			goa.WebhookMaxBodySize = max
+		})
+
+		It("returns a request too large error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(http.StatusRequestEntityTooLarge))
+		})
+	})
+})
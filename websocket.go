@@ -0,0 +1,146 @@
+package goa
+
+import (
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// WebSocket close status codes defined in RFC 6455 section 7.4.1.
+const (
+	// WSNormalClosure indicates that the purpose of the connection has been fulfilled.
+	WSNormalClosure = 1000
+	// WSGoingAway indicates that an endpoint is going away, for example a server going down.
+	WSGoingAway = 1001
+	// WSProtocolError indicates that an endpoint received a frame it could not process.
+	WSProtocolError = 1002
+	// WSUnsupportedData indicates that an endpoint received a type of data it cannot accept.
+	WSUnsupportedData = 1003
+	// WSInvalidPayload indicates that an endpoint received a message that is not valid.
+	WSInvalidPayload = 1007
+	// WSPolicyViolation indicates that an endpoint received a message that violates its policy.
+	WSPolicyViolation = 1008
+	// WSMessageTooBig indicates that an endpoint received a message that is too big to process.
+	WSMessageTooBig = 1009
+	// WSInternalError indicates that the server encountered an unexpected condition.
+	WSInternalError = 1011
+)
+
+// WSConn is a websocket connection that exchanges JSON encoded messages. It sends pings to keep
+// the connection alive and closes the connection with a status code. The generated code wraps
+// WSConn to provide typed Send and Recv methods.
+type WSConn struct {
+	*websocket.Conn
+
+	mu     sync.Mutex
+	closed bool
+	done   chan struct{}
+}
+
+// NewWSConn wraps ws. If pingInterval is greater than zero a ping is sent every pingInterval until
+// the connection is closed.
+func NewWSConn(ws *websocket.Conn, pingInterval time.Duration) *WSConn {
+	c := &WSConn{Conn: ws, done: make(chan struct{})}
+	if pingInterval > 0 {
+		go c.ping(pingInterval)
+	}
+	return c
+}
+
+// Send encodes v in JSON and sends it in a text frame. It returns net.ErrClosed once the
+// connection is closed.
+func (c *WSConn) Send(v interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return net.ErrClosed
+	}
+	return websocket.JSON.Send(c.Conn, v)
+}
+
+// Recv receives a text frame and decodes its JSON content into v. It returns io.EOF when the peer
+// closes the connection.
+func (c *WSConn) Recv(v interface{}) error {
+	return websocket.JSON.Receive(c.Conn, v)
+}
+
+// CloseWithStatus sends a close frame with the given status code and reason, stops the pings and
+// closes the connection.
+func (c *WSConn) CloseWithStatus(code int, reason string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	close(c.done)
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	payload = append(payload, reason...)
+	err := c.writeFrame(websocket.CloseFrame, payload)
+
+	// websocket.Conn.Close sends its own close frame with a normal closure status before closing
+	// the underlying connection, make that write fail so that the peer only gets the frame above.
+	// This also applies to the Close call made by websocket.Server once the handler returns.
+	c.Conn.SetWriteDeadline(time.Now().Add(-time.Second))
+	c.Conn.Close()
+	return err
+}
+
+// Close closes the connection with the WSNormalClosure status code.
+func (c *WSConn) Close() error {
+	return c.CloseWithStatus(WSNormalClosure, "")
+}
+
+// ping sends a ping frame every interval until the connection is closed.
+func (c *WSConn) ping(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.mu.Lock()
+			if c.closed {
+				c.mu.Unlock()
+				return
+			}
+			err := c.writeFrame(websocket.PingFrame, nil)
+			c.mu.Unlock()
+			if err != nil {
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// writeFrame writes a frame of the given type, c.mu must be held. The frame is written with its
+// own frame writer so that the payload type of the connection used by Send is left untouched.
+func (c *WSConn) writeFrame(payloadType byte, payload []byte) error {
+	codec := websocket.Codec{
+		Marshal: func(interface{}) ([]byte, byte, error) { return payload, payloadType, nil },
+	}
+	return codec.Send(c.Conn, nil)
+}
+
+// WSCloseStatus returns the close status code corresponding to the error returned by a websocket
+// handler: WSNormalClosure if err is nil, WSInvalidPayload for bad request errors such as message
+// validation errors, WSPolicyViolation for other client errors and WSInternalError otherwise.
+func WSCloseStatus(err error) int {
+	if err == nil {
+		return WSNormalClosure
+	}
+	if serr, ok := err.(ServiceError); ok {
+		switch status := serr.ResponseStatus(); {
+		case status == 400:
+			return WSInvalidPayload
+		case status > 400 && status < 500:
+			return WSPolicyViolation
+		}
+	}
+	return WSInternalError
+}
@@ -0,0 +1,138 @@
+package goa_test
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shogo82148/goa-v1"
+	"golang.org/x/net/websocket"
+)
+
+var _ = Describe("WSConn", func() {
+	var srv *httptest.Server
+	var conn *goa.WSConn
+	var serverErr chan error
+
+	BeforeEach(func() {
+		errc := make(chan error, 1)
+		serverErr = errc
+		srv = httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+			c := goa.NewWSConn(ws, 0)
+			var msg map[string]int
+			for {
+				if err := c.Recv(&msg); err != nil {
+					if err == io.EOF {
+						err = nil
+					}
+					errc <- err
+					return
+				}
+				msg["count"]++
+				if err := c.Send(msg); err != nil {
+					errc <- err
+					return
+				}
+			}
+		}))
+		ws, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), "", srv.URL)
+		Ω(err).ShouldNot(HaveOccurred())
+		conn = goa.NewWSConn(ws, 0)
+	})
+
+	AfterEach(func() {
+		conn.Close()
+		srv.Close()
+	})
+
+	It("sends and receives JSON messages", func() {
+		Ω(conn.Send(map[string]int{"count": 41})).ShouldNot(HaveOccurred())
+		var msg map[string]int
+		Ω(conn.Recv(&msg)).ShouldNot(HaveOccurred())
+		Ω(msg).Should(Equal(map[string]int{"count": 42}))
+	})
+
+	It("closes the connection with a status code", func() {
+		Ω(conn.CloseWithStatus(goa.WSGoingAway, "bye")).ShouldNot(HaveOccurred())
+		Eventually(serverErr).Should(Receive(BeNil()))
+		Ω(conn.Send(1)).Should(HaveOccurred())
+	})
+
+	It("can be closed twice", func() {
+		Ω(conn.Close()).ShouldNot(HaveOccurred())
+		Ω(conn.Close()).ShouldNot(HaveOccurred())
+	})
+
+	Context("closed with a status code", func() {
+		var (
+			ln     net.Listener
+			frames chan []byte
+		)
+
+		BeforeEach(func() {
+			var err error
+			ln, err = net.Listen("tcp", "127.0.0.1:0")
+			Ω(err).ShouldNot(HaveOccurred())
+			frames = make(chan []byte, 1)
+			go func() {
+				defer GinkgoRecover()
+				c, err := ln.Accept()
+				Ω(err).ShouldNot(HaveOccurred())
+				defer c.Close()
+				r := bufio.NewReader(c)
+				req, err := http.ReadRequest(r)
+				Ω(err).ShouldNot(HaveOccurred())
+				h := sha1.Sum([]byte(req.Header.Get("Sec-WebSocket-Key") + "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+				_, err = io.WriteString(c, "HTTP/1.1 101 Switching Protocols\r\n"+
+					"Upgrade: websocket\r\n"+
+					"Connection: Upgrade\r\n"+
+					"Sec-WebSocket-Accept: "+base64.StdEncoding.EncodeToString(h[:])+"\r\n\r\n")
+				Ω(err).ShouldNot(HaveOccurred())
+				c.SetReadDeadline(time.Now().Add(5 * time.Second))
+				b, err := io.ReadAll(r)
+				Ω(err).ShouldNot(HaveOccurred())
+				frames <- b
+			}()
+		})
+
+		AfterEach(func() {
+			ln.Close()
+		})
+
+		It("sends a single close frame and closes the connection", func() {
+			url := "ws://" + ln.Addr().String()
+			ws, err := websocket.Dial(url, "", "http://"+ln.Addr().String())
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(goa.NewWSConn(ws, 0).CloseWithStatus(goa.WSGoingAway, "bye")).ShouldNot(HaveOccurred())
+			var b []byte
+			Eventually(frames).Should(Receive(&b))
+
+			// Client frames are masked: opcode, masked length, 4 bytes mask, masked payload.
+			Ω(b).Should(HaveLen(2 + 4 + 5))
+			Ω(b[0]).Should(Equal(byte(0x88)))
+			Ω(b[1]).Should(Equal(byte(0x80 | 5)))
+			payload := b[6:]
+			for i := range payload {
+				payload[i] ^= b[2+i%4]
+			}
+			Ω(payload).Should(Equal([]byte{0x03, 0xe9, 'b', 'y', 'e'}))
+		})
+	})
+})
+
+var _ = Describe("WSCloseStatus", func() {
+	It("maps errors to close status codes", func() {
+		Ω(goa.WSCloseStatus(nil)).Should(Equal(goa.WSNormalClosure))
+		Ω(goa.WSCloseStatus(goa.ErrBadRequest("invalid"))).Should(Equal(goa.WSInvalidPayload))
+		Ω(goa.WSCloseStatus(goa.ErrUnauthorized("denied"))).Should(Equal(goa.WSPolicyViolation))
+		Ω(goa.WSCloseStatus(io.ErrUnexpectedEOF)).Should(Equal(goa.WSInternalError))
+	})
+})